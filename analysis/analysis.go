@@ -0,0 +1,303 @@
+// Package analysis lints statements for patterns that are legal SQL but
+// usually a mistake: statements that touch every row, joins that multiply
+// rows, predicates that silently match nothing or cannot use an index.
+//
+//	a := analysis.New()
+//	for _, f := range a.Analyze(query.Core) {
+//	    fmt.Println(f)
+//	}
+//
+// Each Rule has a default Severity, which options can change or turn off:
+//
+//	a := analysis.New(
+//	    analysis.Disable(analysis.SelectStar),
+//	    analysis.WithSeverity(analysis.LeadingWildcard, analysis.SeverityError),
+//	    analysis.WithMaxOffset(5000),
+//	)
+//
+// Findings carry the path to the node they concern, written with the edge
+// labels of the DOT and tree exports, e.g. "WHERE[0].RIGHT".
+//
+// Analyzer.Transformer adapts an Analyzer to plugins.Transformer.
+// Registered with Use, it makes ToSQL fail with an *Error when a statement
+// has findings of SeverityError:
+//
+//	query.Use(analysis.New().Transformer())
+//
+// Transformers see one SELECT core at a time, so rules about set
+// operations only apply when the whole statement is passed to Analyze.
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+)
+
+// Severity ranks a Finding.
+type Severity int
+
+const (
+	SeverityOff     Severity = iota // rule disabled
+	SeverityInfo                    // worth knowing; rarely wrong
+	SeverityWarning                 // likely a mistake or a performance problem
+	SeverityError                   // almost certainly a bug
+)
+
+var severityNames = [...]string{
+	SeverityOff:     "off",
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+// String returns the lower-case name of the severity.
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Rule identifies a check.
+type Rule int
+
+const (
+	MissingWhere         Rule = iota // UPDATE or DELETE without WHERE
+	CartesianJoin                    // join without ON that is not a CROSS JOIN
+	NotInSubquery                    // NOT IN (subquery), which is never true if the subquery returns NULL
+	LeadingWildcard                  // LIKE pattern starting with % or _
+	SelectStar                       // SELECT * outside EXISTS
+	SubqueryOrder                    // ORDER BY without LIMIT in a subquery
+	LargeOffset                      // OFFSET beyond the configured maximum
+	OrChain                          // OR of equalities on one column
+	LockWithSetOperation             // FOR UPDATE/SHARE on a side of UNION, INTERSECT or EXCEPT
+	numRules
+)
+
+var ruleNames = [...]string{
+	MissingWhere:         "missing-where",
+	CartesianJoin:        "cartesian-join",
+	NotInSubquery:        "not-in-subquery",
+	LeadingWildcard:      "leading-wildcard",
+	SelectStar:           "select-star",
+	SubqueryOrder:        "subquery-order",
+	LargeOffset:          "large-offset",
+	OrChain:              "or-chain",
+	LockWithSetOperation: "lock-with-set-operation",
+}
+
+var defaultSeverity = [numRules]Severity{
+	MissingWhere:         SeverityError,
+	CartesianJoin:        SeverityError,
+	NotInSubquery:        SeverityWarning,
+	LeadingWildcard:      SeverityWarning,
+	SelectStar:           SeverityWarning,
+	SubqueryOrder:        SeverityWarning,
+	LargeOffset:          SeverityWarning,
+	OrChain:              SeverityInfo,
+	LockWithSetOperation: SeverityError,
+}
+
+// String returns the kebab-case name of the rule, as accepted by ParseRule.
+func (r Rule) String() string {
+	if r >= 0 && int(r) < len(ruleNames) {
+		return ruleNames[r]
+	}
+	return fmt.Sprintf("Rule(%d)", int(r))
+}
+
+// Rules returns every rule, in declaration order.
+func Rules() []Rule {
+	rules := make([]Rule, numRules)
+	for i := range rules {
+		rules[i] = Rule(i)
+	}
+	return rules
+}
+
+// ParseRule returns the rule with the given name.
+func ParseRule(name string) (Rule, error) {
+	for i, n := range ruleNames {
+		if n == name {
+			return Rule(i), nil
+		}
+	}
+	return 0, fmt.Errorf("analysis: unknown rule %q", name)
+}
+
+// Finding is a problem found in a statement.
+type Finding struct {
+	Rule     Rule
+	Severity Severity
+	Message  string
+	Node     nodes.Node // the node the finding is about
+	Path     string     // edge labels from the statement to Node; empty for the statement itself
+}
+
+// String formats the finding as "severity: path: message (rule)".
+func (f Finding) String() string {
+	var sb strings.Builder
+	sb.WriteString(f.Severity.String())
+	sb.WriteString(": ")
+	if f.Path != "" {
+		sb.WriteString(f.Path)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(f.Message)
+	sb.WriteString(" (")
+	sb.WriteString(f.Rule.String())
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// Error is returned by the Transformer when a statement has findings at
+// or above its failing severity.
+type Error struct {
+	Findings []Finding
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		msgs[i] = f.Message
+	}
+	return "analysis: " + strings.Join(msgs, "; ")
+}
+
+// DefaultMaxOffset is the largest OFFSET not reported by LargeOffset.
+const DefaultMaxOffset = 1000
+
+// DefaultOrChainLength is the number of equalities on one column joined by
+// OR from which OrChain reports them.
+const DefaultOrChainLength = 3
+
+// Analyzer checks statements against a set of rules.
+type Analyzer struct {
+	severity      [numRules]Severity
+	maxOffset     int64
+	orChainLength int
+	failOn        Severity
+}
+
+// Option configures an Analyzer.
+type Option func(*Analyzer)
+
+// WithSeverity sets the severity reported for rule. SeverityOff disables it.
+func WithSeverity(rule Rule, s Severity) Option {
+	return func(a *Analyzer) {
+		if rule >= 0 && rule < numRules {
+			a.severity[rule] = s
+		}
+	}
+}
+
+// Disable turns the given rules off.
+func Disable(rules ...Rule) Option {
+	return func(a *Analyzer) {
+		for _, r := range rules {
+			WithSeverity(r, SeverityOff)(a)
+		}
+	}
+}
+
+// WithMaxOffset sets the largest OFFSET that LargeOffset accepts.
+func WithMaxOffset(n int64) Option {
+	return func(a *Analyzer) { a.maxOffset = n }
+}
+
+// WithOrChainLength sets how many equalities on one column an OR chain
+// must have before OrChain reports it.
+func WithOrChainLength(n int) Option {
+	return func(a *Analyzer) { a.orChainLength = max(n, 2) }
+}
+
+// FailOn sets the lowest severity that makes the Transformer fail.
+// The default is SeverityError.
+func FailOn(s Severity) Option {
+	return func(a *Analyzer) { a.failOn = max(s, SeverityInfo) }
+}
+
+// New returns an Analyzer with every rule at its default severity.
+func New(opts ...Option) *Analyzer {
+	a := &Analyzer{
+		severity:      defaultSeverity,
+		maxOffset:     DefaultMaxOffset,
+		orChainLength: DefaultOrChainLength,
+		failOn:        SeverityError,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Severity returns the severity the analyzer reports for rule.
+func (a *Analyzer) Severity(rule Rule) Severity {
+	if rule < 0 || rule >= numRules {
+		return SeverityOff
+	}
+	return a.severity[rule]
+}
+
+// Analyze lints a statement and its subqueries. Any node is accepted;
+// nodes that are not statements are checked as expressions.
+func (a *Analyzer) Analyze(n nodes.Node) []Finding {
+	w := &walker{a: a}
+	w.walk(n, frame{})
+	return w.findings
+}
+
+// Transformer returns a plugins.Transformer that leaves statements
+// unchanged, or fails with an *Error when they have findings at or above
+// the FailOn severity.
+func (a *Analyzer) Transformer() plugins.Transformer {
+	return &transformer{a: a}
+}
+
+type transformer struct {
+	a *Analyzer
+}
+
+func (t *transformer) check(n nodes.Node) error {
+	var failed []Finding
+	for _, f := range t.a.Analyze(n) {
+		if f.Severity >= t.a.failOn {
+			failed = append(failed, f)
+		}
+	}
+	if len(failed) > 0 {
+		return &Error{Findings: failed}
+	}
+	return nil
+}
+
+func (t *transformer) TransformSelect(core *nodes.SelectCore) (*nodes.SelectCore, error) {
+	if err := t.check(core); err != nil {
+		return nil, err
+	}
+	return core, nil
+}
+
+func (t *transformer) TransformInsert(stmt *nodes.InsertStatement) (*nodes.InsertStatement, error) {
+	if err := t.check(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (t *transformer) TransformUpdate(stmt *nodes.UpdateStatement) (*nodes.UpdateStatement, error) {
+	if err := t.check(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (t *transformer) TransformDelete(stmt *nodes.DeleteStatement) (*nodes.DeleteStatement, error) {
+	if err := t.check(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
@@ -0,0 +1,228 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+var (
+	users = nodes.NewTable("users")
+	posts = nodes.NewTable("posts")
+)
+
+// assertFindings checks that findings holds exactly the given rules, and
+// that each is reported at the matching path.
+func assertFindings(t *testing.T, findings []Finding, want ...any) {
+	t.Helper()
+	if len(findings) != len(want)/2 {
+		t.Fatalf("expected %d findings, got %d: %v", len(want)/2, len(findings), findings)
+	}
+	for i, f := range findings {
+		rule, path := want[2*i].(Rule), want[2*i+1].(string)
+		if f.Rule != rule || f.Path != path {
+			t.Errorf("finding %d: expected %s at %q, got %s at %q", i, rule, path, f.Rule, f.Path)
+		}
+	}
+}
+
+func TestCleanQuery(t *testing.T) {
+	t.Parallel()
+	sub := managers.NewSelectManager(posts).Select(posts.Col("id")).Where(posts.Col("user_id").Eq(users.Col("id")))
+	q := managers.NewSelectManager(users).
+		Select(users.Col("id"), users.Col("email")).
+		Where(users.Col("email").Like("bob%")).
+		Where(nodes.Exists(sub.Core)).
+		Order(users.Col("id").Asc()).
+		Limit(10).Offset(20)
+	assertFindings(t, New().Analyze(q.Core))
+}
+
+func TestMissingWhere(t *testing.T) {
+	t.Parallel()
+	upd := managers.NewUpdateManager(users).Set(users.Col("active"), false)
+	assertFindings(t, New().Analyze(upd.Statement), MissingWhere, "")
+
+	del := managers.NewDeleteManager(users)
+	findings := New().Analyze(del.Statement)
+	assertFindings(t, findings, MissingWhere, "")
+	testutil.AssertEqual(t, findings[0].Severity, SeverityError)
+	testutil.AssertEqual(t, findings[0].String(), "error: DELETE without WHERE removes every row (missing-where)")
+
+	del.Where(users.Col("id").Eq(1))
+	assertFindings(t, New().Analyze(del.Statement))
+}
+
+func TestCartesianJoin(t *testing.T) {
+	t.Parallel()
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Joins: []*nodes.JoinNode{
+			{Left: users, Right: posts, Type: nodes.InnerJoin},
+			{Left: users, Right: posts, Type: nodes.CrossJoin},
+			{Left: users, Right: posts, Type: nodes.LeftOuterJoin, On: posts.Col("user_id").Eq(users.Col("id"))},
+		},
+	}
+	findings := New().Analyze(core)
+	assertFindings(t, findings, CartesianJoin, "JOIN[0]")
+	testutil.AssertEqual(t, findings[0].Node, nodes.Node(core.Joins[0]))
+}
+
+func TestNotInSubquery(t *testing.T) {
+	t.Parallel()
+	sub := managers.NewSelectManager(posts).Select(posts.Col("user_id"))
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Wheres: []nodes.Node{
+			users.Col("id").NotIn(sub),
+			users.Col("id").NotIn(1, 2),
+			users.Col("id").In(sub),
+		},
+	}
+	assertFindings(t, New().Analyze(core), NotInSubquery, "WHERE[0]")
+}
+
+func TestLeadingWildcard(t *testing.T) {
+	t.Parallel()
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Wheres: []nodes.Node{
+			users.Col("email").Like("%@example.com"),
+			users.Col("name").NotLike("_ob"),
+			users.Col("name").Like("b%"),
+		},
+	}
+	findings := New().Analyze(core)
+	assertFindings(t, findings, LeadingWildcard, "WHERE[0]", LeadingWildcard, "WHERE[1]")
+	testutil.AssertEqual(t, findings[0].Message, `LIKE pattern "%@example.com" starts with a wildcard and cannot use an index`)
+}
+
+func TestSelectStar(t *testing.T) {
+	t.Parallel()
+	sub := &nodes.SelectCore{From: posts, Wheres: []nodes.Node{posts.Col("user_id").Eq(users.Col("id"))}}
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id"), users.Star()},
+		Wheres:      []nodes.Node{nodes.Exists(sub)},
+	}
+	assertFindings(t, New().Analyze(core), SelectStar, "SELECT[1]")
+
+	// No projections renders as SELECT *.
+	assertFindings(t, New().Analyze(&nodes.SelectCore{From: users}), SelectStar, "")
+}
+
+func TestSubqueryOrder(t *testing.T) {
+	t.Parallel()
+	ordered := &nodes.SelectCore{
+		From:        posts,
+		Projections: []nodes.Node{posts.Col("user_id")},
+		Orders:      []nodes.Node{posts.Col("id").Desc()},
+	}
+	core := &nodes.SelectCore{
+		From:        &nodes.TableAlias{Relation: ordered, AliasName: "p"},
+		Projections: []nodes.Node{nodes.NewAttribute(&nodes.Table{Name: "p"}, "user_id")},
+		Orders:      []nodes.Node{nodes.NewAttribute(&nodes.Table{Name: "p"}, "user_id").Asc()},
+	}
+	assertFindings(t, New().Analyze(core), SubqueryOrder, "FROM.RELATION")
+
+	ordered.Limit = nodes.Literal(5)
+	assertFindings(t, New().Analyze(core))
+}
+
+func TestLargeOffset(t *testing.T) {
+	t.Parallel()
+	q := managers.NewSelectManager(users).Select(users.Col("id")).Offset(5000)
+	findings := New().Analyze(q.Core)
+	assertFindings(t, findings, LargeOffset, "OFFSET")
+	testutil.AssertEqual(t, findings[0].Message, "OFFSET 5000 reads and discards 5000 rows; page by key instead")
+
+	assertFindings(t, New(WithMaxOffset(10000)).Analyze(q.Core))
+}
+
+func TestOrChain(t *testing.T) {
+	t.Parallel()
+	status := users.Col("status")
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Wheres: []nodes.Node{
+			status.Eq("a").Or(status.Eq("b")).Or(status.Eq("c")),
+			status.Eq("a").Or(users.Col("role").Eq("b")).Or(status.Eq("c")),
+			status.Eq("a").Or(status.Eq("b")),
+		},
+	}
+	findings := New().Analyze(core)
+	assertFindings(t, findings, OrChain, "WHERE[0].EXPR")
+	testutil.AssertEqual(t, findings[0].Message, "3 equalities on users.status joined by OR; use IN")
+	testutil.AssertEqual(t, findings[0].Severity, SeverityInfo)
+
+	assertFindings(t, New(WithOrChainLength(2)).Analyze(core), OrChain, "WHERE[0].EXPR", OrChain, "WHERE[2].EXPR")
+}
+
+func TestLockWithSetOperation(t *testing.T) {
+	t.Parallel()
+	left := &nodes.SelectCore{From: users, Projections: []nodes.Node{users.Col("id")}, Lock: nodes.ForUpdate}
+	right := &nodes.SelectCore{From: posts, Projections: []nodes.Node{posts.Col("user_id")}}
+	union := &nodes.SetOperationNode{Left: left, Right: right, Type: nodes.Union}
+	findings := New().Analyze(union)
+	assertFindings(t, findings, LockWithSetOperation, "LEFT")
+	testutil.AssertEqual(t, findings[0].Message, "locking clause cannot be used with UNION")
+}
+
+func TestNestedPaths(t *testing.T) {
+	t.Parallel()
+	sub := managers.NewSelectManager(posts).Select(posts.Col("user_id")).Where(posts.Col("title").Like("%x"))
+	ins := managers.NewInsertManager(users).Columns(users.Col("id")).FromSelect(sub)
+	assertFindings(t, New().Analyze(ins.Statement), LeadingWildcard, "SELECT.WHERE[0]")
+}
+
+func TestConfiguration(t *testing.T) {
+	t.Parallel()
+	core := &nodes.SelectCore{From: users, Wheres: []nodes.Node{users.Col("email").Like("%x")}}
+
+	assertFindings(t, New(Disable(SelectStar)).Analyze(core), LeadingWildcard, "WHERE[0]")
+
+	a := New(WithSeverity(LeadingWildcard, SeverityError))
+	testutil.AssertEqual(t, a.Severity(LeadingWildcard), SeverityError)
+	testutil.AssertEqual(t, a.Analyze(core)[1].Severity, SeverityError)
+}
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+	for _, r := range Rules() {
+		got, err := ParseRule(r.String())
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, got, r)
+	}
+	_, err := ParseRule("nope")
+	testutil.AssertEqual(t, err.Error(), `analysis: unknown rule "nope"`)
+	testutil.AssertEqual(t, SeverityWarning.String(), "warning")
+}
+
+func TestTransformer(t *testing.T) {
+	t.Parallel()
+	q := managers.NewSelectManager(users).Where(users.Col("email").Like("%x"))
+	q.Use(New().Transformer())
+	_, _, err := q.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+
+	q = managers.NewSelectManager(users).Where(users.Col("email").Like("%x"))
+	q.Use(New(FailOn(SeverityWarning), Disable(SelectStar)).Transformer())
+	_, _, err = q.ToSQL(visitors.NewPostgresVisitor())
+	var lintErr *Error
+	testutil.AssertEqual(t, errors.As(err, &lintErr), true)
+	testutil.AssertEqual(t, len(lintErr.Findings), 1)
+	testutil.AssertEqual(t, err.Error(), `analysis: LIKE pattern "%x" starts with a wildcard and cannot use an index`)
+
+	del := managers.NewDeleteManager(users)
+	del.Use(New().Transformer())
+	_, _, err = del.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertEqual(t, errors.As(err, &lintErr), true)
+}
@@ -0,0 +1,455 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// walker accumulates the findings of one statement.
+type walker struct {
+	a        *Analyzer
+	findings []Finding
+}
+
+// frame is the position of a node in the tree.
+type frame struct {
+	path   string
+	nested bool // inside another query; queries here are subqueries
+	exists bool // the subquery of EXISTS, whose projections are ignored
+	inOr   bool // an operand of OR, already covered by the outermost OR
+}
+
+// subquery is implemented by managers.SelectManager, which may stand in
+// for its SelectCore anywhere in a tree.
+type subquery interface {
+	CloneCore() *nodes.SelectCore
+}
+
+func (w *walker) report(rule Rule, n nodes.Node, path, format string, args ...any) {
+	sev := w.a.Severity(rule)
+	if sev == SeverityOff {
+		return
+	}
+	w.findings = append(w.findings, Finding{
+		Rule:     rule,
+		Severity: sev,
+		Message:  fmt.Sprintf(format, args...),
+		Node:     n,
+		Path:     path,
+	})
+}
+
+// walk checks n and its descendants.
+func (w *walker) walk(n nodes.Node, f frame) {
+	if sq, ok := n.(subquery); ok {
+		n = sq.CloneCore()
+	}
+	if n == nil {
+		return
+	}
+	w.check(n, f)
+
+	for _, e := range edges(n) {
+		child := frame{path: joinPath(f.path, e.label), nested: true}
+		switch n.(type) {
+		case *nodes.GroupingNode:
+			// Parentheses change nothing about what they enclose.
+			child.nested, child.exists, child.inOr = f.nested, f.exists, f.inOr
+		case *nodes.OrNode:
+			child.inOr = true
+		case *nodes.InsertStatement, *nodes.CreateViewStatement,
+			*nodes.CreateTableAsStatement, *nodes.ExplainStatement:
+			// The query these wrap is the statement, not a subquery.
+			child.nested = f.nested
+		case *nodes.ExistsNode:
+			child.exists = true
+		}
+		w.walk(e.node, child)
+	}
+}
+
+// check applies the rules that concern n itself.
+func (w *walker) check(n nodes.Node, f frame) {
+	switch n := n.(type) {
+	case *nodes.UpdateStatement:
+		if len(n.Wheres) == 0 {
+			w.report(MissingWhere, n, f.path, "UPDATE without WHERE changes every row")
+		}
+	case *nodes.DeleteStatement:
+		if len(n.Wheres) == 0 {
+			w.report(MissingWhere, n, f.path, "DELETE without WHERE removes every row")
+		}
+	case *nodes.SelectCore:
+		w.selectCore(n, f)
+	case *nodes.SetOperationNode:
+		w.setOperation(n, f)
+	case *nodes.JoinNode:
+		if n.On == nil && n.Type != nodes.CrossJoin && n.Type != nodes.StringJoin {
+			w.report(CartesianJoin, n, f.path, "%s without ON joins every row with every row; use CROSS JOIN if that is intended", n.Type)
+		}
+	case *nodes.InNode:
+		if n.Negate && len(n.Vals) == 1 && isQuery(n.Vals[0]) {
+			w.report(NotInSubquery, n, f.path, "NOT IN (subquery) is never true if the subquery returns a NULL; use NOT EXISTS")
+		}
+	case *nodes.ComparisonNode:
+		if n.Op == nodes.OpLike || n.Op == nodes.OpNotLike {
+			if p, ok := stringValue(n.Right); ok && (strings.HasPrefix(p, "%") || strings.HasPrefix(p, "_")) {
+				w.report(LeadingWildcard, n, f.path, "LIKE pattern %q starts with a wildcard and cannot use an index", p)
+			}
+		}
+	case *nodes.OrNode:
+		if !f.inOr {
+			w.orChain(n, f.path)
+		}
+	}
+}
+
+func (w *walker) selectCore(core *nodes.SelectCore, f frame) {
+	if !f.exists {
+		if len(core.Projections) == 0 {
+			w.report(SelectStar, core, f.path, "SELECT * returns whatever columns the table has; list them")
+		}
+		for i, p := range core.Projections {
+			if _, ok := p.(*nodes.StarNode); ok {
+				w.report(SelectStar, p, joinPath(f.path, fmt.Sprintf("SELECT[%d]", i)),
+					"SELECT * returns whatever columns the table has; list them")
+			}
+		}
+	}
+	if f.nested && len(core.Orders) > 0 && core.Limit == nil && core.Offset == nil {
+		w.report(SubqueryOrder, core, f.path, "ORDER BY in a subquery without LIMIT does not order the outer query")
+	}
+	w.offset(core.Offset, joinPath(f.path, "OFFSET"))
+}
+
+func (w *walker) setOperation(n *nodes.SetOperationNode, f frame) {
+	for _, side := range []struct {
+		label string
+		node  nodes.Node
+	}{{"LEFT", n.Left}, {"RIGHT", n.Right}} {
+		if core := queryCore(side.node); core != nil && core.Lock != nodes.NoLock {
+			w.report(LockWithSetOperation, core, joinPath(f.path, side.label),
+				"locking clause cannot be used with %s", n.Type)
+		}
+	}
+	w.offset(n.Offset, joinPath(f.path, "OFFSET"))
+}
+
+func (w *walker) offset(n nodes.Node, path string) {
+	if v, ok := intValue(n); ok && v > w.a.maxOffset {
+		w.report(LargeOffset, n, path, "OFFSET %d reads and discards %d rows; page by key instead", v, v)
+	}
+}
+
+// orChain reports an OR whose terms are all equalities on the same column
+// when there are enough of them.
+func (w *walker) orChain(or *nodes.OrNode, path string) {
+	terms := orTerms(or)
+	if len(terms) < w.a.orChainLength {
+		return
+	}
+	var col nodes.Node
+	for _, t := range terms {
+		cmp, ok := t.(*nodes.ComparisonNode)
+		if !ok || cmp.Op != nodes.OpEq || isQuery(cmp.Right) {
+			return
+		}
+		if col == nil {
+			col = cmp.Left
+		} else if !nodes.Equal(col, cmp.Left) {
+			return
+		}
+	}
+	w.report(OrChain, or, path, "%d equalities on %s joined by OR; use IN", len(terms), describe(col))
+}
+
+// orTerms flattens a tree of ORs, looking through parentheses.
+func orTerms(n nodes.Node) []nodes.Node {
+	switch n := n.(type) {
+	case *nodes.OrNode:
+		return append(orTerms(n.Left), orTerms(n.Right)...)
+	case *nodes.GroupingNode:
+		if _, ok := n.Expr.(*nodes.OrNode); ok {
+			return orTerms(n.Expr)
+		}
+	}
+	return []nodes.Node{n}
+}
+
+// isQuery reports whether n is a SELECT or set operation.
+func isQuery(n nodes.Node) bool {
+	switch n := n.(type) {
+	case *nodes.SelectCore, *nodes.SetOperationNode, subquery:
+		return true
+	case *nodes.GroupingNode:
+		return isQuery(n.Expr)
+	}
+	return false
+}
+
+// queryCore returns the SelectCore of a query operand, or nil when it is
+// another set operation or not a query.
+func queryCore(n nodes.Node) *nodes.SelectCore {
+	switch n := n.(type) {
+	case *nodes.SelectCore:
+		return n
+	case subquery:
+		return n.CloneCore()
+	case *nodes.GroupingNode:
+		return queryCore(n.Expr)
+	}
+	return nil
+}
+
+// stringValue returns the value of a string literal or bound string.
+func stringValue(n nodes.Node) (string, bool) {
+	switch n := n.(type) {
+	case *nodes.LiteralNode:
+		s, ok := n.Value.(string)
+		return s, ok
+	case *nodes.BindParamNode:
+		s, ok := n.Value.(string)
+		return s, ok
+	}
+	return "", false
+}
+
+// intValue returns the value of an integer literal or bound integer.
+func intValue(n nodes.Node) (int64, bool) {
+	var v any
+	switch n := n.(type) {
+	case *nodes.LiteralNode:
+		v = n.Value
+	case *nodes.BindParamNode:
+		v = n.Value
+	default:
+		return 0, false
+	}
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true // #nosec G115 - offsets are far below the int64 limit
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true // #nosec G115 - offsets are far below the int64 limit
+	}
+	return 0, false
+}
+
+// describe names an expression in a finding message.
+func describe(n nodes.Node) string {
+	if a, ok := n.(*nodes.Attribute); ok {
+		if q := nodes.RelationName(a.Relation); q != "" {
+			return q + "." + a.Name
+		}
+		return a.Name
+	}
+	return "one expression"
+}
+
+func joinPath(path, label string) string {
+	if path == "" {
+		return label
+	}
+	return path + "." + label
+}
+
+// edge is a labelled link from a node to one of its children.
+type edge struct {
+	label string
+	node  nodes.Node
+}
+
+// edges returns the children of n, labelled as in the DOT export.
+func edges(n nodes.Node) []edge {
+	var out []edge
+	add := func(label string, child nodes.Node) {
+		if child != nil {
+			out = append(out, edge{label, child})
+		}
+	}
+	list := func(prefix string, children []nodes.Node) {
+		for i, c := range children {
+			add(fmt.Sprintf("%s[%d]", prefix, i), c)
+		}
+	}
+	assignments := func(list []*nodes.AssignmentNode) {
+		for i, a := range list {
+			add(fmt.Sprintf("SET[%d]", i), a)
+		}
+	}
+	window := func(w *nodes.WindowDefinition) {
+		if w != nil {
+			list("PARTITION", w.PartitionBy)
+			list("ORDER", w.OrderBy)
+		}
+	}
+
+	switch n := n.(type) {
+	case *nodes.SelectCore:
+		for i, cte := range n.CTEs {
+			add(fmt.Sprintf("CTE[%d]", i), cte)
+		}
+		for i, c := range n.DistinctOn {
+			add(fmt.Sprintf("DISTINCT ON.COL[%d]", i), c)
+		}
+		add("FROM", n.From)
+		list("SELECT", n.Projections)
+		for i, j := range n.Joins {
+			add(fmt.Sprintf("JOIN[%d]", i), j)
+		}
+		list("WHERE", n.Wheres)
+		list("GROUP", n.Groups)
+		list("HAVING", n.Havings)
+		for i, w := range n.Windows {
+			if w != nil {
+				for j, p := range w.PartitionBy {
+					add(fmt.Sprintf("WINDOW[%d].PARTITION[%d]", i, j), p)
+				}
+				for j, o := range w.OrderBy {
+					add(fmt.Sprintf("WINDOW[%d].ORDER[%d]", i, j), o)
+				}
+			}
+		}
+		list("QUALIFY", n.Qualifies)
+		list("ORDER", n.Orders)
+		if n.LimitBy != nil {
+			add("LIMIT BY.LIMIT", n.LimitBy.Limit)
+			add("LIMIT BY.OFFSET", n.LimitBy.Offset)
+			for i, c := range n.LimitBy.Columns {
+				add(fmt.Sprintf("LIMIT BY.BY[%d]", i), c)
+			}
+		}
+		add("LIMIT", n.Limit)
+		add("OFFSET", n.Offset)
+	case *nodes.SetOperationNode:
+		add("LEFT", n.Left)
+		add("RIGHT", n.Right)
+		list("ORDER", n.Orders)
+		add("LIMIT", n.Limit)
+		add("OFFSET", n.Offset)
+	case *nodes.InsertStatement:
+		add("INTO", n.Into)
+		list("COLUMN", n.Columns)
+		for i, row := range n.Values {
+			for j, v := range row {
+				add(fmt.Sprintf("VALUES[%d][%d]", i, j), v)
+			}
+		}
+		add("SELECT", n.Select)
+		list("RETURNING", n.Returning)
+		if n.OnConflict != nil {
+			add("ON_CONFLICT", n.OnConflict)
+		}
+	case *nodes.UpdateStatement:
+		add("TABLE", n.Table)
+		assignments(n.Assignments)
+		list("WHERE", n.Wheres)
+		list("RETURNING", n.Returning)
+	case *nodes.DeleteStatement:
+		add("FROM", n.From)
+		list("WHERE", n.Wheres)
+		list("RETURNING", n.Returning)
+	case *nodes.OnConflictNode:
+		list("TARGET", n.Columns)
+		assignments(n.Assignments)
+		list("WHERE", n.Wheres)
+	case *nodes.AssignmentNode:
+		add("COLUMN", n.Left)
+		add("VALUE", n.Right)
+	case *nodes.CTENode:
+		add("QUERY", n.Query)
+	case *nodes.CreateViewStatement:
+		add("AS", n.Query)
+	case *nodes.CreateTableAsStatement:
+		add("AS", n.Query)
+	case *nodes.ExplainStatement:
+		add("STATEMENT", n.Statement)
+	case *nodes.TableAlias:
+		add("RELATION", n.Relation)
+	case *nodes.JoinNode:
+		add("RIGHT", n.Right)
+		add("ON", n.On)
+	case *nodes.ComparisonNode:
+		add("LEFT", n.Left)
+		add("RIGHT", n.Right)
+	case *nodes.AndNode:
+		add("LEFT", n.Left)
+		add("RIGHT", n.Right)
+	case *nodes.OrNode:
+		add("LEFT", n.Left)
+		add("RIGHT", n.Right)
+	case *nodes.InfixNode:
+		add("LEFT", n.Left)
+		add("RIGHT", n.Right)
+	case *nodes.UnaryNode:
+		add("EXPR", n.Expr)
+	case *nodes.UnaryMathNode:
+		add("EXPR", n.Expr)
+	case *nodes.NotNode:
+		add("EXPR", n.Expr)
+	case *nodes.GroupingNode:
+		add("EXPR", n.Expr)
+	case *nodes.AliasNode:
+		add("EXPR", n.Expr)
+	case *nodes.StarNode:
+		for i, r := range n.Replace {
+			add(fmt.Sprintf("REPLACE[%d]", i), r)
+		}
+	case *nodes.OrderingNode:
+		add("EXPR", n.Expr)
+	case *nodes.ExtractNode:
+		add("FROM", n.Expr)
+	case *nodes.InNode:
+		add("EXPR", n.Expr)
+		list("VAL", n.Vals)
+	case *nodes.BetweenNode:
+		add("EXPR", n.Expr)
+		add("LOW", n.Low)
+		add("HIGH", n.High)
+	case *nodes.ExistsNode:
+		add("SUBQUERY", n.Subquery)
+	case *nodes.AggregateNode:
+		add("EXPR", n.Expr)
+		add("FILTER", n.Filter)
+	case *nodes.NamedFunctionNode:
+		list("ARG", n.Args)
+	case *nodes.WindowFuncNode:
+		list("ARG", n.Args)
+	case *nodes.OverNode:
+		add("EXPR", n.Expr)
+		window(n.Window)
+	case *nodes.CaseNode:
+		add("OPERAND", n.Operand)
+		for i, c := range n.Whens {
+			add(fmt.Sprintf("WHEN[%d]", i), c.Condition)
+			add(fmt.Sprintf("THEN[%d]", i), c.Result)
+		}
+		add("ELSE", n.ElseVal)
+	case *nodes.GroupingSetNode:
+		if n.Type == nodes.GroupingSets {
+			for i, set := range n.Sets {
+				for j, c := range set {
+					add(fmt.Sprintf("SET[%d][%d]", i, j), c)
+				}
+			}
+		} else {
+			list("COL", n.Columns)
+		}
+	case *nodes.CastedNode:
+		if v, ok := n.Value.(nodes.Node); ok {
+			add("EXPR", v)
+		}
+	case nodes.Parent:
+		list("CHILD", n.Children())
+	}
+	return out
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/bawdo/gosbee/schema"
+)
+
+// genOptions configures generate.
+type genOptions struct {
+	// Package is the package clause of the generated files.
+	Package string
+
+	// Engine selects the dialect of the cast types set on attributes.
+	Engine string
+}
+
+type tableData struct {
+	Package string
+	Time    bool // whether a row field is a time.Time
+	Name    string
+	View    bool
+	Var     string // Users
+	Type    string // UsersTable
+	Row     string // UsersRow
+	Columns []columnData
+}
+
+type columnData struct {
+	Name     string
+	Field    string
+	CastType string
+	GoType   string
+	Tag      string
+}
+
+var fileTemplate = template.Must(template.New("table").Parse(`// Code generated by gosbee-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .Time}}
+	"time"
+{{end}}
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// {{.Type}} is the {{printf "%q" .Name}} {{if .View}}view{{else}}table{{end}} with an attribute per column.
+type {{.Type}} struct {
+	Table *nodes.Table
+{{range .Columns}}
+	{{.Field}} *nodes.Attribute
+{{- end}}
+}
+
+// {{.Var}} is the {{printf "%q" .Name}} {{if .View}}view{{else}}table{{end}}.
+var {{.Var}} = new{{.Type}}()
+
+func new{{.Type}}() {{.Type}} {
+	t := nodes.NewTable({{printf "%q" .Name}})
+	return {{.Type}}{
+		Table: t,
+{{- range .Columns}}
+		{{.Field}}: t.Col({{printf "%q" .Name}}){{if .CastType}}.Typed({{printf "%q" .CastType}}){{end}},
+{{- end}}
+	}
+}
+
+// Columns returns the attributes in column order.
+func (t {{.Type}}) Columns() []nodes.Node {
+	return []nodes.Node{ {{- range $i, $c := .Columns}}{{if $i}}, {{end}}t.{{$c.Field}}{{end -}} }
+}
+
+// {{.Row}} is a row of {{printf "%q" .Name}}.
+type {{.Row}} struct {
+{{- range .Columns}}
+	{{.Field}} {{.GoType}} ` + "`" + `db:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+`))
+
+// generate renders one file per table of s, keyed by file name.
+func generate(s *schema.Schema, opts genOptions) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(s.Tables))
+	owners := make(map[string]string)
+	for _, t := range s.Tables {
+		data := newTableData(t, opts)
+		for _, ident := range []string{data.Var, data.Type, data.Row, fileName(t.Name)} {
+			if other, ok := owners[ident]; ok {
+				return nil, fmt.Errorf("tables %q and %q both generate %s", other, t.Name, ident)
+			}
+			owners[ident] = t.Name
+		}
+		var buf bytes.Buffer
+		if err := fileTemplate.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("table %q: %w", t.Name, err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("table %q: %w", t.Name, err)
+		}
+		files[fileName(t.Name)] = src
+	}
+	return files, nil
+}
+
+// reservedFields are the names a column field must not take.
+var reservedFields = map[string]bool{"Table": true, "Columns": true}
+
+func newTableData(t *schema.Table, opts genOptions) tableData {
+	name := exportedName(t.Name)
+	data := tableData{
+		Package: opts.Package,
+		Name:    t.Name,
+		View:    t.View,
+		Var:     name,
+		Type:    name + "Table",
+		Row:     name + "Row",
+	}
+
+	// A single integer key is assumed to be generated by the database, so
+	// the row omits it from INSERTs while it is zero.
+	autoKey := ""
+	if len(t.PrimaryKey) == 1 {
+		if c := t.Column(t.PrimaryKey[0]); c != nil && goType(c.Type) == "int64" {
+			autoKey = c.Name
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, c := range t.Columns {
+		field := exportedName(c.Name)
+		if reservedFields[field] {
+			field += "Col"
+		}
+		for base, i := field, 2; used[field]; i++ {
+			field = fmt.Sprintf("%s%d", base, i)
+		}
+		used[field] = true
+
+		typ := goType(c.Type)
+		if c.Nullable && typ != "[]byte" && typ != "any" {
+			typ = "*" + typ
+		}
+		data.Time = data.Time || strings.HasSuffix(typ, "time.Time")
+
+		tag := c.Name
+		switch {
+		case c.Name == autoKey:
+			tag += ",pk,omitempty"
+		case slices.Contains(t.PrimaryKey, c.Name):
+			tag += ",pk"
+		}
+		data.Columns = append(data.Columns, columnData{
+			Name:     c.Name,
+			Field:    field,
+			CastType: castType(opts.Engine, c.Type),
+			GoType:   typ,
+			Tag:      tag,
+		})
+	}
+	return data
+}
+
+// initialisms are upper-cased whole when they form a word of a name.
+var initialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true,
+	"dns": true, "eof": true, "guid": true, "html": true, "http": true,
+	"https": true, "id": true, "ip": true, "json": true, "rpc": true,
+	"sla": true, "sql": true, "ssh": true, "tcp": true, "tls": true,
+	"ttl": true, "udp": true, "ui": true, "uid": true, "uri": true,
+	"url": true, "utc": true, "uuid": true, "xml": true,
+}
+
+// exportedName converts a snake_case or camelCase SQL name to an exported
+// Go identifier: "user_id" becomes "UserID".
+func exportedName(sqlName string) string {
+	words := strings.FieldsFunc(sqlName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// packageName converts a directory name to a package name.
+func packageName(dir string) string {
+	name := strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, dir))
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return "tables"
+	}
+	return name
+}
+
+// fileName returns the generated file name for a table. The ".gen.go"
+// suffix keeps names such as "ab_test" from being read as test files.
+func fileName(table string) string {
+	name := strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, table))
+	return name + ".gen.go"
+}
+
+var typeArgs = regexp.MustCompile(`\s*\(.*\)`)
+
+// baseType lower-cases a column type and strips its arguments and
+// modifiers: "VARCHAR(255)" becomes "varchar", "int unsigned" becomes "int".
+func baseType(sqlType string) string {
+	t := strings.ToLower(strings.TrimSpace(sqlType))
+	t = typeArgs.ReplaceAllString(t, "")
+	t = strings.TrimSuffix(t, " unsigned")
+	return strings.TrimSuffix(t, " zerofill")
+}
+
+// goType maps a column type to the Go type its values scan into.
+func goType(sqlType string) string {
+	if strings.EqualFold(strings.TrimSpace(sqlType), "tinyint(1)") {
+		return "bool"
+	}
+	t := baseType(sqlType)
+	switch t {
+	case "bool", "boolean":
+		return "bool"
+	case "smallint", "integer", "int", "bigint", "tinyint", "mediumint",
+		"int2", "int4", "int8", "serial", "smallserial", "bigserial", "year":
+		return "int64"
+	case "real", "float", "float4", "float8", "double", "double precision":
+		return "float64"
+	case "numeric", "decimal":
+		// Kept as text so no precision is lost.
+		return "string"
+	case "date", "datetime", "timestamp", "timestamptz",
+		"timestamp with time zone", "timestamp without time zone":
+		return "time.Time"
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "[]byte"
+	case "text", "varchar", "char", "character", "character varying", "nvarchar",
+		"tinytext", "mediumtext", "longtext", "uuid", "json", "jsonb", "citext",
+		"enum", "set", "name", "inet", "cidr", "xml", "interval",
+		"time", "timetz", "time with time zone", "time without time zone":
+		return "string"
+	}
+
+	// Otherwise follow SQLite's type affinity rules.
+	switch {
+	case strings.Contains(t, "int"):
+		return "int64"
+	case strings.Contains(t, "char"), strings.Contains(t, "clob"), strings.Contains(t, "text"):
+		return "string"
+	case strings.Contains(t, "blob"):
+		return "[]byte"
+	case strings.Contains(t, "real"), strings.Contains(t, "floa"), strings.Contains(t, "doub"):
+		return "float64"
+	}
+	return "any"
+}
+
+// castType returns the type Attribute.Coerce casts values to, or "" when
+// values should be left uncast.
+func castType(engine, sqlType string) string {
+	var t string
+	switch engine {
+	case "mysql":
+		t = mysqlCastType(sqlType)
+	case "postgres":
+		t = strings.ToLower(strings.TrimSpace(sqlType))
+		switch t {
+		case "serial":
+			t = "integer"
+		case "smallserial":
+			t = "smallint"
+		case "bigserial":
+			t = "bigint"
+		case "array":
+			t = ""
+		}
+	default:
+		t = strings.ToLower(strings.TrimSpace(sqlType))
+	}
+	for _, r := range t {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" (),_", r) {
+			return ""
+		}
+	}
+	return t
+}
+
+// mysqlCastType maps a MySQL column type to one of the few types MySQL's
+// CAST accepts.
+func mysqlCastType(sqlType string) string {
+	raw := strings.ToLower(strings.TrimSpace(sqlType))
+	switch t := baseType(sqlType); t {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "year", "bool", "boolean":
+		if strings.Contains(raw, "unsigned") {
+			return "unsigned"
+		}
+		return "signed"
+	case "decimal", "numeric":
+		return strings.Replace(raw, "numeric", "decimal", 1)
+	case "float", "double", "real":
+		return "double"
+	case "date", "time", "json":
+		return t
+	case "datetime", "timestamp":
+		return "datetime"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "binary"
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return "char"
+	}
+	return ""
+}
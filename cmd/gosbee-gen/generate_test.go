@@ -0,0 +1,207 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/schema"
+)
+
+const wantUsers = `// Code generated by gosbee-gen. DO NOT EDIT.
+
+package models
+
+import (
+	"time"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// UsersTable is the "users" table with an attribute per column.
+type UsersTable struct {
+	Table *nodes.Table
+
+	ID        *nodes.Attribute
+	Email     *nodes.Attribute
+	CreatedAt *nodes.Attribute
+	Bio       *nodes.Attribute
+}
+
+// Users is the "users" table.
+var Users = newUsersTable()
+
+func newUsersTable() UsersTable {
+	t := nodes.NewTable("users")
+	return UsersTable{
+		Table:     t,
+		ID:        t.Col("id").Typed("integer"),
+		Email:     t.Col("email").Typed("character varying"),
+		CreatedAt: t.Col("created_at").Typed("timestamp with time zone"),
+		Bio:       t.Col("bio").Typed("text"),
+	}
+}
+
+// Columns returns the attributes in column order.
+func (t UsersTable) Columns() []nodes.Node {
+	return []nodes.Node{t.ID, t.Email, t.CreatedAt, t.Bio}
+}
+
+// UsersRow is a row of "users".
+type UsersRow struct {
+	ID        int64     ` + "`db:\"id,pk,omitempty\"`" + `
+	Email     string    ` + "`db:\"email\"`" + `
+	CreatedAt time.Time ` + "`db:\"created_at\"`" + `
+	Bio       *string   ` + "`db:\"bio\"`" + `
+}
+`
+
+func TestGenerateTable(t *testing.T) {
+	t.Parallel()
+	s := schema.New(&schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			{Name: "id", Type: "integer"},
+			{Name: "email", Type: "character varying"},
+			{Name: "created_at", Type: "timestamp with time zone"},
+			{Name: "bio", Type: "text", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	})
+	files, err := generate(s, genOptions{Package: "models", Engine: "postgres"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(files), 1)
+	testutil.AssertEqual(t, string(files["users.gen.go"]), wantUsers)
+}
+
+func TestGenerateCompositeKeyAndReservedNames(t *testing.T) {
+	t.Parallel()
+	s := schema.New(&schema.Table{
+		Name: "post_tags",
+		Columns: []*schema.Column{
+			{Name: "post_id", Type: "INTEGER"},
+			{Name: "table", Type: "TEXT"},
+			{Name: "Table", Type: "TEXT", Nullable: true},
+		},
+		PrimaryKey: []string{"post_id", "table"},
+	})
+	files, err := generate(s, genOptions{Package: "models", Engine: "sqlite"})
+	testutil.AssertNoError(t, err)
+	src := strings.Join(strings.Fields(string(files["post_tags.gen.go"])), " ")
+	for _, want := range []string{
+		"type PostTagsTable struct",
+		"var PostTags = newPostTagsTable()",
+		"PostID int64 `db:\"post_id,pk\"`",
+		"TableCol string `db:\"table,pk\"`",
+		"TableCol2 *string `db:\"Table\"`",
+		`TableCol: t.Col("table").Typed("text"),`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %q in:\n%s", want, files["post_tags.gen.go"])
+		}
+	}
+	if strings.Contains(src, `"time"`) {
+		t.Error("unexpected time import")
+	}
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	t.Parallel()
+	s := schema.New(
+		&schema.Table{Name: "user_roles"},
+		&schema.Table{Name: "userRoles"},
+	)
+	_, err := generate(s, genOptions{Package: "models", Engine: "sqlite"})
+	testutil.AssertError(t, err)
+}
+
+func TestExportedName(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"users":       "Users",
+		"user_id":     "UserID",
+		"api_key":     "APIKey",
+		"createdAt":   "CreatedAt",
+		"order-items": "OrderItems",
+		"2fa_codes":   "X2faCodes",
+		"":            "X",
+	}
+	for in, want := range cases {
+		testutil.AssertEqual(t, exportedName(in), want)
+	}
+}
+
+func TestGoType(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"integer":                  "int64",
+		"BIGINT UNSIGNED":          "int64",
+		"tinyint(1)":               "bool",
+		"varchar(255)":             "string",
+		"numeric(10,2)":            "string",
+		"double precision":         "float64",
+		"timestamp with time zone": "time.Time",
+		"bytea":                    "[]byte",
+		"time":                     "string",
+		"interval":                 "string",
+		"NATIVE CHARACTER(70)":     "string",
+		"":                         "any",
+		"ARRAY":                    "any",
+	}
+	for in, want := range cases {
+		testutil.AssertEqual(t, goType(in), want)
+	}
+}
+
+func TestCastType(t *testing.T) {
+	t.Parallel()
+	cases := []struct{ engine, in, want string }{
+		{"postgres", "bigserial", "bigint"},
+		{"postgres", "character varying", "character varying"},
+		{"postgres", "ARRAY", ""},
+		{"mysql", "int(11) unsigned", "unsigned"},
+		{"mysql", "varchar(64)", "char"},
+		{"mysql", "decimal(10,2)", "decimal(10,2)"},
+		{"mysql", "enum('a','b')", "char"},
+		{"mysql", "geometry", ""},
+		{"sqlite", "VARCHAR(20)", "varchar(20)"},
+		{"sqlite", "", ""},
+	}
+	for _, c := range cases {
+		testutil.AssertEqual(t, castType(c.engine, c.in), c.want)
+	}
+}
+
+func TestRunSchemaFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	ddl := filepath.Join(dir, "schema.sql")
+	testutil.AssertNoError(t, os.WriteFile(ddl, []byte(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL);
+CREATE TABLE ab_test (id INTEGER PRIMARY KEY);
+`), 0o644))
+	out := filepath.Join(dir, "models")
+
+	testutil.AssertNoError(t, run([]string{"-schema", ddl, "-out", out, "-engine", "sqlite", "-tables", "users, ab_test"}))
+	for _, name := range []string{"users.gen.go", "ab_test.gen.go"} {
+		src, err := os.ReadFile(filepath.Join(out, name))
+		testutil.AssertNoError(t, err)
+		if !strings.Contains(string(src), "package models") {
+			t.Errorf("%s: expected package models, got:\n%s", name, src)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	ddl := filepath.Join(dir, "schema.sql")
+	testutil.AssertNoError(t, os.WriteFile(ddl, []byte(`CREATE TABLE users (id INTEGER);`), 0o644))
+
+	testutil.AssertError(t, run([]string{"-dsn", "", "-schema", ""}))
+	testutil.AssertError(t, run([]string{"-schema", ddl, "-out", dir, "-tables", "missing"}))
+	testutil.AssertError(t, run([]string{"-schema", ddl, "-out", dir, "-engine", "oracle"}))
+	testutil.AssertError(t, run([]string{"-schema", filepath.Join(dir, "nope.sql")}))
+}
@@ -0,0 +1,165 @@
+// gosbee-gen generates typed table definitions from a database schema.
+//
+// For every table and view it writes a Go file declaring a variable that
+// holds the *nodes.Table and one *nodes.Attribute per column, with
+// TypeName set for Attribute.Coerce, plus a row struct tagged for
+// exec.Query and InsertManager.FromStructs. A misspelt column then fails
+// at compile time instead of at query time.
+//
+// Usage:
+//
+//	gosbee-gen -engine postgres -dsn "$DATABASE_URL" -out ./models
+//	gosbee-gen -schema schema.sql -out ./models
+//
+// A schema file is executed in an in-memory SQLite database and read back
+// from there, so it must be DDL that SQLite accepts. -engine then selects
+// the dialect of the generated cast types (sqlite by default).
+//
+// Configuration (env vars):
+//
+//	GOSBEE_ENGINE=postgres|mysql|sqlite  (default for -engine)
+//	DATABASE_URL=<dsn>                    (default for -dsn)
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bawdo/gosbee/schema"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+var driverName = map[string]string{
+	"postgres": "pgx",
+	"mysql":    "mysql",
+	"sqlite":   "sqlite",
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "gosbee-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("gosbee-gen", flag.ContinueOnError)
+	engine := fs.String("engine", os.Getenv("GOSBEE_ENGINE"), "database engine: postgres, mysql or sqlite")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_URL"), "connection string of the database to read")
+	schemaFile := fs.String("schema", "", "SQL schema file to read instead of a database")
+	out := fs.String("out", ".", "output directory")
+	pkg := fs.String("pkg", "", "package name (default: the output directory name)")
+	tables := fs.String("tables", "", "comma-separated tables to generate (default: all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var (
+		s   *schema.Schema
+		err error
+	)
+	switch {
+	case *schemaFile != "":
+		if *engine == "" {
+			*engine = "sqlite"
+		}
+		s, err = loadFile(ctx, *schemaFile)
+	case *dsn != "":
+		s, err = loadDatabase(ctx, *engine, *dsn)
+	default:
+		return errors.New("either -dsn or -schema is required")
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := driverName[*engine]; !ok {
+		return fmt.Errorf("unsupported engine %q", *engine)
+	}
+
+	if *pkg == "" {
+		abs, err := filepath.Abs(*out)
+		if err != nil {
+			return err
+		}
+		*pkg = packageName(filepath.Base(abs))
+	}
+	if *tables != "" {
+		if s, err = filterTables(s, strings.Split(*tables, ",")); err != nil {
+			return err
+		}
+	}
+
+	files, err := generate(s, genOptions{Package: *pkg, Engine: *engine})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if err := os.WriteFile(filepath.Join(*out, name), files[name], 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("gosbee-gen: wrote %d files to %s\n", len(files), *out)
+	return nil
+}
+
+// loadDatabase introspects the database at dsn.
+func loadDatabase(ctx context.Context, engine, dsn string) (*schema.Schema, error) {
+	driver, ok := driverName[engine]
+	if !ok {
+		return nil, fmt.Errorf("unsupported engine %q (set -engine or GOSBEE_ENGINE)", engine)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	return schema.Load(ctx, db, engine)
+}
+
+// loadFile executes the DDL in path in an in-memory SQLite database and
+// introspects the result.
+func loadFile(ctx context.Context, path string) (*schema.Schema, error) {
+	ddl, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, string(ddl)); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schema.Load(ctx, db, "sqlite")
+}
+
+// filterTables keeps the named tables of s.
+func filterTables(s *schema.Schema, names []string) (*schema.Schema, error) {
+	var keep []*schema.Table
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		t := s.Table(name)
+		if t == nil {
+			return nil, fmt.Errorf("no table %q in the schema", name)
+		}
+		keep = append(keep, t)
+	}
+	return schema.New(keep...), nil
+}
@@ -25,6 +25,9 @@ func (s *Session) initCommands() {
 		{prefix: "ast", handler: func(_ string) error { return s.cmdAST() }},
 		{prefix: "dot ", handler: func(a string) error { return s.cmdDot(a) }},
 		{prefix: "dot", handler: func(_ string) error { return fmt.Errorf("usage: dot <filepath>") }},
+		{prefix: "mermaid ", handler: func(a string) error { return s.cmdMermaid(a) }},
+		{prefix: "mermaid", handler: func(_ string) error { return fmt.Errorf("usage: mermaid <filepath>") }},
+		{prefix: "tree", handler: func(_ string) error { return s.cmdTree() }},
 		{prefix: "reset", handler: func(_ string) error { return s.cmdReset() }},
 		{prefix: "tables", handler: func(_ string) error { return s.cmdTables() }},
 		{prefix: "help", handler: func(_ string) error { s.cmdHelp(); return nil }},
@@ -97,12 +100,33 @@ func (s *Session) initCommands() {
 		{prefix: "update ", handler: func(a string) error { return s.cmdUpdate(a) }, completer: completeTableArgs},
 		{prefix: "set ", handler: func(a string) error { return s.cmdSet(a) }, completer: completeColumnArgs},
 
+		// --- DDL builders ---
+		{prefix: "create table ", handler: func(a string) error { return s.cmdCreateTable(a) }},
+		{prefix: "create unique index ", handler: func(a string) error { return s.cmdCreateIndex(a, true) }},
+		{prefix: "create index ", handler: func(a string) error { return s.cmdCreateIndex(a, false) }},
+		{prefix: "create or replace view ", handler: func(a string) error { return s.cmdCreateView(a, true, false) }},
+		{prefix: "create materialized view ", handler: func(a string) error { return s.cmdCreateView(a, false, true) }},
+		{prefix: "create view ", handler: func(a string) error { return s.cmdCreateView(a, false, false) }},
+		{prefix: "refresh materialized view ", handler: func(a string) error { return s.cmdRefresh(a) }},
+		{prefix: "alter table ", handler: func(a string) error { return s.cmdAlterTable(a) }, completer: completeTableArgs},
+		{prefix: "column ", handler: func(a string) error { return s.cmdColumn(a) }},
+		{prefix: "primary key ", handler: func(a string) error { return s.cmdPrimaryKey(a) }},
+		{prefix: "add column ", handler: func(a string) error { return s.cmdAddColumn(a) }},
+		{prefix: "drop column ", handler: func(a string) error { return s.cmdDropColumn(a) }},
+		{prefix: "rename column ", handler: func(a string) error { return s.cmdRenameColumn(a) }},
+		{prefix: "drop table ", handler: func(a string) error { return s.cmdDrop(a, nodes.DropTable) }, completer: completeTableArgs},
+		{prefix: "drop index ", handler: func(a string) error { return s.cmdDrop(a, nodes.DropIndex) }},
+
 		// --- database connectivity ---
 		{prefix: "connect ", handler: func(a string) error { return s.cmdConnect(a) }},
 		{prefix: "connect", handler: func(_ string) error { return s.cmdConnect("") }},
 		{prefix: "disconnect", handler: func(_ string) error { return s.cmdDisconnect() }},
 		{prefix: "exec", handler: func(_ string) error { return s.cmdExec() }},
 		{prefix: "run", handler: func(_ string) error { return s.cmdExec() }},
+		{prefix: "explain analyze", handler: func(_ string) error { return s.cmdExplain(true) }},
+		{prefix: "explain", handler: func(_ string) error { return s.cmdExplain(false) }},
+		{prefix: "check", handler: func(_ string) error { return s.cmdCheck() }},
+		{prefix: "lint", handler: func(_ string) error { return s.cmdLint() }},
 
 		// --- expression evaluation ---
 		{prefix: "expr ", handler: func(a string) error { return s.cmdExpr(a) }, completer: completeColumnArgs},
@@ -152,18 +152,14 @@ func (c *replCompleter) completeColumnRef(prefix string) []string {
 		if colPrefix == "" || colPrefix == "*" {
 			candidates := []string{tableName + ".*"}
 			if c.sess.conn != nil {
-				for _, col := range c.sess.conn.schemaColumns(tableName) {
-					candidates = append(candidates, tableName+"."+col)
-				}
+				candidates = append(candidates, c.sess.conn.schemaCompletions(tableName+".")...)
 			}
 			return filterPrefix(candidates, prefix)
 		}
 
 		var candidates []string
 		if c.sess.conn != nil {
-			for _, col := range c.sess.conn.schemaColumns(tableName) {
-				candidates = append(candidates, tableName+"."+col)
-			}
+			candidates = c.sess.conn.schemaCompletions(prefix)
 		}
 		// Always include the star option.
 		candidates = append(candidates, tableName+".*")
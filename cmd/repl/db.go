@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
 	"os"
 	"strings"
 
+	"github.com/bawdo/gosbee/schema"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "modernc.org/sqlite"
@@ -20,16 +22,11 @@ var driverName = map[string]string{
 
 const maxRows = 1000
 
-type schemaCache struct {
-	tables  []string
-	columns map[string][]string // table name -> column names
-}
-
 type dbConn struct {
 	db     *sql.DB
 	dsn    string
 	engine string
-	schema schemaCache
+	schema *schema.Schema
 }
 
 func connect(engine, dsn string) (*dbConn, error) {
@@ -49,7 +46,6 @@ func connect(engine, dsn string) (*dbConn, error) {
 	}
 
 	conn := &dbConn{db: db, dsn: dsn, engine: engine}
-	conn.schema.columns = make(map[string][]string)
 	if err := conn.loadSchema(); err != nil {
 		// Non-fatal: schema introspection is best-effort for autocomplete.
 		fmt.Fprintf(os.Stderr, "  Note: schema introspection failed: %v\n", err)
@@ -70,6 +66,14 @@ func (c *dbConn) execQuery(sqlStr string, params []any) (string, error) {
 	return formatRows(rows)
 }
 
+// execStatement runs a statement that returns no rows, such as DDL.
+func (c *dbConn) execStatement(sqlStr string) error {
+	if _, err := c.db.Exec(sqlStr); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+	return nil
+}
+
 func formatRows(rows *sql.Rows) (string, error) {
 	columns, err := rows.Columns()
 	if err != nil {
@@ -180,72 +184,37 @@ func buildSeparator(widths []int) string {
 	return b.String()
 }
 
+// loadSchema (re)introspects the connected database for autocompletion
+// and the OPA column resolver.
 func (c *dbConn) loadSchema() error {
-	var query string
-	switch c.engine {
-	case "postgres":
-		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = 'public' ORDER BY table_name"
-	case "mysql":
-		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() ORDER BY table_name"
-	case "sqlite":
-		query = "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
-	default:
-		return fmt.Errorf("unsupported engine: %s", c.engine)
-	}
-	tables, err := c.queryStringColumn(query)
+	sch, err := schema.Load(context.Background(), c.db, c.engine)
 	if err != nil {
 		return err
 	}
-	c.schema.tables = tables
+	c.schema = sch
 	return nil
 }
 
 func (c *dbConn) schemaTables() []string {
-	return c.schema.tables
+	if c.schema == nil {
+		return nil
+	}
+	return c.schema.TableNames()
 }
 
 func (c *dbConn) schemaColumns(table string) []string {
-	if cols, ok := c.schema.columns[table]; ok {
-		return cols
-	}
-	var query string
-	var param any
-	switch c.engine {
-	case "postgres":
-		query = "SELECT column_name FROM information_schema.columns WHERE table_schema = 'public' AND table_name = $1 ORDER BY ordinal_position"
-		param = table
-	case "mysql":
-		query = "SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position"
-		param = table
-	case "sqlite":
-		query = "SELECT name FROM pragma_table_info(?)"
-		param = table
-	default:
+	if c.schema == nil {
 		return nil
 	}
-	cols, err := c.queryStringColumn(query, param)
-	if err != nil {
-		return nil
-	}
-	c.schema.columns[table] = cols
-	return cols
+	return c.schema.ColumnNames(table)
 }
 
-func (c *dbConn) queryStringColumn(query string, params ...any) ([]string, error) {
-	rows, err := c.db.Query(query, params...)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = rows.Close() }()
-	var result []string
-	for rows.Next() {
-		var s string
-		if err := rows.Scan(&s); err != nil {
-			return nil, err
-		}
-		result = append(result, s)
+// schemaCompletions returns "table.column" completions for prefix.
+func (c *dbConn) schemaCompletions(prefix string) []string {
+	if c.schema == nil {
+		return nil
 	}
-	return result, rows.Err()
+	return c.schema.Complete(prefix)
 }
 
 func sanitizeDSN(dsn string) string {
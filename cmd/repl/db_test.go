@@ -333,3 +333,165 @@ func TestExecUnionQueryParameterised(t *testing.T) {
 		t.Errorf("expected UNION ALL in exec output, got: %s", out)
 	}
 }
+
+func TestExecDDLCreatesTable(t *testing.T) {
+	sess := NewSession("sqlite", nil)
+	if err := sess.Execute("connect :memory:"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = sess.conn.close() }()
+
+	for _, cmd := range []string{
+		"create table widgets",
+		"column id integer primary key",
+		"column name text not null default 'unnamed'",
+	} {
+		if err := sess.Execute(cmd); err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+	}
+	out, err := sess.Exec("exec")
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if !strings.Contains(out, "OK") {
+		t.Errorf("expected OK in exec output, got: %s", out)
+	}
+
+	_, err = sess.conn.db.Exec(`INSERT INTO widgets (id) VALUES (1)`)
+	if err != nil {
+		t.Fatalf("insert into created table: %v", err)
+	}
+	result, err := sess.conn.execQuery("SELECT name FROM widgets", nil)
+	if err != nil {
+		t.Fatalf("execQuery: %v", err)
+	}
+	if !strings.Contains(result, "unnamed") {
+		t.Errorf("expected default value, got:\n%s", result)
+	}
+}
+
+func TestExplainPrintsPlan(t *testing.T) {
+	sess := NewSession("sqlite", nil)
+	if err := sess.Execute("connect :memory:"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = sess.conn.close() }()
+
+	if _, err := sess.conn.db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);
+		CREATE INDEX users_email ON users (email)`); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	for _, cmd := range []string{"table users", "from users", "select users.id", "where users.email = 'a@example.com'"} {
+		if err := sess.Execute(cmd); err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+	}
+	out, err := sess.Exec("explain")
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if !strings.Contains(out, "EXPLAIN QUERY PLAN SELECT") {
+		t.Errorf("expected EXPLAIN statement in output, got: %s", out)
+	}
+	if !strings.Contains(out, "SEARCH on users using users_email") {
+		t.Errorf("expected plan tree in output, got: %s", out)
+	}
+
+	if _, err := sess.Exec("explain analyze"); err == nil {
+		t.Error("expected error for EXPLAIN ANALYZE on sqlite")
+	}
+}
+
+func TestExplainRequiresConnection(t *testing.T) {
+	sess := NewSession("postgres", nil)
+	if _, err := sess.Exec("explain"); err == nil {
+		t.Error("expected error when not connected")
+	}
+}
+
+func TestExecDDLRefreshesSchema(t *testing.T) {
+	sess := NewSession("sqlite", nil)
+	if err := sess.Execute("connect :memory:"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = sess.conn.close() }()
+
+	for _, cmd := range []string{"create table gadgets", "column id integer primary key", "column label text", "exec"} {
+		if err := sess.Execute(cmd); err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+	}
+	got := sess.conn.schemaCompletions("gadgets.")
+	if len(got) != 2 || got[0] != "gadgets.id" || got[1] != "gadgets.label" {
+		t.Errorf("expected completions for the new table, got %v", got)
+	}
+}
+
+// connectWithUsers connects to an in-memory SQLite database holding a
+// users table and loads its schema.
+func connectWithUsers(t *testing.T) *Session {
+	t.Helper()
+	sess := NewSession("sqlite", nil)
+	if err := sess.Execute("connect :memory:"); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { _ = sess.conn.close() })
+	if _, err := sess.conn.db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT)`); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if err := sess.conn.loadSchema(); err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	return sess
+}
+
+func TestCheckReportsProblems(t *testing.T) {
+	sess := connectWithUsers(t)
+	for _, cmd := range []string{"table users", "from users", "select users.emial", "where users.id = 1"} {
+		if err := sess.Execute(cmd); err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+	}
+	out, err := sess.Exec("check")
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !strings.Contains(out, `unknown column: column "users"."emial" does not exist`) {
+		t.Errorf("expected unknown column problem, got: %s", out)
+	}
+
+	if err := sess.Execute("select users.email"); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	out, err = sess.Exec("check")
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !strings.Contains(out, "No problems found") {
+		t.Errorf("expected no problems, got: %s", out)
+	}
+}
+
+func TestValidatePluginRejectsQuery(t *testing.T) {
+	sess := connectWithUsers(t)
+	if _, err := sess.Exec("plugin validate"); err != nil {
+		t.Fatalf("plugin validate: %v", err)
+	}
+	for _, cmd := range []string{"table users", "from users", "select users.emial"} {
+		if err := sess.Execute(cmd); err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+	}
+	_, err := sess.Exec("exec")
+	if err == nil || !strings.Contains(err.Error(), "emial") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestValidatePluginRequiresConnection(t *testing.T) {
+	sess := NewSession("sqlite", nil)
+	if err := sess.Execute("plugin validate"); err == nil {
+		t.Error("expected error without a connection")
+	}
+}
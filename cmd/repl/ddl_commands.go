@@ -0,0 +1,589 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+)
+
+// ddlBuilder is implemented by every DDL manager.
+type ddlBuilder interface {
+	ToSQL(v nodes.Visitor) (string, []any, error)
+}
+
+// --- DDL command handlers ---
+
+func (s *Session) cmdCreateTable(args string) error {
+	tokens := strings.Fields(args)
+	ifNotExists := false
+	if len(tokens) > 3 && strings.EqualFold(strings.Join(tokens[:3], " "), "if not exists") {
+		ifNotExists = true
+		tokens = tokens[3:]
+	}
+	if len(tokens) != 1 {
+		return errors.New("usage: create table [if not exists] <name>")
+	}
+	m := managers.NewCreateTableManager(s.ensureTable(tokens[0]))
+	if ifNotExists {
+		m.IfNotExists()
+	}
+	s.setMode(modeDDL)
+	s.ddlQuery = m
+	_, _ = fmt.Fprintf(s.out, "  CREATE TABLE %q\n", tokens[0])
+	return nil
+}
+
+func (s *Session) cmdColumn(args string) error {
+	m, ok := s.ddlQuery.(*managers.CreateTableManager)
+	if !ok {
+		return errors.New("column command requires an active CREATE TABLE (use 'create table <name>' first)")
+	}
+	name, typ, opts, err := parseColumnDef(args)
+	if err != nil {
+		return err
+	}
+	m.Column(name, typ, opts...)
+	_, _ = fmt.Fprintf(s.out, "  Column %q added\n", name)
+	return nil
+}
+
+func (s *Session) cmdPrimaryKey(args string) error {
+	m, ok := s.ddlQuery.(*managers.CreateTableManager)
+	if !ok {
+		return errors.New("primary key command requires an active CREATE TABLE (use 'create table <name>' first)")
+	}
+	cols, err := parseNameList(args)
+	if err != nil {
+		return fmt.Errorf("primary key: %w", err)
+	}
+	m.PrimaryKey(cols...)
+	_, _ = fmt.Fprintf(s.out, "  PRIMARY KEY set (%d columns)\n", len(cols))
+	return nil
+}
+
+func (s *Session) cmdAlterTable(args string) error {
+	name := strings.TrimSpace(args)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return errors.New("usage: alter table <name>")
+	}
+	s.setMode(modeDDL)
+	s.ddlQuery = managers.NewAlterTableManager(s.ensureTable(name))
+	_, _ = fmt.Fprintf(s.out, "  ALTER TABLE %q\n", name)
+	return nil
+}
+
+func (s *Session) alterQuery(cmd string) (*managers.AlterTableManager, error) {
+	m, ok := s.ddlQuery.(*managers.AlterTableManager)
+	if !ok {
+		return nil, fmt.Errorf("%s command requires an active ALTER TABLE (use 'alter table <name>' first)", cmd)
+	}
+	return m, nil
+}
+
+func (s *Session) cmdAddColumn(args string) error {
+	m, err := s.alterQuery("add column")
+	if err != nil {
+		return err
+	}
+	name, typ, opts, err := parseColumnDef(args)
+	if err != nil {
+		return err
+	}
+	m.AddColumn(name, typ, opts...)
+	_, _ = fmt.Fprintf(s.out, "  ADD COLUMN %q\n", name)
+	return nil
+}
+
+func (s *Session) cmdDropColumn(args string) error {
+	m, err := s.alterQuery("drop column")
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(args)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return errors.New("usage: drop column <name>")
+	}
+	m.DropColumn(name)
+	_, _ = fmt.Fprintf(s.out, "  DROP COLUMN %q\n", name)
+	return nil
+}
+
+func (s *Session) cmdRenameColumn(args string) error {
+	m, err := s.alterQuery("rename column")
+	if err != nil {
+		return err
+	}
+	parts := strings.Fields(args)
+	if len(parts) != 3 || !strings.EqualFold(parts[1], "to") {
+		return errors.New("usage: rename column <old> to <new>")
+	}
+	m.RenameColumn(parts[0], parts[2])
+	_, _ = fmt.Fprintf(s.out, "  RENAME COLUMN %q TO %q\n", parts[0], parts[2])
+	return nil
+}
+
+// cmdCreateIndex handles "create [unique] index [concurrently] [if not exists]
+// <name> on <table> (<cols>)". unique is passed by the registry entry.
+func (s *Session) cmdCreateIndex(args string, unique bool) error {
+	const usage = "usage: create [unique] index [concurrently] [if not exists] <name> on <table> (<cols>)"
+	rest := strings.TrimSpace(args)
+	lower := strings.ToLower(rest)
+	concurrently := strings.HasPrefix(lower, "concurrently ")
+	if concurrently {
+		rest = strings.TrimSpace(rest[len("concurrently "):])
+		lower = strings.ToLower(rest)
+	}
+	ifNotExists := strings.HasPrefix(lower, "if not exists ")
+	if ifNotExists {
+		rest = strings.TrimSpace(rest[len("if not exists "):])
+	}
+
+	open := strings.Index(rest, "(")
+	if open < 0 || !strings.HasSuffix(rest, ")") {
+		return errors.New(usage)
+	}
+	head := strings.Fields(rest[:open])
+	if len(head) != 3 || !strings.EqualFold(head[1], "on") {
+		return errors.New(usage)
+	}
+	name, tableName := head[0], head[2]
+	table := s.ensureTable(tableName)
+
+	var cols []nodes.Node
+	for _, p := range splitTopLevelCommas(rest[open+1 : len(rest)-1]) {
+		col, err := s.parseIndexElement(table, strings.TrimSpace(p))
+		if err != nil {
+			return fmt.Errorf("create index: %w", err)
+		}
+		cols = append(cols, col)
+	}
+	if len(cols) == 0 {
+		return errors.New(usage)
+	}
+
+	m := managers.NewCreateIndexManager(name, table).On(cols...)
+	if unique {
+		m.Unique()
+	}
+	if concurrently {
+		m.Concurrently()
+	}
+	if ifNotExists {
+		m.IfNotExists()
+	}
+	s.setMode(modeDDL)
+	s.ddlQuery = m
+	_, _ = fmt.Fprintf(s.out, "  CREATE INDEX %q ON %q (%d columns)\n", name, tableName, len(cols))
+	return nil
+}
+
+// parseIndexElement parses one index key: a bare column of table, a
+// table.column reference, or an expression, optionally followed by asc/desc.
+func (s *Session) parseIndexElement(table *nodes.Table, part string) (nodes.Node, error) {
+	tokens := tokenize(part)
+	if len(tokens) == 0 {
+		return nil, errors.New("empty index column")
+	}
+	dir := ""
+	if last := strings.ToLower(tokens[len(tokens)-1]); last == "asc" || last == "desc" {
+		dir = last
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	var expr nodes.Node
+	if len(tokens) == 1 && isIdentifier(tokens[0]) && !strings.Contains(tokens[0], ".") {
+		expr = table.Col(tokens[0])
+	} else {
+		n, end, err := s.parseArithExpr(tokens, 0)
+		if err != nil {
+			return nil, err
+		}
+		if end != len(tokens) {
+			return nil, fmt.Errorf("unexpected token %q in index column", tokens[end])
+		}
+		expr = n
+	}
+
+	switch dir {
+	case "asc":
+		return &nodes.OrderingNode{Expr: expr, Direction: nodes.Asc}, nil
+	case "desc":
+		return &nodes.OrderingNode{Expr: expr, Direction: nodes.Desc}, nil
+	}
+	return expr, nil
+}
+
+// cmdCreateView handles "create [or replace] [materialized] view <name>
+// [(<cols>)]", snapshotting the current SELECT query and its plugins.
+func (s *Session) cmdCreateView(args string, orReplace, materialized bool) error {
+	if s.mode != modeSelect || s.query == nil {
+		return errors.New("create view requires a SELECT query (use 'from <table>' first)")
+	}
+	if len(s.setOps) > 0 {
+		return errors.New("create view does not support set operations")
+	}
+	name, cols, err := parseNameWithColumns(args)
+	if err != nil {
+		return errors.New("usage: create [or replace] [materialized] view <name> [(<cols>)]")
+	}
+
+	s.attachCTEs()
+	snapshot := managers.NewSelectManager(s.query.Core.From)
+	snapshot.Core = s.query.CloneCore()
+	s.cleanupCTEs()
+	s.plugins.applyTo(func(t plugins.Transformer) { snapshot.Use(t) })
+
+	m := managers.NewCreateViewManager(name, snapshot).Columns(cols...)
+	if orReplace {
+		m.OrReplace()
+	}
+	if materialized {
+		m.Materialized()
+	}
+	s.setMode(modeDDL)
+	s.ddlQuery = m
+	_, _ = fmt.Fprintf(s.out, "  CREATE VIEW %q from current query\n", name)
+	return nil
+}
+
+// cmdRefresh handles "refresh materialized view [concurrently] <name>".
+func (s *Session) cmdRefresh(args string) error {
+	tokens := strings.Fields(args)
+	concurrently := len(tokens) == 2 && strings.EqualFold(tokens[0], "concurrently")
+	if concurrently {
+		tokens = tokens[1:]
+	}
+	if len(tokens) != 1 {
+		return errors.New("usage: refresh materialized view [concurrently] <name>")
+	}
+	m := managers.NewRefreshMaterializedViewManager(tokens[0])
+	if concurrently {
+		m.Concurrently()
+	}
+	s.setMode(modeDDL)
+	s.ddlQuery = m
+	_, _ = fmt.Fprintf(s.out, "  REFRESH MATERIALIZED VIEW %q\n", tokens[0])
+	return nil
+}
+
+// cmdDrop handles "drop table [if exists] <name> [cascade]" and
+// "drop index [concurrently] [if exists] <name> [on <table>] [cascade]".
+func (s *Session) cmdDrop(args string, kind nodes.DropKind) error {
+	tokens := strings.Fields(args)
+	concurrently, ifExists, cascade := false, false, false
+	if kind == nodes.DropIndex && len(tokens) > 0 && strings.EqualFold(tokens[0], "concurrently") {
+		concurrently = true
+		tokens = tokens[1:]
+	}
+	if len(tokens) > 2 && strings.EqualFold(tokens[0], "if") && strings.EqualFold(tokens[1], "exists") {
+		ifExists = true
+		tokens = tokens[2:]
+	}
+	if len(tokens) > 1 && strings.EqualFold(tokens[len(tokens)-1], "cascade") {
+		cascade = true
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	var m *managers.DropManager
+	switch {
+	case kind == nodes.DropTable && len(tokens) == 1:
+		m = managers.NewDropTableManager(s.ensureTable(tokens[0]))
+	case kind == nodes.DropIndex && len(tokens) == 1:
+		m = managers.NewDropIndexManager(tokens[0])
+	case kind == nodes.DropIndex && len(tokens) == 3 && strings.EqualFold(tokens[1], "on"):
+		m = managers.NewDropIndexManager(tokens[0]).On(s.ensureTable(tokens[2]))
+	case kind == nodes.DropTable:
+		return errors.New("usage: drop table [if exists] <name> [cascade]")
+	default:
+		return errors.New("usage: drop index [concurrently] [if exists] <name> [on <table>] [cascade]")
+	}
+	if concurrently {
+		m.Concurrently()
+	}
+	if ifExists {
+		m.IfExists()
+	}
+	if cascade {
+		m.Cascade()
+	}
+	s.setMode(modeDDL)
+	s.ddlQuery = m
+	_, _ = fmt.Fprintf(s.out, "  DROP %q\n", tokens[0])
+	return nil
+}
+
+// execDDL renders the current DDL statement with v and runs it on the
+// connection. DDL returns no rows, so only the statement is echoed.
+func (s *Session) execDDL(v nodes.Visitor) error {
+	if s.ddlQuery == nil {
+		return errors.New("no DDL statement defined")
+	}
+	sqlStr, _, err := s.ddlQuery.ToSQL(v)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(s.out, "  %s;\n", sqlStr)
+	if err := s.conn.execStatement(sqlStr); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(s.out, "  OK")
+	if err := s.conn.loadSchema(); err != nil {
+		_, _ = fmt.Fprintf(s.out, "  Note: schema refresh failed: %v\n", err)
+	}
+	return nil
+}
+
+// --- DDL parsing helpers ---
+
+// columnKeywords start the constraint part of a column definition.
+var columnKeywords = []string{"not", "null", "default", "primary", "unique", "references"}
+
+// parseColumnDef parses "<name> <type> [not null] [default <value>]
+// [primary key] [unique] [references <table>(<cols>) [on delete <action>]]"
+// into the arguments of CreateTableManager.Column.
+func parseColumnDef(args string) (string, string, []nodes.ColumnOption, error) {
+	const usage = "usage: column <name> <type> [not null] [default <value>] [primary key] [unique] [references <table>(<cols>)]"
+	tokens := tokenize(strings.TrimSpace(args))
+	if len(tokens) < 2 {
+		return "", "", nil, errors.New(usage)
+	}
+	typ, pos := scanUntilKeyword(tokens, 1, columnKeywords...)
+	if len(typ) == 0 {
+		return "", "", nil, errors.New(usage)
+	}
+
+	var opts []nodes.ColumnOption
+	for pos < len(tokens) {
+		word := strings.ToLower(tokens[pos])
+		next := ""
+		if pos+1 < len(tokens) {
+			next = strings.ToLower(tokens[pos+1])
+		}
+		switch {
+		case word == "not" && next == "null":
+			opts = append(opts, nodes.NotNull())
+			pos += 2
+		case word == "null":
+			pos++
+		case word == "primary" && next == "key":
+			opts = append(opts, nodes.PrimaryKey())
+			pos += 2
+		case word == "unique":
+			opts = append(opts, nodes.Unique())
+			pos++
+		case word == "default" && pos+1 < len(tokens):
+			v, err := parseValue(tokens[pos+1])
+			if err != nil {
+				return "", "", nil, fmt.Errorf("default: %w", err)
+			}
+			opts = append(opts, nodes.Default(v))
+			pos += 2
+		case word == "references" && pos+1 < len(tokens):
+			ref, end, err := parseReferences(tokens, pos+1)
+			if err != nil {
+				return "", "", nil, err
+			}
+			opts = append(opts,
+				nodes.References(ref.Table, ref.Columns...),
+				nodes.OnDelete(ref.OnDelete),
+				nodes.OnUpdate(ref.OnUpdate))
+			pos = end
+		default:
+			return "", "", nil, fmt.Errorf("unexpected token %q in column definition", tokens[pos])
+		}
+	}
+	return tokens[0], joinTypeTokens(typ), opts, nil
+}
+
+// parseReferences parses "<table> [(<cols>)] [on delete <action>]
+// [on update <action>]" starting at pos.
+func parseReferences(tokens []string, pos int) (*nodes.ForeignKeyRef, int, error) {
+	ref := &nodes.ForeignKeyRef{Table: nodes.NewTable(tokens[pos])}
+	pos++
+	if pos < len(tokens) && tokens[pos] == "(" {
+		pos++
+		for pos < len(tokens) && tokens[pos] != ")" {
+			if tokens[pos] != "," {
+				ref.Columns = append(ref.Columns, tokens[pos])
+			}
+			pos++
+		}
+		if pos == len(tokens) {
+			return nil, pos, errors.New("missing closing parenthesis in references")
+		}
+		pos++
+	}
+	for pos+2 < len(tokens) && strings.EqualFold(tokens[pos], "on") {
+		event := strings.ToLower(tokens[pos+1])
+		action, n := referentialAction(tokens[pos+2:])
+		if n == 0 || (event != "delete" && event != "update") {
+			return nil, pos, fmt.Errorf("unexpected %q in references", strings.Join(tokens[pos:], " "))
+		}
+		if event == "delete" {
+			ref.OnDelete = action
+		} else {
+			ref.OnUpdate = action
+		}
+		pos += 2 + n
+	}
+	return ref, pos, nil
+}
+
+// referentialAction parses the action words at the start of tokens and
+// returns the action and the number of tokens consumed (0 if none).
+func referentialAction(tokens []string) (nodes.ReferentialAction, int) {
+	first := strings.ToLower(tokens[0])
+	second := ""
+	if len(tokens) > 1 {
+		second = strings.ToLower(tokens[1])
+	}
+	switch {
+	case first == "cascade":
+		return nodes.ActionCascade, 1
+	case first == "restrict":
+		return nodes.ActionRestrict, 1
+	case first == "no" && second == "action":
+		return nodes.ActionNoAction, 2
+	case first == "set" && second == "null":
+		return nodes.ActionSetNull, 2
+	case first == "set" && second == "default":
+		return nodes.ActionSetDefault, 2
+	}
+	return nodes.ActionNone, 0
+}
+
+// joinTypeTokens reassembles a type name split by tokenize, so that
+// "varchar ( 255 )" becomes "varchar(255)" and "numeric ( 10 , 2 )"
+// becomes "numeric(10, 2)".
+func joinTypeTokens(tokens []string) string {
+	var sb strings.Builder
+	for i, t := range tokens {
+		if i > 0 && t != "(" && t != ")" && t != "," && tokens[i-1] != "(" {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(t)
+	}
+	return sb.String()
+}
+
+// parseNameWithColumns parses "<name> [(<cols>)]".
+func parseNameWithColumns(args string) (string, []string, error) {
+	args = strings.TrimSpace(args)
+	name, rest := args, ""
+	if i := strings.IndexAny(args, " ("); i >= 0 {
+		name, rest = args[:i], strings.TrimSpace(args[i:])
+	}
+	if name == "" {
+		return "", nil, errors.New("missing name")
+	}
+	if rest == "" {
+		return name, nil, nil
+	}
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return "", nil, fmt.Errorf("unexpected %q", rest)
+	}
+	cols, err := parseNameList(rest)
+	return name, cols, err
+}
+
+// parseNameList parses "(a, b)" or "a, b" into column names.
+func parseNameList(args string) ([]string, error) {
+	args = strings.TrimSpace(args)
+	args = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")")
+	var names []string
+	for _, p := range strings.Split(args, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if strings.ContainsAny(p, " \t()") {
+			return nil, fmt.Errorf("invalid column name %q", p)
+		}
+		names = append(names, p)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("expected at least one column")
+	}
+	return names, nil
+}
+
+// --- DDL AST display ---
+
+func (s *Session) cmdASTDDL() error {
+	if s.ddlQuery == nil {
+		return errors.New("no DDL statement defined")
+	}
+	_, _ = fmt.Fprintf(s.out, "  Engine: %s\n", s.engine)
+	switch m := s.ddlQuery.(type) {
+	case *managers.CreateTableManager:
+		st := m.Statement
+		_, _ = fmt.Fprintf(s.out, "  Mode: CREATE TABLE\n")
+		_, _ = fmt.Fprintf(s.out, "  TABLE:  %s\n", st.Table.Name)
+		for i, c := range st.Columns {
+			_, _ = fmt.Fprintf(s.out, "  COLUMN[%d]: %s %s\n", i, c.Name, c.Type)
+		}
+		if len(st.Constraints) > 0 {
+			_, _ = fmt.Fprintf(s.out, "  CONSTRAINTS: %d\n", len(st.Constraints))
+		}
+	case *managers.AlterTableManager:
+		st := m.Statement
+		_, _ = fmt.Fprintf(s.out, "  Mode: ALTER TABLE\n")
+		_, _ = fmt.Fprintf(s.out, "  TABLE:  %s\n", st.Table.Name)
+		for i, a := range st.Actions {
+			_, _ = fmt.Fprintf(s.out, "  ACTION[%d]: %s\n", i, alterActionSummary(a))
+		}
+	case *managers.CreateIndexManager:
+		st := m.Statement
+		_, _ = fmt.Fprintf(s.out, "  Mode: CREATE INDEX\n")
+		_, _ = fmt.Fprintf(s.out, "  INDEX:  %s ON %s\n", st.Name, st.Table.Name)
+		names := make([]string, len(st.Columns))
+		for i, c := range st.Columns {
+			names[i] = nodeSummary(c)
+		}
+		_, _ = fmt.Fprintf(s.out, "  COLUMNS: %s\n", strings.Join(names, ", "))
+		if len(st.Wheres) > 0 {
+			_, _ = fmt.Fprintf(s.out, "  WHERE:  %d condition(s)\n", len(st.Wheres))
+		}
+	case *managers.CreateViewManager:
+		st := m.Statement
+		kind := "VIEW"
+		if st.Materialized {
+			kind = "MATERIALIZED VIEW"
+		}
+		_, _ = fmt.Fprintf(s.out, "  Mode: CREATE %s\n", kind)
+		_, _ = fmt.Fprintf(s.out, "  VIEW:   %s\n", st.Name)
+		if len(st.Columns) > 0 {
+			_, _ = fmt.Fprintf(s.out, "  COLUMNS: %s\n", strings.Join(st.Columns, ", "))
+		}
+		_, _ = fmt.Fprintf(s.out, "  AS:     SELECT FROM %s\n", nodeSummary(m.Query.Core.From))
+	case *managers.RefreshMaterializedViewManager:
+		_, _ = fmt.Fprintf(s.out, "  Mode: REFRESH MATERIALIZED VIEW\n")
+		_, _ = fmt.Fprintf(s.out, "  VIEW:   %s\n", m.Statement.Name)
+	case *managers.DropManager:
+		st := m.Statement
+		kind := "TABLE"
+		if st.Kind == nodes.DropIndex {
+			kind = "INDEX"
+		}
+		_, _ = fmt.Fprintf(s.out, "  Mode: DROP %s\n", kind)
+		_, _ = fmt.Fprintf(s.out, "  NAME:   %s\n", st.Name)
+	}
+	return nil
+}
+
+func alterActionSummary(a *nodes.AlterAction) string {
+	switch a.Kind {
+	case nodes.AlterAddColumn:
+		return "ADD COLUMN " + a.Column.Name
+	case nodes.AlterDropColumn:
+		return "DROP COLUMN " + a.Name
+	case nodes.AlterRenameColumn:
+		return "RENAME COLUMN " + a.Name + " TO " + a.NewName
+	default:
+		return "ADD CONSTRAINT"
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bawdo/gosbee/explain"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// explainOptions picks the EXPLAIN form whose output the explain package
+// can parse for the connected engine.
+func explainOptions(engine string, analyze bool) (nodes.ExplainOptions, error) {
+	switch engine {
+	case "mysql":
+		if analyze {
+			return nodes.ExplainOptions{Analyze: true}, nil
+		}
+		return nodes.ExplainOptions{Format: nodes.ExplainJSON}, nil
+	case "sqlite":
+		if analyze {
+			return nodes.ExplainOptions{}, errors.New("sqlite does not support EXPLAIN ANALYZE")
+		}
+		return nodes.ExplainOptions{}, nil
+	default:
+		return nodes.ExplainOptions{Analyze: analyze, Format: nodes.ExplainJSON}, nil
+	}
+}
+
+// cmdExplain runs EXPLAIN for the current query and prints the plan tree.
+func (s *Session) cmdExplain(analyze bool) error {
+	if s.conn == nil {
+		return errors.New("not connected (use 'connect <dsn>' first)")
+	}
+	opts, err := explainOptions(s.conn.engine, analyze)
+	if err != nil {
+		return err
+	}
+
+	pv := s.makeParamVisitor()
+	var sqlStr string
+	var params []any
+	switch s.mode {
+	case modeInsert:
+		if s.insertQuery == nil {
+			return errors.New("no INSERT query defined")
+		}
+		sqlStr, params, err = s.insertQuery.Explain(opts).ToSQL(pv)
+	case modeUpdate:
+		if s.updateQuery == nil {
+			return errors.New("no UPDATE query defined")
+		}
+		sqlStr, params, err = s.updateQuery.Explain(opts).ToSQL(pv)
+	case modeDelete:
+		if s.deleteQuery == nil {
+			return errors.New("no DELETE query defined")
+		}
+		sqlStr, params, err = s.deleteQuery.Explain(opts).ToSQL(pv)
+	case modeDDL:
+		return errors.New("explain is not available for DDL statements")
+	default:
+		if s.query == nil {
+			return errNoQuery
+		}
+		s.attachCTEs()
+		defer s.cleanupCTEs()
+		if finalNode := s.buildSetOperationChain(); finalNode != nil {
+			if p, ok := pv.(nodes.Parameterizer); ok {
+				p.Reset()
+			}
+			sqlStr = nodes.Explain(finalNode, opts).Accept(pv)
+			if p, ok := pv.(nodes.Parameterizer); ok {
+				params = p.Params()
+			}
+		} else {
+			sqlStr, params, err = s.query.Explain(opts).ToSQL(pv)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(s.out, "  %s;\n", sqlStr)
+	if len(params) > 0 {
+		_, _ = fmt.Fprintf(s.out, "  Params: %v\n", params)
+	}
+	plan, err := s.conn.explainPlan(sqlStr, params, analyze)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(plan.String(), "\n"), "\n") {
+		_, _ = fmt.Fprintf(s.out, "  %s\n", line)
+	}
+	return nil
+}
+
+// explainPlan runs an EXPLAIN statement and parses its output. The
+// statement runs in a transaction that is rolled back, since EXPLAIN
+// ANALYZE executes INSERT, UPDATE and DELETE statements.
+func (c *dbConn) explainPlan(sqlStr string, params []any, analyze bool) (*explain.Plan, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.Query(sqlStr, params...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if c.engine == "sqlite" {
+		var plan []explain.SQLiteRow
+		for rows.Next() {
+			var r explain.SQLiteRow
+			var notUsed int
+			if err := rows.Scan(&r.ID, &r.Parent, &notUsed, &r.Detail); err != nil {
+				return nil, fmt.Errorf("scan: %w", err)
+			}
+			plan = append(plan, r)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows: %w", err)
+		}
+		return explain.ParseSQLite(plan)
+	}
+
+	var out string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		out += line
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	switch {
+	case c.engine == "mysql" && analyze:
+		return explain.ParseMySQLTree(out)
+	case c.engine == "mysql":
+		return explain.ParseMySQL([]byte(out))
+	default:
+		return explain.ParsePostgres([]byte(out))
+	}
+}
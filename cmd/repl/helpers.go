@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -179,6 +180,7 @@ func (s *Session) setMode(mode dmlMode) {
 	s.insertQuery = nil
 	s.updateQuery = nil
 	s.deleteQuery = nil
+	s.ddlQuery = nil
 }
 
 // rebuildQueryWithPlugins rebuilds the current SELECT query from scratch,
@@ -254,3 +256,41 @@ func tableAliasSourceName(ta *nodes.TableAlias) string {
 	}
 	return "(subquery)"
 }
+
+// withStatement calls fn with the current DML statement, or the current
+// query including its CTEs and set operations. cmd names the command in
+// the error for DDL mode.
+func (s *Session) withStatement(cmd string, fn func(stmt nodes.Node) error) error {
+	var stmt nodes.Node
+	switch s.mode {
+	case modeInsert:
+		if s.insertQuery == nil {
+			return errors.New("no INSERT query defined")
+		}
+		stmt = s.insertQuery.Statement
+	case modeUpdate:
+		if s.updateQuery == nil {
+			return errors.New("no UPDATE query defined")
+		}
+		stmt = s.updateQuery.Statement
+	case modeDelete:
+		if s.deleteQuery == nil {
+			return errors.New("no DELETE query defined")
+		}
+		stmt = s.deleteQuery.Statement
+	case modeDDL:
+		return fmt.Errorf("%s is not available for DDL statements", cmd)
+	default:
+		if s.query == nil {
+			return errNoQuery
+		}
+		s.attachCTEs()
+		defer s.cleanupCTEs()
+		stmt = s.buildSetOperationChain()
+		if stmt == nil {
+			stmt = s.query.Core
+		}
+	}
+
+	return fn(stmt)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bawdo/gosbee/analysis"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// cmdLint checks the current statement with the analysis package's
+// default rules and lists the findings.
+func (s *Session) cmdLint() error {
+	return s.withStatement("lint", func(stmt nodes.Node) error {
+		findings := analysis.New().Analyze(stmt)
+		if len(findings) == 0 {
+			_, _ = fmt.Fprintln(s.out, "  No findings")
+			return nil
+		}
+		for _, f := range findings {
+			_, _ = fmt.Fprintf(s.out, "  %s\n", f)
+		}
+		return nil
+	})
+}
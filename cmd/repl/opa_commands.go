@@ -113,11 +113,10 @@ func (s *Session) columnResolver() opa.ColumnResolver {
 		if s.conn == nil {
 			return nil, fmt.Errorf("no database connection (required for column masking)")
 		}
-		cols := s.conn.schemaColumns(tableName)
-		if cols == nil {
+		if s.conn.schema == nil {
 			return nil, fmt.Errorf("no schema for table %q", tableName)
 		}
-		return cols, nil
+		return s.conn.schema.ColumnResolver()(tableName)
 	}
 }
 
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/plugins/tenant"
+)
+
+const tenantUsage = "usage: plugin tenant <id> [column <col> | <table.col, ...>] [exempt <table1> [table2 ...]]"
+
+// configureTenant parses tenant arguments, registers the plugin in the
+// registry, and rebuilds the query if one exists.
+//
+//	plugin tenant 42
+//	plugin tenant 42 column org_id
+//	plugin tenant acme accounts.org_id, users.tenant_id
+//	plugin tenant 42 exempt countries currencies
+func configureTenant(s *Session, args string) error {
+	rest := strings.TrimSpace(args)
+	var exempt []string
+	if idx := strings.Index(strings.ToLower(rest), " exempt "); idx >= 0 {
+		exempt = strings.Fields(rest[idx+8:])
+		rest = rest[:idx]
+	} else if strings.HasSuffix(strings.ToLower(rest), " exempt") {
+		return errors.New(tenantUsage)
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return errors.New(tenantUsage)
+	}
+	rawID := fields[0]
+	var id any = rawID
+	if n, err := strconv.ParseInt(rawID, 10, 64); err == nil {
+		id = n
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, rawID))
+
+	column := "tenant_id"
+	columns := map[string]string{}
+	opts := []tenant.Option{tenant.WithExemptTables(exempt...)}
+	switch {
+	case rest == "":
+	case strings.HasPrefix(strings.ToLower(rest), "column "):
+		f := strings.Fields(rest)
+		if len(f) != 2 {
+			return errors.New(tenantUsage)
+		}
+		column = f[1]
+		opts = append(opts, tenant.WithColumn(column))
+	case strings.Contains(rest, "."):
+		for _, pair := range strings.Split(rest, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			dot := strings.IndexByte(pair, '.')
+			if dot <= 0 || dot == len(pair)-1 {
+				return fmt.Errorf("invalid table.column pair: %q", pair)
+			}
+			columns[pair[:dot]] = pair[dot+1:]
+			opts = append(opts, tenant.WithTableColumn(pair[:dot], pair[dot+1:]))
+		}
+	default:
+		return errors.New(tenantUsage)
+	}
+
+	statusFn := func() string {
+		parts := []string{"id: " + rawID, "column: " + column}
+		if len(columns) > 0 {
+			pairs := make([]string, 0, len(columns))
+			for t, c := range columns {
+				pairs = append(pairs, t+"."+c)
+			}
+			sort.Strings(pairs)
+			parts = append(parts, "tables: "+strings.Join(pairs, ", "))
+		}
+		if len(exempt) > 0 {
+			parts = append(parts, "exempt: "+strings.Join(exempt, ", "))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	s.plugins.register(pluginEntry{
+		name:    "tenant",
+		factory: func() plugins.Transformer { return tenant.New(id, opts...) },
+		status:  statusFn,
+		color:   "#E67E22",
+	})
+	_, _ = fmt.Fprintf(s.out, "  Tenant scoping enabled (%s)\n", statusFn())
+
+	if s.query != nil {
+		s.rebuildQueryWithPlugins()
+	}
+	return nil
+}
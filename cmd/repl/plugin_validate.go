@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/schema"
+	"github.com/bawdo/gosbee/validate"
+)
+
+// configureValidate enables the validate plugin, which makes sql and exec
+// fail when the query does not match the connected database's schema.
+// The schema is re-read from the connection whenever a query is built, so
+// DDL run in the session is taken into account.
+func configureValidate(s *Session, _ string) error {
+	if s.conn == nil || s.conn.schema == nil {
+		return errors.New("validate needs a database schema (use 'connect <dsn>' first)")
+	}
+	sch := s.conn.schema
+	current := func() *schema.Schema {
+		if s.conn != nil && s.conn.schema != nil {
+			sch = s.conn.schema
+		}
+		return sch
+	}
+	s.plugins.register(pluginEntry{
+		name:    "validate",
+		factory: func() plugins.Transformer { return validate.New(current()) },
+		status:  func() string { return fmt.Sprintf("%d tables", len(current().Tables)) },
+		color:   "#3399CC",
+	})
+	_, _ = fmt.Fprintln(s.out, "  Validation enabled")
+
+	if s.query != nil {
+		s.rebuildQueryWithPlugins()
+	}
+	return nil
+}
+
+// cmdCheck validates the current statement against the connected
+// database's schema and lists the problems found.
+func (s *Session) cmdCheck() error {
+	if s.conn == nil || s.conn.schema == nil {
+		return errors.New("not connected (use 'connect <dsn>' first)")
+	}
+
+	return s.withStatement("check", func(stmt nodes.Node) error {
+		problems := validate.New(s.conn.schema).Check(stmt)
+		if len(problems) == 0 {
+			_, _ = fmt.Fprintln(s.out, "  No problems found")
+			return nil
+		}
+		for _, p := range problems {
+			_, _ = fmt.Fprintf(s.out, "  %s: %s\n", p.Kind, p.Message)
+		}
+		return nil
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/schema"
 )
 
 // helper executes commands then returns GenerateSQL output.
@@ -608,6 +609,64 @@ func TestPluginSoftdeletePerTableColumnsSQL(t *testing.T) {
 	testutil.AssertEqual(t, got, `SELECT * FROM "users" INNER JOIN "posts" ON "users"."id" = "posts"."user_id" WHERE "users"."deleted_at" IS NULL AND "posts"."removed_at" IS NULL`)
 }
 
+// --- Tenant plugin ---
+
+func TestPluginTenant(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("table posts")
+	_ = sess.Execute("plugin tenant 42")
+	_ = sess.Execute("from users")
+	_ = sess.Execute("join posts on users.id = posts.user_id")
+	got, _ := sess.GenerateSQL()
+	testutil.AssertEqual(t, got, `SELECT * FROM "users" INNER JOIN "posts" ON "users"."id" = "posts"."user_id" AND "posts"."tenant_id" = 42 WHERE "users"."tenant_id" = 42`)
+}
+
+func TestPluginTenantColumnAndExempt(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("table countries")
+	err := sess.Execute("plugin tenant acme column org_id exempt countries")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	_ = sess.Execute("from users")
+	_ = sess.Execute("join countries on users.country = countries.code")
+	got, _ := sess.GenerateSQL()
+	testutil.AssertEqual(t, got, `SELECT * FROM "users" INNER JOIN "countries" ON "users"."country" = "countries"."code" WHERE "users"."org_id" = 'acme'`)
+
+	entry, _ := sess.plugins.get("tenant")
+	testutil.AssertEqual(t, entry.status(), "id: acme, column: org_id, exempt: countries")
+}
+
+func TestPluginTenantPerTableColumns(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table accounts")
+	err := sess.Execute("plugin tenant 7 accounts.org_id, users.tenant_id")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	_ = sess.Execute("from accounts")
+	got, _ := sess.GenerateSQL()
+	testutil.AssertEqual(t, got, `SELECT * FROM "accounts" WHERE "accounts"."org_id" = 7`)
+
+	entry, _ := sess.plugins.get("tenant")
+	testutil.AssertEqual(t, entry.status(), "id: 7, column: tenant_id, tables: accounts.org_id, users.tenant_id")
+}
+
+func TestPluginTenantUsage(t *testing.T) {
+	t.Parallel()
+	for _, args := range []string{"", "42 column", "42 bogus", "42 exempt", "42 .org_id"} {
+		sess := NewSession("postgres", nil)
+		if err := sess.Execute("plugin tenant " + args); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
+
 // --- Table alias ---
 
 func TestTableAlias(t *testing.T) {
@@ -2398,10 +2457,10 @@ func TestOPAMaskingExpandsStar(t *testing.T) {
 
 	s := NewSession("postgres", nil)
 	_, _ = s.Exec("from consignments")
-	s.conn = &dbConn{engine: "postgres", schema: schemaCache{
-		tables:  []string{"consignments"},
-		columns: map[string][]string{"consignments": {"id", "account_name", "billed_total"}},
-	}}
+	s.conn = &dbConn{engine: "postgres", schema: schema.New(&schema.Table{
+		Name:    "consignments",
+		Columns: []*schema.Column{{Name: "id"}, {Name: "account_name"}, {Name: "billed_total"}},
+	})}
 
 	s.opaConfig = &opaPluginRef{
 		url:    srv.URL,
@@ -2657,6 +2716,24 @@ func TestDotWithSoftdelete(t *testing.T) {
 	}
 }
 
+func TestDotWithTenant(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("from users")
+	_ = sess.Execute("plugin tenant 42")
+
+	tmp := t.TempDir() + "/test.dot"
+	if _, err := sess.Exec("dot " + tmp); err != nil {
+		t.Fatalf("dot command failed: %v", err)
+	}
+	data, _ := os.ReadFile(tmp) // #nosec G304 - test file path is controlled
+	dot := string(data)
+	if !strings.Contains(dot, `label="tenant"`) || !strings.Contains(dot, "#E67E22") {
+		t.Errorf("expected orange tenant cluster in DOT, got:\n%s", dot)
+	}
+}
+
 func TestHelpIncludesDot(t *testing.T) {
 	t.Parallel()
 	sess := NewSession("postgres", nil)
@@ -2664,6 +2741,142 @@ func TestHelpIncludesDot(t *testing.T) {
 	if !strings.Contains(out, "dot <filepath>") {
 		t.Errorf("expected 'dot <filepath>' in help, got:\n%s", out)
 	}
+	if !strings.Contains(out, "mermaid <filepath>") {
+		t.Errorf("expected 'mermaid <filepath>' in help, got:\n%s", out)
+	}
+}
+
+// --- mermaid and tree command tests ---
+
+func TestMermaidWritesFile(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("from users")
+	_ = sess.Execute("where users.active = true")
+	_ = sess.Execute("plugin softdelete")
+
+	tmp := t.TempDir() + "/test.mmd"
+	out, err := sess.Exec("mermaid " + tmp)
+	if err != nil {
+		t.Fatalf("mermaid command failed: %v", err)
+	}
+	if !strings.Contains(out, "Wrote Mermaid to") {
+		t.Errorf("expected confirmation message, got: %s", out)
+	}
+
+	data, err := os.ReadFile(tmp) // #nosec G304 - test file path is controlled
+	if err != nil {
+		t.Fatalf("failed to read Mermaid file: %v", err)
+	}
+	mmd := string(data)
+	if !strings.HasPrefix(mmd, "flowchart LR") {
+		t.Errorf("expected Mermaid content, got:\n%s", mmd)
+	}
+	if !strings.Contains(mmd, `subgraph cluster_0["softdelete"]`) {
+		t.Errorf("expected softdelete subgraph, got:\n%s", mmd)
+	}
+}
+
+func TestMermaidRequiresFilepath(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("from users")
+
+	_, err := sess.Exec("mermaid")
+	if err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("expected usage error, got: %v", err)
+	}
+}
+
+func TestTreeShowsAST(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("from users")
+	_ = sess.Execute("where users.active = true")
+	_ = sess.Execute("plugin softdelete")
+
+	out, err := sess.Exec("tree")
+	if err != nil {
+		t.Fatalf("tree command failed: %v", err)
+	}
+	for _, want := range []string{
+		"  SelectCore\n",
+		"  ├── FROM: Table users\n",
+		"│   ├── LEFT: Attribute users.active\n",
+		"[softdelete]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in tree, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestTreeRequiresQuery(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	if _, err := sess.Exec("tree"); err == nil {
+		t.Error("expected error for no query")
+	}
+}
+
+// --- lint command tests ---
+
+func TestLintReportsFindings(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("from users")
+	_ = sess.Execute("where users.email like '%@example.com'")
+
+	out, err := sess.Exec("lint")
+	if err != nil {
+		t.Fatalf("lint command failed: %v", err)
+	}
+	for _, want := range []string{
+		"warning: SELECT * returns whatever columns the table has; list them (select-star)",
+		`warning: WHERE[0]: LIKE pattern "%@example.com" starts with a wildcard`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in lint output, got:\n%s", want, out)
+		}
+	}
+
+	_ = sess.Execute("reset")
+	_ = sess.Execute("from users")
+	_ = sess.Execute("select users.id")
+	out, err = sess.Exec("lint")
+	if err != nil {
+		t.Fatalf("lint command failed: %v", err)
+	}
+	if !strings.Contains(out, "No findings") {
+		t.Errorf("expected no findings, got:\n%s", out)
+	}
+}
+
+func TestLintDelete(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("table users")
+	_ = sess.Execute("delete from users")
+
+	out, err := sess.Exec("lint")
+	if err != nil {
+		t.Fatalf("lint command failed: %v", err)
+	}
+	if !strings.Contains(out, "error: DELETE without WHERE removes every row (missing-where)") {
+		t.Errorf("expected missing-where finding, got:\n%s", out)
+	}
+}
+
+func TestLintRequiresQuery(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	if _, err := sess.Exec("lint"); err == nil {
+		t.Error("expected error for no query")
+	}
 }
 
 // --- Arithmetic operations ---
@@ -4920,3 +5133,136 @@ func TestCmdTables(t *testing.T) {
 		t.Errorf("expected \"consignments\" in tables output, got:\n%s", out)
 	}
 }
+
+// --- DDL Builder Tests ---
+
+func TestREPLCreateTable(t *testing.T) {
+	t.Parallel()
+	got := execSQL(t, "postgres",
+		"create table if not exists posts",
+		"column id bigint not null",
+		"column title varchar(255) not null default 'untitled'",
+		"column author_id bigint references users(id) on delete cascade",
+		"column price numeric(10, 2) unique",
+		"primary key (id)",
+	)
+	testutil.AssertEqual(t, got, `CREATE TABLE IF NOT EXISTS "posts" (`+
+		`"id" bigint NOT NULL, `+
+		`"title" varchar(255) NOT NULL DEFAULT 'untitled', `+
+		`"author_id" bigint REFERENCES "users" ("id") ON DELETE CASCADE, `+
+		`"price" numeric(10, 2) UNIQUE, `+
+		`PRIMARY KEY ("id"))`)
+}
+
+func TestREPLAlterTableSQLite(t *testing.T) {
+	t.Parallel()
+	got := execSQL(t, "sqlite",
+		"alter table users",
+		"add column age integer",
+		"rename column mail to email",
+		"drop column legacy",
+	)
+	testutil.AssertEqual(t, got, `ALTER TABLE "users" ADD COLUMN "age" integer; `+
+		`ALTER TABLE "users" RENAME COLUMN "mail" TO "email"; `+
+		`ALTER TABLE "users" DROP COLUMN "legacy"`)
+}
+
+func TestREPLCreatePartialIndex(t *testing.T) {
+	t.Parallel()
+	got := execSQL(t, "postgres",
+		"create unique index concurrently users_email_idx on users (lower(users.email), created_at desc)",
+		"where users.deleted_at is null",
+	)
+	testutil.AssertEqual(t, got, `CREATE UNIQUE INDEX CONCURRENTLY "users_email_idx" ON "users" `+
+		`((LOWER("email")), "created_at" DESC) WHERE "deleted_at" IS NULL`)
+}
+
+func TestREPLCreateIndexUnsupported(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("mysql", nil)
+	_ = sess.Execute("create index users_a on users (a)")
+	_ = sess.Execute("where users.a > 1")
+	_, err := sess.GenerateSQL()
+	if err == nil || !strings.Contains(err.Error(), "does not support partial indexes") {
+		t.Errorf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestREPLDropStatements(t *testing.T) {
+	t.Parallel()
+	testutil.AssertEqual(t, execSQL(t, "postgres", "drop table if exists users cascade"),
+		`DROP TABLE IF EXISTS "users" CASCADE`)
+	testutil.AssertEqual(t, execSQL(t, "mysql", "drop index users_email_idx on users"),
+		"DROP INDEX `users_email_idx` ON `users`")
+}
+
+func TestREPLColumnRequiresCreateTable(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("alter table users")
+	err := sess.Execute("column id bigint")
+	if err == nil || !strings.Contains(err.Error(), "CREATE TABLE") {
+		t.Errorf("expected CREATE TABLE error, got %v", err)
+	}
+	_ = sess.Execute("from users")
+	if sess.ddlQuery != nil {
+		t.Error("expected ddlQuery to be cleared when switching to SELECT")
+	}
+}
+
+func TestREPLASTCreateIndex(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	_ = sess.Execute("create index users_name on users (name)")
+	out, err := sess.Exec("ast")
+	testutil.AssertNoError(t, err)
+	if !strings.Contains(out, "Mode: CREATE INDEX") || !strings.Contains(out, "users_name ON users") {
+		t.Errorf("unexpected AST output:\n%s", out)
+	}
+}
+
+func TestREPLCreateViewSnapshotsQuery(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	for _, cmd := range []string{
+		"table users",
+		"from users",
+		"select users.id, users.name",
+		"where users.active = true",
+		"create or replace view active_users (id, name)",
+	} {
+		if err := sess.Execute(cmd); err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+	}
+	got, err := sess.GenerateSQL()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, got, `CREATE OR REPLACE VIEW "active_users" ("id", "name") AS `+
+		`SELECT "users"."id", "users"."name" FROM "users" WHERE "users"."active" = TRUE`)
+}
+
+func TestREPLCreateViewAppliesPlugins(t *testing.T) {
+	t.Parallel()
+	got := execSQL(t, "postgres",
+		"table users",
+		"plugin softdelete",
+		"from users",
+		"create materialized view live_users",
+	)
+	testutil.AssertEqual(t, got, `CREATE MATERIALIZED VIEW "live_users" AS SELECT * FROM "users" WHERE "users"."deleted_at" IS NULL`)
+}
+
+func TestREPLCreateViewRequiresQuery(t *testing.T) {
+	t.Parallel()
+	sess := NewSession("postgres", nil)
+	err := sess.Execute("create view v")
+	if err == nil || !strings.Contains(err.Error(), "requires a SELECT query") {
+		t.Errorf("expected SELECT query error, got %v", err)
+	}
+}
+
+func TestREPLRefreshMaterializedView(t *testing.T) {
+	t.Parallel()
+	got := execSQL(t, "postgres", "refresh materialized view concurrently live_users")
+	testutil.AssertEqual(t, got, `REFRESH MATERIALIZED VIEW CONCURRENTLY "live_users"`)
+}
@@ -38,6 +38,7 @@ const (
 	modeInsert
 	modeUpdate
 	modeDelete
+	modeDDL
 )
 
 // Session holds the REPL state: registered tables, the current query,
@@ -62,7 +63,8 @@ type Session struct {
 	insertQuery  *managers.InsertManager
 	updateQuery  *managers.UpdateManager
 	deleteQuery  *managers.DeleteManager
-	out          io.Writer // destination for REPL output (default os.Stdout)
+	ddlQuery     ddlBuilder // CREATE/ALTER/DROP in modeDDL
+	out          io.Writer  // destination for REPL output (default os.Stdout)
 }
 
 // NewSession creates a session with the given SQL dialect.
@@ -75,7 +77,9 @@ func NewSession(engine string, rl *readline.Instance) *Session {
 	}
 	s.configurers = []pluginConfigurer{
 		{name: "softdelete", configure: configureSoftdelete},
+		{name: "tenant", configure: configureTenant},
 		{name: "opa", configure: configureOPA},
+		{name: "validate", configure: configureValidate},
 	}
 	s.setEngine(engine)
 	s.initCommands()
@@ -140,6 +144,11 @@ func (s *Session) generateSQLWith(v nodes.Visitor) (string, error) {
 			return "", errors.New("no DELETE query defined")
 		}
 		sql, _, err = s.deleteQuery.ToSQL(v)
+	case modeDDL:
+		if s.ddlQuery == nil {
+			return "", errors.New("no DDL statement defined")
+		}
+		sql, _, err = s.ddlQuery.ToSQL(v)
 	default:
 		if s.query == nil {
 			return "", errNoQuery
@@ -318,6 +327,12 @@ func (s *Session) cmdWhere(args string) error {
 			return errors.New("no DELETE query defined")
 		}
 		s.deleteQuery.Where(cond)
+	case modeDDL:
+		m, ok := s.ddlQuery.(*managers.CreateIndexManager)
+		if !ok {
+			return errors.New("where in DDL mode requires an active CREATE INDEX")
+		}
+		m.Where(cond)
 	default:
 		if s.query == nil {
 			return errNoQuery
@@ -1075,6 +1090,8 @@ func (s *Session) cmdExec() error {
 			return errors.New("no DELETE query defined")
 		}
 		sqlStr, params, err = s.deleteQuery.ToSQL(pv)
+	case modeDDL:
+		return s.execDDL(pv)
 	default:
 		if s.query == nil {
 			return errNoQuery
@@ -1120,6 +1137,8 @@ func (s *Session) cmdAST() error {
 		return s.cmdASTUpdate()
 	case modeDelete:
 		return s.cmdASTDelete()
+	case modeDDL:
+		return s.cmdASTDDL()
 	}
 
 	if s.query == nil {
@@ -1151,11 +1170,58 @@ func (s *Session) cmdDot(args string) error {
 	if fpath == "" {
 		return fmt.Errorf("usage: dot <filepath>")
 	}
+	dv := visitors.NewDotVisitor()
+	if err := s.visitWithProvenance(dv); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fpath, []byte(dv.ToDot()), 0600); err != nil {
+		return fmt.Errorf("failed to write DOT file: %w", err)
+	}
+	_, _ = fmt.Fprintf(s.out, "  Wrote DOT to %s\n", fpath)
+	return nil
+}
+
+// cmdMermaid exports the current query AST as a Mermaid flowchart, with the
+// same colours and plugin clusters as cmdDot.
+func (s *Session) cmdMermaid(args string) error {
+	fpath := strings.TrimSpace(args)
+	if fpath == "" {
+		return fmt.Errorf("usage: mermaid <filepath>")
+	}
+	mv := visitors.NewMermaidVisitor()
+	if err := s.visitWithProvenance(mv.DotVisitor); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fpath, []byte(mv.ToMermaid()), 0600); err != nil {
+		return fmt.Errorf("failed to write Mermaid file: %w", err)
+	}
+	_, _ = fmt.Fprintf(s.out, "  Wrote Mermaid to %s\n", fpath)
+	return nil
+}
+
+// cmdTree prints the current query AST as a text tree, marking the nodes
+// contributed by plugins.
+func (s *Session) cmdTree() error {
+	tv := visitors.NewTreeVisitor()
+	if err := s.visitWithProvenance(tv.DotVisitor); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(tv.ToTree(), "\n"), "\n") {
+		_, _ = fmt.Fprintf(s.out, "  %s\n", line)
+	}
+	return nil
+}
+
+// visitWithProvenance walks a clone of the current query with dv after
+// applying the enabled plugins one at a time, recording which WHERE and
+// JOIN entries each plugin added.
+func (s *Session) visitWithProvenance(dv *visitors.DotVisitor) error {
 	if s.query == nil {
 		return errNoQuery
 	}
 
-	// Clone and apply plugins incrementally for provenance.
 	core := s.query.CloneCore()
 	prov := visitors.NewPluginProvenance()
 	for _, entry := range s.plugins.entries {
@@ -1175,17 +1241,10 @@ func (s *Session) cmdDot(args string) error {
 		}
 	}
 
-	dv := visitors.NewDotVisitor()
 	dv.SetProvenance(prov)
 	core.Accept(dv)
-
-	if err := os.WriteFile(fpath, []byte(dv.ToDot()), 0600); err != nil {
-		return fmt.Errorf("failed to write DOT file: %w", err)
-	}
-	_, _ = fmt.Fprintf(s.out, "  Wrote DOT to %s\n", fpath)
 	return nil
 }
-
 func (s *Session) cmdReset() error {
 	s.setMode(modeSelect)
 	s.setOps = nil
@@ -1248,6 +1307,27 @@ func (s *Session) cmdHelp() {
     where <condition>         Add WHERE (shared with SELECT)
     returning <cols>          Set RETURNING clause
 
+  DDL Builder:
+    create table [if not exists] <name>   Start a CREATE TABLE statement
+    column <name> <type> [not null] [default <v>] [primary key] [unique]
+           [references <t>(<cols>) [on delete <action>]]   Add a column
+    primary key (<cols>)      Add a table-level PRIMARY KEY
+    alter table <name>        Start an ALTER TABLE statement
+    add column <name> <type> ...   Add an ADD COLUMN action
+    drop column <name>        Add a DROP COLUMN action
+    rename column <a> to <b>  Add a RENAME COLUMN action
+    create [unique] index [concurrently] <name> on <table> (<cols>)
+                              Start a CREATE INDEX statement
+    where <condition>         Make the index partial
+    create [or replace] view <name> [(<cols>)]
+                              Save the current SELECT as a view
+    create materialized view <name> [(<cols>)]
+                              Save the current SELECT as a materialized view
+    refresh materialized view [concurrently] <name>
+                              Refresh a materialized view
+    drop table [if exists] <name> [cascade]   DROP TABLE
+    drop index [if exists] <name> [on <table>]   DROP INDEX
+
   Joins:
     join <t> on <cond>        Add an INNER JOIN
     left join <t> on <cond>   Add a LEFT OUTER JOIN
@@ -1286,8 +1366,13 @@ func (s *Session) cmdHelp() {
     sql                       Generate and display SQL
     ast                       Show AST summary
     dot <filepath>            Export AST as Graphviz DOT file
+    mermaid <filepath>        Export AST as Mermaid flowchart
+    tree                      Show AST as a text tree
     expr <expression>         Evaluate a standalone expression
     exec                      Execute query against connected DB (alias: run)
+    explain [analyze]         Show the query plan from the connected DB
+    check                     Validate the query against the DB schema
+    lint                      Report likely mistakes in the query
 
   Configuration:
     engine <name>             Switch dialect (postgres, mysql, sqlite)
@@ -1300,6 +1385,14 @@ func (s *Session) cmdHelp() {
     plugin softdelete <col> on <tables..>  Soft-delete for specific tables
     plugin softdelete <t.col, ...>         Per-table soft-delete columns
 
+  Plugins — Tenant:
+    plugin tenant <id> [column <col>]      Scope queries to a tenant (default: tenant_id)
+    plugin tenant <id> <t.col, ...>        Per-table tenant columns
+    plugin tenant <id> ... exempt <tables> Leave shared tables unscoped
+
+  Plugins — Validation:
+    plugin validate           Reject queries that do not match the DB schema
+
   Plugins — OPA:
     opa                       OPA setup wizard
     opa status                Show OPA configuration
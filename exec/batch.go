@@ -0,0 +1,84 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// ExecBatches splits m with InsertManager.Batches and runs every batch in
+// one transaction, returning the total rows affected and the last insert
+// ID of the final batch. A maxParams of 0 uses the dialect's limit. On a
+// *DB a transaction is started and committed; on a *Tx the batches join it.
+func ExecBatches(ctx context.Context, s Session, m *managers.InsertManager, maxParams int) (Result, error) {
+	var total Result
+	err := runBatches(ctx, s, m, maxParams, func(s Session, b *managers.InsertManager) error {
+		res, err := Exec(ctx, s, b)
+		total.RowsAffected += res.RowsAffected
+		total.LastInsertID = res.LastInsertID
+		return err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return total, nil
+}
+
+// QueryBatches is ExecBatches for an INSERT with RETURNING, scanning the
+// returned rows of every batch into a T.
+func QueryBatches[T any](ctx context.Context, s Session, m *managers.InsertManager, maxParams int) ([]T, error) {
+	var out []T
+	err := runBatches(ctx, s, m, maxParams, func(s Session, b *managers.InsertManager) error {
+		rows, err := Query[T](ctx, s, b)
+		out = append(out, rows...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func runBatches(ctx context.Context, s Session, m *managers.InsertManager, maxParams int, run func(Session, *managers.InsertManager) error) error {
+	if maxParams <= 0 {
+		var err error
+		if maxParams, err = dialectMaxParams(s.Visitor()); err != nil {
+			return err
+		}
+	}
+	batches, err := m.Batches(maxParams)
+	if err != nil {
+		return err
+	}
+	each := func(s Session) error {
+		for _, b := range batches {
+			if err := run(s, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if db, ok := s.(*DB); ok {
+		return db.Transaction(ctx, func(tx *Tx) error { return each(tx) })
+	}
+	return each(s)
+}
+
+// dialectMaxParams returns the bind-parameter limit of v's dialect.
+func dialectMaxParams(v nodes.Visitor) (int, error) {
+	switch v.(type) {
+	case *visitors.PostgresVisitor:
+		return managers.MaxParamsPostgres, nil
+	case *visitors.MySQLVisitor:
+		return managers.MaxParamsMySQL, nil
+	case *visitors.SQLiteVisitor:
+		return managers.MaxParamsSQLite, nil
+	case *visitors.MSSQLVisitor:
+		return managers.MaxParamsMSSQL, nil
+	default:
+		return 0, fmt.Errorf("exec: no parameter limit known for %T; pass maxParams", v)
+	}
+}
@@ -0,0 +1,95 @@
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+func TestExecBatchesInsertsAllRows(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	ctx := context.Background()
+
+	m := managers.NewInsertManager(users)
+	rows := make([]any, 25)
+	for i := range rows {
+		rows[i] = user{Email: string(rune('a'+i)) + "@example.com"}
+	}
+	testutil.AssertNoError(t, m.FromStructs(rows...))
+
+	res, err := ExecBatches(ctx, db, m, 4)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, res.RowsAffected, int64(25))
+
+	n, err := QueryOne[int](ctx, db, managers.NewSelectManager(users).Select(nodes.Count(nil)))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 25)
+}
+
+func TestExecBatchesRollsBackOnFailure(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	ctx := context.Background()
+	insertUsers(t, db, user{Email: "taken@example.com"})
+
+	// The duplicate email fails in the last batch; the earlier batches
+	// must be rolled back with it.
+	m := managers.NewInsertManager(users)
+	testutil.AssertNoError(t, m.FromStructs(
+		user{Email: "a@example.com"}, user{Email: "b@example.com"}, user{Email: "taken@example.com"},
+	))
+	_, err := ExecBatches(ctx, db, m, 2)
+	testutil.AssertError(t, err)
+
+	n, err := QueryOne[int](ctx, db, managers.NewSelectManager(users).Select(nodes.Count(nil)))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 1)
+}
+
+func TestQueryBatchesReturning(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	ctx := context.Background()
+
+	m := managers.NewInsertManager(users)
+	testutil.AssertNoError(t, m.FromStructs(user{Email: "a"}, user{Email: "b"}, user{Email: "c"}))
+	m.Returning(users.Col("id"))
+
+	ids, err := QueryBatches[int64](ctx, db, m, 0)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(ids), 3)
+	testutil.AssertEqual(t, ids[2], int64(3))
+}
+
+func TestExecBatchesJoinsTransaction(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	ctx := context.Background()
+
+	tx, err := db.Begin(ctx, nil)
+	testutil.AssertNoError(t, err)
+	m := managers.NewInsertManager(users)
+	testutil.AssertNoError(t, m.FromStructs(user{Email: "a"}, user{Email: "b"}))
+	_, err = ExecBatches(ctx, tx, m, 1)
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, tx.Rollback())
+
+	n, err := QueryOne[int](ctx, db, managers.NewSelectManager(users).Select(nodes.Count(nil)))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 0)
+}
+
+func TestDialectMaxParams(t *testing.T) {
+	t.Parallel()
+	n, err := dialectMaxParams(visitors.NewMSSQLVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, managers.MaxParamsMSSQL)
+
+	_, err = dialectMaxParams(visitors.NewDuckDBVisitor())
+	testutil.AssertError(t, err)
+}
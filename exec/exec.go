@@ -0,0 +1,252 @@
+// Package exec runs gosbee managers on database/sql and scans the results
+// into Go values.
+//
+// Wrap a *sql.DB with New, which picks the SQL dialect from the driver:
+//
+//	db, err := exec.New(sqlDB)
+//	users, err := exec.Query[User](ctx, db, gosbee.NewSelect(usersTable))
+//
+// Rows scan into structs by their `db` tags, or into a single value when T
+// is not a struct. Query, QueryOne, Iter and Exec accept a *DB or a *Tx.
+package exec
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/bawdo/gosbee/internal/drivers"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// Builder is implemented by every gosbee manager.
+type Builder interface {
+	ToSQL(v nodes.Visitor) (string, []any, error)
+}
+
+// Session runs statements. It is implemented by *DB and *Tx.
+type Session interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+
+	// Visitor returns the visitor statements are rendered with.
+	Visitor() nodes.Visitor
+}
+
+// DB is a *sql.DB paired with the dialect its statements are rendered in.
+type DB struct {
+	*sql.DB
+	newVisitor func() nodes.Visitor
+}
+
+// New wraps db, selecting the dialect from its driver. The PostgreSQL
+// (pgx, lib/pq), MySQL, SQLite (modernc, mattn), SQL Server (go-mssqldb),
+// Oracle (go-ora, godror), ClickHouse (clickhouse-go) and DuckDB
+// (go-duckdb) drivers are recognised; use NewWithVisitor for others. opts are passed to the visitor.
+func New(db *sql.DB, opts ...visitors.Option) (*DB, error) {
+	newVisitor, err := detectDialect(db.Driver(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{DB: db, newVisitor: newVisitor}, nil
+}
+
+// NewWithVisitor wraps db, rendering statements with visitors returned by
+// newVisitor.
+func NewWithVisitor(db *sql.DB, newVisitor func() nodes.Visitor) *DB {
+	return &DB{DB: db, newVisitor: newVisitor}
+}
+
+// Visitor returns a visitor for the database's dialect.
+func (d *DB) Visitor() nodes.Visitor {
+	return d.newVisitor()
+}
+
+// Begin starts a transaction.
+func (d *DB) Begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, newVisitor: d.newVisitor}, nil
+}
+
+// Transaction runs fn in a transaction, committing when fn returns nil and
+// rolling back otherwise.
+func (d *DB) Transaction(ctx context.Context, fn func(*Tx) error) (err error) {
+	tx, err := d.Begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Tx is a transaction started by DB.Begin.
+type Tx struct {
+	*sql.Tx
+	newVisitor func() nodes.Visitor
+}
+
+// Visitor returns a visitor for the transaction's dialect.
+func (t *Tx) Visitor() nodes.Visitor {
+	return t.newVisitor()
+}
+
+// Result reports the outcome of Exec.
+type Result struct {
+	RowsAffected int64
+
+	// LastInsertID is 0 when the driver does not report it, as with
+	// PostgreSQL; use RETURNING there instead.
+	LastInsertID int64
+}
+
+// Exec runs a statement that returns no rows. When q is an INSERT, UPDATE
+// or DELETE manager, its transformers implementing plugins.RowsChecker
+// check the rows affected, and the first error is returned along with the
+// Result.
+func Exec(ctx context.Context, s Session, q Builder) (Result, error) {
+	query, params, err := q.ToSQL(s.Visitor())
+	if err != nil {
+		return Result{}, err
+	}
+	res, err := s.ExecContext(ctx, query, params...)
+	if err != nil {
+		return Result{}, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return Result{}, err
+	}
+	id, _ := res.LastInsertId()
+	result := Result{RowsAffected: n, LastInsertID: id}
+	if stmt, transformers := statement(q); stmt != nil {
+		for _, t := range transformers {
+			if c, ok := t.(plugins.RowsChecker); ok {
+				if err := c.CheckRowsAffected(stmt, n); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// statement returns the statement of a write manager and its
+// transformers, or nil for any other Builder.
+func statement(q Builder) (nodes.Node, []plugins.Transformer) {
+	switch m := q.(type) {
+	case *managers.InsertManager:
+		return m.Statement, m.Transformers()
+	case *managers.UpdateManager:
+		return m.Statement, m.Transformers()
+	case *managers.DeleteManager:
+		return m.Statement, m.Transformers()
+	}
+	return nil, nil
+}
+
+// Query runs q and scans every row into a T.
+func Query[T any](ctx context.Context, s Session, q Builder) ([]T, error) {
+	var out []T
+	for v, err := range Iter[T](ctx, s, q) {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// QueryOne runs q and scans the first row into a T. It returns
+// sql.ErrNoRows when there are no rows.
+func QueryOne[T any](ctx context.Context, s Session, q Builder) (T, error) {
+	for v, err := range Iter[T](ctx, s, q) {
+		return v, err
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
+
+// Iter runs q and yields each row scanned into a T. Rows are read as the
+// loop advances; breaking out of the loop closes them. An error ends the
+// sequence.
+func Iter[T any](ctx context.Context, s Session, q Builder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		query, params, err := q.ToSQL(s.Visitor())
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		rows, err := s.QueryContext(ctx, query, params...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer func() { _ = rows.Close() }()
+
+		scan, err := newScanner[T](rows)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for rows.Next() {
+			v, err := scan(rows)
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// detectDialect maps a driver to a visitor constructor.
+func detectDialect(drv driver.Driver, opts []visitors.Option) (func() nodes.Visitor, error) {
+	if newVisitor := engineVisitor(drivers.Engine(drv), opts); newVisitor != nil {
+		return newVisitor, nil
+	}
+	return nil, fmt.Errorf("exec: cannot detect the dialect of driver %T; use NewWithVisitor", drv)
+}
+
+// engineVisitor returns the visitor constructor for an engine reported by
+// drivers.Engine, or nil for an unknown engine.
+func engineVisitor(engine string, opts []visitors.Option) func() nodes.Visitor {
+	switch engine {
+	case "postgres":
+		return func() nodes.Visitor { return visitors.NewPostgresVisitor(opts...) }
+	case "mysql":
+		return func() nodes.Visitor { return visitors.NewMySQLVisitor(opts...) }
+	case "sqlite":
+		return func() nodes.Visitor { return visitors.NewSQLiteVisitor(opts...) }
+	case "mssql":
+		return func() nodes.Visitor { return visitors.NewMSSQLVisitor(opts...) }
+	case "oracle":
+		return func() nodes.Visitor { return visitors.NewOracleVisitor(opts...) }
+	case "clickhouse":
+		return func() nodes.Visitor { return visitors.NewClickHouseVisitor(opts...) }
+	case "duckdb":
+		return func() nodes.Visitor { return visitors.NewDuckDBVisitor(opts...) }
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package exec
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/plugins/optlock"
+	"github.com/bawdo/gosbee/visitors"
+	_ "modernc.org/sqlite"
+)
+
+type user struct {
+	ID    int64   `db:"id,pk,omitempty"`
+	Email string  `db:"email"`
+	Nick  *string `db:"nick"`
+}
+
+var users = nodes.NewTable("users")
+
+func openDB(t *testing.T) *DB {
+	t.Helper()
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	testutil.AssertNoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	_, err = sqlDB.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, nick TEXT)`)
+	testutil.AssertNoError(t, err)
+
+	db, err := New(sqlDB)
+	testutil.AssertNoError(t, err)
+	return db
+}
+
+func insertUsers(t *testing.T, s Session, rows ...any) {
+	t.Helper()
+	m := managers.NewInsertManager(users)
+	testutil.AssertNoError(t, m.FromStructs(rows...))
+	_, err := Exec(context.Background(), s, m)
+	testutil.AssertNoError(t, err)
+}
+
+func TestNewDetectsSQLite(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	if _, ok := db.Visitor().(*visitors.SQLiteVisitor); !ok {
+		t.Errorf("expected SQLiteVisitor, got %T", db.Visitor())
+	}
+}
+
+func TestExecReportsRowsAndInsertID(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	m := managers.NewInsertManager(users)
+	testutil.AssertNoError(t, m.FromStructs(user{Email: "a@example.com"}))
+	res, err := Exec(context.Background(), db, m)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, res.RowsAffected, int64(1))
+	testutil.AssertEqual(t, res.LastInsertID, int64(1))
+}
+
+func TestQueryScansStructs(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	nick := "bee"
+	insertUsers(t, db, user{Email: "a@example.com", Nick: &nick}, user{Email: "b@example.com"})
+
+	q := managers.NewSelectManager(users).
+		Select(users.Col("id"), users.Col("email"), users.Col("nick")).
+		Order(users.Col("id").Asc())
+	got, err := Query[user](context.Background(), db, q)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(got), 2)
+	testutil.AssertEqual(t, got[0].Email, "a@example.com")
+	testutil.AssertEqual(t, *got[0].Nick, "bee")
+	if got[1].Nick != nil {
+		t.Errorf("expected NULL nick, got %q", *got[1].Nick)
+	}
+
+	ptrs, err := Query[*user](context.Background(), db, q)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, ptrs[1].ID, int64(2))
+}
+
+func TestQueryScansScalars(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	insertUsers(t, db, user{Email: "a@example.com"}, user{Email: "b@example.com"})
+
+	emails, err := Query[string](context.Background(), db,
+		managers.NewSelectManager(users).Select(users.Col("email")).Order(users.Col("email").Desc()))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(emails), 2)
+	testutil.AssertEqual(t, emails[0], "b@example.com")
+
+	_, err = Query[string](context.Background(), db,
+		managers.NewSelectManager(users).Select(users.Col("id"), users.Col("email")))
+	testutil.AssertError(t, err)
+}
+
+func TestQueryUnknownColumn(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	insertUsers(t, db, user{Email: "a@example.com"})
+	type partial struct {
+		Email string `db:"email"`
+	}
+	_, err := Query[partial](context.Background(), db, managers.NewSelectManager(users).Select(nodes.Star()))
+	testutil.AssertError(t, err)
+}
+
+func TestQueryOne(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	insertUsers(t, db, user{Email: "a@example.com"})
+
+	u, err := QueryOne[user](context.Background(), db, managers.NewSelectManager(users).
+		Select(users.Col("id"), users.Col("email")).
+		Where(users.Col("email").Eq("a@example.com")))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, u.ID, int64(1))
+
+	_, err = QueryOne[user](context.Background(), db, managers.NewSelectManager(users).
+		Select(users.Col("id")).
+		Where(users.Col("email").Eq("missing")))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestIterStopsEarly(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	insertUsers(t, db, user{Email: "a"}, user{Email: "b"}, user{Email: "c"})
+
+	var seen []string
+	for email, err := range Iter[string](context.Background(), db,
+		managers.NewSelectManager(users).Select(users.Col("email")).Order(users.Col("email").Asc())) {
+		testutil.AssertNoError(t, err)
+		seen = append(seen, email)
+		if len(seen) == 2 {
+			break
+		}
+	}
+	testutil.AssertEqual(t, len(seen), 2)
+
+	// The connection was released when the loop broke.
+	n, err := QueryOne[int](context.Background(), db, managers.NewSelectManager(users).Select(nodes.Count(nil)))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 3)
+}
+
+type failingTransformer struct {
+	plugins.BaseTransformer
+	err error
+}
+
+func (ft failingTransformer) TransformSelect(*nodes.SelectCore) (*nodes.SelectCore, error) {
+	return nil, ft.err
+}
+
+func TestIterBuildError(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	boom := errors.New("boom")
+	q := managers.NewSelectManager(users).Use(failingTransformer{err: boom})
+	for _, err := range Iter[user](context.Background(), db, q) {
+		if !errors.Is(err, boom) {
+			t.Errorf("expected transformer error, got %v", err)
+		}
+	}
+}
+
+func TestTransactionCommitsAndRollsBack(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	ctx := context.Background()
+
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		insertUsers(t, tx, user{Email: "kept@example.com"})
+		return nil
+	})
+	testutil.AssertNoError(t, err)
+
+	boom := errors.New("boom")
+	err = db.Transaction(ctx, func(tx *Tx) error {
+		insertUsers(t, tx, user{Email: "lost@example.com"})
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+
+	emails, err := Query[string](ctx, db, managers.NewSelectManager(users).Select(users.Col("email")))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(emails), 1)
+	testutil.AssertEqual(t, emails[0], "kept@example.com")
+}
+
+// requireRows fails statements that affect no rows.
+type requireRows struct{ plugins.BaseTransformer }
+
+func (requireRows) CheckRowsAffected(_ nodes.Node, n int64) error {
+	if n == 0 {
+		return errors.New("no rows")
+	}
+	return nil
+}
+
+func TestExecChecksRowsAffected(t *testing.T) {
+	t.Parallel()
+	db := openDB(t)
+	insertUsers(t, db, user{Email: "a@example.com"})
+
+	m := managers.NewDeleteManager(users).Where(users.Col("email").Eq("a@example.com"))
+	m.Use(requireRows{})
+	res, err := Exec(context.Background(), db, m)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, res.RowsAffected, int64(1))
+
+	m = managers.NewDeleteManager(users).Where(users.Col("email").Eq("a@example.com"))
+	m.Use(requireRows{})
+	res, err = Exec(context.Background(), db, m)
+	if err == nil || err.Error() != "no rows" {
+		t.Fatalf("expected the checker's error, got %v", err)
+	}
+	testutil.AssertEqual(t, res.RowsAffected, int64(0))
+}
+
+type unknownDriver struct{}
+
+func (unknownDriver) Open(string) (driver.Conn, error) { return nil, errors.New("not implemented") }
+
+func TestNewUnknownDriver(t *testing.T) {
+	t.Parallel()
+	_, err := detectDialect(unknownDriver{}, nil)
+	testutil.AssertError(t, err)
+}
+
+func TestEngineVisitor(t *testing.T) {
+	t.Parallel()
+	tests := map[string]nodes.Visitor{
+		"postgres":   &visitors.PostgresVisitor{},
+		"mysql":      &visitors.MySQLVisitor{},
+		"sqlite":     &visitors.SQLiteVisitor{},
+		"mssql":      &visitors.MSSQLVisitor{},
+		"oracle":     &visitors.OracleVisitor{},
+		"clickhouse": &visitors.ClickHouseVisitor{},
+		"duckdb":     &visitors.DuckDBVisitor{},
+	}
+	for engine, want := range tests {
+		newVisitor := engineVisitor(engine, nil)
+		if newVisitor == nil {
+			t.Fatalf("no visitor for %s", engine)
+		}
+		if got := newVisitor(); reflect.TypeOf(got) != reflect.TypeOf(want) {
+			t.Errorf("%s: expected %T, got %T", engine, want, got)
+		}
+	}
+	if engineVisitor("", nil) != nil {
+		t.Error("expected no visitor for an unknown engine")
+	}
+}
+
+// rowsSession reports a fixed number of rows affected by every statement.
+type rowsSession struct{ rows int64 }
+
+func (rowsSession) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s rowsSession) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return driver.RowsAffected(s.rows), nil
+}
+
+func (rowsSession) Visitor() nodes.Visitor { return visitors.NewPostgresVisitor() }
+
+func TestExecOptimisticLock(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lock := optlock.New()
+	update := managers.NewUpdateManager(users).Set(users.Col("nick"), "a").Where(users.Col("id").Eq(1))
+	update.Use(lock.Expect(3))
+
+	_, err := Exec(ctx, rowsSession{rows: 1}, update)
+	testutil.AssertNoError(t, err)
+	_, err = Exec(ctx, rowsSession{rows: 0}, update)
+	if !errors.Is(err, optlock.ErrStale) {
+		t.Fatalf("expected ErrStale, got %v", err)
+	}
+
+	// A lock attached to other statements does not check their rows.
+	del := managers.NewDeleteManager(users).Where(users.Col("id").Eq(1))
+	del.Use(lock.Expect(3))
+	_, err = Exec(ctx, rowsSession{rows: 0}, del)
+	testutil.AssertNoError(t, err)
+
+	ins := managers.NewInsertManager(users).Columns(users.Col("email")).Values("a@example.com")
+	ins.Use(lock.Expect(3))
+	_, err = Exec(ctx, rowsSession{rows: 0}, ins)
+	testutil.AssertNoError(t, err)
+}
@@ -0,0 +1,75 @@
+package exec
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/bawdo/gosbee/internal/dbtag"
+)
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// newScanner returns a function scanning the current row into a T. Struct
+// types (and pointers to them) are filled by db tag from the result
+// columns; any other T must match a single-column result.
+func newScanner[T any](rows *sql.Rows) (func(*sql.Rows) (T, error), error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeFor[T]()
+	structType, isPtr := typ, false
+	if typ.Kind() == reflect.Pointer {
+		structType, isPtr = typ.Elem(), true
+	}
+
+	if !isStruct(structType) {
+		if len(cols) != 1 {
+			return nil, fmt.Errorf("exec: scanning %d columns into %s, want a struct", len(cols), typ)
+		}
+		return func(rows *sql.Rows) (T, error) {
+			var v T
+			err := rows.Scan(&v)
+			return v, err
+		}, nil
+	}
+
+	meta, err := dbtag.Of(structType)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]*dbtag.Field, len(cols))
+	for i, c := range cols {
+		if fields[i] = meta.Field(c); fields[i] == nil {
+			return nil, fmt.Errorf("exec: column %q has no db field in %s", c, structType)
+		}
+	}
+	return func(rows *sql.Rows) (T, error) {
+		var out T
+		sv := reflect.New(structType).Elem()
+		dest := make([]any, len(fields))
+		for i, f := range fields {
+			dest[i] = sv.FieldByIndex(f.Index).Addr().Interface()
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return out, err
+		}
+		if isPtr {
+			reflect.ValueOf(&out).Elem().Set(sv.Addr())
+		} else {
+			reflect.ValueOf(&out).Elem().Set(sv)
+		}
+		return out, nil
+	}, nil
+}
+
+// isStruct reports whether t is scanned field by field rather than as a
+// single value.
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(scannerType)
+}
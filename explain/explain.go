@@ -0,0 +1,116 @@
+// Package explain parses the plan output of EXPLAIN into a common tree, so
+// plans from PostgreSQL, MySQL and SQLite can be inspected and printed the
+// same way.
+//
+// Render the EXPLAIN statement with a manager's Explain method, run it, and
+// pass the output to the parser for the engine:
+//
+//	PostgreSQL  EXPLAIN (FORMAT JSON)   ParsePostgres
+//	MySQL       EXPLAIN FORMAT=JSON     ParseMySQL
+//	MySQL       EXPLAIN ANALYZE         ParseMySQLTree
+//	SQLite      EXPLAIN QUERY PLAN      ParseSQLite
+package explain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plan is one node of a query plan.
+type Plan struct {
+	// NodeType is the operation, in the engine's own words: "Seq Scan",
+	// "Table scan", "SCAN", "Nested loop", ...
+	NodeType string
+
+	// Relation is the table the node reads, if any.
+	Relation string
+
+	// Index is the index the node uses, if any.
+	Index string
+
+	// EstimatedRows is the planner's row estimate; 0 when not reported.
+	EstimatedRows float64
+
+	// Cost is the planner's total cost estimate for the node, in the
+	// engine's own units; 0 when not reported (SQLite never reports it).
+	Cost float64
+
+	// ActualRows is the number of rows produced per loop, reported when
+	// the plan was produced with ANALYZE.
+	ActualRows float64
+
+	// Analyzed reports whether ActualRows was measured.
+	Analyzed bool
+
+	// Detail is extra text from the engine, such as a filter condition or
+	// the full SQLite description.
+	Detail string
+
+	Children []*Plan
+}
+
+// String returns the plan as an indented tree, one node per line.
+func (p *Plan) String() string {
+	var sb strings.Builder
+	p.write(&sb, 0)
+	return sb.String()
+}
+
+func (p *Plan) write(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	if depth > 0 {
+		sb.WriteString("-> ")
+	}
+	sb.WriteString(p.NodeType)
+	if p.Relation != "" {
+		sb.WriteString(" on ")
+		sb.WriteString(p.Relation)
+	}
+	if p.Index != "" {
+		sb.WriteString(" using ")
+		sb.WriteString(p.Index)
+	}
+
+	var stats []string
+	if p.Cost > 0 {
+		stats = append(stats, "cost="+formatNumber(p.Cost))
+	}
+	if p.EstimatedRows > 0 {
+		stats = append(stats, "rows="+formatNumber(p.EstimatedRows))
+	}
+	if p.Analyzed {
+		stats = append(stats, "actual rows="+formatNumber(p.ActualRows))
+	}
+	if len(stats) > 0 {
+		sb.WriteString("  (")
+		sb.WriteString(strings.Join(stats, " "))
+		sb.WriteString(")")
+	}
+	if p.Detail != "" {
+		sb.WriteString("  [")
+		sb.WriteString(p.Detail)
+		sb.WriteString("]")
+	}
+	sb.WriteByte('\n')
+
+	for _, c := range p.Children {
+		c.write(sb, depth+1)
+	}
+}
+
+// formatNumber writes integral values without a fraction and others with
+// two decimals.
+func formatNumber(f float64) string {
+	if f == float64(int64(f)) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	return fmt.Sprintf("%.2f", f)
+}
+
+// Walk calls fn for p and every descendant, parents before children.
+func (p *Plan) Walk(fn func(*Plan)) {
+	fn(p)
+	for _, c := range p.Children {
+		c.Walk(fn)
+	}
+}
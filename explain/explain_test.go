@@ -0,0 +1,234 @@
+package explain
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	_ "modernc.org/sqlite"
+)
+
+const pgPlan = `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Join Type": "Left",
+      "Startup Cost": 1.09,
+      "Total Cost": 25.5,
+      "Plan Rows": 120,
+      "Actual Rows": 118,
+      "Hash Cond": "(p.user_id = u.id)",
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Relation Name": "posts",
+          "Alias": "p",
+          "Total Cost": 18.2,
+          "Plan Rows": 820,
+          "Actual Rows": 800,
+          "Filter": "(published)"
+        },
+        {
+          "Node Type": "Hash",
+          "Total Cost": 1.04,
+          "Plan Rows": 4,
+          "Actual Rows": 4,
+          "Plans": [
+            {
+              "Node Type": "Index Scan",
+              "Relation Name": "users",
+              "Index Name": "users_pkey",
+              "Total Cost": 1.04,
+              "Plan Rows": 4,
+              "Actual Rows": 4,
+              "Index Cond": "(id < 5)"
+            }
+          ]
+        }
+      ]
+    },
+    "Planning Time": 0.2,
+    "Execution Time": 1.3
+  }
+]`
+
+func TestParsePostgres(t *testing.T) {
+	t.Parallel()
+	p, err := ParsePostgres([]byte(pgPlan))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, p.String(), `Hash Join (Left)  (cost=25.50 rows=120 actual rows=118)  [Hash Cond: (p.user_id = u.id)]
+  -> Seq Scan on posts  (cost=18.20 rows=820 actual rows=800)  [Filter: (published)]
+  -> Hash  (cost=1.04 rows=4 actual rows=4)
+    -> Index Scan on users using users_pkey  (cost=1.04 rows=4 actual rows=4)  [Index Cond: (id < 5)]
+`)
+}
+
+func TestParsePostgresAggregateStrategy(t *testing.T) {
+	t.Parallel()
+	p, err := ParsePostgres([]byte(`[{"Plan": {"Node Type": "Aggregate", "Strategy": "Hashed",
+		"Total Cost": 10, "Plan Rows": 3, "Plans": [{"Node Type": "Seq Scan", "Relation Name": "t"}]}}]`))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, p.NodeType, "Hashed Aggregate")
+	testutil.AssertEqual(t, p.Analyzed, false)
+	testutil.AssertEqual(t, len(p.Children), 1)
+}
+
+func TestParsePostgresErrors(t *testing.T) {
+	t.Parallel()
+	_, err := ParsePostgres([]byte(`not json`))
+	testutil.AssertError(t, err)
+	_, err = ParsePostgres([]byte(`[]`))
+	testutil.AssertError(t, err)
+}
+
+const mysqlPlan = `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "12.75"},
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "u",
+            "access_type": "ALL",
+            "rows_examined_per_scan": 10,
+            "cost_info": {"read_cost": "1.00", "eval_cost": "1.00", "prefix_cost": "2.00"},
+            "attached_condition": "(u.active = 1)"
+          }
+        },
+        {
+          "table": {
+            "table_name": "p",
+            "access_type": "ref",
+            "key": "posts_user_id",
+            "rows_examined_per_scan": 3,
+            "cost_info": {"prefix_cost": "12.75"}
+          }
+        }
+      ]
+    }
+  }
+}`
+
+func TestParseMySQL(t *testing.T) {
+	t.Parallel()
+	p, err := ParseMySQL([]byte(mysqlPlan))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, p.String(), `Query block  (cost=12.75)
+  -> Sort  [using filesort]
+    -> Nested loop
+      -> Table scan on u  (cost=2 rows=10)  [Filter: (u.active = 1)]
+      -> Index lookup on p using posts_user_id  (cost=12.75 rows=3)
+`)
+}
+
+func TestParseMySQLUnion(t *testing.T) {
+	t.Parallel()
+	p, err := ParseMySQL([]byte(`{"query_block": {"union_result": {"query_specifications": [
+		{"query_block": {"table": {"table_name": "a", "access_type": "ALL"}}},
+		{"query_block": {"table": {"table_name": "b", "access_type": "const"}}}
+	]}}}`))
+	testutil.AssertNoError(t, err)
+	union := p.Children[0]
+	testutil.AssertEqual(t, union.NodeType, "Union")
+	testutil.AssertEqual(t, len(union.Children), 2)
+	testutil.AssertEqual(t, union.Children[1].Children[0].NodeType, "Constant row")
+}
+
+func TestParseMySQLErrors(t *testing.T) {
+	t.Parallel()
+	_, err := ParseMySQL([]byte(`{}`))
+	testutil.AssertError(t, err)
+	_, err = ParseMySQL([]byte(`[`))
+	testutil.AssertError(t, err)
+}
+
+const mysqlTree = `-> Nested loop inner join  (cost=4.70 rows=10) (actual time=0.05..0.09 rows=8 loops=1)
+    -> Filter: (u.active = true)  (cost=1.25 rows=3) (actual time=0.03..0.04 rows=2 loops=1)
+        -> Table scan on u  (cost=1.25 rows=10) (actual time=0.02..0.03 rows=10 loops=1)
+    -> Index lookup on p using posts_user_id (user_id=u.id)  (cost=0.35 rows=3) (actual time=0.01..0.02 rows=4 loops=2)
+`
+
+func TestParseMySQLTree(t *testing.T) {
+	t.Parallel()
+	p, err := ParseMySQLTree(mysqlTree)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, p.String(), `Nested loop inner join  (cost=4.70 rows=10 actual rows=8)
+  -> Filter  (cost=1.25 rows=3 actual rows=2)  [(u.active = true)]
+    -> Table scan on u  (cost=1.25 rows=10 actual rows=10)
+  -> Index lookup on p using posts_user_id  (cost=0.35 rows=3 actual rows=4)  [(user_id=u.id)]
+`)
+}
+
+func TestParseMySQLTreeErrors(t *testing.T) {
+	t.Parallel()
+	_, err := ParseMySQLTree("")
+	testutil.AssertError(t, err)
+	_, err = ParseMySQLTree("-> Table scan on a\n-> Table scan on b")
+	testutil.AssertError(t, err)
+}
+
+func TestParseSQLite(t *testing.T) {
+	t.Parallel()
+	p, err := ParseSQLite([]SQLiteRow{
+		{ID: 3, Parent: 0, Detail: "SCAN u"},
+		{ID: 5, Parent: 0, Detail: "SEARCH p USING INDEX posts_user_id (user_id=?)"},
+		{ID: 9, Parent: 0, Detail: "USE TEMP B-TREE FOR ORDER BY"},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, p.String(), `QUERY PLAN
+  -> SCAN on u  [SCAN u]
+  -> SEARCH on p using posts_user_id  [SEARCH p USING INDEX posts_user_id (user_id=?)]
+  -> USE TEMP B-TREE FOR ORDER BY  [USE TEMP B-TREE FOR ORDER BY]
+`)
+}
+
+func TestParseSQLiteLiveDatabase(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite", ":memory:")
+	testutil.AssertNoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);
+		CREATE INDEX users_email ON users (email)`)
+	testutil.AssertNoError(t, err)
+
+	rows, err := db.Query(`EXPLAIN QUERY PLAN SELECT id FROM users WHERE email = ?`, "a@example.com")
+	testutil.AssertNoError(t, err)
+	defer rows.Close()
+	var plan []SQLiteRow
+	for rows.Next() {
+		var r SQLiteRow
+		var notUsed int
+		testutil.AssertNoError(t, rows.Scan(&r.ID, &r.Parent, &notUsed, &r.Detail))
+		plan = append(plan, r)
+	}
+	testutil.AssertNoError(t, rows.Err())
+
+	p, err := ParseSQLite(plan)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, p.NodeType, "SEARCH")
+	testutil.AssertEqual(t, p.Relation, "users")
+	testutil.AssertEqual(t, p.Index, "users_email")
+}
+
+func TestParseSQLiteEmpty(t *testing.T) {
+	t.Parallel()
+	_, err := ParseSQLite(nil)
+	testutil.AssertError(t, err)
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+	p, err := ParsePostgres([]byte(pgPlan))
+	testutil.AssertNoError(t, err)
+	var relations []string
+	p.Walk(func(n *Plan) {
+		if n.Relation != "" {
+			relations = append(relations, n.Relation)
+		}
+	})
+	testutil.AssertEqual(t, len(relations), 2)
+	testutil.AssertEqual(t, relations[0], "posts")
+	testutil.AssertEqual(t, relations[1], "users")
+}
@@ -0,0 +1,237 @@
+package explain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// mysqlOperations maps the wrapper objects of MySQL FORMAT=JSON output to
+// plan node types, in the order their keys are visited.
+var mysqlOperations = []struct{ key, nodeType string }{
+	{"ordering_operation", "Sort"},
+	{"grouping_operation", "Group"},
+	{"duplicates_removal", "Distinct"},
+	{"windowing", "Window"},
+	{"buffer_result", "Buffer"},
+}
+
+// mysqlAccessTypes names MySQL access types the way its tree output does.
+var mysqlAccessTypes = map[string]string{
+	"ALL":             "Table scan",
+	"index":           "Index scan",
+	"range":           "Index range scan",
+	"ref":             "Index lookup",
+	"eq_ref":          "Single-row index lookup",
+	"const":           "Constant row",
+	"system":          "Constant row",
+	"fulltext":        "Full-text index search",
+	"ref_or_null":     "Index lookup or null",
+	"index_merge":     "Index merge",
+	"unique_subquery": "Unique subquery lookup",
+	"index_subquery":  "Index subquery lookup",
+}
+
+// ParseMySQL parses the output of EXPLAIN FORMAT=JSON.
+func ParseMySQL(data []byte) (*Plan, error) {
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("explain: parsing MySQL plan: %w", err)
+	}
+	block, ok := out["query_block"].(map[string]any)
+	if !ok {
+		return nil, errors.New("explain: MySQL plan has no query_block")
+	}
+	return mysqlQueryBlock(block), nil
+}
+
+// mysqlQueryBlock converts a query_block object.
+func mysqlQueryBlock(block map[string]any) *Plan {
+	p := &Plan{NodeType: "Query block"}
+	if ci, ok := block["cost_info"].(map[string]any); ok {
+		p.Cost = number(ci["query_cost"])
+	}
+	if u, ok := block["union_result"].(map[string]any); ok {
+		p.Children = append(p.Children, mysqlUnion(u))
+		return p
+	}
+	p.Children = mysqlChildren(block)
+	return p
+}
+
+// mysqlChildren collects the plan nodes nested directly in obj.
+func mysqlChildren(obj map[string]any) []*Plan {
+	var plans []*Plan
+	for _, op := range mysqlOperations {
+		if inner, ok := obj[op.key].(map[string]any); ok {
+			p := &Plan{NodeType: op.nodeType, Children: mysqlChildren(inner)}
+			if inner["using_filesort"] == true {
+				p.Detail = "using filesort"
+			}
+			plans = append(plans, p)
+		}
+	}
+	if t, ok := obj["table"].(map[string]any); ok {
+		plans = append(plans, mysqlTable(t))
+	}
+	if loop, ok := obj["nested_loop"].([]any); ok {
+		p := &Plan{NodeType: "Nested loop"}
+		for _, item := range loop {
+			if m, ok := item.(map[string]any); ok {
+				p.Children = append(p.Children, mysqlChildren(m)...)
+			}
+		}
+		plans = append(plans, p)
+	}
+	for _, key := range []string{"attached_subqueries", "optimized_away_subqueries"} {
+		if subs, ok := obj[key].([]any); ok {
+			for _, item := range subs {
+				if m, ok := item.(map[string]any); ok {
+					if block, ok := m["query_block"].(map[string]any); ok {
+						sub := mysqlQueryBlock(block)
+						sub.NodeType = "Subquery"
+						plans = append(plans, sub)
+					}
+				}
+			}
+		}
+	}
+	return plans
+}
+
+func mysqlTable(t map[string]any) *Plan {
+	access, _ := t["access_type"].(string)
+	p := &Plan{NodeType: access}
+	if name, ok := mysqlAccessTypes[access]; ok {
+		p.NodeType = name
+	}
+	p.Relation, _ = t["table_name"].(string)
+	p.Index, _ = t["key"].(string)
+	p.EstimatedRows = number(t["rows_examined_per_scan"])
+	if ci, ok := t["cost_info"].(map[string]any); ok {
+		p.Cost = number(ci["prefix_cost"])
+		if p.Cost == 0 {
+			p.Cost = number(ci["read_cost"]) + number(ci["eval_cost"])
+		}
+	}
+	if cond, ok := t["attached_condition"].(string); ok {
+		p.Detail = "Filter: " + cond
+	}
+	if m, ok := t["materialized_from_subquery"].(map[string]any); ok {
+		if block, ok := m["query_block"].(map[string]any); ok {
+			p.Children = append(p.Children, mysqlQueryBlock(block))
+		}
+	}
+	p.Children = append(p.Children, mysqlChildren(t)...)
+	return p
+}
+
+func mysqlUnion(u map[string]any) *Plan {
+	p := &Plan{NodeType: "Union"}
+	if specs, ok := u["query_specifications"].([]any); ok {
+		for _, item := range specs {
+			if m, ok := item.(map[string]any); ok {
+				if block, ok := m["query_block"].(map[string]any); ok {
+					p.Children = append(p.Children, mysqlQueryBlock(block))
+				}
+			}
+		}
+	}
+	return p
+}
+
+// number reads a JSON number or a numeric string, as MySQL reports costs
+// as strings.
+func number(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
+
+var (
+	mysqlCostRe   = regexp.MustCompile(`\(cost=([\d.e+]+)(?:\.\.[\d.e+]+)? rows=([\d.e+]+)\)`)
+	mysqlActualRe = regexp.MustCompile(`\(actual time=[\d.]+\.\.[\d.]+ rows=([\d.e+]+) loops=\d+\)`)
+)
+
+// ParseMySQLTree parses the text tree produced by EXPLAIN ANALYZE or
+// EXPLAIN FORMAT=TREE.
+func ParseMySQLTree(text string) (*Plan, error) {
+	type frame struct {
+		indent int
+		plan   *Plan
+	}
+	var root *Plan
+	var stack []frame
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasPrefix(trimmed, "-> ") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		p := mysqlTreeLine(strings.TrimPrefix(trimmed, "-> "))
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			if root != nil {
+				return nil, errors.New("explain: MySQL plan tree has more than one root")
+			}
+			root = p
+		} else {
+			parent := stack[len(stack)-1].plan
+			parent.Children = append(parent.Children, p)
+		}
+		stack = append(stack, frame{indent, p})
+	}
+	if root == nil {
+		return nil, errors.New("explain: no plan nodes in MySQL tree output")
+	}
+	return root, nil
+}
+
+// mysqlTreeLine converts one "-> ..." line of tree output.
+func mysqlTreeLine(line string) *Plan {
+	p := &Plan{}
+	if m := mysqlCostRe.FindStringSubmatch(line); m != nil {
+		p.Cost, _ = strconv.ParseFloat(m[1], 64)
+		p.EstimatedRows, _ = strconv.ParseFloat(m[2], 64)
+	}
+	if m := mysqlActualRe.FindStringSubmatch(line); m != nil {
+		p.ActualRows, _ = strconv.ParseFloat(m[1], 64)
+		p.Analyzed = true
+	}
+
+	desc := line
+	if i := strings.Index(desc, "  ("); i >= 0 {
+		desc = desc[:i]
+	}
+	colon := strings.Index(desc, ": ")
+	on := strings.Index(desc, " on ")
+	switch {
+	case on >= 0 && (colon < 0 || on < colon):
+		p.NodeType = desc[:on]
+		rest := strings.Fields(desc[on+len(" on "):])
+		if len(rest) > 0 {
+			p.Relation, rest = rest[0], rest[1:]
+		}
+		if len(rest) > 1 && rest[0] == "using" {
+			p.Index, rest = rest[1], rest[2:]
+		}
+		p.Detail = strings.Join(rest, " ")
+	case colon >= 0:
+		p.NodeType = desc[:colon]
+		p.Detail = desc[colon+2:]
+	default:
+		p.NodeType = desc
+	}
+	return p
+}
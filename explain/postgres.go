@@ -0,0 +1,81 @@
+package explain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// pgNode mirrors the fields of a PostgreSQL FORMAT JSON plan node that
+// Plan keeps.
+type pgNode struct {
+	NodeType     string    `json:"Node Type"`
+	RelationName string    `json:"Relation Name"`
+	IndexName    string    `json:"Index Name"`
+	JoinType     string    `json:"Join Type"`
+	Strategy     string    `json:"Strategy"`
+	Filter       string    `json:"Filter"`
+	IndexCond    string    `json:"Index Cond"`
+	HashCond     string    `json:"Hash Cond"`
+	SortKey      []string  `json:"Sort Key"`
+	PlanRows     float64   `json:"Plan Rows"`
+	TotalCost    float64   `json:"Total Cost"`
+	ActualRows   *float64  `json:"Actual Rows"`
+	Plans        []*pgNode `json:"Plans"`
+}
+
+// ParsePostgres parses the output of EXPLAIN (FORMAT JSON), with or
+// without ANALYZE.
+func ParsePostgres(data []byte) (*Plan, error) {
+	var out []struct {
+		Plan *pgNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("explain: parsing PostgreSQL plan: %w", err)
+	}
+	if len(out) == 0 || out[0].Plan == nil {
+		return nil, errors.New("explain: PostgreSQL plan has no Plan node")
+	}
+	return out[0].Plan.toPlan(), nil
+}
+
+func (n *pgNode) toPlan() *Plan {
+	p := &Plan{
+		NodeType:      n.NodeType,
+		Relation:      n.RelationName,
+		Index:         n.IndexName,
+		EstimatedRows: n.PlanRows,
+		Cost:          n.TotalCost,
+	}
+	if n.JoinType != "" && n.JoinType != "Inner" {
+		p.NodeType += " (" + n.JoinType + ")"
+	}
+	if n.Strategy != "" && n.Strategy != "Plain" {
+		p.NodeType = n.Strategy + " " + p.NodeType
+	}
+	if n.ActualRows != nil {
+		p.ActualRows = *n.ActualRows
+		p.Analyzed = true
+	}
+
+	var detail []string
+	for _, d := range []struct{ label, value string }{
+		{"Index Cond", n.IndexCond},
+		{"Hash Cond", n.HashCond},
+		{"Filter", n.Filter},
+	} {
+		if d.value != "" {
+			detail = append(detail, d.label+": "+d.value)
+		}
+	}
+	if len(n.SortKey) > 0 {
+		detail = append(detail, "Sort Key: "+strings.Join(n.SortKey, ", "))
+	}
+	p.Detail = strings.Join(detail, "; ")
+
+	for _, c := range n.Plans {
+		p.Children = append(p.Children, c.toPlan())
+	}
+	return p
+}
@@ -0,0 +1,64 @@
+package explain
+
+import (
+	"errors"
+	"strings"
+)
+
+// SQLiteRow is one row of EXPLAIN QUERY PLAN output.
+type SQLiteRow struct {
+	ID     int
+	Parent int
+	Detail string
+}
+
+// ParseSQLite builds a plan from the rows of EXPLAIN QUERY PLAN. SQLite
+// reports neither costs nor row estimates. When the plan has several
+// top-level steps they are grouped under a "QUERY PLAN" node.
+func ParseSQLite(rows []SQLiteRow) (*Plan, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("explain: empty SQLite query plan")
+	}
+	root := &Plan{NodeType: "QUERY PLAN"}
+	byID := map[int]*Plan{0: root}
+	for _, row := range rows {
+		p := sqliteDetail(row.Detail)
+		parent, ok := byID[row.Parent]
+		if !ok {
+			parent = root
+		}
+		parent.Children = append(parent.Children, p)
+		byID[row.ID] = p
+	}
+	if len(root.Children) == 1 {
+		return root.Children[0], nil
+	}
+	return root, nil
+}
+
+// sqliteDetail converts a detail string such as
+// "SEARCH users USING INDEX users_email (email=?)".
+func sqliteDetail(detail string) *Plan {
+	p := &Plan{NodeType: detail, Detail: detail}
+	words := strings.Fields(detail)
+	if len(words) < 2 || (words[0] != "SCAN" && words[0] != "SEARCH") {
+		return p
+	}
+	p.NodeType = words[0]
+	rest := words[1:]
+	if rest[0] == "TABLE" && len(rest) > 1 { // SQLite before 3.36
+		rest = rest[1:]
+	}
+	p.Relation = rest[0]
+	for i := 1; i < len(rest); i++ {
+		switch {
+		case rest[i] == "INDEX" && i+1 < len(rest):
+			p.Index = rest[i+1]
+			return p
+		case rest[i] == "INTEGER" && i+2 < len(rest) && rest[i+1] == "PRIMARY":
+			p.Index = "INTEGER PRIMARY KEY"
+			return p
+		}
+	}
+	return p
+}
@@ -28,6 +28,44 @@ type UpdateManager = managers.UpdateManager
 // DeleteManager provides a fluent API for building DELETE queries.
 type DeleteManager = managers.DeleteManager
 
+// CreateTableManager provides a fluent API for building CREATE TABLE statements.
+type CreateTableManager = managers.CreateTableManager
+
+// AlterTableManager provides a fluent API for building ALTER TABLE statements.
+type AlterTableManager = managers.AlterTableManager
+
+// CreateIndexManager provides a fluent API for building CREATE INDEX statements.
+type CreateIndexManager = managers.CreateIndexManager
+
+// DropManager provides a fluent API for building DROP TABLE and DROP INDEX statements.
+type DropManager = managers.DropManager
+
+// CreateViewManager builds CREATE [MATERIALIZED] VIEW statements from a SelectManager.
+type CreateViewManager = managers.CreateViewManager
+
+// CreateTableAsManager builds CREATE TABLE ... AS statements from a SelectManager.
+type CreateTableAsManager = managers.CreateTableAsManager
+
+// RefreshMaterializedViewManager builds REFRESH MATERIALIZED VIEW statements.
+type RefreshMaterializedViewManager = managers.RefreshMaterializedViewManager
+
+// ExplainManager builds EXPLAIN statements around another manager's statement.
+type ExplainManager = managers.ExplainManager
+
+// Bind-parameter limits of each engine, for InsertManager.Batches.
+const (
+	MaxParamsPostgres = managers.MaxParamsPostgres
+	MaxParamsMySQL    = managers.MaxParamsMySQL
+	MaxParamsSQLite   = managers.MaxParamsSQLite
+	MaxParamsMSSQL    = managers.MaxParamsMSSQL
+)
+
+// SetStructOptions controls UpdateManager.SetStruct.
+type SetStructOptions = managers.SetStructOptions
+
+// Template is a compiled query whose named parameters are bound per call.
+type Template = managers.Template
+
 // --- Manager Constructors ---
 
 // NewSelect creates a new SelectManager with the given table as FROM.
@@ -50,6 +88,46 @@ func NewDelete(from nodes.Node) *managers.DeleteManager {
 	return managers.NewDeleteManager(from)
 }
 
+// NewCreateTable creates a new CreateTableManager for the given table.
+func NewCreateTable(table *nodes.Table) *managers.CreateTableManager {
+	return managers.NewCreateTableManager(table)
+}
+
+// NewAlterTable creates a new AlterTableManager for the given table.
+func NewAlterTable(table *nodes.Table) *managers.AlterTableManager {
+	return managers.NewAlterTableManager(table)
+}
+
+// NewCreateIndex creates a new CreateIndexManager for index name on table.
+func NewCreateIndex(name string, table *nodes.Table) *managers.CreateIndexManager {
+	return managers.NewCreateIndexManager(name, table)
+}
+
+// NewDropTable creates a DropManager for DROP TABLE.
+func NewDropTable(table *nodes.Table) *managers.DropManager {
+	return managers.NewDropTableManager(table)
+}
+
+// NewDropIndex creates a DropManager for DROP INDEX.
+func NewDropIndex(name string) *managers.DropManager {
+	return managers.NewDropIndexManager(name)
+}
+
+// NewCreateView creates a CreateViewManager for a view defined by query.
+func NewCreateView(name string, query *managers.SelectManager) *managers.CreateViewManager {
+	return managers.NewCreateViewManager(name, query)
+}
+
+// NewCreateTableAs creates a CreateTableAsManager filling table from query.
+func NewCreateTableAs(table *nodes.Table, query *managers.SelectManager) *managers.CreateTableAsManager {
+	return managers.NewCreateTableAsManager(table, query)
+}
+
+// NewRefreshMaterializedView creates a manager refreshing the named materialized view.
+func NewRefreshMaterializedView(name string) *managers.RefreshMaterializedViewManager {
+	return managers.NewRefreshMaterializedViewManager(name)
+}
+
 // --- Core Node Types ---
 
 // Table represents a SQL table reference.
@@ -61,6 +139,15 @@ type Attribute = nodes.Attribute
 // Node is the base interface all AST nodes implement.
 type Node = nodes.Node
 
+// ExplainOptions selects the EXPLAIN options for Explain on a manager.
+type ExplainOptions = nodes.ExplainOptions
+
+// EXPLAIN output formats.
+const (
+	ExplainText = nodes.ExplainText
+	ExplainJSON = nodes.ExplainJSON
+)
+
 // --- Common Node Constructors ---
 
 // NewTable creates a new table reference.
@@ -78,11 +165,31 @@ func BindParam(value any) *nodes.BindParamNode {
 	return nodes.NewBindParam(value)
 }
 
+// Named creates a named placeholder whose value is supplied when a
+// compiled Template is bound.
+func Named(name string) *nodes.NamedParamNode {
+	return nodes.Named(name)
+}
+
 // Star creates an unqualified star (*) for SELECT *.
 func Star() *nodes.StarNode {
 	return nodes.Star()
 }
 
+// --- Query Comparison ---
+
+// Equal reports whether two trees are structurally identical, values included.
+func Equal(a, b nodes.Node) bool {
+	return nodes.Equal(a, b)
+}
+
+// Fingerprint returns a stable hash and the dialect-independent normalised
+// SQL of a tree, ignoring values and the length of IN lists. It returns an
+// error if the tree cannot be rendered.
+func Fingerprint(n nodes.Node) (uint64, string, error) {
+	return visitors.Fingerprint(n)
+}
+
 // --- Aggregate Functions ---
 
 // Count creates a COUNT(expr) aggregate.
@@ -126,6 +233,25 @@ type PostgresVisitor = visitors.PostgresVisitor
 // MySQLVisitor generates MySQL-compatible SQL.
 type MySQLVisitor = visitors.MySQLVisitor
 
+// MSSQLVisitor generates SQL Server-compatible SQL.
+type MSSQLVisitor = visitors.MSSQLVisitor
+
+// OracleVisitor generates Oracle-compatible SQL.
+type OracleVisitor = visitors.OracleVisitor
+
+// ClickHouseVisitor generates ClickHouse-compatible SQL.
+type ClickHouseVisitor = visitors.ClickHouseVisitor
+
+// DuckDBVisitor generates DuckDB-compatible SQL.
+type DuckDBVisitor = visitors.DuckDBVisitor
+
+// OutParam is the bind parameter the Oracle visitor records for each
+// column of a RETURNING ... INTO clause.
+type OutParam = visitors.OutParam
+
+// Dialect is an immutable, goroutine-safe renderer obtained from a visitor.
+type Dialect = visitors.Dialect
+
 // --- Visitor Constructors ---
 
 // NewSQLiteVisitor creates a new SQLite visitor.
@@ -143,6 +269,26 @@ func NewMySQLVisitor(opts ...visitors.Option) *visitors.MySQLVisitor {
 	return visitors.NewMySQLVisitor(opts...)
 }
 
+// NewMSSQLVisitor creates a new SQL Server visitor.
+func NewMSSQLVisitor(opts ...visitors.Option) *visitors.MSSQLVisitor {
+	return visitors.NewMSSQLVisitor(opts...)
+}
+
+// NewOracleVisitor creates a new Oracle visitor.
+func NewOracleVisitor(opts ...visitors.Option) *visitors.OracleVisitor {
+	return visitors.NewOracleVisitor(opts...)
+}
+
+// NewClickHouseVisitor creates a new ClickHouse visitor.
+func NewClickHouseVisitor(opts ...visitors.Option) *visitors.ClickHouseVisitor {
+	return visitors.NewClickHouseVisitor(opts...)
+}
+
+// NewDuckDBVisitor creates a new DuckDB visitor.
+func NewDuckDBVisitor(opts ...visitors.Option) *visitors.DuckDBVisitor {
+	return visitors.NewDuckDBVisitor(opts...)
+}
+
 // --- Visitor Options ---
 
 // WithParams enables parameterisation mode for visitors.
@@ -160,3 +306,29 @@ func WithParams() visitors.Option {
 func WithoutParams() visitors.Option {
 	return visitors.WithoutParams()
 }
+
+// QuotePolicy controls when visitors quote identifiers.
+type QuotePolicy = visitors.QuotePolicy
+
+// Identifier quoting policies.
+const (
+	QuoteAlways     = visitors.QuoteAlways
+	QuoteWhenNeeded = visitors.QuoteWhenNeeded
+	QuoteNever      = visitors.QuoteNever
+)
+
+// WithQuotePolicy sets when identifiers are quoted (default QuoteAlways).
+func WithQuotePolicy(p QuotePolicy) visitors.Option {
+	return visitors.WithQuotePolicy(p)
+}
+
+// WithLowerCaseIdentifiers folds identifiers to lower case before quoting.
+func WithLowerCaseIdentifiers() visitors.Option {
+	return visitors.WithLowerCaseIdentifiers()
+}
+
+// WithIdentifierLengthCheck panics on identifiers longer than the dialect
+// allows (63 bytes in PostgreSQL, 64 in MySQL).
+func WithIdentifierLengthCheck() visitors.Option {
+	return visitors.WithIdentifierLengthCheck()
+}
@@ -0,0 +1,118 @@
+// Package dbtag reads `db` struct tags into per-type column metadata,
+// shared by the struct-driven managers and the exec package.
+//
+// A tag names the column and may add options after a comma:
+//
+//	ID        int64     `db:"id,pk,omitempty"`
+//	Email     string    `db:"email"`
+//	CreatedAt time.Time `db:"created_at,readonly"`
+//	Scratch   string    `db:"-"`
+//
+// Fields without a db tag are ignored, except untagged embedded structs,
+// whose fields are read as if declared in the outer struct.
+package dbtag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Field describes one tagged struct field.
+type Field struct {
+	// Column is the column name from the tag.
+	Column string
+
+	// Index is the field's index sequence for reflect.Value.FieldByIndex.
+	Index []int
+
+	// OmitEmpty skips the field when it holds its zero value.
+	OmitEmpty bool
+
+	// ReadOnly fields are scanned but never written.
+	ReadOnly bool
+
+	// PrimaryKey fields identify the row in UPDATE statements.
+	PrimaryKey bool
+}
+
+// Struct is the column metadata of a struct type.
+type Struct struct {
+	Type   reflect.Type
+	Fields []*Field
+
+	byColumn map[string]*Field
+}
+
+// Field returns the field mapped to column, or nil.
+func (s *Struct) Field(column string) *Field {
+	return s.byColumn[column]
+}
+
+var cache sync.Map // reflect.Type -> *Struct
+
+// Of returns the metadata for t, which must be a struct or a pointer to
+// one. Results are cached per type.
+func Of(t reflect.Type) (*Struct, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if cached, ok := cache.Load(t); ok {
+		return cached.(*Struct), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dbtag: %s is not a struct", t)
+	}
+	s := &Struct{Type: t, byColumn: make(map[string]*Field)}
+	if err := s.collect(t, nil); err != nil {
+		return nil, err
+	}
+	cached, _ := cache.LoadOrStore(t, s)
+	return cached.(*Struct), nil
+}
+
+func (s *Struct) collect(t reflect.Type, prefix []int) error {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag, tagged := sf.Tag.Lookup("db")
+		index := append(append([]int(nil), prefix...), i)
+
+		if !tagged {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				if err := s.collect(sf.Type, index); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			return fmt.Errorf("dbtag: %s.%s has an empty column name", s.Type, sf.Name)
+		}
+		f := &Field{Column: name, Index: index}
+		for opt := range strings.SplitSeq(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "":
+			case "omitempty":
+				f.OmitEmpty = true
+			case "readonly":
+				f.ReadOnly = true
+			case "pk":
+				f.PrimaryKey = true
+			default:
+				return fmt.Errorf("dbtag: %s.%s has unknown option %q", s.Type, sf.Name, opt)
+			}
+		}
+		if _, dup := s.byColumn[name]; dup {
+			return fmt.Errorf("dbtag: %s maps column %q more than once", s.Type, name)
+		}
+		s.Fields = append(s.Fields, f)
+		s.byColumn[name] = f
+	}
+	return nil
+}
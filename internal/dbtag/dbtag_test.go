@@ -0,0 +1,76 @@
+package dbtag
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Audit struct {
+	CreatedAt string `db:"created_at,readonly"`
+}
+
+type user struct {
+	Audit
+	ID      int64  `db:"id,pk,omitempty"`
+	Name    string `db:"name"`
+	Ignored string `db:"-"`
+	Plain   string
+}
+
+func TestOf(t *testing.T) {
+	t.Parallel()
+	s, err := Of(reflect.TypeFor[*user]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cols []string
+	for _, f := range s.Fields {
+		cols = append(cols, f.Column)
+	}
+	if want := []string{"created_at", "id", "name"}; !reflect.DeepEqual(cols, want) {
+		t.Fatalf("columns = %v, want %v", cols, want)
+	}
+	id := s.Field("id")
+	if !id.PrimaryKey || !id.OmitEmpty || id.ReadOnly {
+		t.Errorf("id options = %+v", id)
+	}
+	if created := s.Field("created_at"); !created.ReadOnly || !reflect.DeepEqual(created.Index, []int{0, 0}) {
+		t.Errorf("created_at = %+v", created)
+	}
+	if s.Field("Plain") != nil || s.Field("Ignored") != nil {
+		t.Error("untagged and skipped fields should not be mapped")
+	}
+}
+
+func TestOfCaches(t *testing.T) {
+	t.Parallel()
+	a, _ := Of(reflect.TypeFor[user]())
+	b, _ := Of(reflect.TypeFor[*user]())
+	if a != b {
+		t.Error("expected the same metadata for T and *T")
+	}
+}
+
+func TestOfErrors(t *testing.T) {
+	t.Parallel()
+	type dup struct {
+		A string `db:"x"`
+		B string `db:"x"`
+	}
+	type badOpt struct {
+		A string `db:"a,sometimes"`
+	}
+	type empty struct {
+		A string `db:",omitempty"`
+	}
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[int](),
+		reflect.TypeFor[dup](),
+		reflect.TypeFor[badOpt](),
+		reflect.TypeFor[empty](),
+	} {
+		if _, err := Of(typ); err == nil {
+			t.Errorf("%s: expected error", typ)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package drivers identifies the database engine behind a database/sql
+// driver without importing any driver package.
+package drivers
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Engine returns the engine behind d, or "" for an unknown driver:
+//
+//   - "postgres" for pgx and lib/pq
+//   - "mysql" for go-sql-driver/mysql
+//   - "sqlite" for the modernc and mattn SQLite drivers
+//   - "mssql" for go-mssqldb
+//   - "oracle" for go-ora and godror
+//   - "clickhouse" for clickhouse-go
+//   - "duckdb" for go-duckdb
+func Engine(d driver.Driver) string {
+	return engineOf(fmt.Sprintf("%T", d))
+}
+
+// engineOf maps the dynamic type name of a driver to its engine.
+func engineOf(typeName string) string {
+	switch typeName {
+	case "*stdlib.Driver", "*pq.Driver":
+		return "postgres"
+	case "*mysql.MySQLDriver":
+		return "mysql"
+	case "*sqlite.Driver", "*sqlite3.SQLiteDriver":
+		return "sqlite"
+	case "*mssql.Driver":
+		return "mssql"
+	case "*go_ora.OracleDriver", "*godror.drv":
+		return "oracle"
+	case "*clickhouse.stdDriver":
+		return "clickhouse"
+	case "duckdb.Driver", "*duckdb.Driver":
+		return "duckdb"
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+)
+
+func TestEngineOf(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"*stdlib.Driver":        "postgres",
+		"*pq.Driver":            "postgres",
+		"*mysql.MySQLDriver":    "mysql",
+		"*sqlite.Driver":        "sqlite",
+		"*sqlite3.SQLiteDriver": "sqlite",
+		"*mssql.Driver":         "mssql",
+		"*go_ora.OracleDriver":  "oracle",
+		"*godror.drv":           "oracle",
+		"*clickhouse.stdDriver": "clickhouse",
+		"duckdb.Driver":         "duckdb",
+		"*exec.unknownDriver":   "",
+	}
+	for typeName, want := range tests {
+		testutil.AssertEqual(t, engineOf(typeName), want)
+	}
+}
@@ -0,0 +1,55 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// AlterTableManager provides a fluent API for building ALTER TABLE
+// statements. Actions are rendered in the order they are added; dialects
+// that cannot combine them emit one statement per action.
+type AlterTableManager struct {
+	Statement *nodes.AlterTableStatement
+}
+
+// NewAlterTableManager creates a new AlterTableManager for the given table.
+func NewAlterTableManager(table *nodes.Table) *AlterTableManager {
+	return &AlterTableManager{
+		Statement: &nodes.AlterTableStatement{Table: table},
+	}
+}
+
+// AddColumn adds an ADD COLUMN action.
+func (m *AlterTableManager) AddColumn(name, typ string, opts ...nodes.ColumnOption) *AlterTableManager {
+	return m.action(&nodes.AlterAction{
+		Kind:   nodes.AlterAddColumn,
+		Column: nodes.NewColumnDef(name, typ, opts...),
+	})
+}
+
+// DropColumn adds a DROP COLUMN action.
+func (m *AlterTableManager) DropColumn(name string) *AlterTableManager {
+	return m.action(&nodes.AlterAction{Kind: nodes.AlterDropColumn, Name: name})
+}
+
+// RenameColumn adds a RENAME COLUMN from TO to action.
+func (m *AlterTableManager) RenameColumn(from, to string) *AlterTableManager {
+	return m.action(&nodes.AlterAction{Kind: nodes.AlterRenameColumn, Name: from, NewName: to})
+}
+
+// AddConstraint adds an ADD constraint action.
+func (m *AlterTableManager) AddConstraint(c *nodes.TableConstraint) *AlterTableManager {
+	return m.action(&nodes.AlterAction{Kind: nodes.AlterAddConstraint, Constraint: c})
+}
+
+func (m *AlterTableManager) action(a *nodes.AlterAction) *AlterTableManager {
+	m.Statement.Actions = append(m.Statement.Actions, a)
+	return m
+}
+
+// ToSQL generates the ALTER TABLE statement(s). Features the dialect cannot
+// express are reported as a *nodes.UnsupportedError.
+func (m *AlterTableManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.statement)
+}
+
+func (m *AlterTableManager) statement() (nodes.Node, error) {
+	return m.Statement, nil
+}
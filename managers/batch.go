@@ -0,0 +1,166 @@
+package managers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// Bind-parameter limits of each engine, for InsertManager.Batches. MySQL's
+// max_allowed_packet limits statement size in bytes and is not covered.
+const (
+	MaxParamsPostgres = 65535
+	MaxParamsMySQL    = 65535
+	MaxParamsSQLite   = 32766 // SQLITE_MAX_VARIABLE_NUMBER since SQLite 3.32
+	MaxParamsMSSQL    = 2098  // 2100 per request, less sp_executesql's own two
+)
+
+// Batches splits a multi-row INSERT into several statements that each bind
+// at most maxParams parameters. Every batch keeps the ON CONFLICT and
+// RETURNING clauses and the registered transformers, which run when each
+// batch is rendered. Parameters added by transformers are measured on the
+// first row and counted for every row. The manager itself is not changed.
+func (m *InsertManager) Batches(maxParams int) ([]*InsertManager, error) {
+	if m.Statement.Select != nil {
+		return nil, errors.New("gosbee: Batches: INSERT ... SELECT cannot be batched")
+	}
+	if len(m.Statement.Values) == 0 {
+		return nil, errors.New("gosbee: Batches: no rows")
+	}
+	if maxParams <= 0 {
+		return nil, fmt.Errorf("gosbee: Batches: invalid parameter limit %d", maxParams)
+	}
+
+	fixed, extra, err := m.batchOverhead()
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []*InsertManager
+	start, used := 0, fixed
+	for i, row := range m.Statement.Values {
+		cost := bindCount(row) + extra
+		if fixed+cost > maxParams {
+			return nil, fmt.Errorf("gosbee: Batches: row %d needs %d parameters, over the limit of %d", i, fixed+cost, maxParams)
+		}
+		if used+cost > maxParams {
+			batches = append(batches, m.batch(start, i))
+			start, used = i, fixed
+		}
+		used += cost
+	}
+	return append(batches, m.batch(start, len(m.Statement.Values))), nil
+}
+
+// batchOverhead runs the transformers on a single-row copy of the
+// statement and reports the parameters bound outside the VALUES rows and
+// the parameters the transformers add to each row.
+func (m *InsertManager) batchOverhead() (fixed, extra int, err error) {
+	probe := m.batch(0, 1)
+	n, err := probe.transformed()
+	if err != nil {
+		return 0, 0, err
+	}
+	stmt, ok := n.(*nodes.InsertStatement)
+	if !ok || len(stmt.Values) != 1 {
+		return 0, 0, errors.New("gosbee: Batches: transformers changed the shape of the statement")
+	}
+	rowParams := bindCount(stmt.Values[0])
+	extra = rowParams - bindCount(m.Statement.Values[0])
+	return bindCount(stmt) - rowParams, max(extra, 0), nil
+}
+
+// batch returns a manager for rows [from, to) of the statement.
+func (m *InsertManager) batch(from, to int) *InsertManager {
+	stmt := m.cloneStatement()
+	stmt.Values = stmt.Values[from:to]
+	b := &InsertManager{Statement: stmt}
+	b.transformers = append(b.transformers, m.transformers...)
+	return b
+}
+
+var (
+	literalNodeType    = reflect.TypeFor[nodes.LiteralNode]()
+	castedNodeType     = reflect.TypeFor[nodes.CastedNode]()
+	bindParamNodeType  = reflect.TypeFor[nodes.BindParamNode]()
+	namedParamNodeType = reflect.TypeFor[nodes.NamedParamNode]()
+	sqlLiteralType     = reflect.TypeFor[nodes.SqlLiteral]()
+	nodeType           = reflect.TypeFor[nodes.Node]()
+)
+
+// bindCount returns the number of parameters v binds when rendered:
+// non-nil literals and casts, bind and named parameters, and the
+// arguments of SQL fragments anywhere in the tree. A named parameter counts once per
+// occurrence, although numbered placeholders reuse a repeated name.
+func bindCount(v any) int {
+	return countBinds(reflect.ValueOf(v), make(map[uintptr]bool))
+}
+
+func countBinds(v reflect.Value, visited map[uintptr]bool) int {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || visited[v.Pointer()] {
+			return 0
+		}
+		// Track only the current path: a node shared between rows or
+		// columns binds once per occurrence.
+		visited[v.Pointer()] = true
+		defer delete(visited, v.Pointer())
+		return countBinds(v.Elem(), visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return countBinds(v.Elem(), visited)
+	case reflect.Struct:
+		switch v.Type() {
+		case literalNodeType, castedNodeType:
+			// Both bind their Value as a single parameter.
+			if v.FieldByName("Value").IsNil() {
+				return 0
+			}
+			return 1
+		case bindParamNodeType, namedParamNodeType:
+			return 1
+		case sqlLiteralType:
+			return fragmentBinds(v, visited)
+		}
+		n := 0
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				n += countBinds(v.Field(i), visited)
+			}
+		}
+		return n
+	case reflect.Slice, reflect.Array:
+		n := 0
+		for i := range v.Len() {
+			n += countBinds(v.Index(i), visited)
+		}
+		return n
+	}
+	return 0
+}
+
+// fragmentBinds counts the parameters of a SqlLiteral. A raw literal binds
+// every argument. A fragment renders node arguments in place and binds the
+// other non-nil ones; nil renders as NULL.
+func fragmentBinds(v reflect.Value, visited map[uintptr]bool) int {
+	binds := v.FieldByName("Binds")
+	if !v.FieldByName("Placeholders").Bool() {
+		return binds.Len()
+	}
+	n := 0
+	for i := range binds.Len() {
+		switch b := binds.Index(i); {
+		case b.IsNil():
+		case b.Elem().Type().Implements(nodeType):
+			n += countBinds(b, visited)
+		default:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,149 @@
+package managers
+
+import (
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// tenantColumn adds a tenant_id column and value to every INSERT row.
+type tenantColumn struct {
+	plugins.BaseTransformer
+}
+
+func (tenantColumn) TransformInsert(stmt *nodes.InsertStatement) (*nodes.InsertStatement, error) {
+	stmt.Columns = append(stmt.Columns, nodes.NewAttribute(stmt.Into, "tenant_id"))
+	for i, row := range stmt.Values {
+		stmt.Values[i] = append(row, nodes.Literal(42))
+	}
+	return stmt, nil
+}
+
+func fiveRowInsert() *InsertManager {
+	users := nodes.NewTable("users")
+	m := NewInsertManager(users).Columns(users.Col("name"), users.Col("email"))
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		m.Values(name, name+"@example.com")
+	}
+	return m
+}
+
+func TestBatchesSplitsRows(t *testing.T) {
+	t.Parallel()
+	m := fiveRowInsert()
+	batches, err := m.Batches(4)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(batches), 3)
+
+	sql, params, err := batches[2].ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `INSERT INTO "users" ("name", "email") VALUES ($1, $2)`)
+	testutil.AssertEqual(t, params[0], any("e"))
+	testutil.AssertEqual(t, len(m.Statement.Values), 5)
+}
+
+func TestBatchesKeepsClausesAndTransformers(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := fiveRowInsert()
+	m.Returning(users.Col("id"))
+	m.OnConflict(users.Col("email")).DoUpdate(&nodes.AssignmentNode{
+		Left:  users.Col("name"),
+		Right: nodes.Literal("dup"),
+	})
+	m.Use(tenantColumn{})
+
+	// Each row binds 3 parameters with the tenant column, plus 1 for the
+	// ON CONFLICT assignment: 2 rows per batch under a limit of 7.
+	batches, err := m.Batches(7)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(batches), 3)
+
+	for _, b := range batches {
+		_, params, err := b.ToSQL(visitors.NewPostgresVisitor())
+		testutil.AssertNoError(t, err)
+		if len(params) > 7 {
+			t.Errorf("batch binds %d parameters, over the limit", len(params))
+		}
+	}
+	sql, _, err := batches[0].ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `INSERT INTO "users" ("name", "email", "tenant_id") VALUES ($1, $2, $3), ($4, $5, $6) `+
+		`ON CONFLICT ("email") DO UPDATE SET "users"."name" = $7 RETURNING "users"."id"`)
+}
+
+func TestBatchesNullsDoNotCount(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewInsertManager(users).Columns(users.Col("name"), users.Col("email"))
+	m.Values("a", nil).Values("b", nil).Values("c", nil)
+	batches, err := m.Batches(3)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(batches), 1)
+}
+
+func TestBatchesCountsFragmentAndNamedParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	point, err := nodes.NewSqlFragment("point(?, ?, ?)", 1.5, 2.5, nodes.NewSqlLiteral("NULL"))
+	testutil.AssertNoError(t, err)
+	m := NewInsertManager(users).Columns(users.Col("name"), users.Col("location"), users.Col("org_id"))
+	for range 10 {
+		m.Values(nodes.NewBoundSqlLiteral("lower(?)", "A"), point, nodes.Named("org"))
+	}
+
+	// Each row binds 1 raw argument, 2 fragment arguments and 1 named
+	// parameter: 2 rows per batch under a limit of 8.
+	batches, err := m.Batches(8)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(batches), 5)
+	for _, b := range batches {
+		tpl, err := b.Compile(visitors.NewSQLiteVisitor())
+		testutil.AssertNoError(t, err)
+		_, params, err := tpl.Bind(map[string]any{"org": 7})
+		testutil.AssertNoError(t, err)
+		if len(params) > 8 {
+			t.Errorf("batch binds %d parameters, over the limit", len(params))
+		}
+	}
+}
+
+func TestBatchesCountsCastedValues(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewInsertManager(users).Columns(users.Col("id"), users.Col("rank"))
+	for i := range 6 {
+		m.Values(nodes.NewCasted(i, "int"), i)
+	}
+
+	batches, err := m.Batches(4)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(batches), 3)
+	for _, b := range batches {
+		_, params, err := b.ToSQL(visitors.NewPostgresVisitor())
+		testutil.AssertNoError(t, err)
+		if len(params) > 4 {
+			t.Errorf("batch binds %d parameters, over the limit", len(params))
+		}
+	}
+}
+
+func TestBatchesErrors(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+
+	_, err := fiveRowInsert().Batches(1)
+	testutil.AssertError(t, err)
+
+	_, err = fiveRowInsert().Batches(0)
+	testutil.AssertError(t, err)
+
+	_, err = NewInsertManager(users).Batches(10)
+	testutil.AssertError(t, err)
+
+	_, err = NewInsertManager(users).FromSelect(NewSelectManager(users)).Batches(10)
+	testutil.AssertError(t, err)
+}
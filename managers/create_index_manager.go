@@ -0,0 +1,59 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// CreateIndexManager provides a fluent API for building CREATE INDEX
+// statements.
+type CreateIndexManager struct {
+	Statement *nodes.CreateIndexStatement
+}
+
+// NewCreateIndexManager creates a new CreateIndexManager for index name on
+// table.
+func NewCreateIndexManager(name string, table *nodes.Table) *CreateIndexManager {
+	return &CreateIndexManager{
+		Statement: &nodes.CreateIndexStatement{Name: name, Table: table},
+	}
+}
+
+// On appends index keys: attributes, orderings such as col.Desc(), or
+// expressions.
+func (m *CreateIndexManager) On(cols ...nodes.Node) *CreateIndexManager {
+	m.Statement.Columns = append(m.Statement.Columns, cols...)
+	return m
+}
+
+// Unique makes the index UNIQUE.
+func (m *CreateIndexManager) Unique() *CreateIndexManager {
+	m.Statement.Unique = true
+	return m
+}
+
+// Concurrently builds the index without blocking writes: CONCURRENTLY in
+// PostgreSQL, ALGORITHM=INPLACE LOCK=NONE in MySQL.
+func (m *CreateIndexManager) Concurrently() *CreateIndexManager {
+	m.Statement.Concurrently = true
+	return m
+}
+
+// IfNotExists adds IF NOT EXISTS.
+func (m *CreateIndexManager) IfNotExists() *CreateIndexManager {
+	m.Statement.IfNotExists = true
+	return m
+}
+
+// Where appends conditions, making the index partial.
+func (m *CreateIndexManager) Where(conditions ...nodes.Node) *CreateIndexManager {
+	m.Statement.Wheres = append(m.Statement.Wheres, conditions...)
+	return m
+}
+
+// ToSQL generates the CREATE INDEX statement. Features the dialect cannot
+// express are reported as a *nodes.UnsupportedError.
+func (m *CreateIndexManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.statement)
+}
+
+func (m *CreateIndexManager) statement() (nodes.Node, error) {
+	return m.Statement, nil
+}
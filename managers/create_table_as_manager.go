@@ -0,0 +1,51 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// CreateTableAsManager provides a fluent API for building CREATE TABLE ...
+// AS <select> statements from a SelectManager.
+type CreateTableAsManager struct {
+	Statement *nodes.CreateTableAsStatement
+	Query     *SelectManager
+}
+
+// NewCreateTableAsManager creates a new CreateTableAsManager that fills
+// table from query. The query's transformers are applied when the
+// statement is rendered.
+func NewCreateTableAsManager(table *nodes.Table, query *SelectManager) *CreateTableAsManager {
+	return &CreateTableAsManager{
+		Statement: &nodes.CreateTableAsStatement{Table: table},
+		Query:     query,
+	}
+}
+
+// Columns sets the new table's column names (PostgreSQL).
+func (m *CreateTableAsManager) Columns(names ...string) *CreateTableAsManager {
+	m.Statement.Columns = names
+	return m
+}
+
+// IfNotExists adds IF NOT EXISTS.
+func (m *CreateTableAsManager) IfNotExists() *CreateTableAsManager {
+	m.Statement.IfNotExists = true
+	return m
+}
+
+// transformed applies the query's transformers and returns a copy of the
+// statement wrapping the result.
+func (m *CreateTableAsManager) transformed() (nodes.Node, error) {
+	q, err := m.Query.transformed()
+	if err != nil {
+		return nil, err
+	}
+	stmt := *m.Statement
+	stmt.Query = q
+	return &stmt, nil
+}
+
+// ToSQL applies the query's transformers and generates the statement.
+// Values in the query are written inline. Features the dialect cannot
+// express are reported as a *nodes.UnsupportedError.
+func (m *CreateTableAsManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.transformed)
+}
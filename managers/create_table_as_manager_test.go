@@ -0,0 +1,49 @@
+package managers
+
+import (
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+func TestCreateTableAsPostgres(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	snapshot := nodes.NewTable("users_2024")
+	q := NewSelectManager(users).
+		Select(users.Col("id"), users.Col("email")).
+		Where(users.Col("created_at").Lt("2025-01-01"))
+
+	sql, params, err := NewCreateTableAsManager(snapshot, q).
+		Columns("user_id", "email").
+		ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE TABLE "users_2024" ("user_id", "email") AS `+
+		`SELECT "users"."id", "users"."email" FROM "users" WHERE "users"."created_at" < '2025-01-01'`)
+	if len(params) != 0 {
+		t.Errorf("expected values inlined, got params %v", params)
+	}
+}
+
+func TestCreateTableAsMySQLRejectsColumnNames(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewCreateTableAsManager(nodes.NewTable("copy"), NewSelectManager(users))
+
+	sql, _, err := m.IfNotExists().ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "CREATE TABLE IF NOT EXISTS `copy` AS SELECT * FROM `users`")
+
+	_, _, err = m.Columns("a").ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertError(t, err)
+}
+
+func TestCreateTableAsNamedParamUnsupported(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	q := NewSelectManager(users).Where(users.Col("org").Eq(nodes.Named("org")))
+	_, _, err := NewCreateTableAsManager(nodes.NewTable("copy"), q).ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertError(t, err)
+}
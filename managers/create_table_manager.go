@@ -0,0 +1,64 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// CreateTableManager provides a fluent API for building CREATE TABLE
+// statements.
+type CreateTableManager struct {
+	Statement *nodes.CreateTableStatement
+}
+
+// NewCreateTableManager creates a new CreateTableManager for the given table.
+func NewCreateTableManager(table *nodes.Table) *CreateTableManager {
+	return &CreateTableManager{
+		Statement: &nodes.CreateTableStatement{Table: table},
+	}
+}
+
+// IfNotExists adds IF NOT EXISTS.
+func (m *CreateTableManager) IfNotExists() *CreateTableManager {
+	m.Statement.IfNotExists = true
+	return m
+}
+
+// Column appends a column definition.
+func (m *CreateTableManager) Column(name, typ string, opts ...nodes.ColumnOption) *CreateTableManager {
+	m.Statement.Columns = append(m.Statement.Columns, nodes.NewColumnDef(name, typ, opts...))
+	return m
+}
+
+// PrimaryKey adds a table-level PRIMARY KEY (cols) constraint.
+func (m *CreateTableManager) PrimaryKey(cols ...string) *CreateTableManager {
+	return m.Constraint(nodes.PrimaryKeyConstraint(cols...))
+}
+
+// Unique adds a table-level UNIQUE (cols) constraint.
+func (m *CreateTableManager) Unique(cols ...string) *CreateTableManager {
+	return m.Constraint(nodes.UniqueConstraint(cols...))
+}
+
+// Check adds a table-level CHECK (expr) constraint.
+func (m *CreateTableManager) Check(expr nodes.Node) *CreateTableManager {
+	return m.Constraint(nodes.CheckConstraint(expr))
+}
+
+// ForeignKey adds a FOREIGN KEY (cols) REFERENCES ref(refCols) constraint.
+func (m *CreateTableManager) ForeignKey(cols []string, ref *nodes.Table, refCols ...string) *CreateTableManager {
+	return m.Constraint(nodes.ForeignKeyConstraint(cols, ref, refCols...))
+}
+
+// Constraint appends table-level constraints.
+func (m *CreateTableManager) Constraint(constraints ...*nodes.TableConstraint) *CreateTableManager {
+	m.Statement.Constraints = append(m.Statement.Constraints, constraints...)
+	return m
+}
+
+// ToSQL generates the CREATE TABLE statement. Features the dialect cannot
+// express are reported as a *nodes.UnsupportedError.
+func (m *CreateTableManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.statement)
+}
+
+func (m *CreateTableManager) statement() (nodes.Node, error) {
+	return m.Statement, nil
+}
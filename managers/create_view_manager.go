@@ -0,0 +1,108 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// CreateViewManager provides a fluent API for building CREATE VIEW and
+// CREATE MATERIALIZED VIEW statements from a SelectManager.
+type CreateViewManager struct {
+	Statement *nodes.CreateViewStatement
+	Query     *SelectManager
+}
+
+// NewCreateViewManager creates a new CreateViewManager for a view named
+// name defined by query. The query's transformers are applied when the
+// statement is rendered.
+func NewCreateViewManager(name string, query *SelectManager) *CreateViewManager {
+	return &CreateViewManager{
+		Statement: &nodes.CreateViewStatement{Name: name},
+		Query:     query,
+	}
+}
+
+// Columns sets the view's output column names.
+func (m *CreateViewManager) Columns(names ...string) *CreateViewManager {
+	m.Statement.Columns = names
+	return m
+}
+
+// OrReplace adds OR REPLACE.
+func (m *CreateViewManager) OrReplace() *CreateViewManager {
+	m.Statement.OrReplace = true
+	return m
+}
+
+// IfNotExists adds IF NOT EXISTS (SQLite, or materialized views in
+// PostgreSQL).
+func (m *CreateViewManager) IfNotExists() *CreateViewManager {
+	m.Statement.IfNotExists = true
+	return m
+}
+
+// Materialized makes the view a PostgreSQL materialized view.
+func (m *CreateViewManager) Materialized() *CreateViewManager {
+	m.Statement.Materialized = true
+	return m
+}
+
+// WithNoData creates a materialized view without populating it.
+func (m *CreateViewManager) WithNoData() *CreateViewManager {
+	m.Statement.WithNoData = true
+	return m
+}
+
+// transformed applies the query's transformers and returns a copy of the
+// statement wrapping the result.
+func (m *CreateViewManager) transformed() (nodes.Node, error) {
+	q, err := m.Query.transformed()
+	if err != nil {
+		return nil, err
+	}
+	stmt := *m.Statement
+	stmt.Query = q
+	return &stmt, nil
+}
+
+// ToSQL applies the query's transformers and generates the statement.
+// Values in the query are written inline, since views cannot take bind
+// parameters. Features the dialect cannot express are reported as a
+// *nodes.UnsupportedError.
+func (m *CreateViewManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.transformed)
+}
+
+// RefreshMaterializedViewManager provides a fluent API for building
+// REFRESH MATERIALIZED VIEW statements.
+type RefreshMaterializedViewManager struct {
+	Statement *nodes.RefreshMaterializedViewStatement
+}
+
+// NewRefreshMaterializedViewManager creates a manager refreshing the named
+// materialized view.
+func NewRefreshMaterializedViewManager(name string) *RefreshMaterializedViewManager {
+	return &RefreshMaterializedViewManager{
+		Statement: &nodes.RefreshMaterializedViewStatement{Name: name},
+	}
+}
+
+// Concurrently refreshes without locking out readers. The view needs a
+// unique index.
+func (m *RefreshMaterializedViewManager) Concurrently() *RefreshMaterializedViewManager {
+	m.Statement.Concurrently = true
+	return m
+}
+
+// WithNoData empties the view and leaves it unscannable.
+func (m *RefreshMaterializedViewManager) WithNoData() *RefreshMaterializedViewManager {
+	m.Statement.WithNoData = true
+	return m
+}
+
+// ToSQL generates the REFRESH statement. Dialects without materialized
+// views report a *nodes.UnsupportedError.
+func (m *RefreshMaterializedViewManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.statement)
+}
+
+func (m *RefreshMaterializedViewManager) statement() (nodes.Node, error) {
+	return m.Statement, nil
+}
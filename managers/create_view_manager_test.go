@@ -0,0 +1,103 @@
+package managers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// --- CreateViewManager ---
+
+func TestCreateViewAppliesTransformers(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	q := NewSelectManager(users).
+		Select(users.Col("id"), users.Col("name")).
+		Where(users.Col("active").Eq(true))
+	q.Use(&countingTransformer{})
+
+	sql, params, err := NewCreateViewManager("active_users", q).
+		OrReplace().
+		Columns("id", "name").
+		ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE OR REPLACE VIEW "active_users" ("id", "name") AS `+
+		`SELECT "users"."id", "users"."name" FROM "users" `+
+		`WHERE "users"."active" = TRUE AND "users"."injected" = 'by_plugin'`)
+	if len(params) != 0 {
+		t.Errorf("expected values inlined, got params %v", params)
+	}
+	if len(q.Core.Wheres) != 1 {
+		t.Errorf("expected query to be left untouched, got %d wheres", len(q.Core.Wheres))
+	}
+}
+
+func TestCreateViewTransformerError(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	q := NewSelectManager(users)
+	q.Use(failingTransformer{})
+
+	_, _, err := NewCreateViewManager("v", q).ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertError(t, err)
+}
+
+func TestCreateMaterializedViewPostgres(t *testing.T) {
+	t.Parallel()
+	orders := nodes.NewTable("orders")
+	q := NewSelectManager(orders).
+		Select(orders.Col("region"), nodes.Count(nil).As("n")).
+		Group(orders.Col("region"))
+
+	sql, _, err := NewCreateViewManager("order_counts", q).
+		Materialized().
+		IfNotExists().
+		WithNoData().
+		ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE MATERIALIZED VIEW IF NOT EXISTS "order_counts" AS `+
+		`SELECT "orders"."region", COUNT(*) AS "n" FROM "orders" GROUP BY "orders"."region" WITH NO DATA`)
+}
+
+func TestCreateMaterializedViewUnsupportedInMySQL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, _, err := NewCreateViewManager("v", NewSelectManager(users)).
+		Materialized().
+		ToSQL(visitors.NewMySQLVisitor())
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+	}
+	testutil.AssertEqual(t, ue.Feature, "materialized views")
+}
+
+func TestCreateViewSQLite(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewCreateViewManager("all_users", NewSelectManager(users))
+
+	sql, _, err := m.IfNotExists().ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE VIEW IF NOT EXISTS "all_users" AS SELECT * FROM "users"`)
+
+	_, _, err = m.OrReplace().ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertError(t, err)
+}
+
+// --- RefreshMaterializedViewManager ---
+
+func TestRefreshMaterializedView(t *testing.T) {
+	t.Parallel()
+	sql, _, err := NewRefreshMaterializedViewManager("order_counts").
+		Concurrently().
+		ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `REFRESH MATERIALIZED VIEW CONCURRENTLY "order_counts"`)
+
+	_, _, err = NewRefreshMaterializedViewManager("order_counts").ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertError(t, err)
+}
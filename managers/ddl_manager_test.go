@@ -0,0 +1,116 @@
+package managers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// --- CreateTableManager ---
+
+func TestCreateTablePostgres(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	orgs := nodes.NewTable("orgs")
+	m := NewCreateTableManager(users).
+		IfNotExists().
+		Column("id", "bigint", nodes.PrimaryKey()).
+		Column("email", "varchar(255)", nodes.NotNull(), nodes.Unique()).
+		Column("active", "boolean", nodes.NotNull(), nodes.Default(true)).
+		Column("org_id", "bigint", nodes.References(orgs, "id"), nodes.OnDelete(nodes.ActionCascade)).
+		Check(users.Col("email").NotEq(""))
+
+	sql, params, err := m.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE TABLE IF NOT EXISTS "users" (`+
+		`"id" bigint PRIMARY KEY, `+
+		`"email" varchar(255) NOT NULL UNIQUE, `+
+		`"active" boolean NOT NULL DEFAULT TRUE, `+
+		`"org_id" bigint REFERENCES "orgs" ("id") ON DELETE CASCADE, `+
+		`CHECK ("email" != ''))`)
+	if len(params) != 0 {
+		t.Errorf("expected no params, got %v", params)
+	}
+}
+
+func TestCreateTableMySQLHoistsForeignKeys(t *testing.T) {
+	t.Parallel()
+	posts := nodes.NewTable("posts")
+	users := nodes.NewTable("users")
+	m := NewCreateTableManager(posts).
+		Column("id", "bigint", nodes.NotNull()).
+		Column("user_id", "bigint", nodes.References(users, "id")).
+		PrimaryKey("id")
+
+	sql, _, err := m.ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "CREATE TABLE `posts` (`id` bigint NOT NULL, `user_id` bigint, "+
+		"PRIMARY KEY (`id`), FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))")
+}
+
+// --- AlterTableManager ---
+
+func TestAlterTableSQLiteOneActionPerStatement(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewAlterTableManager(users).
+		AddColumn("age", "integer").
+		DropColumn("legacy")
+
+	sql, _, err := m.ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `ALTER TABLE "users" ADD COLUMN "age" integer; ALTER TABLE "users" DROP COLUMN "legacy"`)
+}
+
+func TestAlterTableUnsupportedReturnsError(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewAlterTableManager(users).AddConstraint(nodes.UniqueConstraint("email"))
+
+	_, _, err := m.ToSQL(visitors.NewSQLiteVisitor())
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+	}
+	testutil.AssertEqual(t, ue.Feature, "ALTER TABLE ADD CONSTRAINT")
+}
+
+// --- CreateIndexManager ---
+
+func TestCreateIndexPartialPostgres(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewCreateIndexManager("users_email_idx", users).
+		Unique().
+		Concurrently().
+		On(nodes.Lower(users.Col("email"))).
+		Where(users.Col("deleted_at").IsNull())
+
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE UNIQUE INDEX CONCURRENTLY "users_email_idx" ON "users" ((LOWER("email"))) WHERE "deleted_at" IS NULL`)
+}
+
+// --- DropManager ---
+
+func TestDropIndexMySQLNeedsTable(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, _, err := NewDropIndexManager("users_email_idx").ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertError(t, err)
+
+	sql, _, err := NewDropIndexManager("users_email_idx").On(users).ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "DROP INDEX `users_email_idx` ON `users`")
+}
+
+func TestDropTableCascade(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	sql, _, err := NewDropTableManager(users).IfExists().Cascade().ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `DROP TABLE IF EXISTS "users" CASCADE`)
+}
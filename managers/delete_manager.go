@@ -36,23 +36,35 @@ func (m *DeleteManager) Use(t plugins.Transformer) *DeleteManager {
 	return m
 }
 
-// toSQLCore applies transformers and generates SQL.
-func (m *DeleteManager) toSQLCore(v nodes.Visitor) (string, error) {
+// transformed applies all registered transformers to a copy of the
+// statement and returns the result.
+func (m *DeleteManager) transformed() (nodes.Node, error) {
 	stmt := m.cloneStatement()
 	for _, t := range m.transformers {
 		var err error
 		stmt, err = t.TransformDelete(stmt)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
-	return stmt.Accept(v), nil
+	return stmt, nil
 }
 
 // ToSQL applies transformers and generates SQL with parameters.
 // Returns SQL string, parameter values (if parameterised), and any error.
 func (m *DeleteManager) ToSQL(v nodes.Visitor) (string, []any, error) {
-	return toSQLParams(v, m.toSQLCore)
+	return toSQLParams(v, m.transformed)
+}
+
+// Explain returns a manager that renders EXPLAIN around this statement.
+func (m *DeleteManager) Explain(opts nodes.ExplainOptions) *ExplainManager {
+	return newExplainManager(m.transformed, opts)
+}
+
+// Compile applies transformers and renders the query once, returning a
+// Template whose nodes.Named placeholders are filled by Template.Bind.
+func (m *DeleteManager) Compile(v nodes.Visitor) (*Template, error) {
+	return compileTemplate(v, m.transformed)
 }
 
 // ToSQLParams applies transformers and generates parameterized SQL.
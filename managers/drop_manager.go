@@ -0,0 +1,57 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// DropManager provides a fluent API for building DROP TABLE and DROP INDEX
+// statements.
+type DropManager struct {
+	Statement *nodes.DropStatement
+}
+
+// NewDropTableManager creates a DropManager for DROP TABLE.
+func NewDropTableManager(table *nodes.Table) *DropManager {
+	return &DropManager{
+		Statement: &nodes.DropStatement{Kind: nodes.DropTable, Name: table.Name},
+	}
+}
+
+// NewDropIndexManager creates a DropManager for DROP INDEX.
+func NewDropIndexManager(name string) *DropManager {
+	return &DropManager{
+		Statement: &nodes.DropStatement{Kind: nodes.DropIndex, Name: name},
+	}
+}
+
+// IfExists adds IF EXISTS.
+func (m *DropManager) IfExists() *DropManager {
+	m.Statement.IfExists = true
+	return m
+}
+
+// Cascade adds CASCADE.
+func (m *DropManager) Cascade() *DropManager {
+	m.Statement.Cascade = true
+	return m
+}
+
+// Concurrently drops an index without blocking (PostgreSQL).
+func (m *DropManager) Concurrently() *DropManager {
+	m.Statement.Concurrently = true
+	return m
+}
+
+// On sets the table owning a dropped index, which MySQL requires.
+func (m *DropManager) On(table *nodes.Table) *DropManager {
+	m.Statement.On = table
+	return m
+}
+
+// ToSQL generates the DROP statement. Features the dialect cannot express
+// are reported as a *nodes.UnsupportedError.
+func (m *DropManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.statement)
+}
+
+func (m *DropManager) statement() (nodes.Node, error) {
+	return m.Statement, nil
+}
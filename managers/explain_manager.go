@@ -0,0 +1,39 @@
+package managers
+
+import "github.com/bawdo/gosbee/nodes"
+
+// ExplainManager renders EXPLAIN around the statement of another manager.
+// It is created by the Explain method of SelectManager, InsertManager,
+// UpdateManager and DeleteManager, and reads that manager when rendered,
+// so later changes to it are included.
+type ExplainManager struct {
+	Options nodes.ExplainOptions
+	source  func() (nodes.Node, error)
+}
+
+func newExplainManager(source func() (nodes.Node, error), opts nodes.ExplainOptions) *ExplainManager {
+	return &ExplainManager{Options: opts, source: source}
+}
+
+// transformed applies the source manager's transformers and wraps the
+// result in an ExplainStatement.
+func (m *ExplainManager) transformed() (nodes.Node, error) {
+	stmt, err := m.source()
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Explain(stmt, m.Options), nil
+}
+
+// ToSQL generates the EXPLAIN statement with the source statement's
+// parameters. Options the dialect cannot express are reported as a
+// *nodes.UnsupportedError.
+func (m *ExplainManager) ToSQL(v nodes.Visitor) (string, []any, error) {
+	return toSQLParams(v, m.transformed)
+}
+
+// Compile renders the EXPLAIN statement once, returning a Template whose
+// nodes.Named placeholders are filled by Template.Bind.
+func (m *ExplainManager) Compile(v nodes.Visitor) (*Template, error) {
+	return compileTemplate(v, m.transformed)
+}
@@ -0,0 +1,96 @@
+package managers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// --- ExplainManager ---
+
+func TestExplainSelectKeepsParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	q := NewSelectManager(users).
+		Select(users.Col("id")).
+		Where(users.Col("email").Eq("a@example.com"))
+
+	sql, params, err := q.Explain(nodes.ExplainOptions{Analyze: true, Format: nodes.ExplainJSON}).
+		ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `EXPLAIN (ANALYZE, FORMAT JSON) SELECT "users"."id" FROM "users" WHERE "users"."email" = $1`)
+	testutil.AssertEqual(t, len(params), 1)
+	testutil.AssertEqual(t, params[0], any("a@example.com"))
+}
+
+func TestExplainAppliesTransformers(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	ct := &countingTransformer{}
+	q := NewSelectManager(users).Select(users.Col("id"))
+	q.Use(ct)
+
+	sql, _, err := q.Explain(nodes.ExplainOptions{}).ToSQL(visitors.NewSQLiteVisitor(visitors.WithoutParams()))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `EXPLAIN QUERY PLAN SELECT "users"."id" FROM "users" WHERE "users"."injected" = 'by_plugin'`)
+	testutil.AssertEqual(t, ct.called, 1)
+	if len(q.Core.Wheres) != 0 {
+		t.Errorf("expected query to be left untouched, got %d wheres", len(q.Core.Wheres))
+	}
+}
+
+func TestExplainSeesLaterChanges(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	q := NewSelectManager(users).Select(users.Col("id"))
+	e := q.Explain(nodes.ExplainOptions{})
+	q.Where(users.Col("id").Eq(1))
+
+	sql, _, err := e.ToSQL(visitors.NewMySQLVisitor(visitors.WithoutParams()))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "EXPLAIN SELECT `users`.`id` FROM `users` WHERE `users`.`id` = 1")
+}
+
+func TestExplainDML(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	v := visitors.NewPostgresVisitor()
+
+	ins, _, err := NewInsertManager(users).
+		Columns(users.Col("name")).
+		Values("alice").
+		Explain(nodes.ExplainOptions{}).
+		ToSQL(v)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, ins, `EXPLAIN INSERT INTO "users" ("name") VALUES ($1)`)
+
+	upd, _, err := NewUpdateManager(users).
+		Set(users.Col("name"), "bob").
+		Where(users.Col("id").Eq(1)).
+		Explain(nodes.ExplainOptions{Analyze: true}).
+		ToSQL(v)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, upd, `EXPLAIN (ANALYZE) UPDATE "users" SET "users"."name" = $1 WHERE "users"."id" = $2`)
+
+	del, _, err := NewDeleteManager(users).
+		Where(users.Col("id").Eq(1)).
+		Explain(nodes.ExplainOptions{Format: nodes.ExplainJSON}).
+		ToSQL(v)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, del, `EXPLAIN (FORMAT JSON) DELETE FROM "users" WHERE "users"."id" = $1`)
+}
+
+func TestExplainUnsupportedOptionReturnsError(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, _, err := NewSelectManager(users).
+		Explain(nodes.ExplainOptions{Analyze: true}).
+		ToSQL(visitors.NewSQLiteVisitor())
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected UnsupportedError, got %v", err)
+	}
+}
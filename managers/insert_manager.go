@@ -1,6 +1,9 @@
 package managers
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/bawdo/gosbee/nodes"
 	"github.com/bawdo/gosbee/plugins"
 )
@@ -36,6 +39,35 @@ func (m *InsertManager) Values(vals ...any) *InsertManager {
 	return m
 }
 
+// FromStructs sets the column list from the db tags of rows and appends
+// one row of values per struct. Rows may be structs or pointers to structs
+// and must all have the same type. Readonly fields are skipped, as are
+// omitempty fields holding their zero value; every row must end up with
+// the same columns. Calling FromStructs again appends more rows and
+// requires the same columns. On error the manager is left unchanged.
+func (m *InsertManager) FromStructs(rows ...any) error {
+	cols, values, err := structRows(rows)
+	if err != nil {
+		return err
+	}
+	if len(m.Statement.Values) > 0 {
+		existing, ok := attributeNames(m.Statement.Columns)
+		if !ok || !slices.Equal(existing, cols) {
+			return fmt.Errorf("gosbee: FromStructs: columns %v do not match existing rows", cols)
+		}
+	}
+
+	columns := make([]nodes.Node, len(cols))
+	for i, c := range cols {
+		columns[i] = nodes.NewAttribute(m.Statement.Into, c)
+	}
+	m.Statement.Columns = columns
+	for _, vals := range values {
+		m.Values(vals...)
+	}
+	return nil
+}
+
 // FromSelect sets a SELECT subquery as the source of rows.
 // Mutually exclusive with Values — if Select is set, Values are ignored
 // by the visitor.
@@ -64,23 +96,35 @@ func (m *InsertManager) Use(t plugins.Transformer) *InsertManager {
 	return m
 }
 
-// toSQLCore applies transformers and generates SQL.
-func (m *InsertManager) toSQLCore(v nodes.Visitor) (string, error) {
+// transformed applies all registered transformers to a copy of the
+// statement and returns the result.
+func (m *InsertManager) transformed() (nodes.Node, error) {
 	stmt := m.cloneStatement()
 	for _, t := range m.transformers {
 		var err error
 		stmt, err = t.TransformInsert(stmt)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
-	return stmt.Accept(v), nil
+	return stmt, nil
 }
 
 // ToSQL applies transformers and generates SQL with parameters.
 // Returns SQL string, parameter values (if parameterised), and any error.
 func (m *InsertManager) ToSQL(v nodes.Visitor) (string, []any, error) {
-	return toSQLParams(v, m.toSQLCore)
+	return toSQLParams(v, m.transformed)
+}
+
+// Explain returns a manager that renders EXPLAIN around this statement.
+func (m *InsertManager) Explain(opts nodes.ExplainOptions) *ExplainManager {
+	return newExplainManager(m.transformed, opts)
+}
+
+// Compile applies transformers and renders the query once, returning a
+// Template whose nodes.Named placeholders are filled by Template.Bind.
+func (m *InsertManager) Compile(v nodes.Visitor) (*Template, error) {
+	return compileTemplate(v, m.transformed)
 }
 
 // ToSQLParams applies transformers and generates parameterized SQL.
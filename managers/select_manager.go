@@ -115,6 +115,52 @@ func (m *SelectManager) StringJoin(raw nodes.RawSQL) *SelectManager {
 	return m
 }
 
+// ArrayJoin adds an ARRAY JOIN (ClickHouse), which unfolds an array
+// column or expression into one row per element. Rows with an empty
+// array are dropped.
+func (m *SelectManager) ArrayJoin(array nodes.Node) *SelectManager {
+	return m.arrayJoin(array, nodes.ArrayJoin)
+}
+
+// LeftArrayJoin adds a LEFT ARRAY JOIN (ClickHouse), which keeps rows
+// whose array is empty.
+func (m *SelectManager) LeftArrayJoin(array nodes.Node) *SelectManager {
+	return m.arrayJoin(array, nodes.LeftArrayJoin)
+}
+
+func (m *SelectManager) arrayJoin(array nodes.Node, jt nodes.JoinType) *SelectManager {
+	join := &nodes.JoinNode{
+		Left:  m.Core.From,
+		Right: array,
+		Type:  jt,
+	}
+	m.Core.Joins = append(m.Core.Joins, join)
+	return m
+}
+
+// Final adds the FINAL modifier to the FROM table (ClickHouse), which
+// merges the rows of a ReplacingMergeTree or similar table before they
+// are read.
+func (m *SelectManager) Final() *SelectManager {
+	m.Core.Final = true
+	return m
+}
+
+// Sample reads only the given fraction of the FROM table's rows, a value
+// in (0, 1]. ClickHouse writes SAMPLE 0.1 and DuckDB USING SAMPLE 10%.
+// Set Core.Sample directly for a ClickHouse offset or a DuckDB sampling
+// method.
+func (m *SelectManager) Sample(ratio float64) *SelectManager {
+	m.Core.Sample = &nodes.SampleClause{Ratio: ratio}
+	return m
+}
+
+// SampleRows reads approximately n rows of the FROM table.
+func (m *SelectManager) SampleRows(n int64) *SelectManager {
+	m.Core.Sample = &nodes.SampleClause{Rows: n}
+	return m
+}
+
 // Group appends one or more expressions to the GROUP BY clause.
 func (m *SelectManager) Group(columns ...nodes.Node) *SelectManager {
 	m.Core.Groups = append(m.Core.Groups, columns...)
@@ -134,6 +180,14 @@ func (m *SelectManager) Window(defs ...*nodes.WindowDefinition) *SelectManager {
 	return m
 }
 
+// Qualify appends one or more conditions to the QUALIFY clause
+// (ClickHouse, DuckDB), which filters rows on the results of window
+// functions.
+func (m *SelectManager) Qualify(conditions ...nodes.Node) *SelectManager {
+	m.Core.Qualifies = append(m.Core.Qualifies, conditions...)
+	return m
+}
+
 // Order sets the ORDER BY clause. Pass OrderingNode values
 // (e.g., table.Col("name").Asc()).
 func (m *SelectManager) Order(orderings ...nodes.Node) *SelectManager {
@@ -147,6 +201,13 @@ func (m *SelectManager) Limit(n int) *SelectManager {
 	return m
 }
 
+// LimitBy sets a LIMIT n BY clause (ClickHouse), which keeps the first n
+// rows for each distinct value of the given columns.
+func (m *SelectManager) LimitBy(n int, columns ...nodes.Node) *SelectManager {
+	m.Core.LimitBy = &nodes.LimitByClause{Limit: nodes.Literal(n), Columns: columns}
+	return m
+}
+
 // Offset sets the OFFSET value.
 func (m *SelectManager) Offset(n int) *SelectManager {
 	m.Core.Offset = nodes.Literal(n)
@@ -261,25 +322,36 @@ func (m *SelectManager) Use(t plugins.Transformer) *SelectManager {
 	return m
 }
 
-// toSQLCore applies all registered transformers to a copy of the SelectCore,
-// then generates SQL using the given visitor.
-func (m *SelectManager) toSQLCore(v nodes.Visitor) (string, error) {
+// transformed applies all registered transformers to a copy of the
+// SelectCore and returns the result.
+func (m *SelectManager) transformed() (nodes.Node, error) {
 	core := m.CloneCore()
 	for _, t := range m.transformers {
 		var err error
 		core, err = t.TransformSelect(core)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
-	return core.Accept(v), nil
+	return core, nil
 }
 
 // ToSQL applies all registered transformers and generates SQL with parameters.
 // Returns SQL string, parameter values (if parameterised), and any error.
 // Parameters are collected automatically when the visitor has parameterisation enabled.
 func (m *SelectManager) ToSQL(v nodes.Visitor) (string, []any, error) {
-	return toSQLParams(v, m.toSQLCore)
+	return toSQLParams(v, m.transformed)
+}
+
+// Explain returns a manager that renders EXPLAIN around this statement.
+func (m *SelectManager) Explain(opts nodes.ExplainOptions) *ExplainManager {
+	return newExplainManager(m.transformed, opts)
+}
+
+// Compile applies transformers and renders the query once, returning a
+// Template whose nodes.Named placeholders are filled by Template.Bind.
+func (m *SelectManager) Compile(v nodes.Visitor) (*Template, error) {
+	return compileTemplate(v, m.transformed)
 }
 
 // ToSQLParams applies transformers and generates parameterized SQL.
@@ -323,6 +395,9 @@ func (m *SelectManager) CloneCore() *nodes.SelectCore {
 	windows := make([]*nodes.WindowDefinition, len(m.Core.Windows))
 	copy(windows, m.Core.Windows)
 
+	qualifies := make([]nodes.Node, len(m.Core.Qualifies))
+	copy(qualifies, m.Core.Qualifies)
+
 	orders := make([]nodes.Node, len(m.Core.Orders))
 	copy(orders, m.Core.Orders)
 
@@ -337,13 +412,17 @@ func (m *SelectManager) CloneCore() *nodes.SelectCore {
 
 	return &nodes.SelectCore{
 		From:        m.Core.From,
+		Final:       m.Core.Final,
+		Sample:      m.Core.Sample,
 		Projections: projections,
 		Wheres:      wheres,
 		Joins:       joins,
 		Groups:      groups,
 		Havings:     havings,
 		Windows:     windows,
+		Qualifies:   qualifies,
 		Orders:      orders,
+		LimitBy:     m.Core.LimitBy,
 		Limit:       m.Core.Limit,
 		Offset:      m.Core.Offset,
 		Distinct:    m.Core.Distinct,
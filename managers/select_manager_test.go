@@ -7,6 +7,7 @@ import (
 	"github.com/bawdo/gosbee/internal/testutil"
 	"github.com/bawdo/gosbee/nodes"
 	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/visitors"
 )
 
 // --- NewSelectManager ---
@@ -1086,6 +1087,56 @@ func TestSelectManagerAs(t *testing.T) {
 
 // --- Transformers ---
 
+func TestToSQLReturnsRenderError(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).Where(users.Col("id").Eq(struct{}{}))
+
+	for _, v := range []nodes.Visitor{
+		visitors.NewPostgresVisitor(visitors.WithoutParams()),
+		visitors.NewFormattingVisitor(visitors.NewPostgresVisitor(visitors.WithoutParams())),
+	} {
+		_, _, err := m.ToSQL(v)
+		var re *nodes.RenderError
+		if !errors.As(err, &re) {
+			t.Fatalf("%T: expected *nodes.RenderError, got %v", v, err)
+		}
+		testutil.AssertEqual(t, err.Error(), "gosbee: unsupported literal type struct {}")
+	}
+}
+
+func TestToSQLReturnsInvalidNodeErrors(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	tests := []struct {
+		name string
+		m    *SelectManager
+		want string
+	}{
+		{
+			"fragment placeholder mismatch",
+			NewSelectManager(users).Where(&nodes.SqlLiteral{Raw: "a = ? AND b = ?", Binds: []any{1}, Placeholders: true}),
+			`gosbee: SQL fragment "a = ? AND b = ?" has 2 placeholder(s) but 1 argument(s)`,
+		},
+		{
+			"sample ratio out of range",
+			NewSelectManager(users).Sample(1.5),
+			"gosbee: sample ratio 1.5 is not in (0, 1]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := tt.m.ToSQL(visitors.NewClickHouseVisitor())
+			var re *nodes.RenderError
+			if !errors.As(err, &re) {
+				t.Fatalf("expected *nodes.RenderError, got %v", err)
+			}
+			testutil.AssertEqual(t, err.Error(), tt.want)
+		})
+	}
+}
+
 func TestTransformers(t *testing.T) {
 	t.Parallel()
 	users := nodes.NewTable("users")
@@ -1145,3 +1196,48 @@ func TestLateralJoinWithComplexSubquery(t *testing.T) {
 		t.Error("expected non-empty SQL")
 	}
 }
+
+// --- Analytical clauses ---
+
+func TestAnalyticalClauses(t *testing.T) {
+	t.Parallel()
+	events := nodes.NewTable("events")
+	rank := nodes.RowNumber().Over(&nodes.WindowDefinition{PartitionBy: []nodes.Node{events.Col("user_id")}})
+	m := NewSelectManager(events).
+		Final().
+		Sample(0.1).
+		ArrayJoin(events.Col("tags")).
+		LeftArrayJoin(events.Col("urls")).
+		Qualify(rank.Eq(1)).
+		LimitBy(3, events.Col("user_id"))
+
+	if !m.Core.Final {
+		t.Error("expected Final to be true")
+	}
+	if m.Core.Sample == nil || m.Core.Sample.Ratio != 0.1 {
+		t.Errorf("expected a 0.1 sample, got %+v", m.Core.Sample)
+	}
+	if len(m.Core.Joins) != 2 || m.Core.Joins[0].Type != nodes.ArrayJoin || m.Core.Joins[1].Type != nodes.LeftArrayJoin {
+		t.Fatalf("expected ARRAY JOIN and LEFT ARRAY JOIN, got %+v", m.Core.Joins)
+	}
+	if len(m.Core.Qualifies) != 1 {
+		t.Errorf("expected 1 QUALIFY condition, got %d", len(m.Core.Qualifies))
+	}
+	if m.Core.LimitBy == nil || len(m.Core.LimitBy.Columns) != 1 {
+		t.Fatalf("expected LIMIT BY one column, got %+v", m.Core.LimitBy)
+	}
+
+	m.SampleRows(1000)
+	if m.Core.Sample.Rows != 1000 || m.Core.Sample.Ratio != 0 {
+		t.Errorf("expected a 1000-row sample, got %+v", m.Core.Sample)
+	}
+
+	clone := m.CloneCore()
+	if !clone.Final || clone.Sample != m.Core.Sample || clone.LimitBy != m.Core.LimitBy {
+		t.Error("analytical clauses not cloned")
+	}
+	clone.Qualifies = append(clone.Qualifies, rank.Lt(5))
+	if len(m.Core.Qualifies) != 1 {
+		t.Error("modifying clone affected original Qualifies")
+	}
+}
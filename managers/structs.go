@@ -0,0 +1,121 @@
+package managers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/bawdo/gosbee/internal/dbtag"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// SetStructOptions controls UpdateManager.SetStruct.
+type SetStructOptions struct {
+	// OmitZero skips every zero-valued field, not only those tagged
+	// omitempty.
+	OmitZero bool
+
+	// Columns limits the SET clause to the named columns. Empty means all
+	// writable columns.
+	Columns []string
+}
+
+// structValue dereferences v to a struct value and loads its metadata.
+func structValue(v any) (reflect.Value, *dbtag.Struct, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, nil, errors.New("nil struct pointer")
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return reflect.Value{}, nil, errors.New("nil value, want a struct")
+	}
+	meta, err := dbtag.Of(rv.Type())
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return rv, meta, nil
+}
+
+// fieldValue returns the value of f in rv, dereferencing pointers so that
+// a nil pointer becomes NULL.
+func fieldValue(rv reflect.Value, f *dbtag.Field) (val any, zero bool) {
+	fv := rv.FieldByIndex(f.Index)
+	zero = fv.IsZero()
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return nil, zero
+		}
+		fv = fv.Elem()
+	}
+	return fv.Interface(), zero
+}
+
+// insertRow returns the columns and values a struct contributes to an
+// INSERT: every field that is not readonly, less omitempty fields holding
+// their zero value.
+func insertRow(rv reflect.Value, meta *dbtag.Struct) ([]string, []any) {
+	var cols []string
+	var vals []any
+	for _, f := range meta.Fields {
+		if f.ReadOnly {
+			continue
+		}
+		val, zero := fieldValue(rv, f)
+		if f.OmitEmpty && zero {
+			continue
+		}
+		cols = append(cols, f.Column)
+		vals = append(vals, val)
+	}
+	return cols, vals
+}
+
+// attributeNames returns the names of cols, which must all be attributes.
+func attributeNames(cols []nodes.Node) ([]string, bool) {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		a, ok := c.(*nodes.Attribute)
+		if !ok {
+			return nil, false
+		}
+		names[i] = a.Name
+	}
+	return names, true
+}
+
+// structRows converts rows to INSERT columns and value rows, checking that
+// every row has the same type and produces the same columns.
+func structRows(rows []any) ([]string, [][]any, error) {
+	if len(rows) == 0 {
+		return nil, nil, errors.New("gosbee: FromStructs: no rows")
+	}
+	var cols []string
+	var typ reflect.Type
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		rv, meta, err := structValue(row)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gosbee: FromStructs: row %d: %w", i, err)
+		}
+		rowCols, vals := insertRow(rv, meta)
+		if i == 0 {
+			typ, cols = rv.Type(), rowCols
+			if len(cols) == 0 {
+				return nil, nil, fmt.Errorf("gosbee: FromStructs: %s has no writable db fields", typ)
+			}
+		} else {
+			if rv.Type() != typ {
+				return nil, nil, fmt.Errorf("gosbee: FromStructs: row %d is %s, want %s", i, rv.Type(), typ)
+			}
+			if !slices.Equal(rowCols, cols) {
+				return nil, nil, fmt.Errorf("gosbee: FromStructs: row %d has columns %v, want %v", i, rowCols, cols)
+			}
+		}
+		values[i] = vals
+	}
+	return cols, values, nil
+}
@@ -0,0 +1,154 @@
+package managers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+type account struct {
+	ID        int64   `db:"id,pk,omitempty"`
+	Email     string  `db:"email"`
+	Nickname  *string `db:"nickname"`
+	Score     int     `db:"score,omitempty"`
+	CreatedAt string  `db:"created_at,readonly"`
+	Scratch   string  `db:"-"`
+	internal  string
+}
+
+// --- InsertManager.FromStructs ---
+
+func TestFromStructsBuildsColumnsAndRows(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	nick := "al"
+	m := NewInsertManager(users)
+	err := m.FromStructs(
+		account{Email: "a@example.com", Nickname: &nick, Score: 3, CreatedAt: "ignored"},
+		&account{Email: "b@example.com", Score: 5},
+	)
+	testutil.AssertNoError(t, err)
+
+	sql, params, err := m.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `INSERT INTO "users" ("email", "nickname", "score") VALUES ($1, $2, $3), ($4, NULL, $5)`)
+	testutil.AssertEqual(t, len(params), 5)
+	testutil.AssertEqual(t, params[1], any("al"))
+	testutil.AssertEqual(t, params[4], any(5))
+}
+
+func TestFromStructsIncludesNonZeroPrimaryKey(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewInsertManager(users)
+	testutil.AssertNoError(t, m.FromStructs(account{ID: 7, Email: "a@example.com", Score: 1}))
+	sql, _, err := m.ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `INSERT INTO "users" ("id", "email", "nickname", "score") VALUES (?, ?, NULL, ?)`)
+}
+
+func TestFromStructsShapeMismatch(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewInsertManager(users)
+	err := m.FromStructs(account{Email: "a", Score: 1}, account{Email: "b"})
+	testutil.AssertError(t, err)
+	if !strings.Contains(err.Error(), "row 1 has columns") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(m.Statement.Values) != 0 {
+		t.Errorf("expected manager unchanged, got %d rows", len(m.Statement.Values))
+	}
+}
+
+func TestFromStructsTypeMismatch(t *testing.T) {
+	t.Parallel()
+	type other struct {
+		Email string `db:"email"`
+	}
+	m := NewInsertManager(nodes.NewTable("users"))
+	err := m.FromStructs(account{Email: "a"}, other{Email: "b"})
+	testutil.AssertError(t, err)
+	if !strings.Contains(err.Error(), "row 1 is") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFromStructsAppendsMatchingRows(t *testing.T) {
+	t.Parallel()
+	m := NewInsertManager(nodes.NewTable("users"))
+	testutil.AssertNoError(t, m.FromStructs(account{Email: "a"}))
+	testutil.AssertNoError(t, m.FromStructs(account{Email: "b"}))
+	testutil.AssertEqual(t, len(m.Statement.Values), 2)
+	testutil.AssertError(t, m.FromStructs(account{Email: "c", Score: 1}))
+}
+
+func TestFromStructsRejectsBadInput(t *testing.T) {
+	t.Parallel()
+	m := NewInsertManager(nodes.NewTable("users"))
+	testutil.AssertError(t, m.FromStructs())
+	testutil.AssertError(t, m.FromStructs(42))
+	testutil.AssertError(t, m.FromStructs((*account)(nil)))
+	testutil.AssertError(t, m.FromStructs(struct{ Name string }{"x"}))
+}
+
+// --- UpdateManager.SetStruct ---
+
+func TestSetStructUsesPrimaryKeyInWhere(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewUpdateManager(users)
+	err := m.SetStruct(&account{ID: 9, Email: "a@example.com", CreatedAt: "x"}, SetStructOptions{})
+	testutil.AssertNoError(t, err)
+
+	sql, params, err := m.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `UPDATE "users" SET "users"."email" = $1, "users"."nickname" = NULL WHERE "users"."id" = $2`)
+	testutil.AssertEqual(t, len(params), 2)
+	testutil.AssertEqual(t, params[1], any(int64(9)))
+}
+
+func TestSetStructOmitZeroAndColumns(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+
+	m := NewUpdateManager(users)
+	testutil.AssertNoError(t, m.SetStruct(account{ID: 1, Score: 4}, SetStructOptions{OmitZero: true}))
+	sql, _, err := m.ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `UPDATE "users" SET "users"."score" = ? WHERE "users"."id" = ?`)
+
+	m = NewUpdateManager(users)
+	testutil.AssertNoError(t, m.SetStruct(account{ID: 1, Email: "e", Score: 4}, SetStructOptions{Columns: []string{"email"}}))
+	sql, _, err = m.ToSQL(visitors.NewSQLiteVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `UPDATE "users" SET "users"."email" = ? WHERE "users"."id" = ?`)
+}
+
+func TestSetStructErrors(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	cases := []struct {
+		name string
+		v    any
+		opts SetStructOptions
+	}{
+		{"zero primary key", account{Email: "a"}, SetStructOptions{}},
+		{"unknown column", account{ID: 1}, SetStructOptions{Columns: []string{"missing"}}},
+		{"readonly column", account{ID: 1}, SetStructOptions{Columns: []string{"created_at"}}},
+		{"nothing to set", account{ID: 1}, SetStructOptions{OmitZero: true}},
+		{"not a struct", "x", SetStructOptions{}},
+	}
+	for _, c := range cases {
+		m := NewUpdateManager(users)
+		if err := m.SetStruct(c.v, c.opts); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+		if len(m.Statement.Assignments) != 0 || len(m.Statement.Wheres) != 0 {
+			t.Errorf("%s: expected manager unchanged", c.name)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package managers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// Template is a query that has been transformed and rendered once, with
+// nodes.Named placeholders left as open slots. Bind fills the slots by name
+// and returns the SQL with positional parameters, without walking the AST
+// or re-running transformers.
+//
+// A Template is immutable after compilation and safe for concurrent use.
+type Template struct {
+	sql   string
+	slots []any // fixed values, or open slots holding a *nodes.NamedParamNode
+	names []string
+}
+
+// compileTemplate renders the query built by build once and records the
+// parameter slots. The visitor must collect parameters, either as a
+// nodes.Parameterizer or a nodes.Renderer.
+func compileTemplate(v nodes.Visitor, build func() (nodes.Node, error)) (*Template, error) {
+	_, isParameterizer := v.(nodes.Parameterizer)
+	_, isRenderer := v.(nodes.Renderer)
+	if !isParameterizer && !isRenderer {
+		return nil, errors.New("gosbee: template compilation requires a parameterizing visitor")
+	}
+	sql, params, err := renderParams(v, build)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]any, len(params))
+	copy(slots, params)
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range slots {
+		if np, ok := namedSlot(p); ok && !seen[np.Name] {
+			seen[np.Name] = true
+			names = append(names, np.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return &Template{sql: sql, slots: slots, names: names}, nil
+}
+
+// SQL returns the rendered SQL text.
+func (t *Template) SQL() string {
+	return t.sql
+}
+
+// Names returns the sorted, de-duplicated named parameters of the template.
+func (t *Template) Names() []string {
+	names := make([]string, len(t.names))
+	copy(names, t.names)
+	return names
+}
+
+// Bind substitutes values for the template's named parameters and returns
+// the SQL and positional parameter list. Every named parameter must be
+// supplied, and values must not contain names the template does not use.
+func (t *Template) Bind(values map[string]any) (string, []any, error) {
+	var missing []string
+	for _, name := range t.names {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("gosbee: missing value for named parameter(s): %s", strings.Join(missing, ", "))
+	}
+
+	if len(values) > len(t.names) {
+		var extra []string
+		for name := range values {
+			if !t.has(name) {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		return "", nil, fmt.Errorf("gosbee: unknown named parameter(s): %s", strings.Join(extra, ", "))
+	}
+
+	params := make([]any, len(t.slots))
+	for i, s := range t.slots {
+		np, ok := namedSlot(s)
+		if !ok {
+			params[i] = s
+			continue
+		}
+		if a, ok := s.(sql.NamedArg); ok {
+			params[i] = sql.Named(a.Name, values[np.Name])
+		} else {
+			params[i] = values[np.Name]
+		}
+	}
+	return t.sql, params, nil
+}
+
+// namedSlot returns the named parameter left open in a rendered parameter:
+// the node itself, or a sql.NamedArg wrapping it for dialects that bind
+// parameters by name.
+func namedSlot(p any) (*nodes.NamedParamNode, bool) {
+	if a, ok := p.(sql.NamedArg); ok {
+		p = a.Value
+	}
+	np, ok := p.(*nodes.NamedParamNode)
+	return np, ok
+}
+
+func (t *Template) has(name string) bool {
+	i := sort.SearchStrings(t.names, name)
+	return i < len(t.names) && t.names[i] == name
+}
@@ -0,0 +1,206 @@
+package managers
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+// --- Compile / Bind ---
+
+func TestCompileSelectBindsNamedParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).
+		Where(users.Col("tenant_id").Eq(nodes.Named("tenant"))).
+		Where(users.Col("active").Eq(true))
+
+	tmpl, err := m.Compile(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+
+	sql, params, err := tmpl.Bind(map[string]any{"tenant": 7})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT * FROM "users" WHERE "users"."tenant_id" = $1 AND "users"."active" = $2`)
+	if len(params) != 2 || params[0] != 7 || params[1] != true {
+		t.Errorf("expected params [7 true], got %v", params)
+	}
+}
+
+func TestCompileRepeatedNamePostgres(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).
+		Where(users.Col("a").Eq(nodes.Named("x"))).
+		Where(users.Col("b").Eq(nodes.Named("x")))
+
+	tmpl, err := m.Compile(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	sql, params, err := tmpl.Bind(map[string]any{"x": "v"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT * FROM "users" WHERE "users"."a" = $1 AND "users"."b" = $1`)
+	if len(params) != 1 || params[0] != "v" {
+		t.Errorf("expected params [v], got %v", params)
+	}
+}
+
+func TestCompileRepeatedNameMySQL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).
+		Where(users.Col("a").Eq(nodes.Named("x"))).
+		Where(users.Col("b").Eq(nodes.Named("x")))
+
+	tmpl, err := m.Compile(visitors.NewMySQLVisitor())
+	testutil.AssertNoError(t, err)
+	_, params, err := tmpl.Bind(map[string]any{"x": "v"})
+	testutil.AssertNoError(t, err)
+	if len(params) != 2 || params[0] != "v" || params[1] != "v" {
+		t.Errorf("expected params [v v], got %v", params)
+	}
+}
+
+func TestBindMissingName(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).Where(users.Col("a").Eq(nodes.Named("x")))
+	tmpl, err := m.Compile(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+
+	_, _, err = tmpl.Bind(map[string]any{})
+	testutil.AssertError(t, err)
+}
+
+func TestBindExtraName(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).Where(users.Col("a").Eq(nodes.Named("x")))
+	tmpl, err := m.Compile(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+
+	_, _, err = tmpl.Bind(map[string]any{"x": 1, "y": 2})
+	testutil.AssertError(t, err)
+}
+
+func TestCompileRunsTransformersOnce(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	tr := &countingTransformer{}
+	m := NewSelectManager(users).Where(users.Col("a").Eq(nodes.Named("x"))).Use(tr)
+
+	tmpl, err := m.Compile(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	for i := range 3 {
+		if _, _, err := tmpl.Bind(map[string]any{"x": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testutil.AssertEqual(t, tr.called, 1)
+}
+
+func TestCompileRequiresParameterizer(t *testing.T) {
+	t.Parallel()
+	m := NewSelectManager(nodes.NewTable("users"))
+	_, err := m.Compile(testutil.StubVisitor{})
+	testutil.AssertError(t, err)
+}
+
+func TestCompileWithDialect(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).Where(users.Col("id").Eq(nodes.Named("id")))
+	tmpl, err := m.Compile(visitors.NewPostgresVisitor().Dialect())
+	testutil.AssertNoError(t, err)
+	sql, params, err := tmpl.Bind(map[string]any{"id": 3})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT * FROM "users" WHERE "users"."id" = $1`)
+	testutil.AssertEqual(t, len(params), 1)
+}
+
+func TestCompileDML(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	v := visitors.NewSQLiteVisitor()
+
+	ins, err := NewInsertManager(users).
+		Columns(users.Col("name")).
+		Values(nodes.Named("name")).
+		Compile(v)
+	testutil.AssertNoError(t, err)
+	sql, params, err := ins.Bind(map[string]any{"name": "Alice"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `INSERT INTO "users" ("name") VALUES (?)`)
+	testutil.AssertEqual(t, params[0], any("Alice"))
+
+	upd, err := NewUpdateManager(users).
+		Set(users.Col("name"), nodes.Named("name")).
+		Where(users.Col("id").Eq(nodes.Named("id"))).
+		Compile(v)
+	testutil.AssertNoError(t, err)
+	if got := upd.Names(); len(got) != 2 || got[0] != "id" || got[1] != "name" {
+		t.Errorf("expected names [id name], got %v", got)
+	}
+
+	del, err := NewDeleteManager(users).Where(users.Col("id").Eq(nodes.Named("id"))).Compile(v)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, del.SQL(), `DELETE FROM "users" WHERE "users"."id" = ?`)
+}
+
+func TestTemplateConcurrentBind(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	tmpl, err := NewSelectManager(users).
+		Where(users.Col("id").Eq(nodes.Named("id"))).
+		Compile(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, params, err := tmpl.Bind(map[string]any{"id": i})
+			if err != nil || params[0] != i {
+				t.Errorf("bind %d: got %v, %v", i, params, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestToSQLRejectsNamedParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := NewSelectManager(users).Where(users.Col("id").Eq(nodes.Named("id")))
+
+	_, _, err := m.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, err.Error(), `gosbee: named parameter "id": use Compile and Bind`)
+
+	_, _, err = NewDeleteManager(users).Where(users.Col("id").Eq(nodes.Named("id"))).ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertError(t, err)
+}
+
+func TestBindKeepsClickHouseParamNames(t *testing.T) {
+	t.Parallel()
+	events := nodes.NewTable("events")
+	m := NewSelectManager(events).
+		Where(events.Col("day").GtEq(nodes.Named("from").WithType("Date"))).
+		Where(events.Col("name").Eq("signup"))
+	tmpl, err := m.Compile(visitors.NewClickHouseVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, tmpl.Names()[0], "from")
+
+	query, params, err := tmpl.Bind(map[string]any{"from": "2026-01-01"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, query, "SELECT * FROM `events` WHERE `events`.`day` >= {from:Date} AND `events`.`name` = {p2:String}")
+	testutil.AssertEqual(t, len(params), 2)
+	testutil.AssertEqual(t, params[0], any(sql.Named("from", "2026-01-01")))
+	testutil.AssertEqual(t, params[1], any(sql.Named("p2", "signup")))
+
+	_, _, err = m.ToSQL(visitors.NewClickHouseVisitor())
+	testutil.AssertError(t, err)
+}
@@ -1,6 +1,8 @@
 package managers
 
 import (
+	"fmt"
+
 	"github.com/bawdo/gosbee/nodes"
 	"github.com/bawdo/gosbee/plugins"
 )
@@ -21,19 +23,55 @@ func (tm *treeManager) Transformers() []plugins.Transformer {
 	return tm.transformers
 }
 
-// toSQLParams is a helper that resets a parameterizer (if present), calls
-// the provided generate function, and returns SQL + params.
-func toSQLParams(v nodes.Visitor, generate func(nodes.Visitor) (string, error)) (string, []any, error) {
-	p, _ := v.(nodes.Parameterizer)
-	if p != nil {
-		p.Reset()
+// toSQLParams renders the statement built by build with v, like
+// renderParams, but rejects named parameters: they are open slots that
+// only a compiled Template can fill.
+func toSQLParams(v nodes.Visitor, build func() (nodes.Node, error)) (string, []any, error) {
+	sql, params, err := renderParams(v, build)
+	if err != nil {
+		return "", nil, err
 	}
+	for _, p := range params {
+		if np, ok := namedSlot(p); ok {
+			return "", nil, fmt.Errorf("gosbee: named parameter %q: use Compile and Bind", np.Name)
+		}
+	}
+	return sql, params, nil
+}
 
-	sql, err := generate(v)
+// renderParams runs build to obtain the transformed statement, then renders
+// it with v. Visitors implementing nodes.Renderer render with per-call state
+// and leave the visitor untouched; other visitors are reset, walked with
+// Accept, and their collected parameters returned. A *nodes.UnsupportedError
+// or *nodes.RenderError raised by the visitor is returned as the error.
+func renderParams(v nodes.Visitor, build func() (nodes.Node, error)) (sql string, params []any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch e := r.(type) {
+			case *nodes.UnsupportedError:
+				sql, params, err = "", nil, e
+			case *nodes.RenderError:
+				sql, params, err = "", nil, e
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	n, err := build()
 	if err != nil {
 		return "", nil, err
 	}
 
+	if r, ok := v.(nodes.Renderer); ok {
+		return r.Render(n)
+	}
+
+	p, _ := v.(nodes.Parameterizer)
+	if p != nil {
+		p.Reset()
+	}
+	sql = n.Accept(v)
 	if p != nil {
 		return sql, p.Params(), nil
 	}
@@ -1,6 +1,9 @@
 package managers
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/bawdo/gosbee/nodes"
 	"github.com/bawdo/gosbee/plugins"
 )
@@ -28,6 +31,49 @@ func (m *UpdateManager) Set(col nodes.Node, val any) *UpdateManager {
 	return m
 }
 
+// SetStruct adds a SET assignment for each writable db-tagged field of v,
+// a struct or pointer to one. Readonly fields are skipped, as are zero
+// values of omitempty fields (or of every field with opts.OmitZero).
+// Primary-key fields are not assigned; each adds a "pk = value" WHERE
+// condition instead, and a zero key is an error. On error the manager is
+// left unchanged.
+func (m *UpdateManager) SetStruct(v any, opts SetStructOptions) error {
+	rv, meta, err := structValue(v)
+	if err != nil {
+		return fmt.Errorf("gosbee: SetStruct: %w", err)
+	}
+	for _, c := range opts.Columns {
+		if f := meta.Field(c); f == nil || f.ReadOnly || f.PrimaryKey {
+			return fmt.Errorf("gosbee: SetStruct: %s has no writable column %q", meta.Type, c)
+		}
+	}
+
+	var assignments []*nodes.AssignmentNode
+	var wheres []nodes.Node
+	for _, f := range meta.Fields {
+		val, zero := fieldValue(rv, f)
+		col := nodes.NewAttribute(m.Statement.Table, f.Column)
+		switch {
+		case f.PrimaryKey:
+			if zero {
+				return fmt.Errorf("gosbee: SetStruct: primary key %q is zero", f.Column)
+			}
+			wheres = append(wheres, col.Eq(val))
+		case f.ReadOnly:
+		case zero && (f.OmitEmpty || opts.OmitZero):
+		case len(opts.Columns) > 0 && !slices.Contains(opts.Columns, f.Column):
+		default:
+			assignments = append(assignments, &nodes.AssignmentNode{Left: col, Right: nodes.Literal(val)})
+		}
+	}
+	if len(assignments) == 0 {
+		return fmt.Errorf("gosbee: SetStruct: no columns to set from %s", meta.Type)
+	}
+	m.Statement.Assignments = append(m.Statement.Assignments, assignments...)
+	m.Statement.Wheres = append(m.Statement.Wheres, wheres...)
+	return nil
+}
+
 // Where appends conditions to the WHERE clause.
 func (m *UpdateManager) Where(conditions ...nodes.Node) *UpdateManager {
 	m.Statement.Wheres = append(m.Statement.Wheres, conditions...)
@@ -46,23 +92,35 @@ func (m *UpdateManager) Use(t plugins.Transformer) *UpdateManager {
 	return m
 }
 
-// toSQLCore applies transformers and generates SQL.
-func (m *UpdateManager) toSQLCore(v nodes.Visitor) (string, error) {
+// transformed applies all registered transformers to a copy of the
+// statement and returns the result.
+func (m *UpdateManager) transformed() (nodes.Node, error) {
 	stmt := m.cloneStatement()
 	for _, t := range m.transformers {
 		var err error
 		stmt, err = t.TransformUpdate(stmt)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
-	return stmt.Accept(v), nil
+	return stmt, nil
 }
 
 // ToSQL applies transformers and generates SQL with parameters.
 // Returns SQL string, parameter values (if parameterised), and any error.
 func (m *UpdateManager) ToSQL(v nodes.Visitor) (string, []any, error) {
-	return toSQLParams(v, m.toSQLCore)
+	return toSQLParams(v, m.transformed)
+}
+
+// Explain returns a manager that renders EXPLAIN around this statement.
+func (m *UpdateManager) Explain(opts nodes.ExplainOptions) *ExplainManager {
+	return newExplainManager(m.transformed, opts)
+}
+
+// Compile applies transformers and renders the query once, returning a
+// Template whose nodes.Named placeholders are filled by Template.Bind.
+func (m *UpdateManager) Compile(v nodes.Visitor) (*Template, error) {
+	return compileTemplate(v, m.transformed)
 }
 
 // ToSQLParams applies transformers and generates parameterized SQL.
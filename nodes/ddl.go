@@ -0,0 +1,313 @@
+package nodes
+
+// ReferentialAction is the ON DELETE / ON UPDATE action of a foreign key.
+type ReferentialAction int
+
+const (
+	ActionNone       ReferentialAction = iota // clause omitted
+	ActionNoAction                            // NO ACTION
+	ActionRestrict                            // RESTRICT
+	ActionCascade                             // CASCADE
+	ActionSetNull                             // SET NULL
+	ActionSetDefault                          // SET DEFAULT
+)
+
+// String returns the SQL keyword for the action.
+func (a ReferentialAction) String() string {
+	switch a {
+	case ActionNoAction:
+		return "NO ACTION"
+	case ActionRestrict:
+		return "RESTRICT"
+	case ActionCascade:
+		return "CASCADE"
+	case ActionSetNull:
+		return "SET NULL"
+	case ActionSetDefault:
+		return "SET DEFAULT"
+	default:
+		return ""
+	}
+}
+
+// ForeignKeyRef is the REFERENCES target of a foreign key.
+type ForeignKeyRef struct {
+	Table    *Table
+	Columns  []string
+	OnDelete ReferentialAction
+	OnUpdate ReferentialAction
+}
+
+// ColumnDef describes one column in CREATE TABLE or ALTER TABLE ADD COLUMN.
+// Column-level constraints are rendered inline where the dialect supports
+// them.
+type ColumnDef struct {
+	Name       string
+	Type       string // SQL type name, e.g. "bigint", "varchar(255)"
+	NotNull    bool
+	Default    Node           // DEFAULT expression, nil for none
+	PrimaryKey bool           // column-level PRIMARY KEY
+	Unique     bool           // column-level UNIQUE
+	Check      Node           // column-level CHECK expression
+	References *ForeignKeyRef // column-level REFERENCES
+	Generated  Node           // GENERATED ALWAYS AS (expr)
+	Virtual    bool           // generated column is VIRTUAL rather than STORED
+}
+
+// ColumnOption configures a ColumnDef.
+type ColumnOption func(*ColumnDef)
+
+// NewColumnDef creates a column definition with the given options applied.
+func NewColumnDef(name, typ string, opts ...ColumnOption) *ColumnDef {
+	c := &ColumnDef{Name: name, Type: typ}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// NotNull marks the column NOT NULL.
+func NotNull() ColumnOption {
+	return func(c *ColumnDef) { c.NotNull = true }
+}
+
+// Default sets the column DEFAULT. Go values are wrapped with Literal and
+// are always rendered inline, since DDL cannot take bind parameters.
+func Default(val any) ColumnOption {
+	return func(c *ColumnDef) { c.Default = Literal(val) }
+}
+
+// PrimaryKey marks the column as the table's primary key.
+func PrimaryKey() ColumnOption {
+	return func(c *ColumnDef) { c.PrimaryKey = true }
+}
+
+// Unique adds a column-level UNIQUE constraint.
+func Unique() ColumnOption {
+	return func(c *ColumnDef) { c.Unique = true }
+}
+
+// Check adds a column-level CHECK constraint.
+func Check(expr Node) ColumnOption {
+	return func(c *ColumnDef) { c.Check = expr }
+}
+
+// References adds a column-level foreign key to table(cols).
+func References(table *Table, cols ...string) ColumnOption {
+	return func(c *ColumnDef) { c.References = &ForeignKeyRef{Table: table, Columns: cols} }
+}
+
+// OnDelete sets the ON DELETE action of the column's foreign key. It must
+// follow References.
+func OnDelete(action ReferentialAction) ColumnOption {
+	return func(c *ColumnDef) {
+		if c.References != nil {
+			c.References.OnDelete = action
+		}
+	}
+}
+
+// OnUpdate sets the ON UPDATE action of the column's foreign key. It must
+// follow References.
+func OnUpdate(action ReferentialAction) ColumnOption {
+	return func(c *ColumnDef) {
+		if c.References != nil {
+			c.References.OnUpdate = action
+		}
+	}
+}
+
+// GeneratedAs makes the column a stored generated column computed from expr.
+func GeneratedAs(expr Node) ColumnOption {
+	return func(c *ColumnDef) { c.Generated = expr }
+}
+
+// GeneratedVirtual makes the column a virtual generated column computed
+// from expr (MySQL, SQLite, PostgreSQL 18+).
+func GeneratedVirtual(expr Node) ColumnOption {
+	return func(c *ColumnDef) {
+		c.Generated = expr
+		c.Virtual = true
+	}
+}
+
+// ConstraintKind identifies a table-level constraint.
+type ConstraintKind int
+
+const (
+	ConstraintPrimaryKey ConstraintKind = iota
+	ConstraintUnique
+	ConstraintCheck
+	ConstraintForeignKey
+)
+
+// TableConstraint is a table-level constraint in CREATE TABLE or
+// ALTER TABLE ADD CONSTRAINT.
+type TableConstraint struct {
+	Name       string // optional CONSTRAINT name
+	Kind       ConstraintKind
+	Columns    []string
+	Check      Node           // for ConstraintCheck
+	References *ForeignKeyRef // for ConstraintForeignKey
+}
+
+// PrimaryKeyConstraint creates a PRIMARY KEY (cols) constraint.
+func PrimaryKeyConstraint(cols ...string) *TableConstraint {
+	return &TableConstraint{Kind: ConstraintPrimaryKey, Columns: cols}
+}
+
+// UniqueConstraint creates a UNIQUE (cols) constraint.
+func UniqueConstraint(cols ...string) *TableConstraint {
+	return &TableConstraint{Kind: ConstraintUnique, Columns: cols}
+}
+
+// CheckConstraint creates a CHECK (expr) constraint.
+func CheckConstraint(expr Node) *TableConstraint {
+	return &TableConstraint{Kind: ConstraintCheck, Check: expr}
+}
+
+// ForeignKeyConstraint creates a FOREIGN KEY (cols) REFERENCES
+// ref(refCols) constraint.
+func ForeignKeyConstraint(cols []string, ref *Table, refCols ...string) *TableConstraint {
+	return &TableConstraint{
+		Kind:       ConstraintForeignKey,
+		Columns:    cols,
+		References: &ForeignKeyRef{Table: ref, Columns: refCols},
+	}
+}
+
+// Named sets the constraint name.
+func (c *TableConstraint) Named(name string) *TableConstraint {
+	c.Name = name
+	return c
+}
+
+// OnDelete sets the ON DELETE action of a foreign key constraint. On any
+// other kind of constraint, rendering fails with a *RenderError.
+func (c *TableConstraint) OnDelete(action ReferentialAction) *TableConstraint {
+	if c.References == nil {
+		c.References = &ForeignKeyRef{}
+	}
+	c.References.OnDelete = action
+	return c
+}
+
+// OnUpdate sets the ON UPDATE action of a foreign key constraint. On any
+// other kind of constraint, rendering fails with a *RenderError.
+func (c *TableConstraint) OnUpdate(action ReferentialAction) *TableConstraint {
+	if c.References == nil {
+		c.References = &ForeignKeyRef{}
+	}
+	c.References.OnUpdate = action
+	return c
+}
+
+// CreateTableStatement represents CREATE TABLE.
+type CreateTableStatement struct {
+	Table       *Table
+	IfNotExists bool
+	Columns     []*ColumnDef
+	Constraints []*TableConstraint
+}
+
+func (n *CreateTableStatement) Accept(v Visitor) string { return v.VisitCreateTable(n) }
+
+// AlterActionKind identifies an ALTER TABLE action.
+type AlterActionKind int
+
+const (
+	AlterAddColumn AlterActionKind = iota
+	AlterDropColumn
+	AlterRenameColumn
+	AlterAddConstraint
+)
+
+// AlterAction is one action of an ALTER TABLE statement.
+type AlterAction struct {
+	Kind       AlterActionKind
+	Column     *ColumnDef       // for AlterAddColumn
+	Name       string           // column name for drop/rename
+	NewName    string           // for AlterRenameColumn
+	Constraint *TableConstraint // for AlterAddConstraint
+}
+
+// AlterTableStatement represents ALTER TABLE with one or more actions.
+// Dialects that allow only one action per statement render a sequence of
+// statements separated by semicolons.
+type AlterTableStatement struct {
+	Table   *Table
+	Actions []*AlterAction
+}
+
+func (n *AlterTableStatement) Accept(v Visitor) string { return v.VisitAlterTable(n) }
+
+// CreateIndexStatement represents CREATE INDEX. Columns may be attributes,
+// orderings of attributes, or arbitrary expressions; Wheres makes the index
+// partial.
+type CreateIndexStatement struct {
+	Name         string
+	Table        *Table
+	Columns      []Node
+	Unique       bool
+	Concurrently bool
+	IfNotExists  bool
+	Wheres       []Node
+}
+
+func (n *CreateIndexStatement) Accept(v Visitor) string { return v.VisitCreateIndex(n) }
+
+// DropKind identifies the kind of object a DROP statement removes.
+type DropKind int
+
+const (
+	DropTable DropKind = iota
+	DropIndex
+)
+
+// DropStatement represents DROP TABLE or DROP INDEX.
+type DropStatement struct {
+	Kind         DropKind
+	Name         string
+	On           *Table // table owning a dropped index (required by MySQL)
+	IfExists     bool
+	Cascade      bool
+	Concurrently bool // DROP INDEX CONCURRENTLY (PostgreSQL)
+}
+
+func (n *DropStatement) Accept(v Visitor) string { return v.VisitDrop(n) }
+
+// CreateViewStatement represents CREATE [OR REPLACE] [MATERIALIZED] VIEW
+// name [(cols)] AS query.
+type CreateViewStatement struct {
+	Name         string
+	Columns      []string // optional output column names
+	OrReplace    bool
+	IfNotExists  bool
+	Materialized bool // PostgreSQL materialized view
+	WithNoData   bool // create a materialized view without populating it
+	Query        Node
+}
+
+func (n *CreateViewStatement) Accept(v Visitor) string { return v.VisitCreateView(n) }
+
+// CreateTableAsStatement represents CREATE TABLE name [(cols)] AS query.
+type CreateTableAsStatement struct {
+	Table       *Table
+	Columns     []string // optional column names (PostgreSQL)
+	IfNotExists bool
+	Query       Node
+}
+
+func (n *CreateTableAsStatement) Accept(v Visitor) string { return v.VisitCreateTableAs(n) }
+
+// RefreshMaterializedViewStatement represents REFRESH MATERIALIZED VIEW
+// [CONCURRENTLY] name [WITH NO DATA].
+type RefreshMaterializedViewStatement struct {
+	Name         string
+	Concurrently bool
+	WithNoData   bool
+}
+
+func (n *RefreshMaterializedViewStatement) Accept(v Visitor) string {
+	return v.VisitRefreshMaterializedView(n)
+}
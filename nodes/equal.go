@@ -0,0 +1,85 @@
+package nodes
+
+import "reflect"
+
+// Equal reports whether a and b are structurally identical trees: the same
+// node types with the same field values, compared recursively. Literal and
+// bind values take part in the comparison; use a fingerprint to compare
+// query shapes instead.
+//
+// Only exported fields are compared, so the back-references installed by
+// constructors (for Predications and friends) do not matter, and nil and
+// empty slices are considered equal. Nodes built with struct literals and
+// nodes built with constructors therefore compare equal.
+func Equal(a, b Node) bool {
+	return equalValue(reflect.ValueOf(a), reflect.ValueOf(b), make(map[[2]uintptr]bool))
+}
+
+// equalValue compares two values of the tree. visited holds pointer pairs
+// already under comparison so shared or cyclic references terminate.
+func equalValue(a, b reflect.Value, visited map[[2]uintptr]bool) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		key := [2]uintptr{a.Pointer(), b.Pointer()}
+		if visited[key] {
+			return true
+		}
+		visited[key] = true
+		return equalValue(a.Elem(), b.Elem(), visited)
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValue(a.Elem(), b.Elem(), visited)
+	case reflect.Struct:
+		t := a.Type()
+		for i := range t.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if !equalValue(a.Field(i), b.Field(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := range a.Len() {
+			if !equalValue(a.Index(i), b.Index(i), visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			bv := b.MapIndex(iter.Key())
+			if !bv.IsValid() || !equalValue(iter.Value(), bv, visited) {
+				return false
+			}
+		}
+		return true
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	default:
+		return a.Equal(b)
+	}
+}
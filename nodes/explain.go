@@ -0,0 +1,32 @@
+package nodes
+
+// ExplainFormat selects the output format of EXPLAIN.
+type ExplainFormat int
+
+const (
+	ExplainText ExplainFormat = iota // the engine's default text output
+	ExplainJSON                      // FORMAT JSON (PostgreSQL) / FORMAT=JSON (MySQL)
+)
+
+// ExplainOptions configures an EXPLAIN statement. Options a dialect cannot
+// express are reported as an *UnsupportedError when rendering.
+type ExplainOptions struct {
+	Analyze bool // execute the statement and report actual rows and timings
+	Buffers bool // report buffer usage (PostgreSQL, requires Analyze)
+	Verbose bool // include additional detail (PostgreSQL)
+	Format  ExplainFormat
+}
+
+// ExplainStatement represents EXPLAIN wrapped around another statement.
+// SQLite renders it as EXPLAIN QUERY PLAN.
+type ExplainStatement struct {
+	Statement Node
+	Options   ExplainOptions
+}
+
+// Explain wraps stmt in an EXPLAIN statement.
+func Explain(stmt Node, opts ExplainOptions) *ExplainStatement {
+	return &ExplainStatement{Statement: stmt, Options: opts}
+}
+
+func (n *ExplainStatement) Accept(v Visitor) string { return v.VisitExplain(n) }
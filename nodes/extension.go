@@ -0,0 +1,64 @@
+package nodes
+
+// Extension is implemented by node types defined outside gosbee, such as a
+// PostGIS operator. Visitors hand an Extension to VisitExtension, and the
+// built-in dialects render it by calling RenderSQL, so a custom node needs
+// no changes to the visitors:
+//
+//	type Distance struct{ Left, Right nodes.Node }
+//
+//	func (n *Distance) Accept(v nodes.Visitor) string { return v.VisitExtension(n) }
+//
+//	func (n *Distance) RenderSQL(ctx nodes.RenderContext) {
+//		if ctx.Dialect() != "PostgreSQL" {
+//			ctx.Unsupported("the <-> operator")
+//		}
+//		ctx.Node(n.Left)
+//		ctx.Write(" <-> ")
+//		ctx.Node(n.Right)
+//	}
+type Extension interface {
+	Node
+
+	// RenderSQL writes the node's SQL through ctx.
+	RenderSQL(ctx RenderContext)
+}
+
+// RenderContext is the rendering state passed to an Extension and to the
+// clause writers of a custom dialect. It writes into the statement being
+// rendered and shares its quoting and bind parameters.
+type RenderContext interface {
+	// Dialect returns the name of the dialect being rendered, as used in
+	// UnsupportedError: "PostgreSQL", "MySQL", "SQLite", "SQL Server",
+	// "Oracle", "ClickHouse", "DuckDB", or the name of a custom dialect.
+	Dialect() string
+
+	// Write appends raw SQL text.
+	Write(sql string)
+
+	// Ident writes an identifier, quoted as the dialect requires.
+	Ident(name string)
+
+	// Node renders a child node.
+	Node(n Node)
+
+	// Value writes a Go value as a bind placeholder, or inline when the
+	// visitor does not parameterize.
+	Value(v any)
+
+	// Unsupported aborts rendering with an *UnsupportedError for the
+	// given feature.
+	Unsupported(feature string)
+}
+
+// Labeler is optionally implemented by an Extension to name itself in tree
+// views such as the DOT graph; the default label is its Go type.
+type Labeler interface {
+	Label() string
+}
+
+// Parent is optionally implemented by an Extension to expose its child
+// nodes to tree views, the analysis linter and the validator.
+type Parent interface {
+	Children() []Node
+}
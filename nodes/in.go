@@ -20,3 +20,17 @@ type BetweenNode struct {
 }
 
 func (n *BetweenNode) Accept(v Visitor) string { return v.VisitBetween(n) }
+
+// NewInNode creates an InNode with properly initialised embedded structs.
+func NewInNode(expr Node, vals []Node, negate bool) *InNode {
+	n := &InNode{Expr: expr, Vals: vals, Negate: negate}
+	n.self = n
+	return n
+}
+
+// NewBetweenNode creates a BetweenNode with properly initialised embedded structs.
+func NewBetweenNode(expr, low, high Node, negate bool) *BetweenNode {
+	n := &BetweenNode{Expr: expr, Low: low, High: high, Negate: negate}
+	n.self = n
+	return n
+}
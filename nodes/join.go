@@ -9,7 +9,9 @@ const (
 	RightOuterJoin
 	FullOuterJoin
 	CrossJoin
-	StringJoin // raw SQL join fragment
+	StringJoin    // raw SQL join fragment
+	ArrayJoin     // ARRAY JOIN (ClickHouse)
+	LeftArrayJoin // LEFT ARRAY JOIN (ClickHouse)
 )
 
 // String returns the display name for this join type.
@@ -27,6 +29,10 @@ func (t JoinType) String() string {
 		return "CROSS JOIN"
 	case StringJoin:
 		return "STRING JOIN"
+	case ArrayJoin:
+		return "ARRAY JOIN"
+	case LeftArrayJoin:
+		return "LEFT ARRAY JOIN"
 	default:
 		return "JOIN"
 	}
@@ -35,7 +41,7 @@ func (t JoinType) String() string {
 // JoinNode represents a SQL JOIN clause.
 type JoinNode struct {
 	Left    Node     // source table
-	Right   Node     // target table or subquery
+	Right   Node     // target table, subquery, or the array of an ARRAY JOIN
 	Type    JoinType // join type
 	On      Node     // join condition (nil for CROSS JOIN)
 	Lateral bool     // LATERAL modifier (PostgreSQL)
@@ -0,0 +1,1398 @@
+package nodes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// JSONVersion is the version of the JSON format written by MarshalJSON.
+// Decode rejects documents written with any other version.
+const JSONVersion = 1
+
+// ErrRawSQL is returned by Decode when a document contains a SqlLiteral
+// and raw SQL has not been allowed with AllowRawSQL.
+var ErrRawSQL = errors.New("raw SQL is not allowed")
+
+// A JSON document wraps the root node with the format version:
+//
+//	{"version":1,"node":{"type":"Comparison","op":"eq","left":{...},"right":{...}}}
+//
+// Every node is an object whose "type" is the node's name as used by the
+// Visitor methods (Table, Attribute, SelectCore, ...). Zero fields are
+// omitted. Enumerations are written as names ("eq", "left_outer") and Go
+// values as {"kind":"int64","value":42}, so that a value decodes to the
+// type it had. Shared subtrees, such as the table of several attributes,
+// are written once per reference.
+type jsonDocument struct {
+	Version int             `json:"version"`
+	Node    json.RawMessage `json:"node"`
+}
+
+// DecodeOption configures Decode.
+type DecodeOption func(*decoder)
+
+// AllowRawSQL makes Decode accept SqlLiteral nodes. Only use it for
+// documents from a trusted source: raw SQL is rendered verbatim.
+func AllowRawSQL() DecodeOption {
+	return func(d *decoder) { d.allowRaw = true }
+}
+
+// Decode builds the tree of a JSON document written by MarshalJSON. Nodes
+// are rebuilt as their constructors would build them, so Predications and
+// friends work on the result.
+//
+// SECURITY: A document may come from an untrusted source, so SqlLiteral
+// nodes are rejected with ErrRawSQL unless AllowRawSQL is given.
+// Identifiers, function names and type names are checked by the visitors
+// when the tree is rendered.
+func Decode(data []byte, opts ...DecodeOption) (n Node, err error) {
+	d := &decoder{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	var doc jsonDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("nodes: invalid JSON document: %w", err)
+	}
+	if doc.Version != JSONVersion {
+		return nil, fmt.Errorf("nodes: unsupported JSON version %d (want %d)", doc.Version, JSONVersion)
+	}
+	if isNull(doc.Node) {
+		return nil, errors.New(`nodes: JSON document has no "node"`)
+	}
+	defer recoverJSON(&err)
+	return d.node(doc.Node, "node"), nil
+}
+
+// marshalNode writes the JSON document of n.
+func marshalNode(n Node) (data []byte, err error) {
+	defer recoverJSON(&err)
+	return json.Marshal(jsonDocument{Version: JSONVersion, Node: (&encoder{}).node(n)})
+}
+
+// unmarshalNode decodes a JSON document into dst, which must be of the
+// document's node type. Raw SQL is rejected; use Decode with AllowRawSQL
+// to accept it.
+func unmarshalNode[T any, P interface {
+	*T
+	Node
+}](dst P, data []byte) error {
+	n, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	src, ok := n.(P)
+	if !ok {
+		return fmt.Errorf("nodes: cannot unmarshal %T into %T", n, dst)
+	}
+	*dst = *src
+	bindSelf(dst)
+	return nil
+}
+
+// jsonError carries an encoding or decoding error out of the recursion.
+type jsonError struct{ err error }
+
+func recoverJSON(err *error) {
+	if r := recover(); r != nil {
+		je, ok := r.(jsonError)
+		if !ok {
+			panic(r)
+		}
+		*err = je.err
+	}
+}
+
+// The back-references of Predications, Arithmetics and Combinable are
+// reached through these methods, which are promoted to the embedding
+// nodes.
+func (p *Predications) predications() *Predications { return p }
+func (a *Arithmetics) arithmetics() *Arithmetics    { return a }
+func (c *Combinable) combinable() *Combinable       { return c }
+
+// bindSelf points the embedded Predications, Arithmetics and Combinable of
+// n at n, as the constructors do.
+func bindSelf(n Node) {
+	if p, ok := n.(interface{ predications() *Predications }); ok {
+		p.predications().self = n
+	}
+	if a, ok := n.(interface{ arithmetics() *Arithmetics }); ok {
+		a.arithmetics().self = n
+	}
+	if c, ok := n.(interface{ combinable() *Combinable }); ok {
+		c.combinable().self = n
+	}
+}
+
+// --- Names ---
+
+var aggregateFuncNames = [...]string{
+	AggCount: "count", AggSum: "sum", AggAvg: "avg", AggMin: "min", AggMax: "max",
+}
+
+var extractFieldNames = [...]string{
+	ExtractYear: "year", ExtractMonth: "month", ExtractDay: "day",
+	ExtractHour: "hour", ExtractMinute: "minute", ExtractSecond: "second",
+	ExtractDow: "dow", ExtractDoy: "doy", ExtractEpoch: "epoch",
+	ExtractQuarter: "quarter", ExtractWeek: "week",
+}
+
+var infixOpNames = [...]string{
+	OpPlus: "plus", OpMinus: "minus", OpMultiply: "multiply", OpDivide: "divide",
+	OpBitwiseAnd: "bitwise_and", OpBitwiseOr: "bitwise_or", OpBitwiseXor: "bitwise_xor",
+	OpShiftLeft: "shift_left", OpShiftRight: "shift_right", OpConcat: "concat",
+}
+
+var unaryMathOpNames = [...]string{
+	OpBitwiseNot: "bitwise_not",
+}
+
+var comparisonOpNames = [...]string{
+	OpEq: "eq", OpNotEq: "not_eq", OpGt: "gt", OpGtEq: "gt_eq", OpLt: "lt", OpLtEq: "lt_eq",
+	OpLike: "like", OpNotLike: "not_like", OpRegexp: "regexp", OpNotRegexp: "not_regexp",
+	OpDistinctFrom: "distinct_from", OpNotDistinctFrom: "not_distinct_from",
+	OpCaseSensitiveEq: "case_sensitive_eq", OpCaseInsensitiveEq: "case_insensitive_eq",
+	OpContains: "contains", OpOverlaps: "overlaps",
+}
+
+var unaryOpNames = [...]string{
+	OpIsNull: "is_null", OpIsNotNull: "is_not_null",
+}
+
+var joinTypeNames = [...]string{
+	InnerJoin: "inner", LeftOuterJoin: "left_outer", RightOuterJoin: "right_outer",
+	FullOuterJoin: "full_outer", CrossJoin: "cross", StringJoin: "string",
+	ArrayJoin: "array", LeftArrayJoin: "left_array",
+}
+
+var orderDirectionNames = [...]string{
+	Asc: "asc", Desc: "desc",
+}
+
+var nullsDirectionNames = [...]string{
+	NullsDefault: "default", NullsFirst: "first", NullsLast: "last",
+}
+
+var lockModeNames = [...]string{
+	NoLock: "none", ForUpdate: "update", ForShare: "share",
+	ForNoKeyUpdate: "no_key_update", ForKeyShare: "key_share",
+}
+
+var onConflictActionNames = [...]string{
+	DoNothing: "nothing", DoUpdate: "update",
+}
+
+var setOpTypeNames = [...]string{
+	Union: "union", UnionAll: "union_all", Intersect: "intersect",
+	IntersectAll: "intersect_all", Except: "except", ExceptAll: "except_all",
+}
+
+var groupingSetTypeNames = [...]string{
+	Cube: "cube", Rollup: "rollup", GroupingSets: "grouping_sets",
+}
+
+var windowFuncNames = [...]string{
+	WinRowNumber: "row_number", WinRank: "rank", WinDenseRank: "dense_rank",
+	WinNtile: "ntile", WinLag: "lag", WinLead: "lead", WinFirstValue: "first_value",
+	WinLastValue: "last_value", WinNthValue: "nth_value", WinCumeDist: "cume_dist",
+	WinPercentRank: "percent_rank",
+}
+
+var frameTypeNames = [...]string{
+	FrameRows: "rows", FrameRange: "range",
+}
+
+var boundTypeNames = [...]string{
+	BoundUnboundedPreceding: "unbounded_preceding", BoundPreceding: "preceding",
+	BoundCurrentRow: "current_row", BoundFollowing: "following",
+	BoundUnboundedFollowing: "unbounded_following",
+}
+
+var referentialActionNames = [...]string{
+	ActionNone: "none", ActionNoAction: "no_action", ActionRestrict: "restrict",
+	ActionCascade: "cascade", ActionSetNull: "set_null", ActionSetDefault: "set_default",
+}
+
+var constraintKindNames = [...]string{
+	ConstraintPrimaryKey: "primary_key", ConstraintUnique: "unique",
+	ConstraintCheck: "check", ConstraintForeignKey: "foreign_key",
+}
+
+var alterActionKindNames = [...]string{
+	AlterAddColumn: "add_column", AlterDropColumn: "drop_column",
+	AlterRenameColumn: "rename_column", AlterAddConstraint: "add_constraint",
+}
+
+var dropKindNames = [...]string{
+	DropTable: "table", DropIndex: "index",
+}
+
+var explainFormatNames = [...]string{
+	ExplainText: "text", ExplainJSON: "json",
+}
+
+// valueKinds maps the kind names of Go values to their types. A slice of
+// one of these types is written with a "[]" prefix, as in "[]int64".
+var valueKinds = map[string]reflect.Type{
+	"bool":    reflect.TypeFor[bool](),
+	"string":  reflect.TypeFor[string](),
+	"int":     reflect.TypeFor[int](),
+	"int8":    reflect.TypeFor[int8](),
+	"int16":   reflect.TypeFor[int16](),
+	"int32":   reflect.TypeFor[int32](),
+	"int64":   reflect.TypeFor[int64](),
+	"uint":    reflect.TypeFor[uint](),
+	"uint8":   reflect.TypeFor[uint8](),
+	"uint16":  reflect.TypeFor[uint16](),
+	"uint32":  reflect.TypeFor[uint32](),
+	"uint64":  reflect.TypeFor[uint64](),
+	"float32": reflect.TypeFor[float32](),
+	"float64": reflect.TypeFor[float64](),
+	"time":    reflect.TypeFor[time.Time](),
+	"bytes":   reflect.TypeFor[[]byte](),
+}
+
+var valueKindNames = func() map[reflect.Type]string {
+	m := make(map[reflect.Type]string, len(valueKinds))
+	for k, t := range valueKinds {
+		m[t] = k
+	}
+	return m
+}()
+
+// --- Encoding ---
+
+type encoder struct{}
+
+func (e *encoder) fail(format string, args ...any) {
+	panic(jsonError{fmt.Errorf("nodes: "+format, args...)})
+}
+
+// object writes a JSON object with the given type, if any, followed by
+// the key/value pairs of fields. Empty strings, false, zero numbers, empty
+// lists and absent children are omitted.
+func (e *encoder) object(typ string, fields ...any) json.RawMessage {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	if typ != "" {
+		b.WriteString(`"type":`)
+		e.write(&b, typ)
+	}
+	for i := 0; i < len(fields); i += 2 {
+		switch v := fields[i+1].(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case bool:
+			if !v {
+				continue
+			}
+		case int64:
+			if v == 0 {
+				continue
+			}
+		case float64:
+			if v == 0 {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		case json.RawMessage:
+			if len(v) == 0 {
+				continue
+			}
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		e.write(&b, fields[i])
+		b.WriteByte(':')
+		e.write(&b, fields[i+1])
+	}
+	b.WriteByte('}')
+	return b.Bytes()
+}
+
+func (e *encoder) write(b *bytes.Buffer, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		e.fail("%v", err)
+	}
+	b.Write(data)
+}
+
+// name returns the name of an enumeration value.
+func name[E ~int](e *encoder, names []string, v E) string {
+	if int(v) < 0 || int(v) >= len(names) {
+		e.fail("cannot encode %T(%d)", v, int(v))
+	}
+	return names[v]
+}
+
+// optional returns the name of an enumeration value, or "" for the zero
+// value so that it is omitted.
+func optional[E ~int](e *encoder, names []string, v E) string {
+	if v == 0 {
+		return ""
+	}
+	return name(e, names, v)
+}
+
+// list writes a list of nodes, or nothing when it is empty.
+func list[T Node](e *encoder, ns []T) json.RawMessage {
+	if len(ns) == 0 {
+		return nil
+	}
+	items := make([]json.RawMessage, len(ns))
+	for i, n := range ns {
+		items[i] = e.node(n)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		e.fail("%v", err)
+	}
+	return data
+}
+
+func (e *encoder) rows(rows [][]Node) json.RawMessage {
+	if len(rows) == 0 {
+		return nil
+	}
+	items := make([]json.RawMessage, len(rows))
+	for i, row := range rows {
+		if items[i] = list(e, row); items[i] == nil {
+			items[i] = json.RawMessage("[]")
+		}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		e.fail("%v", err)
+	}
+	return data
+}
+
+// node writes n as a tagged object, or nothing when n is nil.
+func (e *encoder) node(n Node) json.RawMessage {
+	if n == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(n); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil
+	}
+	switch n := n.(type) {
+	case *Table:
+		return e.object("Table", "name", n.Name)
+	case *TableAlias:
+		return e.object("TableAlias", "relation", e.node(n.Relation), "alias", n.AliasName)
+	case *Attribute:
+		return e.object("Attribute", "relation", e.node(n.Relation), "name", n.Name, "type_name", n.TypeName)
+	case *LiteralNode:
+		return e.object("Literal", "value", e.value(n.Value))
+	case *StarNode:
+		return e.object("Star", "table", e.node(n.Table), "exclude", n.Exclude, "replace", list(e, n.Replace))
+	case *SqlLiteral:
+		return e.object("SqlLiteral", "raw", string(n.Raw), "binds", e.values(n.Binds), "placeholders", n.Placeholders)
+	case *ComparisonNode:
+		return e.object("Comparison", "op", name(e, comparisonOpNames[:], n.Op), "left", e.node(n.Left), "right", e.node(n.Right))
+	case *UnaryNode:
+		return e.object("Unary", "op", name(e, unaryOpNames[:], n.Op), "expr", e.node(n.Expr))
+	case *AndNode:
+		return e.object("And", "left", e.node(n.Left), "right", e.node(n.Right))
+	case *OrNode:
+		return e.object("Or", "left", e.node(n.Left), "right", e.node(n.Right))
+	case *NotNode:
+		return e.object("Not", "expr", e.node(n.Expr))
+	case *InNode:
+		return e.object("In", "expr", e.node(n.Expr), "values", list(e, n.Vals), "negate", n.Negate)
+	case *BetweenNode:
+		return e.object("Between", "expr", e.node(n.Expr), "low", e.node(n.Low), "high", e.node(n.High), "negate", n.Negate)
+	case *GroupingNode:
+		return e.object("Grouping", "expr", e.node(n.Expr))
+	case *JoinNode:
+		return e.object("Join", "join_type", name(e, joinTypeNames[:], n.Type),
+			"left", e.node(n.Left), "right", e.node(n.Right), "on", e.node(n.On), "lateral", n.Lateral)
+	case *OrderingNode:
+		return e.object("Ordering", "expr", e.node(n.Expr),
+			"direction", name(e, orderDirectionNames[:], n.Direction), "nulls", optional(e, nullsDirectionNames[:], n.Nulls))
+	case *SelectCore:
+		windows := make([]json.RawMessage, len(n.Windows))
+		for i, w := range n.Windows {
+			windows[i] = e.window(w)
+		}
+		var sample, limitBy json.RawMessage
+		if s := n.Sample; s != nil {
+			sample = e.object("", "ratio", s.Ratio, "rows", s.Rows, "offset", s.Offset, "method", s.Method)
+		}
+		if lb := n.LimitBy; lb != nil {
+			limitBy = e.object("", "limit", e.node(lb.Limit), "offset", e.node(lb.Offset), "columns", list(e, lb.Columns))
+		}
+		return e.object("SelectCore",
+			"ctes", list(e, n.CTEs),
+			"comment", n.Comment,
+			"hints", n.Hints,
+			"distinct", n.Distinct,
+			"distinct_on", list(e, n.DistinctOn),
+			"projections", list(e, n.Projections),
+			"from", e.node(n.From),
+			"final", n.Final,
+			"sample", sample,
+			"joins", list(e, n.Joins),
+			"wheres", list(e, n.Wheres),
+			"groups", list(e, n.Groups),
+			"havings", list(e, n.Havings),
+			"windows", e.raws(windows),
+			"qualifies", list(e, n.Qualifies),
+			"orders", list(e, n.Orders),
+			"limit_by", limitBy,
+			"limit", e.node(n.Limit),
+			"offset", e.node(n.Offset),
+			"lock", optional(e, lockModeNames[:], n.Lock),
+			"skip_locked", n.SkipLocked)
+	case *InsertStatement:
+		return e.object("InsertStatement",
+			"into", e.node(n.Into),
+			"columns", list(e, n.Columns),
+			"values", e.rows(n.Values),
+			"select", e.node(n.Select),
+			"on_conflict", e.node(n.OnConflict),
+			"returning", list(e, n.Returning))
+	case *UpdateStatement:
+		return e.object("UpdateStatement",
+			"table", e.node(n.Table),
+			"assignments", list(e, n.Assignments),
+			"wheres", list(e, n.Wheres),
+			"returning", list(e, n.Returning))
+	case *DeleteStatement:
+		return e.object("DeleteStatement",
+			"from", e.node(n.From),
+			"wheres", list(e, n.Wheres),
+			"returning", list(e, n.Returning))
+	case *AssignmentNode:
+		return e.object("Assignment", "left", e.node(n.Left), "right", e.node(n.Right))
+	case *OnConflictNode:
+		return e.object("OnConflict",
+			"columns", list(e, n.Columns),
+			"action", name(e, onConflictActionNames[:], n.Action),
+			"assignments", list(e, n.Assignments),
+			"wheres", list(e, n.Wheres))
+	case *InfixNode:
+		return e.object("Infix", "op", name(e, infixOpNames[:], n.Op), "left", e.node(n.Left), "right", e.node(n.Right))
+	case *UnaryMathNode:
+		return e.object("UnaryMath", "op", name(e, unaryMathOpNames[:], n.Op), "expr", e.node(n.Expr))
+	case *AggregateNode:
+		return e.object("Aggregate", "func", name(e, aggregateFuncNames[:], n.Func),
+			"expr", e.node(n.Expr), "distinct", n.Distinct, "filter", e.node(n.Filter))
+	case *ExtractNode:
+		return e.object("Extract", "field", name(e, extractFieldNames[:], n.Field), "expr", e.node(n.Expr))
+	case *WindowFuncNode:
+		return e.object("WindowFunction", "func", name(e, windowFuncNames[:], n.Func), "args", list(e, n.Args))
+	case *OverNode:
+		return e.object("Over", "expr", e.node(n.Expr), "window", e.window(n.Window), "window_name", n.WindowName)
+	case *ExistsNode:
+		return e.object("Exists", "subquery", e.node(n.Subquery), "negated", n.Negated)
+	case *SetOperationNode:
+		return e.object("SetOperation", "op", name(e, setOpTypeNames[:], n.Type),
+			"left", e.node(n.Left), "right", e.node(n.Right),
+			"orders", list(e, n.Orders), "limit", e.node(n.Limit), "offset", e.node(n.Offset))
+	case *CTENode:
+		return e.object("CTE", "name", n.Name, "columns", n.Columns, "recursive", n.Recursive, "query", e.node(n.Query))
+	case *NamedFunctionNode:
+		return e.object("NamedFunction", "name", n.Name, "args", list(e, n.Args), "distinct", n.Distinct)
+	case *CaseNode:
+		whens := make([]json.RawMessage, len(n.Whens))
+		for i, w := range n.Whens {
+			whens[i] = e.object("", "when", e.node(w.Condition), "then", e.node(w.Result))
+		}
+		return e.object("Case", "operand", e.node(n.Operand), "whens", e.raws(whens), "else", e.node(n.ElseVal))
+	case *GroupingSetNode:
+		return e.object("GroupingSet", "set_type", name(e, groupingSetTypeNames[:], n.Type),
+			"columns", list(e, n.Columns), "sets", e.rows(n.Sets))
+	case *AliasNode:
+		return e.object("Alias", "expr", e.node(n.Expr), "name", n.Name)
+	case *BindParamNode:
+		return e.object("BindParam", "value", e.value(n.Value))
+	case *CastedNode:
+		return e.object("Casted", "value", e.value(n.Value), "type_name", n.TypeName)
+	case *NamedParamNode:
+		return e.object("NamedParam", "name", n.Name, "type_name", n.TypeName)
+	case *CreateTableStatement:
+		cols := make([]json.RawMessage, len(n.Columns))
+		for i, c := range n.Columns {
+			cols[i] = e.columnDef(c)
+		}
+		cons := make([]json.RawMessage, len(n.Constraints))
+		for i, c := range n.Constraints {
+			cons[i] = e.constraint(c)
+		}
+		return e.object("CreateTable", "table", e.node(n.Table), "if_not_exists", n.IfNotExists,
+			"columns", e.raws(cols), "constraints", e.raws(cons))
+	case *AlterTableStatement:
+		actions := make([]json.RawMessage, len(n.Actions))
+		for i, a := range n.Actions {
+			if a == nil {
+				continue
+			}
+			actions[i] = e.object("", "kind", name(e, alterActionKindNames[:], a.Kind),
+				"column", e.columnDef(a.Column), "name", a.Name, "new_name", a.NewName,
+				"constraint", e.constraint(a.Constraint))
+		}
+		return e.object("AlterTable", "table", e.node(n.Table), "actions", e.raws(actions))
+	case *CreateIndexStatement:
+		return e.object("CreateIndex", "name", n.Name, "table", e.node(n.Table), "columns", list(e, n.Columns),
+			"unique", n.Unique, "concurrently", n.Concurrently, "if_not_exists", n.IfNotExists,
+			"wheres", list(e, n.Wheres))
+	case *DropStatement:
+		return e.object("Drop", "kind", name(e, dropKindNames[:], n.Kind), "name", n.Name, "on", e.node(n.On),
+			"if_exists", n.IfExists, "cascade", n.Cascade, "concurrently", n.Concurrently)
+	case *CreateViewStatement:
+		return e.object("CreateView", "name", n.Name, "columns", n.Columns, "or_replace", n.OrReplace,
+			"if_not_exists", n.IfNotExists, "materialized", n.Materialized, "with_no_data", n.WithNoData,
+			"query", e.node(n.Query))
+	case *CreateTableAsStatement:
+		return e.object("CreateTableAs", "table", e.node(n.Table), "columns", n.Columns,
+			"if_not_exists", n.IfNotExists, "query", e.node(n.Query))
+	case *RefreshMaterializedViewStatement:
+		return e.object("RefreshMaterializedView", "name", n.Name, "concurrently", n.Concurrently,
+			"with_no_data", n.WithNoData)
+	case *ExplainStatement:
+		return e.object("Explain", "statement", e.node(n.Statement), "analyze", n.Options.Analyze,
+			"buffers", n.Options.Buffers, "verbose", n.Options.Verbose,
+			"format", optional(e, explainFormatNames[:], n.Options.Format))
+	default:
+		e.fail("cannot encode %T", n)
+		return nil
+	}
+}
+
+// raws writes a list of already written objects, or nothing when it is
+// empty.
+func (e *encoder) raws(items []json.RawMessage) json.RawMessage {
+	if len(items) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		e.fail("%v", err)
+	}
+	return data
+}
+
+func (e *encoder) window(w *WindowDefinition) json.RawMessage {
+	if w == nil {
+		return nil
+	}
+	var frame json.RawMessage
+	if f := w.Frame; f != nil {
+		var end json.RawMessage
+		if f.End != nil {
+			end = e.bound(*f.End)
+		}
+		frame = e.object("", "frame_type", name(e, frameTypeNames[:], f.Type), "start", e.bound(f.Start), "end", end)
+	}
+	return e.object("", "name", w.Name, "partition_by", list(e, w.PartitionBy),
+		"order_by", list(e, w.OrderBy), "frame", frame)
+}
+
+func (e *encoder) bound(b FrameBound) json.RawMessage {
+	return e.object("", "bound_type", name(e, boundTypeNames[:], b.Type), "offset", e.node(b.Offset))
+}
+
+func (e *encoder) columnDef(c *ColumnDef) json.RawMessage {
+	if c == nil {
+		return nil
+	}
+	return e.object("", "name", c.Name, "data_type", c.Type, "not_null", c.NotNull,
+		"default", e.node(c.Default), "primary_key", c.PrimaryKey, "unique", c.Unique,
+		"check", e.node(c.Check), "references", e.foreignKey(c.References),
+		"generated", e.node(c.Generated), "virtual", c.Virtual)
+}
+
+func (e *encoder) constraint(c *TableConstraint) json.RawMessage {
+	if c == nil {
+		return nil
+	}
+	return e.object("", "name", c.Name, "kind", name(e, constraintKindNames[:], c.Kind),
+		"columns", c.Columns, "check", e.node(c.Check), "references", e.foreignKey(c.References))
+}
+
+func (e *encoder) foreignKey(r *ForeignKeyRef) json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return e.object("", "table", e.node(r.Table), "columns", r.Columns,
+		"on_delete", optional(e, referentialActionNames[:], r.OnDelete),
+		"on_update", optional(e, referentialActionNames[:], r.OnUpdate))
+}
+
+// value writes a Go value with its kind, or nothing when it is nil.
+func (e *encoder) value(v any) json.RawMessage {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case Node:
+		return e.object("", "kind", "node", "value", e.node(v))
+	case []any:
+		items := make([]json.RawMessage, len(v))
+		for i, item := range v {
+			items[i] = e.value(item)
+		}
+		data, err := json.Marshal(items)
+		if err != nil {
+			e.fail("%v", err)
+		}
+		return e.object("", "kind", "list", "value", json.RawMessage(data))
+	}
+	t := reflect.TypeOf(v)
+	kind, ok := valueKindNames[t]
+	if !ok && t.Kind() == reflect.Slice {
+		if k, ok := valueKindNames[t.Elem()]; ok {
+			kind = "[]" + k
+		}
+	}
+	if kind == "" {
+		e.fail("cannot encode value of type %T", v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		e.fail("cannot encode %s value: %v", kind, err)
+	}
+	return e.object("", "kind", kind, "value", json.RawMessage(data))
+}
+
+// values writes a list of Go values, or nothing when it is empty.
+func (e *encoder) values(vs []any) json.RawMessage {
+	if len(vs) == 0 {
+		return nil
+	}
+	items := make([]json.RawMessage, len(vs))
+	for i, v := range vs {
+		items[i] = e.value(v)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		e.fail("%v", err)
+	}
+	return data
+}
+
+// --- Decoding ---
+
+type decoder struct {
+	allowRaw bool
+}
+
+func (d *decoder) fail(where, format string, args ...any) {
+	panic(jsonError{fmt.Errorf("nodes: %s: %s", where, fmt.Sprintf(format, args...))})
+}
+
+func isNull(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+// object is a JSON object being decoded. Fields are removed as they are
+// read so that unknown fields can be reported.
+type object struct {
+	where  string
+	fields map[string]json.RawMessage
+}
+
+func (d *decoder) object(raw json.RawMessage, where string) *object {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		d.fail(where, "expected a JSON object")
+	}
+	return &object{where: where, fields: fields}
+}
+
+func (o *object) take(key string) (json.RawMessage, string) {
+	raw := o.fields[key]
+	delete(o.fields, key)
+	return raw, o.where + "." + key
+}
+
+// done reports the fields of o that were not read.
+func (d *decoder) done(o *object) {
+	for key := range o.fields {
+		d.fail(o.where, "unknown field %q", key)
+	}
+}
+
+func (d *decoder) unmarshal(raw json.RawMessage, where, what string, v any) {
+	if isNull(raw) {
+		return
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		d.fail(where, "expected %s", what)
+	}
+}
+
+func (d *decoder) str(o *object, key string) string {
+	var s string
+	raw, where := o.take(key)
+	d.unmarshal(raw, where, "a string", &s)
+	return s
+}
+
+func (d *decoder) flag(o *object, key string) bool {
+	var b bool
+	raw, where := o.take(key)
+	d.unmarshal(raw, where, "a boolean", &b)
+	return b
+}
+
+func (d *decoder) float(o *object, key string) float64 {
+	var f float64
+	raw, where := o.take(key)
+	d.unmarshal(raw, where, "a number", &f)
+	return f
+}
+
+func (d *decoder) int(o *object, key string) int64 {
+	var i int64
+	raw, where := o.take(key)
+	d.unmarshal(raw, where, "an integer", &i)
+	return i
+}
+
+func (d *decoder) strs(o *object, key string) []string {
+	var s []string
+	raw, where := o.take(key)
+	d.unmarshal(raw, where, "a list of strings", &s)
+	return s
+}
+
+// array returns the items of a JSON array field.
+func (d *decoder) array(o *object, key string) ([]json.RawMessage, string) {
+	var items []json.RawMessage
+	raw, where := o.take(key)
+	d.unmarshal(raw, where, "a list", &items)
+	return items, where
+}
+
+// sub returns a nested object without a type, or nil when it is absent.
+func (d *decoder) sub(raw json.RawMessage, where string) *object {
+	if isNull(raw) {
+		return nil
+	}
+	return d.object(raw, where)
+}
+
+// enum decodes the name of an enumeration value.
+func enum[E ~int](d *decoder, o *object, key string, names []string) E {
+	raw, where := o.take(key)
+	var s string
+	d.unmarshal(raw, where, "a string", &s)
+	if s == "" {
+		return 0
+	}
+	for i, n := range names {
+		if n == s {
+			return E(i)
+		}
+	}
+	d.fail(where, "unknown value %q", s)
+	return 0
+}
+
+func (d *decoder) child(o *object, key string) Node {
+	raw, where := o.take(key)
+	return d.node(raw, where)
+}
+
+func (d *decoder) children(o *object, key string) []Node {
+	items, where := d.array(o, key)
+	return d.nodes(items, where)
+}
+
+func (d *decoder) nodes(items []json.RawMessage, where string) []Node {
+	if len(items) == 0 {
+		return nil
+	}
+	ns := make([]Node, len(items))
+	for i, item := range items {
+		ns[i] = d.node(item, fmt.Sprintf("%s[%d]", where, i))
+	}
+	return ns
+}
+
+func (d *decoder) rows(o *object, key string) [][]Node {
+	items, where := d.array(o, key)
+	if len(items) == 0 {
+		return nil
+	}
+	rows := make([][]Node, len(items))
+	for i, item := range items {
+		var row []json.RawMessage
+		w := fmt.Sprintf("%s[%d]", where, i)
+		d.unmarshal(item, w, "a list", &row)
+		rows[i] = d.nodes(row, w)
+	}
+	return rows
+}
+
+// as converts a decoded node to the type a field requires.
+func as[T Node](d *decoder, n Node, where string) T {
+	var zero T
+	if n == nil {
+		return zero
+	}
+	t, ok := n.(T)
+	if !ok {
+		d.fail(where, "unexpected %T", n)
+	}
+	return t
+}
+
+func childAs[T Node](d *decoder, o *object, key string) T {
+	raw, where := o.take(key)
+	return as[T](d, d.node(raw, where), where)
+}
+
+func childrenAs[T Node](d *decoder, o *object, key string) []T {
+	items, where := d.array(o, key)
+	if len(items) == 0 {
+		return nil
+	}
+	ts := make([]T, len(items))
+	for i, item := range items {
+		w := fmt.Sprintf("%s[%d]", where, i)
+		ts[i] = as[T](d, d.node(item, w), w)
+	}
+	return ts
+}
+
+// node decodes a tagged object, or nil for null.
+func (d *decoder) node(raw json.RawMessage, where string) Node {
+	if isNull(raw) {
+		return nil
+	}
+	o := d.object(raw, where)
+	typ := d.str(o, "type")
+	if typ == "" {
+		d.fail(where, `missing node "type"`)
+	}
+	o.where = typ
+	n := d.build(typ, o, where)
+	d.done(o)
+	bindSelf(n)
+	return n
+}
+
+func (d *decoder) build(typ string, o *object, where string) Node {
+	switch typ {
+	case "Table":
+		return &Table{Name: d.str(o, "name")}
+	case "TableAlias":
+		return &TableAlias{Relation: d.child(o, "relation"), AliasName: d.str(o, "alias")}
+	case "Attribute":
+		return &Attribute{Relation: d.child(o, "relation"), Name: d.str(o, "name"), TypeName: d.str(o, "type_name")}
+	case "Literal":
+		return &LiteralNode{Value: d.value(o, "value")}
+	case "Star":
+		return &StarNode{
+			Table:   childAs[*Table](d, o, "table"),
+			Exclude: d.strs(o, "exclude"),
+			Replace: childrenAs[*AliasNode](d, o, "replace"),
+		}
+	case "SqlLiteral":
+		if !d.allowRaw {
+			panic(jsonError{fmt.Errorf("nodes: %s: %w", where, ErrRawSQL)})
+		}
+		return &SqlLiteral{Raw: RawSQL(d.str(o, "raw")), Binds: d.values(o, "binds"), Placeholders: d.flag(o, "placeholders")}
+	case "Comparison":
+		return &ComparisonNode{Op: enum[ComparisonOp](d, o, "op", comparisonOpNames[:]), Left: d.child(o, "left"), Right: d.child(o, "right")}
+	case "Unary":
+		return &UnaryNode{Op: enum[UnaryOp](d, o, "op", unaryOpNames[:]), Expr: d.child(o, "expr")}
+	case "And":
+		return &AndNode{Left: d.child(o, "left"), Right: d.child(o, "right")}
+	case "Or":
+		return &OrNode{Left: d.child(o, "left"), Right: d.child(o, "right")}
+	case "Not":
+		return &NotNode{Expr: d.child(o, "expr")}
+	case "In":
+		return &InNode{Expr: d.child(o, "expr"), Vals: d.children(o, "values"), Negate: d.flag(o, "negate")}
+	case "Between":
+		return &BetweenNode{Expr: d.child(o, "expr"), Low: d.child(o, "low"), High: d.child(o, "high"), Negate: d.flag(o, "negate")}
+	case "Grouping":
+		return &GroupingNode{Expr: d.child(o, "expr")}
+	case "Join":
+		return &JoinNode{
+			Type:    enum[JoinType](d, o, "join_type", joinTypeNames[:]),
+			Left:    d.child(o, "left"),
+			Right:   d.child(o, "right"),
+			On:      d.child(o, "on"),
+			Lateral: d.flag(o, "lateral"),
+		}
+	case "Ordering":
+		return &OrderingNode{
+			Expr:      d.child(o, "expr"),
+			Direction: enum[OrderDirection](d, o, "direction", orderDirectionNames[:]),
+			Nulls:     enum[NullsDirection](d, o, "nulls", nullsDirectionNames[:]),
+		}
+	case "SelectCore":
+		n := &SelectCore{
+			CTEs:        childrenAs[*CTENode](d, o, "ctes"),
+			Comment:     d.str(o, "comment"),
+			Hints:       d.strs(o, "hints"),
+			Distinct:    d.flag(o, "distinct"),
+			DistinctOn:  d.children(o, "distinct_on"),
+			Projections: d.children(o, "projections"),
+			From:        d.child(o, "from"),
+			Final:       d.flag(o, "final"),
+			Joins:       childrenAs[*JoinNode](d, o, "joins"),
+			Wheres:      d.children(o, "wheres"),
+			Groups:      d.children(o, "groups"),
+			Havings:     d.children(o, "havings"),
+			Qualifies:   d.children(o, "qualifies"),
+			Orders:      d.children(o, "orders"),
+			Limit:       d.child(o, "limit"),
+			Offset:      d.child(o, "offset"),
+			Lock:        enum[LockMode](d, o, "lock", lockModeNames[:]),
+			SkipLocked:  d.flag(o, "skip_locked"),
+		}
+		items, where := d.array(o, "windows")
+		for i, item := range items {
+			n.Windows = append(n.Windows, d.window(item, fmt.Sprintf("%s[%d]", where, i)))
+		}
+		if s := d.sub(o.take("sample")); s != nil {
+			n.Sample = &SampleClause{
+				Ratio:  d.float(s, "ratio"),
+				Rows:   d.int(s, "rows"),
+				Offset: d.float(s, "offset"),
+				Method: d.str(s, "method"),
+			}
+			d.done(s)
+		}
+		if lb := d.sub(o.take("limit_by")); lb != nil {
+			n.LimitBy = &LimitByClause{Limit: d.child(lb, "limit"), Offset: d.child(lb, "offset"), Columns: d.children(lb, "columns")}
+			d.done(lb)
+		}
+		return n
+	case "InsertStatement":
+		return &InsertStatement{
+			Into:       d.child(o, "into"),
+			Columns:    d.children(o, "columns"),
+			Values:     d.rows(o, "values"),
+			Select:     d.child(o, "select"),
+			OnConflict: childAs[*OnConflictNode](d, o, "on_conflict"),
+			Returning:  d.children(o, "returning"),
+		}
+	case "UpdateStatement":
+		return &UpdateStatement{
+			Table:       d.child(o, "table"),
+			Assignments: childrenAs[*AssignmentNode](d, o, "assignments"),
+			Wheres:      d.children(o, "wheres"),
+			Returning:   d.children(o, "returning"),
+		}
+	case "DeleteStatement":
+		return &DeleteStatement{
+			From:      d.child(o, "from"),
+			Wheres:    d.children(o, "wheres"),
+			Returning: d.children(o, "returning"),
+		}
+	case "Assignment":
+		return &AssignmentNode{Left: d.child(o, "left"), Right: d.child(o, "right")}
+	case "OnConflict":
+		return &OnConflictNode{
+			Columns:     d.children(o, "columns"),
+			Action:      enum[OnConflictAction](d, o, "action", onConflictActionNames[:]),
+			Assignments: childrenAs[*AssignmentNode](d, o, "assignments"),
+			Wheres:      d.children(o, "wheres"),
+		}
+	case "Infix":
+		return &InfixNode{Op: enum[InfixOp](d, o, "op", infixOpNames[:]), Left: d.child(o, "left"), Right: d.child(o, "right")}
+	case "UnaryMath":
+		return &UnaryMathNode{Op: enum[UnaryMathOp](d, o, "op", unaryMathOpNames[:]), Expr: d.child(o, "expr")}
+	case "Aggregate":
+		return &AggregateNode{
+			Func:     enum[AggregateFunc](d, o, "func", aggregateFuncNames[:]),
+			Expr:     d.child(o, "expr"),
+			Distinct: d.flag(o, "distinct"),
+			Filter:   d.child(o, "filter"),
+		}
+	case "Extract":
+		return &ExtractNode{Field: enum[ExtractField](d, o, "field", extractFieldNames[:]), Expr: d.child(o, "expr")}
+	case "WindowFunction":
+		return &WindowFuncNode{Func: enum[WindowFunc](d, o, "func", windowFuncNames[:]), Args: d.children(o, "args")}
+	case "Over":
+		raw, w := o.take("window")
+		return &OverNode{Expr: d.child(o, "expr"), Window: d.window(raw, w), WindowName: d.str(o, "window_name")}
+	case "Exists":
+		return &ExistsNode{Subquery: d.child(o, "subquery"), Negated: d.flag(o, "negated")}
+	case "SetOperation":
+		return &SetOperationNode{
+			Type:   enum[SetOpType](d, o, "op", setOpTypeNames[:]),
+			Left:   d.child(o, "left"),
+			Right:  d.child(o, "right"),
+			Orders: d.children(o, "orders"),
+			Limit:  d.child(o, "limit"),
+			Offset: d.child(o, "offset"),
+		}
+	case "CTE":
+		return &CTENode{Name: d.str(o, "name"), Columns: d.strs(o, "columns"), Recursive: d.flag(o, "recursive"), Query: d.child(o, "query")}
+	case "NamedFunction":
+		return &NamedFunctionNode{Name: d.str(o, "name"), Args: d.children(o, "args"), Distinct: d.flag(o, "distinct")}
+	case "Case":
+		n := &CaseNode{Operand: d.child(o, "operand")}
+		items, where := d.array(o, "whens")
+		for i, item := range items {
+			w := d.object(item, fmt.Sprintf("%s[%d]", where, i))
+			n.Whens = append(n.Whens, CaseWhen{Condition: d.child(w, "when"), Result: d.child(w, "then")})
+			d.done(w)
+		}
+		n.ElseVal = d.child(o, "else")
+		return n
+	case "GroupingSet":
+		return &GroupingSetNode{
+			Type:    enum[GroupingSetType](d, o, "set_type", groupingSetTypeNames[:]),
+			Columns: d.children(o, "columns"),
+			Sets:    d.rows(o, "sets"),
+		}
+	case "Alias":
+		return &AliasNode{Expr: d.child(o, "expr"), Name: d.str(o, "name")}
+	case "BindParam":
+		return &BindParamNode{Value: d.value(o, "value")}
+	case "Casted":
+		return &CastedNode{Value: d.value(o, "value"), TypeName: d.str(o, "type_name")}
+	case "NamedParam":
+		return &NamedParamNode{Name: d.str(o, "name"), TypeName: d.str(o, "type_name")}
+	case "CreateTable":
+		n := &CreateTableStatement{Table: childAs[*Table](d, o, "table"), IfNotExists: d.flag(o, "if_not_exists")}
+		items, where := d.array(o, "columns")
+		for i, item := range items {
+			n.Columns = append(n.Columns, d.columnDef(item, fmt.Sprintf("%s[%d]", where, i)))
+		}
+		items, where = d.array(o, "constraints")
+		for i, item := range items {
+			n.Constraints = append(n.Constraints, d.constraint(item, fmt.Sprintf("%s[%d]", where, i)))
+		}
+		return n
+	case "AlterTable":
+		n := &AlterTableStatement{Table: childAs[*Table](d, o, "table")}
+		items, where := d.array(o, "actions")
+		for i, item := range items {
+			a := d.sub(item, fmt.Sprintf("%s[%d]", where, i))
+			if a == nil {
+				n.Actions = append(n.Actions, nil)
+				continue
+			}
+			col, colWhere := a.take("column")
+			cons, consWhere := a.take("constraint")
+			n.Actions = append(n.Actions, &AlterAction{
+				Kind:       enum[AlterActionKind](d, a, "kind", alterActionKindNames[:]),
+				Column:     d.columnDef(col, colWhere),
+				Name:       d.str(a, "name"),
+				NewName:    d.str(a, "new_name"),
+				Constraint: d.constraint(cons, consWhere),
+			})
+			d.done(a)
+		}
+		return n
+	case "CreateIndex":
+		return &CreateIndexStatement{
+			Name:         d.str(o, "name"),
+			Table:        childAs[*Table](d, o, "table"),
+			Columns:      d.children(o, "columns"),
+			Unique:       d.flag(o, "unique"),
+			Concurrently: d.flag(o, "concurrently"),
+			IfNotExists:  d.flag(o, "if_not_exists"),
+			Wheres:       d.children(o, "wheres"),
+		}
+	case "Drop":
+		return &DropStatement{
+			Kind:         enum[DropKind](d, o, "kind", dropKindNames[:]),
+			Name:         d.str(o, "name"),
+			On:           childAs[*Table](d, o, "on"),
+			IfExists:     d.flag(o, "if_exists"),
+			Cascade:      d.flag(o, "cascade"),
+			Concurrently: d.flag(o, "concurrently"),
+		}
+	case "CreateView":
+		return &CreateViewStatement{
+			Name:         d.str(o, "name"),
+			Columns:      d.strs(o, "columns"),
+			OrReplace:    d.flag(o, "or_replace"),
+			IfNotExists:  d.flag(o, "if_not_exists"),
+			Materialized: d.flag(o, "materialized"),
+			WithNoData:   d.flag(o, "with_no_data"),
+			Query:        d.child(o, "query"),
+		}
+	case "CreateTableAs":
+		return &CreateTableAsStatement{
+			Table:       childAs[*Table](d, o, "table"),
+			Columns:     d.strs(o, "columns"),
+			IfNotExists: d.flag(o, "if_not_exists"),
+			Query:       d.child(o, "query"),
+		}
+	case "RefreshMaterializedView":
+		return &RefreshMaterializedViewStatement{
+			Name:         d.str(o, "name"),
+			Concurrently: d.flag(o, "concurrently"),
+			WithNoData:   d.flag(o, "with_no_data"),
+		}
+	case "Explain":
+		return &ExplainStatement{
+			Statement: d.child(o, "statement"),
+			Options: ExplainOptions{
+				Analyze: d.flag(o, "analyze"),
+				Buffers: d.flag(o, "buffers"),
+				Verbose: d.flag(o, "verbose"),
+				Format:  enum[ExplainFormat](d, o, "format", explainFormatNames[:]),
+			},
+		}
+	default:
+		d.fail(where, "unknown node type %q", typ)
+		return nil
+	}
+}
+
+func (d *decoder) window(raw json.RawMessage, where string) *WindowDefinition {
+	o := d.sub(raw, where)
+	if o == nil {
+		return nil
+	}
+	w := &WindowDefinition{
+		Name:        d.str(o, "name"),
+		PartitionBy: d.children(o, "partition_by"),
+		OrderBy:     d.children(o, "order_by"),
+	}
+	if f := d.sub(o.take("frame")); f != nil {
+		start, startWhere := f.take("start")
+		w.Frame = &WindowFrame{
+			Type:  enum[FrameType](d, f, "frame_type", frameTypeNames[:]),
+			Start: d.bound(start, startWhere),
+		}
+		if end, endWhere := f.take("end"); !isNull(end) {
+			b := d.bound(end, endWhere)
+			w.Frame.End = &b
+		}
+		d.done(f)
+	}
+	d.done(o)
+	return w
+}
+
+func (d *decoder) bound(raw json.RawMessage, where string) FrameBound {
+	o := d.object(raw, where)
+	b := FrameBound{Type: enum[BoundType](d, o, "bound_type", boundTypeNames[:]), Offset: d.child(o, "offset")}
+	d.done(o)
+	return b
+}
+
+func (d *decoder) columnDef(raw json.RawMessage, where string) *ColumnDef {
+	o := d.sub(raw, where)
+	if o == nil {
+		return nil
+	}
+	c := &ColumnDef{
+		Name:       d.str(o, "name"),
+		Type:       d.str(o, "data_type"),
+		NotNull:    d.flag(o, "not_null"),
+		Default:    d.child(o, "default"),
+		PrimaryKey: d.flag(o, "primary_key"),
+		Unique:     d.flag(o, "unique"),
+		Check:      d.child(o, "check"),
+		References: d.foreignKey(o.take("references")),
+		Generated:  d.child(o, "generated"),
+		Virtual:    d.flag(o, "virtual"),
+	}
+	d.done(o)
+	return c
+}
+
+func (d *decoder) constraint(raw json.RawMessage, where string) *TableConstraint {
+	o := d.sub(raw, where)
+	if o == nil {
+		return nil
+	}
+	c := &TableConstraint{
+		Name:       d.str(o, "name"),
+		Kind:       enum[ConstraintKind](d, o, "kind", constraintKindNames[:]),
+		Columns:    d.strs(o, "columns"),
+		Check:      d.child(o, "check"),
+		References: d.foreignKey(o.take("references")),
+	}
+	d.done(o)
+	return c
+}
+
+func (d *decoder) foreignKey(raw json.RawMessage, where string) *ForeignKeyRef {
+	o := d.sub(raw, where)
+	if o == nil {
+		return nil
+	}
+	r := &ForeignKeyRef{
+		Table:    childAs[*Table](d, o, "table"),
+		Columns:  d.strs(o, "columns"),
+		OnDelete: enum[ReferentialAction](d, o, "on_delete", referentialActionNames[:]),
+		OnUpdate: enum[ReferentialAction](d, o, "on_update", referentialActionNames[:]),
+	}
+	d.done(o)
+	return r
+}
+
+func (d *decoder) value(o *object, key string) any {
+	raw, where := o.take(key)
+	return d.valueOf(raw, where)
+}
+
+func (d *decoder) values(o *object, key string) []any {
+	items, where := d.array(o, key)
+	if len(items) == 0 {
+		return nil
+	}
+	vs := make([]any, len(items))
+	for i, item := range items {
+		vs[i] = d.valueOf(item, fmt.Sprintf("%s[%d]", where, i))
+	}
+	return vs
+}
+
+// valueOf decodes a Go value written with its kind.
+func (d *decoder) valueOf(raw json.RawMessage, where string) any {
+	o := d.sub(raw, where)
+	if o == nil {
+		return nil
+	}
+	kind := d.str(o, "kind")
+	var v any
+	switch kind {
+	case "node":
+		v = d.child(o, "value")
+	case "list":
+		v = d.values(o, "value")
+		if v == nil {
+			v = []any{}
+		}
+	default:
+		t, ok := valueKinds[kind]
+		if !ok && len(kind) > 2 && kind[:2] == "[]" {
+			if et, ok := valueKinds[kind[2:]]; ok {
+				t = reflect.SliceOf(et)
+			}
+		}
+		if t == nil {
+			d.fail(where, "unknown value kind %q", kind)
+		}
+		ptr := reflect.New(t)
+		val, valWhere := o.take("value")
+		d.unmarshal(val, valWhere, "a "+kind+" value", ptr.Interface())
+		v = ptr.Elem().Interface()
+	}
+	d.done(o)
+	return v
+}
+
+// --- json.Marshaler and json.Unmarshaler ---
+//
+// MarshalJSON writes a node as a versioned JSON document; see Decode.
+// UnmarshalJSON reads a document of the same node type and, unlike Decode
+// with AllowRawSQL, always rejects raw SQL.
+
+func (n *Table) MarshalJSON() ([]byte, error)          { return marshalNode(n) }
+func (n *Table) UnmarshalJSON(data []byte) error       { return unmarshalNode(n, data) }
+func (n *TableAlias) MarshalJSON() ([]byte, error)     { return marshalNode(n) }
+func (n *TableAlias) UnmarshalJSON(data []byte) error  { return unmarshalNode(n, data) }
+func (n *Attribute) MarshalJSON() ([]byte, error)      { return marshalNode(n) }
+func (n *Attribute) UnmarshalJSON(data []byte) error   { return unmarshalNode(n, data) }
+func (n *LiteralNode) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *LiteralNode) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *StarNode) MarshalJSON() ([]byte, error)       { return marshalNode(n) }
+func (n *StarNode) UnmarshalJSON(data []byte) error    { return unmarshalNode(n, data) }
+func (n *SqlLiteral) MarshalJSON() ([]byte, error)     { return marshalNode(n) }
+func (n *SqlLiteral) UnmarshalJSON(data []byte) error  { return unmarshalNode(n, data) }
+
+func (n *ComparisonNode) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *ComparisonNode) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *UnaryNode) MarshalJSON() ([]byte, error)         { return marshalNode(n) }
+func (n *UnaryNode) UnmarshalJSON(data []byte) error      { return unmarshalNode(n, data) }
+func (n *AndNode) MarshalJSON() ([]byte, error)           { return marshalNode(n) }
+func (n *AndNode) UnmarshalJSON(data []byte) error        { return unmarshalNode(n, data) }
+func (n *OrNode) MarshalJSON() ([]byte, error)            { return marshalNode(n) }
+func (n *OrNode) UnmarshalJSON(data []byte) error         { return unmarshalNode(n, data) }
+func (n *NotNode) MarshalJSON() ([]byte, error)           { return marshalNode(n) }
+func (n *NotNode) UnmarshalJSON(data []byte) error        { return unmarshalNode(n, data) }
+func (n *InNode) MarshalJSON() ([]byte, error)            { return marshalNode(n) }
+func (n *InNode) UnmarshalJSON(data []byte) error         { return unmarshalNode(n, data) }
+func (n *BetweenNode) MarshalJSON() ([]byte, error)       { return marshalNode(n) }
+func (n *BetweenNode) UnmarshalJSON(data []byte) error    { return unmarshalNode(n, data) }
+func (n *GroupingNode) MarshalJSON() ([]byte, error)      { return marshalNode(n) }
+func (n *GroupingNode) UnmarshalJSON(data []byte) error   { return unmarshalNode(n, data) }
+func (n *JoinNode) MarshalJSON() ([]byte, error)          { return marshalNode(n) }
+func (n *JoinNode) UnmarshalJSON(data []byte) error       { return unmarshalNode(n, data) }
+func (n *OrderingNode) MarshalJSON() ([]byte, error)      { return marshalNode(n) }
+func (n *OrderingNode) UnmarshalJSON(data []byte) error   { return unmarshalNode(n, data) }
+
+func (n *SelectCore) MarshalJSON() ([]byte, error)         { return marshalNode(n) }
+func (n *SelectCore) UnmarshalJSON(data []byte) error      { return unmarshalNode(n, data) }
+func (n *InsertStatement) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *InsertStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *UpdateStatement) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *UpdateStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *DeleteStatement) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *DeleteStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *AssignmentNode) MarshalJSON() ([]byte, error)     { return marshalNode(n) }
+func (n *AssignmentNode) UnmarshalJSON(data []byte) error  { return unmarshalNode(n, data) }
+func (n *OnConflictNode) MarshalJSON() ([]byte, error)     { return marshalNode(n) }
+func (n *OnConflictNode) UnmarshalJSON(data []byte) error  { return unmarshalNode(n, data) }
+
+func (n *InfixNode) MarshalJSON() ([]byte, error)           { return marshalNode(n) }
+func (n *InfixNode) UnmarshalJSON(data []byte) error        { return unmarshalNode(n, data) }
+func (n *UnaryMathNode) MarshalJSON() ([]byte, error)       { return marshalNode(n) }
+func (n *UnaryMathNode) UnmarshalJSON(data []byte) error    { return unmarshalNode(n, data) }
+func (n *AggregateNode) MarshalJSON() ([]byte, error)       { return marshalNode(n) }
+func (n *AggregateNode) UnmarshalJSON(data []byte) error    { return unmarshalNode(n, data) }
+func (n *ExtractNode) MarshalJSON() ([]byte, error)         { return marshalNode(n) }
+func (n *ExtractNode) UnmarshalJSON(data []byte) error      { return unmarshalNode(n, data) }
+func (n *WindowFuncNode) MarshalJSON() ([]byte, error)      { return marshalNode(n) }
+func (n *WindowFuncNode) UnmarshalJSON(data []byte) error   { return unmarshalNode(n, data) }
+func (n *OverNode) MarshalJSON() ([]byte, error)            { return marshalNode(n) }
+func (n *OverNode) UnmarshalJSON(data []byte) error         { return unmarshalNode(n, data) }
+func (n *ExistsNode) MarshalJSON() ([]byte, error)          { return marshalNode(n) }
+func (n *ExistsNode) UnmarshalJSON(data []byte) error       { return unmarshalNode(n, data) }
+func (n *SetOperationNode) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *SetOperationNode) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *CTENode) MarshalJSON() ([]byte, error)             { return marshalNode(n) }
+func (n *CTENode) UnmarshalJSON(data []byte) error          { return unmarshalNode(n, data) }
+
+func (n *NamedFunctionNode) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *NamedFunctionNode) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
+func (n *CaseNode) MarshalJSON() ([]byte, error)             { return marshalNode(n) }
+func (n *CaseNode) UnmarshalJSON(data []byte) error          { return unmarshalNode(n, data) }
+func (n *GroupingSetNode) MarshalJSON() ([]byte, error)      { return marshalNode(n) }
+func (n *GroupingSetNode) UnmarshalJSON(data []byte) error   { return unmarshalNode(n, data) }
+func (n *AliasNode) MarshalJSON() ([]byte, error)            { return marshalNode(n) }
+func (n *AliasNode) UnmarshalJSON(data []byte) error         { return unmarshalNode(n, data) }
+func (n *BindParamNode) MarshalJSON() ([]byte, error)        { return marshalNode(n) }
+func (n *BindParamNode) UnmarshalJSON(data []byte) error     { return unmarshalNode(n, data) }
+func (n *CastedNode) MarshalJSON() ([]byte, error)           { return marshalNode(n) }
+func (n *CastedNode) UnmarshalJSON(data []byte) error        { return unmarshalNode(n, data) }
+func (n *NamedParamNode) MarshalJSON() ([]byte, error)       { return marshalNode(n) }
+func (n *NamedParamNode) UnmarshalJSON(data []byte) error    { return unmarshalNode(n, data) }
+
+func (n *CreateTableStatement) MarshalJSON() ([]byte, error)             { return marshalNode(n) }
+func (n *CreateTableStatement) UnmarshalJSON(data []byte) error          { return unmarshalNode(n, data) }
+func (n *AlterTableStatement) MarshalJSON() ([]byte, error)              { return marshalNode(n) }
+func (n *AlterTableStatement) UnmarshalJSON(data []byte) error           { return unmarshalNode(n, data) }
+func (n *CreateIndexStatement) MarshalJSON() ([]byte, error)             { return marshalNode(n) }
+func (n *CreateIndexStatement) UnmarshalJSON(data []byte) error          { return unmarshalNode(n, data) }
+func (n *DropStatement) MarshalJSON() ([]byte, error)                    { return marshalNode(n) }
+func (n *DropStatement) UnmarshalJSON(data []byte) error                 { return unmarshalNode(n, data) }
+func (n *CreateViewStatement) MarshalJSON() ([]byte, error)              { return marshalNode(n) }
+func (n *CreateViewStatement) UnmarshalJSON(data []byte) error           { return unmarshalNode(n, data) }
+func (n *CreateTableAsStatement) MarshalJSON() ([]byte, error)           { return marshalNode(n) }
+func (n *CreateTableAsStatement) UnmarshalJSON(data []byte) error        { return unmarshalNode(n, data) }
+func (n *RefreshMaterializedViewStatement) MarshalJSON() ([]byte, error) { return marshalNode(n) }
+func (n *RefreshMaterializedViewStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalNode(n, data)
+}
+func (n *ExplainStatement) MarshalJSON() ([]byte, error)    { return marshalNode(n) }
+func (n *ExplainStatement) UnmarshalJSON(data []byte) error { return unmarshalNode(n, data) }
@@ -0,0 +1,500 @@
+package nodes
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jsonCorpus returns the trees built by the tests in nodes_test.go, plus
+// DDL and EXPLAIN statements, so that every node type is round-tripped.
+func jsonCorpus() []struct {
+	name string
+	node Node
+} {
+	users := NewTable("users")
+	posts := NewTable("posts")
+	u := users.Alias("u")
+	sub := &SelectCore{From: posts, Projections: []Node{posts.Col("user_id")}}
+	frag, _ := NewSqlFragment("jsonb_path_exists(?, ?)", users.Col("data"), "$.tags")
+	win := NewWindowDef("w").Partition(users.Col("dept")).Order(users.Col("salary").Desc()).
+		Rows(Preceding(Literal(2)), Following(Literal(1)))
+
+	return []struct {
+		name string
+		node Node
+	}{
+		// Tables, attributes and literals
+		{"table", users},
+		{"table alias", u},
+		{"alias of subquery", &TableAlias{Relation: sub, AliasName: "s"}},
+		{"attribute", users.Col("id")},
+		{"alias attribute", u.Col("name")},
+		{"typed attribute", users.Col("age").Typed("integer")},
+		{"star", Star()},
+		{"qualified star", users.Star()},
+		{"literal", Literal(42)},
+		{"nil literal", Literal(nil)},
+		{"sql literal", NewSqlLiteral("NOW()")},
+		{"bound sql literal", NewBoundSqlLiteral("age > ?", 18)},
+		{"sql fragment", frag},
+
+		// Predications
+		{"eq", users.Col("id").Eq(1)},
+		{"not eq", users.Col("id").NotEq(1)},
+		{"gt", users.Col("age").Gt(18)},
+		{"gt eq", users.Col("age").GtEq(18)},
+		{"lt", users.Col("age").Lt(65)},
+		{"lt eq", users.Col("age").LtEq(65)},
+		{"like", users.Col("name").Like("A%")},
+		{"not like", users.Col("name").NotLike("A%")},
+		{"regexp", users.Col("name").MatchesRegexp("^A")},
+		{"not regexp", users.Col("name").DoesNotMatchRegexp("^A")},
+		{"distinct from", users.Col("a").IsDistinctFrom(users.Col("b"))},
+		{"not distinct from", users.Col("a").IsNotDistinctFrom(nil)},
+		{"case sensitive eq", users.Col("name").CaseSensitiveEq("x")},
+		{"case insensitive eq", users.Col("name").CaseInsensitiveEq("x")},
+		{"contains", users.Col("tags").Contains("go")},
+		{"overlaps", users.Col("tags").Overlaps("go")},
+		{"node to node", users.Col("id").Eq(posts.Col("user_id"))},
+		{"is null", users.Col("deleted_at").IsNull()},
+		{"is not null", users.Col("deleted_at").IsNotNull()},
+		{"in", users.Col("id").In(1, 2, 3)},
+		{"not in", users.Col("id").NotIn(1, 2)},
+		{"in subquery", users.Col("id").In(sub)},
+		{"between", users.Col("age").Between(18, 65)},
+		{"not between", users.Col("age").NotBetween(18, 65)},
+		{"eq any", users.Col("id").EqAny(1, 2)},
+		{"eq all", users.Col("id").EqAll(1, 2)},
+		{"matches any", users.Col("name").MatchesAny("a%", "b%")},
+		{"in all", users.Col("id").InAll([]any{1, 2}, []any{3})},
+
+		// Combinators
+		{"and", users.Col("a").Eq(1).And(users.Col("b").Eq(2))},
+		{"or", users.Col("a").Eq(1).Or(users.Col("b").Eq(2))},
+		{"not", users.Col("a").Eq(1).Not()},
+		{"bare or", NewOrNode(users.Col("a").Eq(1), users.Col("b").Eq(2))},
+		{"grouping", NewGroupingNode(users.Col("a").Eq(1))},
+		{"empty nodes", &ComparisonNode{}},
+
+		// Ordering
+		{"asc", users.Col("name").Asc()},
+		{"desc", users.Col("name").Desc()},
+		{"nulls last", NewOrderingNode(users.Col("name"), Desc, NullsLast)},
+
+		// SELECT
+		{"select core", &SelectCore{
+			CTEs:        []*CTENode{{Name: "recent", Query: sub, Columns: []string{"user_id"}}},
+			Comment:     "report",
+			Hints:       []string{"SET_VAR(x=1)"},
+			Distinct:    true,
+			DistinctOn:  []Node{users.Col("id")},
+			Projections: []Node{users.Col("id"), users.Col("name").As("n")},
+			From:        users,
+			Joins: []*JoinNode{
+				{Left: users, Right: posts, Type: LeftOuterJoin, On: users.Col("id").Eq(posts.Col("user_id"))},
+				{Left: users, Right: &TableAlias{Relation: sub, AliasName: "s"}, Type: CrossJoin, Lateral: true},
+			},
+			Wheres:     []Node{users.Col("active").Eq(true)},
+			Groups:     []Node{users.Col("dept")},
+			Havings:    []Node{Count(nil).Gt(1)},
+			Windows:    []*WindowDefinition{win},
+			Orders:     []Node{users.Col("name").Asc()},
+			Limit:      Literal(10),
+			Offset:     Literal(20),
+			Lock:       ForUpdate,
+			SkipLocked: true,
+		}},
+		{"string join", &SelectCore{From: users, Joins: []*JoinNode{
+			{Left: users, Right: NewSqlLiteral("JOIN posts USING (user_id)"), Type: StringJoin},
+		}}},
+		{"analytic select", &SelectCore{
+			Projections: []Node{users.Star().Excluding("password").Replacing(Lower(users.Col("email")).As("email"))},
+			From:        users,
+			Final:       true,
+			Sample:      &SampleClause{Ratio: 0.1, Rows: 1000, Offset: 0.5, Method: "bernoulli"},
+			Joins:       []*JoinNode{{Left: users, Right: users.Col("tags").As("tag"), Type: LeftArrayJoin}},
+			Qualifies:   []Node{users.Col("rank").Eq(1)},
+			LimitBy:     &LimitByClause{Limit: Literal(3), Offset: Literal(1), Columns: []Node{users.Col("dept")}},
+		}},
+		{"join", &JoinNode{Left: users, Right: posts, Type: FullOuterJoin}},
+		{"set operation", &SetOperationNode{
+			Left: sub, Right: sub, Type: UnionAll,
+			Orders: []Node{posts.Col("user_id").Asc()}, Limit: Literal(5), Offset: Literal(1),
+		}},
+		{"cte", &CTENode{Name: "tree", Query: sub, Recursive: true}},
+		{"exists", Exists(sub)},
+		{"not exists", NotExists(sub)},
+
+		// DML
+		{"insert", &InsertStatement{
+			Into:      users,
+			Columns:   []Node{users.Col("name"), users.Col("age")},
+			Values:    [][]Node{{Literal("Alice"), Literal(30)}, {Literal("Bob"), NewBindParam(40)}},
+			Returning: []Node{users.Col("id")},
+			OnConflict: &OnConflictNode{
+				Columns:     []Node{users.Col("name")},
+				Action:      DoUpdate,
+				Assignments: []*AssignmentNode{{Left: users.Col("age"), Right: Literal(31)}},
+				Wheres:      []Node{users.Col("age").Lt(31)},
+			},
+		}},
+		{"insert select", &InsertStatement{Into: users, Select: sub}},
+		{"update", &UpdateStatement{
+			Table:       users,
+			Assignments: []*AssignmentNode{{Left: users.Col("name"), Right: Literal("Carol")}},
+			Wheres:      []Node{users.Col("id").Eq(1)},
+			Returning:   []Node{users.Star()},
+		}},
+		{"delete", &DeleteStatement{From: users, Wheres: []Node{users.Col("id").Eq(1)}, Returning: []Node{users.Col("id")}}},
+		{"assignment", &AssignmentNode{Left: users.Col("name"), Right: Literal("x")}},
+		{"on conflict do nothing", &OnConflictNode{Columns: []Node{users.Col("id")}, Action: DoNothing}},
+
+		// Arithmetic
+		{"plus", users.Col("age").Plus(1)},
+		{"minus", users.Col("age").Minus(1)},
+		{"multiply", users.Col("age").Multiply(2)},
+		{"divide", users.Col("age").Divide(2)},
+		{"bitwise and", users.Col("flags").BitwiseAnd(4)},
+		{"bitwise or", users.Col("flags").BitwiseOr(4)},
+		{"bitwise xor", users.Col("flags").BitwiseXor(4)},
+		{"shift left", users.Col("flags").ShiftLeft(1)},
+		{"shift right", users.Col("flags").ShiftRight(1)},
+		{"concat", users.Col("first").Concat(users.Col("last"))},
+		{"bitwise not", users.Col("flags").BitwiseNot()},
+		{"chained arithmetic", users.Col("price").Multiply(users.Col("qty")).Plus(1).Gt(100)},
+
+		// Aggregates and EXTRACT
+		{"count star", Count(nil)},
+		{"count", Count(users.Col("id"))},
+		{"count distinct", CountDistinct(users.Col("dept"))},
+		{"sum", Sum(users.Col("salary"))},
+		{"avg", Avg(users.Col("salary"))},
+		{"min", Min(users.Col("salary"))},
+		{"max", Max(users.Col("salary"))},
+		{"filter", Count(nil).WithFilter(users.Col("active").Eq(true))},
+		{"extract year", Extract(ExtractYear, users.Col("created_at"))},
+		{"extract epoch", Extract(ExtractEpoch, users.Col("created_at")).Gt(0)},
+
+		// Window functions
+		{"row number", RowNumber()},
+		{"rank", Rank()},
+		{"dense rank", DenseRank()},
+		{"cume dist", CumeDist()},
+		{"percent rank", PercentRank()},
+		{"ntile", Ntile(Literal(4))},
+		{"first value", FirstValue(users.Col("salary"))},
+		{"last value", LastValue(users.Col("salary"))},
+		{"lag", Lag(users.Col("salary"), Literal(1), Literal(0))},
+		{"lead", Lead(users.Col("salary"))},
+		{"nth value", NthValue(users.Col("salary"), Literal(2))},
+		{"over", RowNumber().Over(win)},
+		{"over name", Rank().OverName("w")},
+		{"aggregate over", Sum(users.Col("salary")).Over(NewWindowDef().Range(UnboundedPreceding(), CurrentRow()))},
+		{"over unbounded following", Sum(users.Col("salary")).Over(NewWindowDef().Rows(CurrentRow(), UnboundedFollowing()))},
+		{"over single bound", Sum(users.Col("salary")).Over(NewWindowDef().Rows(UnboundedPreceding()))},
+		{"function over", Lower(users.Col("name")).OverName("w")},
+
+		// Functions, CASE and grouping sets
+		{"named function", NewNamedFunction("COALESCE", users.Col("nick"), Literal("anon"))},
+		{"coalesce", Coalesce(users.Col("nick"), users.Col("name"))},
+		{"lower", Lower(users.Col("name"))},
+		{"upper", Upper(users.Col("name"))},
+		{"substring", Substring(users.Col("name"), Literal(1), Literal(3))},
+		{"cast", Cast(users.Col("age"), "text")},
+		{"distinct function", &NamedFunctionNode{Name: "STRING_AGG", Args: []Node{users.Col("name")}, Distinct: true}},
+		{"searched case", NewCase().When(users.Col("age").Lt(18), Literal("minor")).Else(Literal("adult"))},
+		{"simple case", NewCase(users.Col("status")).When(Literal(1), Literal("active")).When(Literal(2), Literal("banned"))},
+		{"case as", NewCase().When(Literal(true), Literal(1)).As("flag")},
+		{"cube", NewCube(users.Col("a"), users.Col("b"))},
+		{"rollup", NewRollup(users.Col("a"), users.Col("b"))},
+		{"grouping sets", NewGroupingSets([]Node{users.Col("a")}, []Node{}, []Node{users.Col("a"), users.Col("b")})},
+
+		// Aliases and parameters
+		{"alias", NewAliasNode(users.Col("name"), "n")},
+		{"aggregate as", Count(nil).As("total")},
+		{"bind param", NewBindParam("Alice")},
+		{"named param", Named("tenant_id")},
+		{"typed named param", Named("tenant_id").WithType("UInt64")},
+		{"casted", NewCasted(42, "integer")},
+		{"coerce", users.Col("age").Typed("integer").Coerce(30)},
+
+		// DDL and EXPLAIN
+		{"create table", &CreateTableStatement{
+			Table:       users,
+			IfNotExists: true,
+			Columns: []*ColumnDef{
+				NewColumnDef("id", "bigint", PrimaryKey()),
+				NewColumnDef("email", "varchar(255)", NotNull(), Unique(), Default("")),
+				NewColumnDef("age", "integer", Check(NewTable("users").Col("age").GtEq(0))),
+				NewColumnDef("team_id", "bigint", References(NewTable("teams"), "id"), OnDelete(ActionCascade), OnUpdate(ActionSetNull)),
+				NewColumnDef("age2", "integer", GeneratedVirtual(users.Col("age").Multiply(2))),
+			},
+			Constraints: []*TableConstraint{
+				PrimaryKeyConstraint("id"),
+				UniqueConstraint("email").Named("users_email_key"),
+				CheckConstraint(users.Col("age").Lt(200)),
+				ForeignKeyConstraint([]string{"team_id"}, NewTable("teams"), "id").OnDelete(ActionRestrict),
+			},
+		}},
+		{"alter table", &AlterTableStatement{Table: users, Actions: []*AlterAction{
+			{Kind: AlterAddColumn, Column: NewColumnDef("bio", "text")},
+			{Kind: AlterDropColumn, Name: "bio"},
+			{Kind: AlterRenameColumn, Name: "name", NewName: "full_name"},
+			{Kind: AlterAddConstraint, Constraint: UniqueConstraint("email")},
+		}}},
+		{"create index", &CreateIndexStatement{
+			Name: "users_email_idx", Table: users, Columns: []Node{users.Col("email")},
+			Unique: true, Concurrently: true, IfNotExists: true, Wheres: []Node{users.Col("deleted_at").IsNull()},
+		}},
+		{"drop", &DropStatement{Kind: DropIndex, Name: "users_email_idx", On: users, IfExists: true, Cascade: true, Concurrently: true}},
+		{"create view", &CreateViewStatement{
+			Name: "active_users", Columns: []string{"id"}, OrReplace: true, IfNotExists: true,
+			Materialized: true, WithNoData: true, Query: sub,
+		}},
+		{"create table as", &CreateTableAsStatement{Table: NewTable("archive"), Columns: []string{"id"}, IfNotExists: true, Query: sub}},
+		{"refresh", &RefreshMaterializedViewStatement{Name: "active_users", Concurrently: true, WithNoData: true}},
+		{"explain", Explain(sub, ExplainOptions{Analyze: true, Buffers: true, Verbose: true, Format: ExplainJSON})},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tt := range jsonCorpus() {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(tt.node)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			got, err := Decode(data, AllowRawSQL())
+			if err != nil {
+				t.Fatalf("decode: %v\n%s", err, data)
+			}
+			if !Equal(got, tt.node) {
+				t.Errorf("round trip changed the tree:\n%s", data)
+			}
+			again, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("marshal again: %v", err)
+			}
+			if string(again) != string(data) {
+				t.Errorf("re-encoding differs:\n got: %s\nwant: %s", again, data)
+			}
+		})
+	}
+}
+
+func TestJSONCorpusCoversEveryNodeType(t *testing.T) {
+	t.Parallel()
+	seen := map[reflect.Type]bool{}
+	for _, tt := range jsonCorpus() {
+		seen[reflect.TypeOf(tt.node)] = true
+	}
+	vt := reflect.TypeFor[Visitor]()
+	for i := range vt.NumMethod() {
+		typ := vt.Method(i).Type.In(0)
+		if typ.Kind() == reflect.Interface {
+			// Extension nodes are defined outside this package.
+			continue
+		}
+		if !seen[typ] {
+			t.Errorf("no %s in the JSON corpus", typ)
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	data, err := json.Marshal(users.Col("age").Gt(int64(18)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":1,"node":{"type":"Comparison","op":"gt",` +
+		`"left":{"type":"Attribute","relation":{"type":"Table","name":"users"},"name":"age"},` +
+		`"right":{"type":"Literal","value":{"kind":"int64","value":18}}}}`
+	if string(data) != want {
+		t.Errorf("got  %s\nwant %s", data, want)
+	}
+}
+
+func TestJSONPreservesValueTypes(t *testing.T) {
+	t.Parallel()
+	when := time.Date(2026, 5, 1, 12, 30, 0, 0, time.FixedZone("", 2*3600))
+	values := []any{
+		"text", true, false, 0, 7, int8(-8), int16(16), int32(32), int64(math.MaxInt64),
+		uint(1), uint8(8), uint16(16), uint32(32), uint64(math.MaxUint64),
+		float32(1.5), 2.25, []byte("raw"), []int{1, 2}, []string{"a", "b"},
+		[]any{1, "a", nil}, NewTable("t").Col("c"),
+	}
+	for _, v := range values {
+		data, err := json.Marshal(NewBindParam(v))
+		if err != nil {
+			t.Fatalf("marshal %T: %v", v, err)
+		}
+		n, err := Decode(data)
+		if err != nil {
+			t.Fatalf("decode %T: %v", v, err)
+		}
+		got := n.(*BindParamNode).Value
+		if reflect.TypeOf(got) != reflect.TypeOf(v) {
+			t.Errorf("%T decoded as %T", v, got)
+		}
+		if !Equal(NewBindParam(got), NewBindParam(v)) {
+			t.Errorf("%T: got %v, want %v", v, got, v)
+		}
+	}
+
+	data, err := json.Marshal(NewBindParam(when))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.(*BindParamNode).Value.(time.Time); !got.Equal(when) || got.Format(time.RFC3339) != when.Format(time.RFC3339) {
+		t.Errorf("got %v, want %v", got, when)
+	}
+}
+
+func TestJSONRejectsUnsupportedValues(t *testing.T) {
+	t.Parallel()
+	type status string
+	for _, v := range []any{status("x"), struct{}{}, map[string]int{}, math.NaN()} {
+		if _, err := json.Marshal(Literal(v)); err == nil {
+			t.Errorf("expected an error for %T", v)
+		}
+	}
+}
+
+func TestJSONDecodeRestoresSelfPointers(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(NewTable("users").Col("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attr := n.(*Attribute)
+	if cmp := attr.Eq(1); cmp.Left != attr {
+		t.Error("expected Predications to reference the decoded attribute")
+	}
+	if sum := attr.Plus(1); sum.Left != attr {
+		t.Error("expected Arithmetics to reference the decoded attribute")
+	}
+
+	var into Attribute
+	if err := json.Unmarshal(data, &into); err != nil {
+		t.Fatal(err)
+	}
+	if cmp := into.Eq(1); cmp.Left != &into {
+		t.Error("expected UnmarshalJSON to point Predications at its receiver")
+	}
+	if and := into.Eq(1).And(into.IsNull()); and.Left.(*ComparisonNode).Left != &into {
+		t.Error("expected Combinable chains to reference the receiver")
+	}
+}
+
+func TestJSONRejectsRawSQL(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	core := &SelectCore{From: users, Wheres: []Node{users.Col("id").Eq(NewSqlLiteral("1 OR 1=1"))}}
+	data, err := json.Marshal(core)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Decode(data)
+	if !errors.Is(err, ErrRawSQL) {
+		t.Fatalf("expected ErrRawSQL, got %v", err)
+	}
+	if want := "nodes: Comparison.right: raw SQL is not allowed"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+
+	var into SelectCore
+	if err := json.Unmarshal(data, &into); !errors.Is(err, ErrRawSQL) {
+		t.Errorf("expected UnmarshalJSON to reject raw SQL, got %v", err)
+	}
+
+	if _, err := Decode(data, AllowRawSQL()); err != nil {
+		t.Errorf("expected AllowRawSQL to accept raw SQL, got %v", err)
+	}
+}
+
+func TestJSONDecodeErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not JSON", `{`, "nodes: invalid JSON document"},
+		{"no version", `{"node":{"type":"Table","name":"t"}}`, "nodes: unsupported JSON version 0 (want 1)"},
+		{"future version", `{"version":2,"node":{"type":"Table","name":"t"}}`, "nodes: unsupported JSON version 2 (want 1)"},
+		{"no node", `{"version":1}`, `nodes: JSON document has no "node"`},
+		{"no type", `{"version":1,"node":{"name":"t"}}`, `nodes: node: missing node "type"`},
+		{"unknown type", `{"version":1,"node":{"type":"Script"}}`, `nodes: node: unknown node type "Script"`},
+		{"unknown field", `{"version":1,"node":{"type":"Table","name":"t","schema":"x"}}`, `nodes: Table: unknown field "schema"`},
+		{"unknown enum", `{"version":1,"node":{"type":"Unary","op":"is_maybe"}}`, `nodes: Unary.op: unknown value "is_maybe"`},
+		{"wrong field type", `{"version":1,"node":{"type":"Table","name":1}}`, "nodes: Table.name: expected a string"},
+		{"wrong node type", `{"version":1,"node":{"type":"Star","table":{"type":"NamedParam","name":"x"}}}`, "nodes: Star.table: unexpected *nodes.NamedParamNode"},
+		{"unknown value kind", `{"version":1,"node":{"type":"Literal","value":{"kind":"complex128","value":1}}}`, `nodes: Literal.value: unknown value kind "complex128"`},
+		{"bad value", `{"version":1,"node":{"type":"Literal","value":{"kind":"int8","value":300}}}`, "nodes: Literal.value.value: expected a int8 value"},
+		{"list item", `{"version":1,"node":{"type":"In","values":[{"type":"Nope"}]}}`, `nodes: In.values[0]: unknown node type "Nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Decode([]byte(tt.data))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("got %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONUnmarshalWrongNodeType(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(NewTable("users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attr Attribute
+	err = json.Unmarshal(data, &attr)
+	if err == nil || err.Error() != "nodes: cannot unmarshal *nodes.Table into *nodes.Attribute" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestJSONMarshalNestedInStructs(t *testing.T) {
+	t.Parallel()
+	type report struct {
+		Name  string      `json:"name"`
+		Query *SelectCore `json:"query"`
+	}
+	users := NewTable("users")
+	in := report{Name: "active", Query: &SelectCore{From: users, Wheres: []Node{users.Col("active").Eq(true)}}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out report
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != in.Name || !Equal(out.Query, in.Query) {
+		t.Errorf("round trip changed the report:\n%s", data)
+	}
+}
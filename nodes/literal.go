@@ -1,5 +1,10 @@
 package nodes
 
+import (
+	"fmt"
+	"strings"
+)
+
 // RawSQL is a string type that marks a value as a developer-controlled raw SQL
 // fragment. It is intentionally not interchangeable with a plain string to prevent
 // user-controlled input from reaching raw SQL injection points without an explicit
@@ -20,12 +25,32 @@ type LiteralNode struct {
 func (n *LiteralNode) Accept(v Visitor) string { return v.VisitLiteral(n) }
 
 // StarNode represents a SQL star (*) or qualified star (table.*).
+// Exclude and Replace modify the columns it expands to: * EXCLUDE (...)
+// and * REPLACE (...) in DuckDB, * EXCEPT (...) and * REPLACE (...) in
+// ClickHouse.
 type StarNode struct {
-	Table *Table // nil for unqualified *
+	Table   *Table       // nil for unqualified *
+	Exclude []string     // columns left out of the expansion
+	Replace []*AliasNode // expressions that replace the column they are aliased to
 }
 
 func (n *StarNode) Accept(v Visitor) string { return v.VisitStar(n) }
 
+// Excluding returns a copy of the star that leaves out the named columns.
+func (n *StarNode) Excluding(columns ...string) *StarNode {
+	out := *n
+	out.Exclude = append(append([]string(nil), n.Exclude...), columns...)
+	return &out
+}
+
+// Replacing returns a copy of the star in which each column named by an
+// alias is replaced by the aliased expression.
+func (n *StarNode) Replacing(replacements ...*AliasNode) *StarNode {
+	out := *n
+	out.Replace = append(append([]*AliasNode(nil), n.Replace...), replacements...)
+	return &out
+}
+
 // SqlLiteral represents a raw SQL fragment injected verbatim into the query.
 //
 // SECURITY: The Raw field is rendered directly into SQL output without escaping
@@ -38,6 +63,10 @@ type SqlLiteral struct {
 	Combinable
 	Raw   RawSQL
 	Binds []any // optional bind parameters for parameterized mode
+
+	// Placeholders marks Raw as using neutral ? markers, one per Bind, that
+	// the visitor rewrites to its own placeholders. See NewSqlFragment.
+	Placeholders bool
 }
 
 func NewSqlLiteral(raw RawSQL) *SqlLiteral {
@@ -61,6 +90,61 @@ func NewBoundSqlLiteral(raw RawSQL, binds ...any) *SqlLiteral {
 	return n
 }
 
+// NewSqlFragment creates a placeholder-aware SqlLiteral. Each ? marker in
+// raw is replaced, in order, by the matching argument: a Node argument is
+// rendered in place, any other value becomes a bind parameter using the
+// dialect's placeholder and running index ($3, ?, ...). Markers inside
+// quoted strings and identifiers are ignored, and ?? writes a literal ?.
+//
+// An error is returned when the number of markers and arguments differ.
+//
+//	nodes.NewSqlFragment("jsonb_path_exists(?, ?)", users.Col("data"), "$.tags")
+//
+// SECURITY: Only the arguments are parameterized. The raw string is injected
+// verbatim into SQL output and must not contain user-controlled input.
+func NewSqlFragment(raw RawSQL, args ...any) (*SqlLiteral, error) {
+	if markers := len(SplitFragment(raw)) - 1; markers != len(args) {
+		return nil, fmt.Errorf("gosbee: SQL fragment %q has %d placeholder(s) but %d argument(s)", string(raw), markers, len(args))
+	}
+	n := NewSqlLiteral(raw)
+	n.Binds = args
+	n.Placeholders = true
+	return n, nil
+}
+
+// SplitFragment splits a placeholder-aware fragment at its ? markers and
+// returns the text around them, so a fragment with n markers yields n+1
+// parts. Markers inside '...', "..." and `...` quotes are kept as text,
+// and ?? is unescaped to a single literal ?.
+func SplitFragment(raw RawSQL) []string {
+	var parts []string
+	var sb strings.Builder
+	var quote byte
+	s := string(raw)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'', c == '"', c == '`':
+			quote = c
+		case c == '?':
+			if i+1 < len(s) && s[i+1] == '?' {
+				sb.WriteByte('?')
+				i++
+				continue
+			}
+			parts = append(parts, sb.String())
+			sb.Reset()
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return append(parts, sb.String())
+}
+
 // Star returns an unqualified StarNode representing SQL *.
 func Star() *StarNode {
 	return &StarNode{}
@@ -25,3 +25,25 @@ type NotNode struct {
 }
 
 func (n *NotNode) Accept(v Visitor) string { return v.VisitNot(n) }
+
+// NewAndNode creates an AndNode with properly initialised embedded structs.
+func NewAndNode(left, right Node) *AndNode {
+	n := &AndNode{Left: left, Right: right}
+	n.self = n
+	return n
+}
+
+// NewOrNode creates an OrNode with properly initialised embedded structs.
+// Unlike Combinable.Or, the result is not wrapped in a GroupingNode.
+func NewOrNode(left, right Node) *OrNode {
+	n := &OrNode{Left: left, Right: right}
+	n.self = n
+	return n
+}
+
+// NewNotNode creates a NotNode with properly initialised embedded structs.
+func NewNotNode(expr Node) *NotNode {
+	n := &NotNode{Expr: expr}
+	n.self = n
+	return n
+}
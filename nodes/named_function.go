@@ -47,6 +47,30 @@ func Cast(expr Node, typeName string) *NamedFunctionNode {
 	return NewNamedFunction("CAST", expr, NewSqlLiteral(RawSQL(typeName)))
 }
 
+// ArgMax creates an ARG_MAX(arg, val) aggregate: the value of arg in the
+// row with the largest val. ClickHouse spells it argMax.
+func ArgMax(arg, val Node) *NamedFunctionNode {
+	return NewNamedFunction("ARG_MAX", arg, val)
+}
+
+// ArgMin creates an ARG_MIN(arg, val) aggregate: the value of arg in the
+// row with the smallest val. ClickHouse spells it argMin.
+func ArgMin(arg, val Node) *NamedFunctionNode {
+	return NewNamedFunction("ARG_MIN", arg, val)
+}
+
+// AnyValue creates an ANY_VALUE(expr) aggregate, which returns the value
+// of expr from an arbitrary row of the group. ClickHouse spells it any.
+func AnyValue(expr Node) *NamedFunctionNode {
+	return NewNamedFunction("ANY_VALUE", expr)
+}
+
+// ApproxCountDistinct creates an APPROX_COUNT_DISTINCT(expr) aggregate,
+// which estimates the number of distinct values. ClickHouse spells it uniq.
+func ApproxCountDistinct(expr Node) *NamedFunctionNode {
+	return NewNamedFunction("APPROX_COUNT_DISTINCT", expr)
+}
+
 // Over wraps the named function with an inline window definition.
 func (n *NamedFunctionNode) Over(def *WindowDefinition) *OverNode {
 	o := NewOverNode(n)
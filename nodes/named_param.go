@@ -0,0 +1,32 @@
+package nodes
+
+// NamedParamNode represents a named bind placeholder whose value is
+// supplied later, when a compiled query template is bound.
+// It always renders as the dialect's bind placeholder; the visitor records
+// the node itself in the parameter list so the slot can be filled by name.
+type NamedParamNode struct {
+	Predications
+	Arithmetics
+	Combinable
+	Name     string
+	TypeName string // parameter type for dialects with typed placeholders (ClickHouse)
+}
+
+func (n *NamedParamNode) Accept(v Visitor) string { return v.VisitNamedParam(n) }
+
+// Named creates a NamedParamNode for the given parameter name.
+func Named(name string) *NamedParamNode {
+	n := &NamedParamNode{Name: name}
+	n.Predications.self = n
+	n.Arithmetics.self = n
+	n.Combinable.self = n
+	return n
+}
+
+// WithType returns a copy of the parameter declared with a SQL type, which
+// ClickHouse placeholders require: {id:UInt64}. Other dialects ignore it.
+func (n *NamedParamNode) WithType(typeName string) *NamedParamNode {
+	out := Named(n.Name)
+	out.TypeName = typeName
+	return out
+}
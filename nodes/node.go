@@ -46,6 +46,16 @@ type Visitor interface {
 	VisitAlias(node *AliasNode) string
 	VisitBindParam(node *BindParamNode) string
 	VisitCasted(node *CastedNode) string
+	VisitNamedParam(node *NamedParamNode) string
+	VisitCreateTable(node *CreateTableStatement) string
+	VisitAlterTable(node *AlterTableStatement) string
+	VisitCreateIndex(node *CreateIndexStatement) string
+	VisitDrop(node *DropStatement) string
+	VisitCreateView(node *CreateViewStatement) string
+	VisitCreateTableAs(node *CreateTableAsStatement) string
+	VisitRefreshMaterializedView(node *RefreshMaterializedViewStatement) string
+	VisitExplain(node *ExplainStatement) string
+	VisitExtension(node Extension) string
 }
 
 // Parameterizer is implemented by visitors that support parameterized queries.
@@ -55,6 +65,38 @@ type Parameterizer interface {
 	Reset()
 }
 
+// Renderer is implemented by dialects that can render a whole tree in one
+// call using per-call state. Unlike Accept followed by Parameterizer.Params,
+// Render does not mutate its receiver, so a Renderer may be shared by
+// concurrent goroutines. A node the dialect cannot render is reported as
+// an *UnsupportedError or *RenderError.
+type Renderer interface {
+	Render(node Node) (string, []any, error)
+}
+
+// UnsupportedError reports a node or clause that a dialect cannot render.
+// Visitors panic with it while walking the tree; manager ToSQL methods
+// recover it and return it as an error.
+type UnsupportedError struct {
+	Dialect string // e.g. "MySQL"
+	Feature string // e.g. "partial indexes"
+}
+
+func (e *UnsupportedError) Error() string {
+	return "gosbee: " + e.Dialect + " does not support " + e.Feature
+}
+
+// RenderError reports a node that cannot be rendered in any dialect, such
+// as a literal of an unsupported Go type. Like UnsupportedError, visitors
+// panic with it and manager ToSQL methods return it as an error.
+type RenderError struct {
+	Message string
+}
+
+func (e *RenderError) Error() string {
+	return "gosbee: " + e.Message
+}
+
 // Literal wraps a raw Go value into a LiteralNode. If val already
 // implements Node, it is returned as-is.
 func Literal(val any) Node {
@@ -62,6 +62,24 @@ func TestUnqualifiedStar(t *testing.T) {
 	}
 }
 
+func TestStarExcludingAndReplacing(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	star := users.Star()
+	ex := star.Excluding("password", "salt")
+	rep := ex.Replacing(Lower(users.Col("email")).As("email"))
+
+	if len(star.Exclude) != 0 || len(star.Replace) != 0 {
+		t.Error("expected Excluding and Replacing to leave the original star unchanged")
+	}
+	if len(ex.Exclude) != 2 || len(ex.Replace) != 0 {
+		t.Errorf("expected 2 excluded columns, got %v", ex.Exclude)
+	}
+	if rep.Table != users || len(rep.Exclude) != 2 || len(rep.Replace) != 1 {
+		t.Errorf("expected the replacement to keep the table and exclusions, got %+v", rep)
+	}
+}
+
 // --- Literal wrapping ---
 
 func TestLiteralWrapsRawValues(t *testing.T) {
@@ -508,36 +526,50 @@ func (sv stubVisitor) VisitComparison(n *ComparisonNode) string {
 	}
 	return n.Left.Accept(sv) + "=?" + n.Right.Accept(sv)
 }
-func (sv stubVisitor) VisitUnary(*UnaryNode) string                 { return "unary" }
-func (sv stubVisitor) VisitAnd(*AndNode) string                     { return "and" }
-func (sv stubVisitor) VisitOr(*OrNode) string                       { return "or" }
-func (sv stubVisitor) VisitNot(*NotNode) string                     { return "not" }
-func (sv stubVisitor) VisitIn(*InNode) string                       { return "in" }
-func (sv stubVisitor) VisitBetween(*BetweenNode) string             { return "between" }
-func (sv stubVisitor) VisitGrouping(*GroupingNode) string           { return "grouping" }
-func (sv stubVisitor) VisitJoin(*JoinNode) string                   { return "join" }
-func (sv stubVisitor) VisitOrdering(*OrderingNode) string           { return "ordering" }
-func (sv stubVisitor) VisitSelectCore(*SelectCore) string           { return "select_core" }
-func (sv stubVisitor) VisitInsertStatement(*InsertStatement) string { return "insert" }
-func (sv stubVisitor) VisitUpdateStatement(*UpdateStatement) string { return "update" }
-func (sv stubVisitor) VisitDeleteStatement(*DeleteStatement) string { return "delete" }
-func (sv stubVisitor) VisitAssignment(*AssignmentNode) string       { return "assign" }
-func (sv stubVisitor) VisitOnConflict(*OnConflictNode) string       { return "conflict" }
-func (sv stubVisitor) VisitInfix(*InfixNode) string                 { return "infix" }
-func (sv stubVisitor) VisitUnaryMath(*UnaryMathNode) string         { return "unary_math" }
-func (sv stubVisitor) VisitAggregate(*AggregateNode) string         { return "aggregate" }
-func (sv stubVisitor) VisitExtract(*ExtractNode) string             { return "extract" }
-func (sv stubVisitor) VisitWindowFunction(*WindowFuncNode) string   { return "window_func" }
-func (sv stubVisitor) VisitOver(*OverNode) string                   { return "over" }
-func (sv stubVisitor) VisitExists(*ExistsNode) string               { return "exists" }
-func (sv stubVisitor) VisitSetOperation(*SetOperationNode) string   { return "set_op" }
-func (sv stubVisitor) VisitCTE(*CTENode) string                     { return "cte" }
-func (sv stubVisitor) VisitNamedFunction(*NamedFunctionNode) string { return "named_func" }
-func (sv stubVisitor) VisitCase(*CaseNode) string                   { return "case" }
-func (sv stubVisitor) VisitGroupingSet(*GroupingSetNode) string     { return "grouping_set" }
-func (sv stubVisitor) VisitAlias(*AliasNode) string                 { return "alias" }
-func (sv stubVisitor) VisitBindParam(*BindParamNode) string         { return "bind_param" }
-func (sv stubVisitor) VisitCasted(*CastedNode) string               { return "casted" }
+func (sv stubVisitor) VisitUnary(*UnaryNode) string                  { return "unary" }
+func (sv stubVisitor) VisitAnd(*AndNode) string                      { return "and" }
+func (sv stubVisitor) VisitOr(*OrNode) string                        { return "or" }
+func (sv stubVisitor) VisitNot(*NotNode) string                      { return "not" }
+func (sv stubVisitor) VisitIn(*InNode) string                        { return "in" }
+func (sv stubVisitor) VisitBetween(*BetweenNode) string              { return "between" }
+func (sv stubVisitor) VisitGrouping(*GroupingNode) string            { return "grouping" }
+func (sv stubVisitor) VisitJoin(*JoinNode) string                    { return "join" }
+func (sv stubVisitor) VisitOrdering(*OrderingNode) string            { return "ordering" }
+func (sv stubVisitor) VisitSelectCore(*SelectCore) string            { return "select_core" }
+func (sv stubVisitor) VisitInsertStatement(*InsertStatement) string  { return "insert" }
+func (sv stubVisitor) VisitUpdateStatement(*UpdateStatement) string  { return "update" }
+func (sv stubVisitor) VisitDeleteStatement(*DeleteStatement) string  { return "delete" }
+func (sv stubVisitor) VisitAssignment(*AssignmentNode) string        { return "assign" }
+func (sv stubVisitor) VisitOnConflict(*OnConflictNode) string        { return "conflict" }
+func (sv stubVisitor) VisitInfix(*InfixNode) string                  { return "infix" }
+func (sv stubVisitor) VisitUnaryMath(*UnaryMathNode) string          { return "unary_math" }
+func (sv stubVisitor) VisitAggregate(*AggregateNode) string          { return "aggregate" }
+func (sv stubVisitor) VisitExtract(*ExtractNode) string              { return "extract" }
+func (sv stubVisitor) VisitWindowFunction(*WindowFuncNode) string    { return "window_func" }
+func (sv stubVisitor) VisitOver(*OverNode) string                    { return "over" }
+func (sv stubVisitor) VisitExists(*ExistsNode) string                { return "exists" }
+func (sv stubVisitor) VisitSetOperation(*SetOperationNode) string    { return "set_op" }
+func (sv stubVisitor) VisitCTE(*CTENode) string                      { return "cte" }
+func (sv stubVisitor) VisitNamedFunction(*NamedFunctionNode) string  { return "named_func" }
+func (sv stubVisitor) VisitCase(*CaseNode) string                    { return "case" }
+func (sv stubVisitor) VisitGroupingSet(*GroupingSetNode) string      { return "grouping_set" }
+func (sv stubVisitor) VisitAlias(*AliasNode) string                  { return "alias" }
+func (sv stubVisitor) VisitBindParam(*BindParamNode) string          { return "bind_param" }
+func (sv stubVisitor) VisitCasted(*CastedNode) string                { return "casted" }
+func (sv stubVisitor) VisitNamedParam(*NamedParamNode) string        { return "named_param" }
+func (sv stubVisitor) VisitCreateTable(*CreateTableStatement) string { return "create_table" }
+func (sv stubVisitor) VisitAlterTable(*AlterTableStatement) string   { return "alter_table" }
+func (sv stubVisitor) VisitCreateIndex(*CreateIndexStatement) string { return "create_index" }
+func (sv stubVisitor) VisitDrop(*DropStatement) string               { return "drop" }
+func (sv stubVisitor) VisitCreateView(*CreateViewStatement) string   { return "create_view" }
+func (sv stubVisitor) VisitCreateTableAs(*CreateTableAsStatement) string {
+	return "create_table_as"
+}
+func (sv stubVisitor) VisitRefreshMaterializedView(*RefreshMaterializedViewStatement) string {
+	return "refresh"
+}
+func (sv stubVisitor) VisitExplain(*ExplainStatement) string { return "explain" }
+func (sv stubVisitor) VisitExtension(Extension) string       { return "extension" }
 
 func TestAllNodesImplementNodeInterface(t *testing.T) {
 	t.Parallel()
@@ -578,6 +610,7 @@ func TestAllNodesImplementNodeInterface(t *testing.T) {
 	nodes = append(nodes, NewAliasNode(NewAttribute(NewTable("t"), "c"), "alias"))
 	nodes = append(nodes, NewBindParam(42))
 	nodes = append(nodes, NewCasted(42, "integer"))
+	nodes = append(nodes, Named("tenant_id"))
 
 	for _, n := range nodes {
 		n.Accept(sv) // should not panic
@@ -1707,6 +1740,20 @@ func TestBindParamAccept(t *testing.T) {
 	bp.Accept(stubVisitor{}) // should not panic
 }
 
+// --- NamedParamNode ---
+
+func TestNamed(t *testing.T) {
+	t.Parallel()
+	n := Named("tenant_id")
+	if n.Name != "tenant_id" {
+		t.Errorf("expected name %q, got %q", "tenant_id", n.Name)
+	}
+	cmp := n.Eq(1)
+	if cmp.Left != n {
+		t.Error("expected Predications self to reference the named param")
+	}
+}
+
 // --- CastedNode ---
 
 func TestNewCasted(t *testing.T) {
@@ -1953,6 +2000,34 @@ func TestNewUnaryMathNode(t *testing.T) {
 
 // --- Over/OverName for named functions ---
 
+func TestPredicateConstructorsSetSelf(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	a, b := users.Col("a"), users.Col("b")
+
+	checks := []struct {
+		name string
+		node interface {
+			Node
+			And(Node) *AndNode
+		}
+	}{
+		{"And", NewAndNode(a, b)},
+		{"Or", NewOrNode(a, b)},
+		{"Not", NewNotNode(a)},
+		{"Grouping", NewGroupingNode(a)},
+		{"In", NewInNode(a, []Node{Literal(1)}, false)},
+		{"Between", NewBetweenNode(a, Literal(1), Literal(2), true)},
+		{"Unary", NewUnaryNode(a, OpIsNull)},
+		{"Ordering", NewOrderingNode(a, Desc, NullsLast)},
+	}
+	for _, c := range checks {
+		if and := c.node.And(b); and.Left != Node(c.node) {
+			t.Errorf("%s: expected Combinable.self to be set correctly", c.name)
+		}
+	}
+}
+
 func TestNamedFunctionOver(t *testing.T) {
 	t.Parallel()
 	fn := Lower(Literal("NAME"))
@@ -2024,3 +2099,119 @@ func TestSetOpTypeString(t *testing.T) {
 		}
 	}
 }
+
+// --- Equal ---
+
+func TestEqualStructurallyIdentical(t *testing.T) {
+	t.Parallel()
+	a := NewTable("users").Col("id").Eq(1).And(NewTable("users").Col("name").Eq(NewBindParam("Alice")))
+	b := NewTable("users").Col("id").Eq(1).And(NewTable("users").Col("name").Eq(NewBindParam("Alice")))
+	if !Equal(a, b) {
+		t.Error("expected trees to be equal")
+	}
+}
+
+func TestEqualDetectsDifferences(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	tests := []struct {
+		name string
+		a, b Node
+	}{
+		{"value", users.Col("id").Eq(1), users.Col("id").Eq(2)},
+		{"value type", users.Col("id").Eq(1), users.Col("id").Eq(int64(1))},
+		{"operator", users.Col("id").Eq(1), users.Col("id").Gt(1)},
+		{"column", users.Col("id").Eq(1), users.Col("uid").Eq(1)},
+		{"table", users.Col("id").Eq(1), NewTable("posts").Col("id").Eq(1)},
+		{"node type", users.Col("id").Eq(1), users.Col("id").Eq(NewBindParam(1))},
+		{"list length", users.Col("id").In(1, 2), users.Col("id").In(1, 2, 3)},
+		{"nil", users.Col("id"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Equal(tt.a, tt.b) {
+				t.Errorf("expected %s difference to make trees unequal", tt.name)
+			}
+		})
+	}
+}
+
+func TestEqualIgnoresConstructorBackReferences(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	built := NewComparisonNode(users.Col("id"), Literal(1), OpEq)
+	literal := &ComparisonNode{Left: users.Col("id"), Right: &LiteralNode{Value: 1}, Op: OpEq}
+	if !Equal(built, literal) {
+		t.Error("expected constructor and struct literal nodes to be equal")
+	}
+}
+
+func TestEqualNilAndEmptySlices(t *testing.T) {
+	t.Parallel()
+	users := NewTable("users")
+	a := &SelectCore{From: users}
+	b := &SelectCore{From: users, Wheres: []Node{}}
+	if !Equal(a, b) {
+		t.Error("expected nil and empty slices to compare equal")
+	}
+	if !Equal(nil, nil) {
+		t.Error("expected nil nodes to be equal")
+	}
+}
+
+// --- SqlFragment ---
+
+func TestNewSqlFragment(t *testing.T) {
+	t.Parallel()
+	col := NewTable("users").Col("id")
+	frag, err := NewSqlFragment("coalesce(?, ?)", col, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !frag.Placeholders {
+		t.Error("expected Placeholders to be set")
+	}
+	if len(frag.Binds) != 2 || frag.Binds[0] != col || frag.Binds[1] != 0 {
+		t.Errorf("unexpected binds: %v", frag.Binds)
+	}
+}
+
+func TestNewSqlFragmentCountMismatch(t *testing.T) {
+	t.Parallel()
+	if _, err := NewSqlFragment("a = ? AND b = ?", 1); err == nil {
+		t.Error("expected error for too few arguments")
+	}
+	if _, err := NewSqlFragment("a = ?", 1, 2); err == nil {
+		t.Error("expected error for too many arguments")
+	}
+}
+
+func TestSplitFragment(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		raw  RawSQL
+		want []string
+	}{
+		{"NOW()", []string{"NOW()"}},
+		{"a = ?", []string{"a = ", ""}},
+		{"? + ?", []string{"", " + ", ""}},
+		{"data ?? 'key' AND id = ?", []string{"data ? 'key' AND id = ", ""}},
+		{"x = '?' AND y = ?", []string{"x = '?' AND y = ", ""}},
+		{`"col?" = ?`, []string{`"col?" = `, ""}},
+		{"`col?` = ?", []string{"`col?` = ", ""}},
+		{"'it''s?' = ?", []string{"'it''s?' = ", ""}},
+	}
+	for _, tt := range tests {
+		got := SplitFragment(tt.raw)
+		if len(got) != len(tt.want) {
+			t.Errorf("SplitFragment(%q) = %q, want %q", tt.raw, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SplitFragment(%q) = %q, want %q", tt.raw, got, tt.want)
+				break
+			}
+		}
+	}
+}
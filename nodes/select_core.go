@@ -31,13 +31,17 @@ func (m LockMode) String() string {
 // The fluent API for building queries lives in the managers package.
 type SelectCore struct {
 	From        Node
+	Final       bool          // FINAL modifier on the FROM table (ClickHouse)
+	Sample      *SampleClause // SAMPLE (ClickHouse) or USING SAMPLE (DuckDB)
 	Projections []Node
 	Wheres      []Node
 	Joins       []*JoinNode
 	Groups      []Node              // GROUP BY expressions
 	Havings     []Node              // HAVING conditions
 	Windows     []*WindowDefinition // WINDOW definitions
+	Qualifies   []Node              // QUALIFY conditions on window functions
 	Orders      []Node              // OrderingNode values
+	LimitBy     *LimitByClause      // LIMIT n BY columns (ClickHouse)
 	Limit       Node                // nil or LiteralNode
 	Offset      Node                // nil or LiteralNode
 	Distinct    bool
@@ -50,3 +54,20 @@ type SelectCore struct {
 }
 
 func (n *SelectCore) Accept(v Visitor) string { return v.VisitSelectCore(n) }
+
+// SampleClause reads a random sample of the rows of a query's FROM clause.
+// Size the sample with either Ratio or Rows.
+type SampleClause struct {
+	Ratio  float64 // fraction of the rows to read, in (0, 1]
+	Rows   int64   // approximate number of rows to read; overrides Ratio
+	Offset float64 // fraction of the data to skip first (ClickHouse)
+	Method string  // sampling method (DuckDB): reservoir, bernoulli or system
+}
+
+// LimitByClause keeps the first Limit rows, after skipping Offset, of each
+// distinct combination of Columns values (ClickHouse LIMIT n BY).
+type LimitByClause struct {
+	Limit   Node
+	Offset  Node // nil for no offset
+	Columns []Node
+}
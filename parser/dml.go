@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// insert parses an INSERT statement.
+func (p *parser) insert() nodes.Node {
+	p.expect("INSERT")
+	if t := p.peek(); p.at("IGNORE", "OR", "LOW_PRIORITY", "DELAYED", "HIGH_PRIORITY") {
+		p.fail(t, "INSERT %s is not supported", strings.ToUpper(t.text))
+	}
+	p.expect("INTO")
+	t := p.peek()
+	name := p.ident()
+	if p.isOp(p.peek(), ".") {
+		p.fail(t, "schema-qualified table names are not supported")
+	}
+	if p.at("AS") {
+		p.fail(p.peek(), "INSERT target aliases are not supported")
+	}
+	target := p.table(name)
+	stmt := &nodes.InsertStatement{Into: target.node}
+
+	if p.isOp(p.peek(), "(") && !p.isKeyword(p.peekAt(1), "SELECT") && !p.isKeyword(p.peekAt(1), "WITH") {
+		p.pos++
+		for {
+			stmt.Columns = append(stmt.Columns, nodes.NewAttribute(target.node, p.ident()))
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+		p.expectOp(")")
+	}
+
+	switch t := p.peek(); {
+	case p.accept("VALUES"):
+		for {
+			p.expectOp("(")
+			stmt.Values = append(stmt.Values, p.values())
+			p.expectOp(")")
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	case p.at("SELECT", "WITH"), p.isOp(t, "("):
+		stmt.Select = p.query()
+	case p.at("DEFAULT"):
+		p.fail(t, "DEFAULT VALUES is not supported")
+	default:
+		p.fail(t, "expected VALUES or SELECT, found %s", describe(t))
+	}
+
+	// The target is in scope only for ON CONFLICT and RETURNING, so that
+	// the source query cannot resolve columns to it.
+	p.scope.relations = append(p.scope.relations, target)
+	if t := p.peek(); p.accept("ON") {
+		if !p.accept("CONFLICT") {
+			p.fail(t, "ON %s is not supported", describe(p.peek()))
+		}
+		stmt.OnConflict = p.onConflict(target)
+	}
+	if p.accept("RETURNING") {
+		stmt.Returning = p.returning()
+	}
+	return stmt
+}
+
+// values parses the values of a VALUES row. DEFAULT is kept as raw SQL.
+func (p *parser) values() []nodes.Node {
+	var row []nodes.Node
+	for {
+		row = append(row, p.value())
+		if !p.acceptOp(",") {
+			return row
+		}
+	}
+}
+
+// value parses an INSERT value or SET value.
+func (p *parser) value() nodes.Node {
+	if p.accept("DEFAULT") {
+		return nodes.NewSqlLiteral("DEFAULT")
+	}
+	return p.fallback(stopList, p.expr)
+}
+
+// onConflict parses the rest of an ON CONFLICT clause.
+func (p *parser) onConflict(target *relation) *nodes.OnConflictNode {
+	oc := &nodes.OnConflictNode{}
+	if t := p.peek(); p.accept("ON", "CONSTRAINT") {
+		p.fail(t, "ON CONFLICT ON CONSTRAINT is not supported")
+	}
+	if p.acceptOp("(") {
+		for {
+			oc.Columns = append(oc.Columns, nodes.NewAttribute(target.node, p.ident()))
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+		p.expectOp(")")
+		if p.at("WHERE") {
+			p.fail(p.peek(), "ON CONFLICT ... WHERE is not supported")
+		}
+	}
+	p.expect("DO")
+	if p.accept("NOTHING") {
+		return oc
+	}
+	p.expect("UPDATE", "SET")
+	oc.Action = nodes.DoUpdate
+	oc.Assignments = p.assignments(target)
+	if p.accept("WHERE") {
+		oc.Wheres = p.conditions()
+	}
+	return oc
+}
+
+// update parses an UPDATE statement.
+func (p *parser) update() nodes.Node {
+	p.expect("UPDATE")
+	if t := p.peek(); p.at("OR", "LOW_PRIORITY", "IGNORE", "ONLY") {
+		p.fail(t, "UPDATE %s is not supported", strings.ToUpper(t.text))
+	}
+	target := p.target()
+	stmt := &nodes.UpdateStatement{Table: target.node}
+	p.expect("SET")
+	stmt.Assignments = p.assignments(target)
+	if t := p.peek(); p.at("FROM") {
+		p.fail(t, "UPDATE ... FROM is not supported")
+	}
+	if p.accept("WHERE") {
+		stmt.Wheres = p.conditions()
+	}
+	if p.accept("RETURNING") {
+		stmt.Returning = p.returning()
+	}
+	return stmt
+}
+
+// delete parses a DELETE statement.
+func (p *parser) delete() nodes.Node {
+	p.expect("DELETE")
+	if t := p.peek(); p.at("LOW_PRIORITY", "QUICK", "IGNORE") {
+		p.fail(t, "DELETE %s is not supported", strings.ToUpper(t.text))
+	}
+	p.expect("FROM")
+	if t := p.peek(); p.at("ONLY") {
+		p.fail(t, "DELETE FROM ONLY is not supported")
+	}
+	target := p.target()
+	stmt := &nodes.DeleteStatement{From: target.node}
+	if t := p.peek(); p.at("USING") {
+		p.fail(t, "DELETE ... USING is not supported")
+	}
+	if p.accept("WHERE") {
+		stmt.Wheres = p.conditions()
+	}
+	if p.accept("RETURNING") {
+		stmt.Returning = p.returning()
+	}
+	return stmt
+}
+
+// target parses the table of an UPDATE or DELETE, with an optional alias,
+// and adds it to the scope.
+func (p *parser) target() *relation {
+	t := p.peek()
+	name := p.ident()
+	if p.isOp(p.peek(), ".") {
+		p.fail(t, "schema-qualified table names are not supported")
+	}
+	r := p.table(name)
+	if p.accept("AS") || p.isName(p.peek()) {
+		alias := p.ident()
+		ta := r.node.(*nodes.Table).Alias(alias)
+		r.name, r.node = alias, ta
+	}
+	p.scope.relations = append(p.scope.relations, r)
+	return r
+}
+
+// assignments parses the col = value list of SET.
+func (p *parser) assignments(target *relation) []*nodes.AssignmentNode {
+	var assigns []*nodes.AssignmentNode
+	for {
+		if t := p.peek(); p.isOp(t, "(") {
+			p.fail(t, "multiple-column assignments are not supported")
+		}
+		col := p.ident()
+		if p.acceptOp(".") {
+			// MySQL allows the column to be qualified by the target.
+			col = p.ident()
+		}
+		p.expectOp("=")
+		assigns = append(assigns, &nodes.AssignmentNode{
+			Left:  nodes.NewAttribute(target.node, col),
+			Right: p.value(),
+		})
+		if !p.acceptOp(",") {
+			return assigns
+		}
+	}
+}
+
+// returning parses a RETURNING list.
+func (p *parser) returning() []nodes.Node {
+	var items []nodes.Node
+	for {
+		items = append(items, p.projection())
+		if !p.acceptOp(",") {
+			return items
+		}
+	}
+}
@@ -0,0 +1,646 @@
+package parser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// binaryLevels lists each dialect's binary operators from the loosest
+// binding to the tightest. Comparisons bind more loosely than all of them.
+var binaryLevels = map[Dialect][][]string{
+	Postgres: {{"|", "&", "<<", ">>", "||"}, {"+", "-"}, {"*", "/"}},
+	MySQL:    {{"|"}, {"&"}, {"<<", ">>"}, {"+", "-"}, {"*", "/"}, {"^"}},
+	SQLite:   {{"|", "&", "<<", ">>"}, {"+", "-"}, {"*", "/"}, {"||"}},
+}
+
+var infixOps = map[string]nodes.InfixOp{
+	"+":  nodes.OpPlus,
+	"-":  nodes.OpMinus,
+	"*":  nodes.OpMultiply,
+	"/":  nodes.OpDivide,
+	"&":  nodes.OpBitwiseAnd,
+	"|":  nodes.OpBitwiseOr,
+	"^":  nodes.OpBitwiseXor,
+	"<<": nodes.OpShiftLeft,
+	">>": nodes.OpShiftRight,
+	"||": nodes.OpConcat,
+}
+
+var comparisonOps = map[string]nodes.ComparisonOp{
+	"=":  nodes.OpEq,
+	"<>": nodes.OpNotEq,
+	"!=": nodes.OpNotEq,
+	">":  nodes.OpGt,
+	">=": nodes.OpGtEq,
+	"<":  nodes.OpLt,
+	"<=": nodes.OpLtEq,
+}
+
+// postgresComparisonOps are the PostgreSQL operators with a comparison
+// node equivalent.
+var postgresComparisonOps = map[string]nodes.ComparisonOp{
+	"~":  nodes.OpRegexp,
+	"!~": nodes.OpNotRegexp,
+	"@>": nodes.OpContains,
+	"&&": nodes.OpOverlaps,
+}
+
+var aggregateFuncs = map[string]nodes.AggregateFunc{
+	"COUNT": nodes.AggCount,
+	"SUM":   nodes.AggSum,
+	"AVG":   nodes.AggAvg,
+	"MIN":   nodes.AggMin,
+	"MAX":   nodes.AggMax,
+}
+
+var windowFuncs = map[string]nodes.WindowFunc{
+	"ROW_NUMBER":   nodes.WinRowNumber,
+	"RANK":         nodes.WinRank,
+	"DENSE_RANK":   nodes.WinDenseRank,
+	"NTILE":        nodes.WinNtile,
+	"LAG":          nodes.WinLag,
+	"LEAD":         nodes.WinLead,
+	"FIRST_VALUE":  nodes.WinFirstValue,
+	"LAST_VALUE":   nodes.WinLastValue,
+	"NTH_VALUE":    nodes.WinNthValue,
+	"CUME_DIST":    nodes.WinCumeDist,
+	"PERCENT_RANK": nodes.WinPercentRank,
+}
+
+var extractFields = map[string]nodes.ExtractField{
+	"YEAR":    nodes.ExtractYear,
+	"MONTH":   nodes.ExtractMonth,
+	"DAY":     nodes.ExtractDay,
+	"HOUR":    nodes.ExtractHour,
+	"MINUTE":  nodes.ExtractMinute,
+	"SECOND":  nodes.ExtractSecond,
+	"DOW":     nodes.ExtractDow,
+	"DOY":     nodes.ExtractDoy,
+	"EPOCH":   nodes.ExtractEpoch,
+	"QUARTER": nodes.ExtractQuarter,
+	"WEEK":    nodes.ExtractWeek,
+}
+
+// niladic lists the functions called without parentheses. They are kept
+// as raw SQL.
+var niladic = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "CURRENT_USER": true,
+	"SESSION_USER": true,
+}
+
+// typeWords lists the words that continue a multi-word type name after
+// :: in PostgreSQL, as in double precision or timestamp with time zone.
+var typeWords = map[string]bool{
+	"PRECISION": true, "VARYING": true, "WITH": true, "WITHOUT": true,
+	"TIME": true, "ZONE": true,
+}
+
+func (p *parser) acceptAnd() bool {
+	if p.cfg.dialect == MySQL && p.acceptOp("&&") {
+		return true
+	}
+	return p.accept("AND")
+}
+
+func (p *parser) atOr() bool {
+	t := p.peek()
+	return p.isKeyword(t, "OR") || p.cfg.dialect == MySQL && p.isOp(t, "||")
+}
+
+func (p *parser) acceptOr() bool {
+	if p.atOr() {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// list parses a comma-separated list of expressions.
+func (p *parser) list() []nodes.Node {
+	var items []nodes.Node
+	for {
+		items = append(items, p.expr())
+		if !p.acceptOp(",") {
+			return items
+		}
+	}
+}
+
+// expr parses an expression.
+func (p *parser) expr() nodes.Node {
+	left := p.and()
+	for p.acceptOr() {
+		left = nodes.NewOrNode(left, p.and())
+	}
+	return left
+}
+
+func (p *parser) and() nodes.Node {
+	left := p.not()
+	for p.acceptAnd() {
+		left = nodes.NewAndNode(left, p.not())
+	}
+	return left
+}
+
+func (p *parser) not() nodes.Node {
+	if !p.at("NOT") {
+		return p.comparison()
+	}
+	p.pos++
+	if p.accept("EXISTS") {
+		return nodes.NotExists(p.subquery())
+	}
+	expr := p.not()
+	// NotNode renders its own parentheses.
+	if g, ok := expr.(*nodes.GroupingNode); ok {
+		expr = g.Expr
+	}
+	return nodes.NewNotNode(expr)
+}
+
+// comparison parses an expression with an optional comparison or
+// predicate: IS, [NOT] IN, [NOT] BETWEEN, [NOT] LIKE and the like.
+func (p *parser) comparison() nodes.Node {
+	left := p.binary(0)
+	t := p.peek()
+	if t.kind == tokOp {
+		op, ok := comparisonOps[t.text]
+		if !ok && p.cfg.dialect == Postgres {
+			op, ok = postgresComparisonOps[t.text]
+		}
+		if !ok {
+			return left
+		}
+		p.pos++
+		if p.at("ANY", "ALL", "SOME") {
+			p.fail(p.peek(), "%s comparisons are not supported", strings.ToUpper(p.peek().text))
+		}
+		return nodes.NewComparisonNode(left, p.binary(0), op)
+	}
+
+	if p.accept("IS") {
+		negate := p.accept("NOT")
+		switch {
+		case p.accept("NULL"):
+			if negate {
+				return nodes.NewUnaryNode(left, nodes.OpIsNotNull)
+			}
+			return nodes.NewUnaryNode(left, nodes.OpIsNull)
+		case p.accept("DISTINCT", "FROM"):
+			if negate {
+				return nodes.NewComparisonNode(left, p.binary(0), nodes.OpNotDistinctFrom)
+			}
+			return nodes.NewComparisonNode(left, p.binary(0), nodes.OpDistinctFrom)
+		}
+		p.fail(t, "IS %s is not supported", describe(p.peek()))
+	}
+
+	negate := false
+	if p.at("NOT") && (p.isKeyword(p.peekAt(1), "LIKE") || p.isKeyword(p.peekAt(1), "IN") ||
+		p.isKeyword(p.peekAt(1), "BETWEEN") || p.isKeyword(p.peekAt(1), "ILIKE") ||
+		p.isKeyword(p.peekAt(1), "REGEXP") || p.isKeyword(p.peekAt(1), "RLIKE")) {
+		p.pos++
+		negate = true
+	}
+	t = p.peek()
+	switch {
+	case p.accept("LIKE"):
+		right := p.binary(0)
+		if p.at("ESCAPE") {
+			p.fail(p.peek(), "LIKE ... ESCAPE is not supported")
+		}
+		if negate {
+			return nodes.NewComparisonNode(left, right, nodes.OpNotLike)
+		}
+		return nodes.NewComparisonNode(left, right, nodes.OpLike)
+	case p.at("ILIKE"):
+		p.fail(t, "ILIKE is not supported")
+	case p.cfg.dialect != Postgres && p.at("REGEXP", "RLIKE"):
+		p.pos++
+		if negate {
+			return nodes.NewComparisonNode(left, p.binary(0), nodes.OpNotRegexp)
+		}
+		return nodes.NewComparisonNode(left, p.binary(0), nodes.OpRegexp)
+	case p.accept("IN"):
+		if next := p.peekAt(1); p.isKeyword(next, "SELECT") || p.isKeyword(next, "WITH") {
+			return nodes.NewInNode(left, []nodes.Node{p.subquery()}, negate)
+		}
+		p.expectOp("(")
+		vals := p.list()
+		p.expectOp(")")
+		return nodes.NewInNode(left, vals, negate)
+	case p.accept("BETWEEN"):
+		if p.at("SYMMETRIC") {
+			p.fail(p.peek(), "BETWEEN SYMMETRIC is not supported")
+		}
+		p.accept("ASYMMETRIC")
+		low := p.binary(0)
+		if t := p.peek(); t.kind == tokEOF {
+			panic(p.errorAt(t, "expected AND after BETWEEN"))
+		}
+		p.expect("AND")
+		return nodes.NewBetweenNode(left, low, p.binary(0), negate)
+	}
+	return left
+}
+
+// binary parses the binary operators of the given level and tighter ones.
+func (p *parser) binary(level int) nodes.Node {
+	levels := binaryLevels[p.cfg.dialect]
+	if level == len(levels) {
+		return p.unary()
+	}
+	left := p.binary(level + 1)
+	for {
+		t := p.peek()
+		if t.kind != tokOp || !slices.Contains(levels[level], t.text) {
+			return left
+		}
+		p.pos++
+		left = nodes.NewInfixNode(left, p.binary(level+1), infixOps[t.text])
+	}
+}
+
+func (p *parser) unary() nodes.Node {
+	t := p.peek()
+	switch {
+	case p.isOp(t, "-"):
+		if next := p.peekAt(1); next.kind == tokNumber && !next.special {
+			p.pos += 2
+			return p.number(next, "-")
+		}
+		p.fail(t, "unary minus is only supported on numbers")
+	case p.isOp(t, "+"):
+		p.pos++
+		return p.unary()
+	case p.isOp(t, "~"):
+		p.pos++
+		return nodes.NewUnaryMathNode(p.unary(), nodes.OpBitwiseNot)
+	}
+	return p.postfix(p.primary())
+}
+
+// postfix parses PostgreSQL :: casts after e.
+func (p *parser) postfix(e nodes.Node) nodes.Node {
+	for p.cfg.dialect == Postgres && p.acceptOp("::") {
+		start := p.pos
+		p.ident()
+		for t := p.peek(); t.kind == tokIdent && typeWords[strings.ToUpper(t.text)]; t = p.peek() {
+			p.pos++
+		}
+		if p.isOp(p.peek(), "(") {
+			p.typeModifiers()
+		}
+		if p.isOp(p.peek(), "[") {
+			p.fail(p.peek(), "array types are not supported")
+		}
+		e = nodes.Cast(e, p.typeName(start, p.pos))
+	}
+	return e
+}
+
+// typeModifiers skips the (n) or (p, s) of a type name.
+func (p *parser) typeModifiers() {
+	p.expectOp("(")
+	for {
+		if t := p.advance(); t.kind != tokNumber {
+			p.fail(t, "expected a type modifier, found %s", describe(t))
+		}
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	p.expectOp(")")
+}
+
+// typeName returns the type name spelled by tokens [from, to).
+func (p *parser) typeName(from, to int) string {
+	return string(p.raw(from, to).Raw)
+}
+
+func (p *parser) primary() nodes.Node {
+	t := p.peek()
+	switch t.kind {
+	case tokNumber:
+		p.pos++
+		return p.number(t, "")
+	case tokString:
+		if t.special {
+			p.fail(t, "string literal %s is not supported", t.text)
+		}
+		p.pos++
+		return nodes.Literal(t.val)
+	case tokParam:
+		p.pos++
+		return nodes.NewBindParam(p.arg(t))
+	case tokOp:
+		if p.isOp(t, "(") {
+			return p.parenthesised()
+		}
+		if p.isOp(t, ")") || p.isOp(t, ",") || p.isOp(t, ";") {
+			panic(p.errorAt(t, "expected an expression, found %q", t.text))
+		}
+		p.fail(t, "unexpected %q", t.text)
+	case tokEOF:
+		p.fail(t, "unexpected end of input")
+	}
+
+	upper := strings.ToUpper(t.text)
+	if t.kind == tokIdent {
+		switch {
+		case upper == "TRUE":
+			p.pos++
+			return nodes.Literal(true)
+		case upper == "FALSE":
+			p.pos++
+			return nodes.Literal(false)
+		case upper == "NULL":
+			p.pos++
+			return nodes.Literal(nil)
+		case upper == "CASE":
+			return p.caseExpr()
+		case upper == "EXISTS":
+			p.pos++
+			return nodes.Exists(p.subquery())
+		case upper == "CAST" && p.isOp(p.peekAt(1), "("):
+			return p.cast()
+		case upper == "EXTRACT" && p.isOp(p.peekAt(1), "("):
+			return p.extract()
+		case niladic[upper] && !p.isOp(p.peekAt(1), "("):
+			p.pos++
+			return nodes.NewSqlLiteral(nodes.RawSQL(t.text))
+		case p.peekAt(1).kind == tokString:
+			p.fail(t, "typed literals such as %s '...' are not supported", upper)
+		case p.isOp(p.peekAt(1), "(") && (p.isName(t) || upper == "LEFT" || upper == "RIGHT"):
+			return p.function(t)
+		}
+	}
+	if !p.isName(t) {
+		p.fail(t, "unexpected %s", describe(t))
+	}
+	if p.isOp(p.peekAt(1), "(") {
+		p.fail(t, "quoted function names are not supported")
+	}
+
+	p.pos++
+	if !p.acceptOp(".") {
+		if p.scope.anyOutput || p.output(p.name(t)) {
+			// A reference to an output column: the bare name.
+			return nodes.NewSqlLiteral(nodes.RawSQL(t.text))
+		}
+		return p.column(t, "", p.name(t))
+	}
+	col := p.peek()
+	if !p.isName(col) {
+		p.fail(col, "expected a column name, found %s", describe(col))
+	}
+	p.pos++
+	if p.isOp(p.peek(), ".") {
+		p.fail(t, "schema-qualified column references are not supported")
+	}
+	return p.column(t, p.name(t), p.name(col))
+}
+
+// number converts a numeric literal. Integers become literals; other
+// numbers are kept as raw SQL so that their exact value and type are
+// preserved.
+func (p *parser) number(t token, sign string) nodes.Node {
+	if !t.special {
+		if n, err := strconv.Atoi(sign + t.text); err == nil {
+			return nodes.Literal(n)
+		}
+	}
+	return nodes.NewSqlLiteral(nodes.RawSQL(sign + t.text))
+}
+
+// parenthesised parses a scalar subquery or a parenthesised expression.
+func (p *parser) parenthesised() nodes.Node {
+	if next := p.peekAt(1); p.isKeyword(next, "SELECT") || p.isKeyword(next, "WITH") {
+		return nodes.NewGroupingNode(p.subquery())
+	}
+	p.pos++
+	e := p.expr()
+	if t := p.peek(); p.isOp(t, ",") {
+		p.fail(t, "row values are not supported")
+	}
+	p.expectOp(")")
+	return nodes.NewGroupingNode(e)
+}
+
+// subquery parses a parenthesised query.
+func (p *parser) subquery() nodes.Node {
+	p.expectOp("(")
+	q := p.query()
+	p.expectOp(")")
+	return q
+}
+
+// caseExpr parses a simple or searched CASE expression.
+func (p *parser) caseExpr() nodes.Node {
+	p.expect("CASE")
+	c := nodes.NewCase()
+	if !p.at("WHEN", "THEN", "ELSE", "END") {
+		c.Operand = p.expr()
+	}
+	for p.accept("WHEN") {
+		cond := p.expr()
+		p.expect("THEN")
+		c.When(cond, p.expr())
+	}
+	if len(c.Whens) == 0 {
+		if t := p.peek(); p.at("THEN", "ELSE", "END") {
+			panic(p.errorAt(t, "expected WHEN, found %s", describe(t)))
+		}
+		p.expect("WHEN")
+	}
+	if p.accept("ELSE") {
+		c.Else(p.expr())
+	}
+	p.expect("END")
+	return c
+}
+
+// cast parses CAST(expr AS type).
+func (p *parser) cast() nodes.Node {
+	p.pos += 2
+	e := p.expr()
+	p.expect("AS")
+	start := p.pos
+	p.ident()
+	for p.peek().kind == tokIdent {
+		p.pos++
+	}
+	if p.isOp(p.peek(), "(") {
+		p.typeModifiers()
+	}
+	for p.peek().kind == tokIdent {
+		p.pos++
+	}
+	if p.isOp(p.peek(), "[") {
+		p.fail(p.peek(), "array types are not supported")
+	}
+	typ := p.typeName(start, p.pos)
+	p.expectOp(")")
+	return nodes.Cast(e, typ)
+}
+
+// extract parses EXTRACT(field FROM expr).
+func (p *parser) extract() nodes.Node {
+	p.pos += 2
+	t := p.peek()
+	field, ok := extractFields[strings.ToUpper(t.text)]
+	if t.kind != tokIdent || !ok {
+		p.fail(t, "EXTRACT field %s is not supported", describe(t))
+	}
+	p.pos++
+	p.expect("FROM")
+	e := p.expr()
+	p.expectOp(")")
+	return nodes.NewExtractNode(field, e)
+}
+
+// function parses a function call, with its FILTER and OVER clauses.
+// COUNT, SUM, AVG, MIN and MAX with one argument become aggregate nodes,
+// and the window functions followed by OVER become window function nodes.
+func (p *parser) function(t token) nodes.Node {
+	p.pos += 2
+	upper := strings.ToUpper(t.text)
+	distinct := p.accept("DISTINCT")
+	if !distinct {
+		p.accept("ALL")
+	}
+	var args []nodes.Node
+	star := false
+	if s := p.peek(); p.acceptOp("*") {
+		if upper != "COUNT" {
+			p.fail(s, "unexpected \"*\"")
+		}
+		star = true
+	} else if !p.isOp(p.peek(), ")") {
+		args = p.list()
+	}
+	if p.at("ORDER") {
+		p.fail(p.peek(), "ORDER BY in function arguments is not supported")
+	}
+	p.expectOp(")")
+
+	var n nodes.Node
+	if fn, ok := aggregateFuncs[upper]; ok && (star || len(args) == 1) {
+		agg := nodes.NewAggregateNode(fn, nil)
+		if !star {
+			agg.Expr = args[0]
+		}
+		agg.Distinct = distinct
+		if p.accept("FILTER") {
+			p.expectOp("(")
+			p.expect("WHERE")
+			agg.Filter = p.expr()
+			p.expectOp(")")
+		}
+		n = agg
+	} else if fn, ok := windowFuncs[upper]; ok && p.at("OVER") {
+		if distinct {
+			p.fail(t, "DISTINCT in window functions is not supported")
+		}
+		n = &nodes.WindowFuncNode{Func: fn, Args: args}
+	} else {
+		if !validFunctionName(t.text) {
+			p.fail(t, "function name %s is not supported", t.text)
+		}
+		if p.at("FILTER") {
+			p.fail(p.peek(), "FILTER is only supported on COUNT, SUM, AVG, MIN and MAX")
+		}
+		f := nodes.NewNamedFunction(t.text, args...)
+		f.Distinct = distinct
+		n = f
+	}
+
+	if !p.accept("OVER") {
+		return n
+	}
+	o := nodes.NewOverNode(n)
+	if p.isName(p.peek()) {
+		o.WindowName = p.ident()
+	} else {
+		o.Window = p.windowSpec()
+	}
+	return o
+}
+
+// validFunctionName reports whether name can be rendered as a function
+// name.
+func validFunctionName(name string) bool {
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// windowSpec parses a parenthesised window specification.
+func (p *parser) windowSpec() *nodes.WindowDefinition {
+	p.expectOp("(")
+	def := &nodes.WindowDefinition{}
+	if t := p.peek(); p.isName(t) && !p.at("PARTITION", "ROWS", "RANGE", "GROUPS") {
+		p.fail(t, "window definitions based on another window are not supported")
+	}
+	if p.accept("PARTITION", "BY") {
+		def.PartitionBy = p.list()
+	}
+	if p.accept("ORDER", "BY") {
+		for {
+			def.OrderBy = append(def.OrderBy, p.ordering())
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	if t := p.peek(); p.at("ROWS", "RANGE", "GROUPS") {
+		p.pos++
+		f := &nodes.WindowFrame{Type: nodes.FrameRows}
+		switch strings.ToUpper(t.text) {
+		case "RANGE":
+			f.Type = nodes.FrameRange
+		case "GROUPS":
+			p.fail(t, "GROUPS frames are not supported")
+		}
+		if p.accept("BETWEEN") {
+			f.Start = p.frameBound()
+			p.expect("AND")
+			end := p.frameBound()
+			f.End = &end
+		} else {
+			f.Start = p.frameBound()
+		}
+		if p.at("EXCLUDE") {
+			p.fail(p.peek(), "EXCLUDE is not supported")
+		}
+		def.Frame = f
+	}
+	p.expectOp(")")
+	return def
+}
+
+// frameBound parses a window frame boundary.
+func (p *parser) frameBound() nodes.FrameBound {
+	switch {
+	case p.accept("UNBOUNDED", "PRECEDING"):
+		return nodes.UnboundedPreceding()
+	case p.accept("UNBOUNDED", "FOLLOWING"):
+		return nodes.UnboundedFollowing()
+	case p.accept("CURRENT", "ROW"):
+		return nodes.CurrentRow()
+	}
+	offset := p.binary(0)
+	if p.accept("PRECEDING") {
+		return nodes.Preceding(offset)
+	}
+	p.expect("FOLLOWING")
+	return nodes.Following(offset)
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/schema"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenSchema is the schema the golden statements are parsed with.
+var goldenSchema = &schema.Schema{Tables: []*schema.Table{
+	goldenTable("users", "id", "email", "name", "active", "created_at", "data"),
+	goldenTable("orders", "id", "user_id", "status", "total", "created_at"),
+	goldenTable("items", "id", "order_id"),
+	goldenTable("bans", "user_id"),
+	goldenTable("categories", "id", "parent_id"),
+}}
+
+func goldenTable(name string, cols ...string) *schema.Table {
+	t := &schema.Table{Name: name}
+	for _, c := range cols {
+		t.Columns = append(t.Columns, &schema.Column{Name: c})
+	}
+	return t
+}
+
+// goldenArgs are the placeholder arguments of the golden statements.
+var goldenArgs = []any{"arg1", "arg2", "arg3", "arg4"}
+
+// goldenDialects lists the golden directories. Statements are parsed in
+// dialect and rendered with visitor; when the visitor renders a dialect
+// the parser does not read, reparse is false and the directory holds
+// translations checked by their rendering alone.
+var goldenDialects = []struct {
+	dir     string
+	dialect Dialect
+	visitor func() nodes.Visitor
+	reparse bool
+}{
+	{"postgres", Postgres, func() nodes.Visitor { return visitors.NewPostgresVisitor(visitors.WithoutParams()) }, true},
+	{"mysql", MySQL, func() nodes.Visitor { return visitors.NewMySQLVisitor(visitors.WithoutParams()) }, true},
+	{"sqlite", SQLite, func() nodes.Visitor { return visitors.NewSQLiteVisitor(visitors.WithoutParams()) }, true},
+	{"mssql", Postgres, func() nodes.Visitor { return visitors.NewMSSQLVisitor(visitors.WithoutParams()) }, false},
+	{"oracle", Postgres, func() nodes.Visitor { return visitors.NewOracleVisitor(visitors.WithoutParams()) }, false},
+	{"clickhouse", Postgres, func() nodes.Visitor { return visitors.NewClickHouseVisitor(visitors.WithoutParams()) }, false},
+	{"duckdb", Postgres, func() nodes.Visitor { return visitors.NewDuckDBVisitor(visitors.WithoutParams()) }, false},
+}
+
+// TestGolden parses each statement in testdata/<dialect>/*.sql, renders it
+// with the dialect's visitor and compares the SQL and warnings with the
+// matching .golden file. Statements are separated by blank lines. Unless
+// the directory holds translations, the rendered SQL must parse back to
+// the same SQL. Run with -update to rewrite the golden files.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+	for _, d := range goldenDialects {
+		files, err := filepath.Glob(filepath.Join("testdata", d.dir, "*.sql"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			t.Run(d.dir+"/"+filepath.Base(file), func(t *testing.T) {
+				t.Parallel()
+				src, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var out strings.Builder
+				for i, stmt := range splitStatements(string(src)) {
+					if i > 0 {
+						out.WriteString("\n")
+					}
+					fmt.Fprintf(&out, "%s\n", stmt)
+					out.WriteString(goldenOutput(t, stmt, d.dialect, d.visitor(), d.reparse))
+				}
+
+				golden := strings.TrimSuffix(file, ".sql") + ".golden"
+				if *update {
+					if err := os.WriteFile(golden, []byte(out.String()), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if out.String() != string(want) {
+					t.Errorf("output differs from %s (run with -update to rewrite):\n%s", golden, out.String())
+				}
+			})
+		}
+	}
+}
+
+// goldenOutput returns the rendered SQL and the warnings of stmt. With
+// reparse, it also checks that the rendered SQL parses back to itself.
+func goldenOutput(t *testing.T, stmt string, d Dialect, v nodes.Visitor, reparse bool) string {
+	t.Helper()
+	opts := []Option{WithDialect(d), WithArgs(goldenArgs...), WithSchema(goldenSchema)}
+	res, err := Parse(nodes.RawSQL(stmt), opts...)
+	if err != nil {
+		return fmt.Sprintf("-- error: %v\n", err)
+	}
+	sql, err := render(res.Statement, v)
+	if err != nil {
+		return fmt.Sprintf("-- error: %v\n", err)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=> %s\n", sql)
+	for _, w := range res.Warnings {
+		fmt.Fprintf(&sb, "-- warning: %s\n", w)
+	}
+	if !reparse {
+		return sb.String()
+	}
+
+	again, err := Parse(nodes.RawSQL(sql), opts...)
+	if err != nil {
+		t.Errorf("%s\nrendered SQL does not parse: %v", stmt, err)
+		return sb.String()
+	}
+	if sql2 := again.Statement.Accept(v); sql2 != sql {
+		t.Errorf("%s\nround trip changed the SQL:\n got: %s\nwant: %s", stmt, sql2, sql)
+	}
+	return sb.String()
+}
+
+// splitStatements splits src at blank lines.
+func splitStatements(src string) []string {
+	var stmts []string
+	for _, block := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n\n") {
+		if block = strings.TrimSpace(block); block != "" {
+			stmts = append(stmts, block)
+		}
+	}
+	return stmts
+}
+
+// render renders n with v, returning a feature the dialect cannot express
+// as an error.
+func render(n nodes.Node, v nodes.Visitor) (sql string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ue, ok := r.(*nodes.UnsupportedError)
+			if !ok {
+				panic(r)
+			}
+			err = ue
+		}
+	}()
+	return n.Accept(v), nil
+}
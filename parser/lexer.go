@@ -0,0 +1,340 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokIdent            // unquoted word, including keywords
+	tokQuoted           // quoted identifier
+	tokString           // string literal
+	tokNumber           // numeric literal
+	tokParam            // bind placeholder ($1, ?, ?2)
+	tokOp               // operator or punctuation
+)
+
+// token is a lexical token. text is the token's source text; val is the
+// decoded value of identifiers and strings.
+type token struct {
+	kind tokenKind
+	text string
+	val  string
+	pos  int // byte offset of the first character
+	end  int // byte offset after the last character
+
+	// special marks string and number forms the parser does not decode,
+	// such as E'...', X'...', $$...$$ and 0x1F.
+	special bool
+}
+
+// comment is a block comment found between tokens.
+type comment struct {
+	pos, end int
+	text     string // content between /* and */
+}
+
+// operators lists the multi-character operators, longest first.
+var operators = []string{
+	"->>", "<=>", "!~*",
+	"<>", "!=", "<=", ">=", "||", "::", "<<", ">>", "@>", "<@", "&&", "!~", "~*", "->",
+}
+
+// lexer splits SQL text into tokens.
+type lexer struct {
+	src      string
+	dialect  Dialect
+	pos      int
+	tokens   []token
+	comments []comment
+}
+
+// lex tokenizes src. The returned slice always ends with a tokEOF token.
+func lex(src string, d Dialect) ([]token, []comment, error) {
+	l := &lexer{src: src, dialect: d}
+	for {
+		if err := l.skipSpace(); err != nil {
+			return nil, nil, err
+		}
+		if l.pos >= len(l.src) {
+			break
+		}
+		if err := l.next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	l.tokens = append(l.tokens, token{kind: tokEOF, pos: len(src), end: len(src)})
+	return l.tokens, l.comments, nil
+}
+
+func (l *lexer) errorf(pos int, format string, args ...any) error {
+	return newError(l.src, pos, format, args...)
+}
+
+// skipSpace skips whitespace and comments, recording block comments.
+func (l *lexer) skipSpace() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "--"), c == '#' && l.dialect == MySQL:
+			end := strings.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end + 1
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf(l.pos, "unterminated comment")
+			}
+			l.comments = append(l.comments, comment{
+				pos:  l.pos,
+				end:  l.pos + end + 4,
+				text: l.src[l.pos+2 : l.pos+2+end],
+			})
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) emit(kind tokenKind, start int, val string, special bool) {
+	l.tokens = append(l.tokens, token{
+		kind:    kind,
+		text:    l.src[start:l.pos],
+		val:     val,
+		pos:     start,
+		end:     l.pos,
+		special: special,
+	})
+}
+
+func (l *lexer) next() error {
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case c == '\'':
+		return l.string(start, '\'', false)
+	case c == '"':
+		if l.dialect == MySQL {
+			return l.string(start, '"', false)
+		}
+		return l.quoted(start, '"')
+	case c == '`' && l.dialect != Postgres:
+		return l.quoted(start, '`')
+	case c == '[' && l.dialect == SQLite:
+		return l.quoted(start, ']')
+	case isDigit(c), c == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1]):
+		l.number(start)
+		return nil
+	case c == '$' && l.dialect == Postgres:
+		if l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1]) {
+			l.pos++
+			for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+				l.pos++
+			}
+			l.emit(tokParam, start, l.src[start+1:l.pos], false)
+			return nil
+		}
+		return l.dollarString(start)
+	case c == '?' && l.dialect != Postgres:
+		l.pos++
+		for l.dialect == SQLite && l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		l.emit(tokParam, start, l.src[start+1:l.pos], false)
+		return nil
+	}
+
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	if isIdentStart(r) {
+		// Prefixed strings: E'...', N'...', X'...', B'...'.
+		if l.pos+size < len(l.src) && l.src[l.pos+size] == '\'' && strings.ContainsRune("eEnNxXbB", r) {
+			return l.string(start, '\'', true)
+		}
+		l.pos += size
+		for l.pos < len(l.src) {
+			r, size = utf8.DecodeRuneInString(l.src[l.pos:])
+			if !isIdentPart(r) {
+				break
+			}
+			l.pos += size
+		}
+		l.emit(tokIdent, start, l.src[start:l.pos], false)
+		return nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			l.emit(tokOp, start, op, false)
+			return nil
+		}
+	}
+	if strings.ContainsRune("(),.;+-*/%=<>~!&|^#@?[]:{}", rune(c)) {
+		l.pos++
+		l.emit(tokOp, start, string(c), false)
+		return nil
+	}
+	return l.errorf(start, "unexpected character %q", r)
+}
+
+// string lexes a quoted string literal. A prefix character, if any, is
+// consumed first and marks the token as special.
+func (l *lexer) string(start int, quote byte, prefixed bool) error {
+	escapes := prefixed && (l.src[start] == 'e' || l.src[start] == 'E')
+	if prefixed {
+		l.pos++
+	}
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == quote:
+			if l.pos+1 < len(l.src) && l.src[l.pos+1] == quote {
+				sb.WriteByte(quote)
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			l.emit(tokString, start, sb.String(), prefixed)
+			return nil
+		case c == '\\' && l.dialect == MySQL && l.pos+1 < len(l.src):
+			sb.WriteString(mysqlEscape(l.src[l.pos+1]))
+			l.pos += 2
+		case c == '\\' && escapes && l.pos+1 < len(l.src):
+			// PostgreSQL E'...' string: left undecoded.
+			sb.WriteString(l.src[l.pos : l.pos+2])
+			l.pos += 2
+		default:
+			sb.WriteByte(c)
+			l.pos++
+		}
+	}
+	return l.errorf(start, "unterminated string")
+}
+
+// mysqlEscape decodes the character after a backslash in a MySQL string.
+// \% and \_ keep their backslash, as they do in MySQL.
+func mysqlEscape(c byte) string {
+	switch c {
+	case '0':
+		return "\x00"
+	case 'b':
+		return "\b"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case 'Z':
+		return "\x1a"
+	case '%', '_':
+		return `\` + string(c)
+	}
+	return string(c)
+}
+
+// dollarString lexes a PostgreSQL dollar-quoted string ($$...$$ or
+// $tag$...$tag$).
+func (l *lexer) dollarString(start int) error {
+	end := strings.IndexByte(l.src[start+1:], '$')
+	if end < 0 {
+		return l.errorf(start, "unexpected character '$'")
+	}
+	tag := l.src[start : start+end+2]
+	for _, r := range tag[1 : len(tag)-1] {
+		if !isIdentPart(r) {
+			return l.errorf(start, "unexpected character '$'")
+		}
+	}
+	body := strings.Index(l.src[start+len(tag):], tag)
+	if body < 0 {
+		return l.errorf(start, "unterminated dollar-quoted string")
+	}
+	l.pos = start + len(tag) + body + len(tag)
+	l.emit(tokString, start, l.src[start+len(tag):start+len(tag)+body], true)
+	return nil
+}
+
+// quoted lexes a quoted identifier ending with close. A doubled closing
+// character stands for itself.
+func (l *lexer) quoted(start int, close byte) error {
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == close {
+			if close != ']' && l.pos+1 < len(l.src) && l.src[l.pos+1] == close {
+				sb.WriteByte(close)
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			if sb.Len() == 0 {
+				return l.errorf(start, "empty quoted identifier")
+			}
+			l.emit(tokQuoted, start, sb.String(), false)
+			return nil
+		}
+		sb.WriteByte(c)
+		l.pos++
+	}
+	return l.errorf(start, "unterminated quoted identifier")
+}
+
+// number lexes a numeric literal. Hexadecimal and binary forms are marked
+// special.
+func (l *lexer) number(start int) {
+	if l.src[l.pos] == '0' && l.pos+1 < len(l.src) && strings.ContainsRune("xXbB", rune(l.src[l.pos+1])) {
+		l.pos += 2
+		for l.pos < len(l.src) && isHexDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		l.emit(tokNumber, start, l.src[start:l.pos], true)
+		return
+	}
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		exp := l.pos + 1
+		if exp < len(l.src) && (l.src[exp] == '+' || l.src[exp] == '-') {
+			exp++
+		}
+		if exp < len(l.src) && isDigit(l.src[exp]) {
+			l.pos = exp
+			for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		}
+	}
+	l.emit(tokNumber, start, l.src[start:l.pos], false)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isIdentStart(r rune) bool { return r == '_' || unicode.IsLetter(r) }
+
+func isIdentPart(r rune) bool { return isIdentStart(r) || unicode.IsDigit(r) || r == '$' }
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+)
+
+func TestLexTokens(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		src     string
+		dialect Dialect
+		kinds   []tokenKind
+		vals    []string
+	}{
+		{
+			name:  "postgres",
+			src:   `SELECT "a""b", 'it''s', $1, 1.5e3 -- comment`,
+			kinds: []tokenKind{tokIdent, tokQuoted, tokOp, tokString, tokOp, tokParam, tokOp, tokNumber, tokEOF},
+			vals:  []string{"SELECT", `a"b`, ",", "it's", ",", "1", ",", "1.5e3", ""},
+		},
+		{
+			name:    "mysql",
+			src:     "`a` \"b\\n\" ? # comment",
+			dialect: MySQL,
+			kinds:   []tokenKind{tokQuoted, tokString, tokParam, tokEOF},
+			vals:    []string{"a", "b\n", "", ""},
+		},
+		{
+			name:    "sqlite",
+			src:     "[a b] ?12 x<>y",
+			dialect: SQLite,
+			kinds:   []tokenKind{tokQuoted, tokParam, tokIdent, tokOp, tokIdent, tokEOF},
+			vals:    []string{"a b", "12", "x", "<>", "y", ""},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			toks, _, err := lex(c.src, c.dialect)
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, len(toks), len(c.kinds))
+			for i, tok := range toks {
+				testutil.AssertEqual(t, tok.kind, c.kinds[i])
+				testutil.AssertEqual(t, tok.val, c.vals[i])
+			}
+		})
+	}
+}
+
+func TestLexSpecialLiterals(t *testing.T) {
+	t.Parallel()
+	toks, _, err := lex(`$tag$a'b$tag$ E'\n' X'1F' 0x1F`, Postgres)
+	testutil.AssertNoError(t, err)
+	for _, tok := range toks[:4] {
+		testutil.AssertEqual(t, tok.special, true)
+	}
+	testutil.AssertEqual(t, toks[0].val, "a'b")
+}
+
+func TestLexComments(t *testing.T) {
+	t.Parallel()
+	toks, comments, err := lex("/* lead */ SELECT /*+ hint */ 1", Postgres)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(toks), 3)
+	testutil.AssertEqual(t, len(comments), 2)
+	testutil.AssertEqual(t, comments[1].text, "+ hint ")
+}
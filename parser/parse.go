@@ -0,0 +1,417 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// parser is a recursive-descent parser over the token stream. Syntax
+// errors panic with *Error and unsupported syntax with *unsupported; both
+// are recovered in parse, and *unsupported also by fallback.
+type parser struct {
+	cfg      config
+	src      string
+	toks     []token
+	comments []comment
+	pos      int
+	warnings []Warning
+	scope    *scope
+	nextArg  int // argument taken by the next ? placeholder
+
+	// outputsOf records the output columns of each parsed SELECT.
+	outputsOf map[*nodes.SelectCore]outputInfo
+}
+
+// unsupported is the panic value for syntax that has no AST equivalent.
+type unsupported struct {
+	tok token
+	msg string
+}
+
+// reserved lists the keywords that cannot be used as bare identifiers or
+// implicit aliases.
+var reserved = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "AS": true, "ASC": true, "BETWEEN": true,
+	"BY": true, "CASE": true, "CROSS": true, "DEFAULT": true, "DESC": true, "DISTINCT": true,
+	"ELSE": true, "END": true, "EXCEPT": true, "EXISTS": true, "FALSE": true,
+	"FETCH": true, "FOR": true, "FROM": true, "FULL": true, "GROUP": true,
+	"HAVING": true, "ILIKE": true, "IN": true, "INNER": true, "INTERSECT": true,
+	"INTO": true, "IS": true, "JOIN": true, "LATERAL": true, "LEFT": true,
+	"LIKE": true, "LIMIT": true, "NATURAL": true, "NOT": true, "NULL": true,
+	"OFFSET": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true,
+	"REGEXP": true, "RETURNING": true, "RIGHT": true, "SELECT": true, "SET": true,
+	"SOME": true, "STRAIGHT_JOIN": true, "THEN": true, "TRUE": true, "UNION": true, "USING": true,
+	"VALUES": true, "WHEN": true, "WHERE": true, "WINDOW": true, "WITH": true,
+}
+
+// parse parses the whole input as one statement.
+func (p *parser) parse() (stmt nodes.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case *Error:
+				err = r
+			case *unsupported:
+				// A clause with no AST equivalent: keep the whole statement.
+				p.warnings = nil
+				p.nextArg = 0
+				stmt = p.raw(0, p.statementEnd())
+				p.warn(r.tok, "%s; the statement is kept as raw SQL", r.msg)
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	p.scope = &scope{}
+	stmt = p.statement()
+	p.acceptOp(";")
+	if t := p.peek(); t.kind != tokEOF {
+		p.fail(t, "unexpected %s after the statement", describe(t))
+	}
+	return stmt, nil
+}
+
+// statementEnd returns the index of the token ending the statement: the
+// trailing semicolon or EOF.
+func (p *parser) statementEnd() int {
+	end := len(p.toks) - 1
+	if end > 0 && p.toks[end-1].kind == tokOp && p.toks[end-1].text == ";" {
+		end--
+	}
+	return end
+}
+
+func (p *parser) statement() nodes.Node {
+	t := p.peek()
+	switch {
+	case p.isKeyword(t, "SELECT"), p.isKeyword(t, "WITH"), p.isOp(t, "("):
+		return p.query()
+	case p.isKeyword(t, "INSERT"):
+		return p.insert()
+	case p.isKeyword(t, "UPDATE"):
+		return p.update()
+	case p.isKeyword(t, "DELETE"):
+		return p.delete()
+	}
+	panic(p.errorAt(t, "expected SELECT, INSERT, UPDATE or DELETE, found %s", describe(t)))
+}
+
+// --- token access ---
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+// peekAt returns the token n positions ahead, or EOF.
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+// after returns the token following t.
+func (p *parser) after(t token) token {
+	i := sort.Search(len(p.toks), func(i int) bool { return p.toks[i].pos > t.pos })
+	if i == len(p.toks) {
+		i--
+	}
+	return p.toks[i]
+}
+
+// atEnd reports whether t ends the enclosing construct whatever the
+// context: end of input, a semicolon or a closing parenthesis.
+func (p *parser) atEnd(t token) bool {
+	return t.kind == tokEOF || p.isOp(t, ";") || p.isOp(t, ")")
+}
+
+func (p *parser) advance() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(t token, kw string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) isOp(t token, op string) bool {
+	return t.kind == tokOp && t.text == op
+}
+
+// at reports whether the current token is one of the keywords.
+func (p *parser) at(kws ...string) bool {
+	t := p.peek()
+	return slices.ContainsFunc(kws, func(kw string) bool { return p.isKeyword(t, kw) })
+}
+
+// accept consumes the given keyword sequence if it comes next.
+func (p *parser) accept(kws ...string) bool {
+	for i, kw := range kws {
+		if !p.isKeyword(p.peekAt(i), kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *parser) acceptOp(op string) bool {
+	if p.isOp(p.peek(), op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kws ...string) {
+	if !p.accept(kws...) {
+		t := p.peek()
+		p.fail(t, "expected %s, found %s", strings.Join(kws, " "), describe(t))
+	}
+}
+
+func (p *parser) expectOp(op string) {
+	if !p.acceptOp(op) {
+		t := p.peek()
+		p.fail(t, "expected %q, found %s", op, describe(t))
+	}
+}
+
+// isName reports whether t can be used as an identifier.
+func (p *parser) isName(t token) bool {
+	return t.kind == tokQuoted || (t.kind == tokIdent && !reserved[strings.ToUpper(t.text)])
+}
+
+// name returns the identifier named by t. Unquoted PostgreSQL identifiers
+// are folded to lower case.
+func (p *parser) name(t token) string {
+	if t.kind == tokIdent && p.cfg.dialect == Postgres {
+		return strings.ToLower(t.val)
+	}
+	return t.val
+}
+
+// ident consumes an identifier and returns its name.
+func (p *parser) ident() string {
+	t := p.peek()
+	if !p.isName(t) {
+		p.fail(t, "expected an identifier, found %s", describe(t))
+	}
+	p.pos++
+	return p.name(t)
+}
+
+// sameName compares identifiers the way the dialect does.
+func (p *parser) sameName(a, b string) bool {
+	if p.cfg.dialect == Postgres {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+// describe names a token for error messages.
+func describe(t token) string {
+	switch t.kind {
+	case tokEOF:
+		return "end of input"
+	case tokIdent:
+		return strings.ToUpper(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// --- errors and warnings ---
+
+func (p *parser) errorAt(t token, format string, args ...any) *Error {
+	return newError(p.src, t.pos, format, args...)
+}
+
+// fail reports syntax at t that cannot be represented as nodes. Input
+// that ends where more is needed is malformed rather than unsupported, so
+// a failure at the end of input is a syntax error.
+func (p *parser) fail(t token, format string, args ...any) {
+	if t.kind == tokEOF {
+		panic(p.errorAt(t, format, args...))
+	}
+	panic(&unsupported{tok: t, msg: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) warn(t token, format string, args ...any) {
+	line, col := position(p.src, t.pos)
+	p.warnings = append(p.warnings, Warning{Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+// --- raw SQL fallback ---
+
+// stopFunc reports whether a token at nesting depth zero ends an item.
+type stopFunc func(p *parser, t token) bool
+
+// fallback runs parse, and if it meets unsupported syntax, rewinds and
+// returns the item's source text as a SqlLiteral instead, reporting a
+// warning. The item ends before the first token at nesting depth zero for
+// which stop reports true.
+func (p *parser) fallback(stop stopFunc, parse func() nodes.Node) (n nodes.Node) {
+	start, nextArg, sc, warnings := p.pos, p.nextArg, p.scope, len(p.warnings)
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		u, ok := r.(*unsupported)
+		if !ok {
+			panic(r)
+		}
+		p.pos, p.nextArg, p.scope, p.warnings = start, nextArg, sc, p.warnings[:warnings]
+		p.skip(stop)
+		if p.pos == start {
+			panic(p.errorAt(u.tok, "%s", u.msg))
+		}
+		n = p.raw(start, p.pos)
+		p.warn(u.tok, "%s; kept as raw SQL", u.msg)
+	}()
+	n = parse()
+	if t := p.peek(); !p.atEnd(t) && !stop(p, t) {
+		if t.kind == tokOp {
+			p.fail(t, "operator %s is not supported", t.text)
+		}
+		p.fail(t, "unexpected %s", describe(t))
+	}
+	return n
+}
+
+// skip advances past an item, up to the first token at depth zero for
+// which stop reports true, an unbalanced closing parenthesis, a semicolon
+// or the end of input.
+func (p *parser) skip(stop stopFunc) {
+	depth, cases := 0, 0
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokEOF:
+			return
+		case p.isOp(t, "(") || p.isOp(t, "["):
+			depth++
+		case p.isOp(t, ")") || p.isOp(t, "]"):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case depth > 0:
+		case p.isKeyword(t, "CASE"):
+			cases++
+		case p.isKeyword(t, "END") && cases > 0:
+			cases--
+		case cases > 0:
+		case p.isOp(t, ";") || stop(p, t):
+			return
+		case p.isKeyword(t, "BETWEEN"):
+			// Step over the AND of BETWEEN x AND y.
+			p.pos++
+			for t := p.peek(); !p.isKeyword(t, "AND"); t = p.peek() {
+				if t.kind == tokEOF {
+					panic(p.errorAt(t, "expected AND after BETWEEN"))
+				}
+				p.pos++
+			}
+		}
+		p.pos++
+	}
+}
+
+// raw returns the source text of tokens [from, to) as a SqlLiteral.
+// Comments are dropped and whitespace is collapsed to single spaces.
+// Placeholders become ? markers bound to their arguments.
+func (p *parser) raw(from, to int) *nodes.SqlLiteral {
+	params := slices.ContainsFunc(p.toks[from:to], func(t token) bool { return t.kind == tokParam })
+	var sb strings.Builder
+	var binds []any
+	for i := from; i < to; i++ {
+		t := p.toks[i]
+		if i > from && p.toks[i-1].end < t.pos {
+			sb.WriteByte(' ')
+		}
+		switch {
+		case t.kind == tokParam:
+			sb.WriteByte('?')
+			binds = append(binds, p.arg(t))
+		case params:
+			sb.WriteString(p.fragmentText(t))
+		default:
+			sb.WriteString(t.text)
+		}
+	}
+	n := nodes.NewSqlLiteral(nodes.RawSQL(sb.String()))
+	if params {
+		n.Binds = binds
+		n.Placeholders = true
+	}
+	return n
+}
+
+// fragmentText returns the text of t in a placeholder-aware fragment,
+// where ? outside '...', "..." and `...` quotes is a marker.
+func (p *parser) fragmentText(t token) string {
+	switch {
+	case t.kind == tokOp:
+		return strings.ReplaceAll(t.text, "?", "??")
+	case t.kind == tokString && strings.HasPrefix(t.text, "$"):
+		// A dollar-quoted string, as a standard one.
+		return "'" + strings.ReplaceAll(t.val, "'", "''") + "'"
+	case t.kind == tokString && p.cfg.dialect == MySQL && strings.Contains(t.text, `\`):
+		// Backslash escapes would hide the closing quote.
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(t.val) + "'"
+	}
+	return t.text
+}
+
+// arg returns the argument for placeholder t.
+func (p *parser) arg(t token) any {
+	idx := p.nextArg
+	if t.val != "" {
+		n, err := strconv.Atoi(t.val)
+		if err != nil || n < 1 {
+			panic(p.errorAt(t, "invalid placeholder %s", t.text))
+		}
+		idx = n - 1
+	} else {
+		p.nextArg++
+	}
+	if idx >= len(p.cfg.args) {
+		panic(p.errorAt(t, "placeholder %s has no argument (%d given)", t.text, len(p.cfg.args)))
+	}
+	return p.cfg.args[idx]
+}
+
+// commentBefore returns the text of the block comment directly before
+// token i, if any.
+func (p *parser) commentBefore(i int) (string, bool) {
+	prev := 0
+	if i > 0 {
+		prev = p.toks[i-1].end
+	}
+	for _, c := range p.comments {
+		if c.pos >= prev && c.end <= p.toks[i].pos && !strings.HasPrefix(c.text, "+") {
+			return strings.TrimSpace(c.text), true
+		}
+	}
+	return "", false
+}
+
+// hintsBefore returns the optimizer hints (/*+ ... */) directly before
+// token i.
+func (p *parser) hintsBefore(i int) []string {
+	var hints []string
+	for _, c := range p.comments {
+		if c.pos >= p.toks[i-1].end && c.end <= p.toks[i].pos && strings.HasPrefix(c.text, "+") {
+			hints = append(hints, strings.TrimSpace(c.text[1:]))
+		}
+	}
+	return hints
+}
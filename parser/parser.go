@@ -0,0 +1,255 @@
+// Package parser turns SQL text into gosbee AST nodes, so that existing
+// SQL strings can be run through transformer plugins and rendered again.
+//
+//	mgr, warnings, err := parser.ParseSelect(
+//		"SELECT id, email FROM users WHERE active = $1",
+//		parser.WithArgs(true),
+//	)
+//	mgr.Use(softdelete.New())
+//	sql, params, err := mgr.ToSQL(visitors.NewPostgresVisitor())
+//
+// SELECT (with joins, CTEs, subqueries, set operations, window functions,
+// CASE and aggregates), INSERT, UPDATE and DELETE statements are parsed
+// in PostgreSQL, MySQL or SQLite syntax. Syntax that has no equivalent
+// node is kept as a nodes.SqlLiteral holding the original text, and a
+// Warning is reported for it; a clause that cannot be represented at all
+// makes the whole statement a SqlLiteral. Malformed SQL, such as an
+// unclosed parenthesis or an empty IN list, fails with an *Error.
+//
+// Column references are always rendered qualified, so unqualified column
+// names are resolved to the FROM entry they belong to. A query with a
+// single FROM entry needs no help; with several, WithSchema supplies the
+// table columns used to tell them apart.
+//
+// SECURITY: the parsed text becomes part of the rendered SQL, partly
+// verbatim. Only parse SQL written by developers, never SQL built from
+// user input; pass user values as placeholders with WithArgs.
+package parser
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/schema"
+)
+
+// Dialect selects the SQL syntax accepted by the parser.
+type Dialect int
+
+const (
+	// Postgres accepts "quoted" identifiers and $1 placeholders, and folds
+	// unquoted identifiers to lower case.
+	Postgres Dialect = iota
+
+	// MySQL accepts `quoted` identifiers, "double-quoted" strings,
+	// backslash escapes in strings and ? placeholders.
+	MySQL
+
+	// SQLite accepts "quoted", `quoted` and [quoted] identifiers and ?
+	// or ?NNN placeholders.
+	SQLite
+)
+
+// String returns the dialect name.
+func (d Dialect) String() string {
+	switch d {
+	case MySQL:
+		return "MySQL"
+	case SQLite:
+		return "SQLite"
+	default:
+		return "PostgreSQL"
+	}
+}
+
+// Option configures Parse.
+type Option func(*config)
+
+type config struct {
+	dialect Dialect
+	args    []any
+	schema  *schema.Schema
+}
+
+// WithDialect sets the SQL syntax to accept. The default is Postgres.
+func WithDialect(d Dialect) Option {
+	return func(c *config) {
+		c.dialect = d
+	}
+}
+
+// WithArgs supplies the values of the statement's placeholders. Each
+// placeholder becomes a nodes.BindParamNode holding its value: $n takes
+// the n-th argument, and ? takes the next one.
+func WithArgs(args ...any) Option {
+	return func(c *config) {
+		c.args = args
+	}
+}
+
+// WithSchema supplies table columns for resolving unqualified column
+// names in queries with more than one FROM entry.
+func WithSchema(s *schema.Schema) Option {
+	return func(c *config) {
+		c.schema = s
+	}
+}
+
+// Warning reports a construct that was kept as raw SQL.
+type Warning struct {
+	Line    int // 1-based
+	Column  int // 1-based, in bytes
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%d:%d: %s", w.Line, w.Column, w.Message)
+}
+
+// Error is a syntax error.
+type Error struct {
+	Line    int // 1-based
+	Column  int // 1-based, in bytes
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("parser: %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// newError returns an Error at byte offset pos of src.
+func newError(src string, pos int, format string, args ...any) *Error {
+	line, col := position(src, pos)
+	return &Error{Line: line, Column: col, Message: fmt.Sprintf(format, args...)}
+}
+
+// position converts a byte offset to a line and column.
+func position(src string, pos int) (line, col int) {
+	before := src[:pos]
+	line = strings.Count(before, "\n") + 1
+	col = pos - strings.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// Result is a parsed statement.
+type Result struct {
+	// Statement is a *nodes.SelectCore, *nodes.SetOperationNode,
+	// *nodes.InsertStatement, *nodes.UpdateStatement or
+	// *nodes.DeleteStatement, or a *nodes.SqlLiteral holding the whole
+	// statement when it could not be represented.
+	Statement nodes.Node
+
+	// Warnings lists the constructs kept as raw SQL.
+	Warnings []Warning
+}
+
+// Parse parses a single SQL statement. A trailing semicolon is allowed.
+func Parse(sql nodes.RawSQL, opts ...Option) (*Result, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	src := string(sql)
+	toks, comments, err := lex(src, cfg.dialect)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		cfg:       cfg,
+		src:       src,
+		toks:      toks,
+		comments:  comments,
+		outputsOf: make(map[*nodes.SelectCore]outputInfo),
+	}
+	stmt, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	// Warnings are reported as found, and FROM is parsed first.
+	slices.SortStableFunc(p.warnings, func(a, b Warning) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	return &Result{Statement: stmt, Warnings: p.warnings}, nil
+}
+
+// ErrStatementKind is returned by ParseSelect, ParseInsert, ParseUpdate
+// and ParseDelete when the SQL is a different kind of statement, or could
+// only be kept as raw SQL.
+var ErrStatementKind = errors.New("parser: unexpected statement kind")
+
+// ParseSelect parses a SELECT statement into a SelectManager. Set
+// operations cannot be held by a SelectManager; use Parse for them.
+func ParseSelect(sql nodes.RawSQL, opts ...Option) (*managers.SelectManager, []Warning, error) {
+	res, err := Parse(sql, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	core, ok := res.Statement.(*nodes.SelectCore)
+	if !ok {
+		return nil, res.Warnings, kindError("SELECT", res.Statement)
+	}
+	return &managers.SelectManager{Core: core}, res.Warnings, nil
+}
+
+// ParseInsert parses an INSERT statement into an InsertManager.
+func ParseInsert(sql nodes.RawSQL, opts ...Option) (*managers.InsertManager, []Warning, error) {
+	res, err := Parse(sql, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	stmt, ok := res.Statement.(*nodes.InsertStatement)
+	if !ok {
+		return nil, res.Warnings, kindError("INSERT", res.Statement)
+	}
+	return &managers.InsertManager{Statement: stmt}, res.Warnings, nil
+}
+
+// ParseUpdate parses an UPDATE statement into an UpdateManager.
+func ParseUpdate(sql nodes.RawSQL, opts ...Option) (*managers.UpdateManager, []Warning, error) {
+	res, err := Parse(sql, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	stmt, ok := res.Statement.(*nodes.UpdateStatement)
+	if !ok {
+		return nil, res.Warnings, kindError("UPDATE", res.Statement)
+	}
+	return &managers.UpdateManager{Statement: stmt}, res.Warnings, nil
+}
+
+// ParseDelete parses a DELETE statement into a DeleteManager.
+func ParseDelete(sql nodes.RawSQL, opts ...Option) (*managers.DeleteManager, []Warning, error) {
+	res, err := Parse(sql, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	stmt, ok := res.Statement.(*nodes.DeleteStatement)
+	if !ok {
+		return nil, res.Warnings, kindError("DELETE", res.Statement)
+	}
+	return &managers.DeleteManager{Statement: stmt}, res.Warnings, nil
+}
+
+func kindError(want string, got nodes.Node) error {
+	var kind string
+	switch got.(type) {
+	case *nodes.SelectCore:
+		kind = "a SELECT"
+	case *nodes.SetOperationNode:
+		kind = "a set operation"
+	case *nodes.InsertStatement:
+		kind = "an INSERT"
+	case *nodes.UpdateStatement:
+		kind = "an UPDATE"
+	case *nodes.DeleteStatement:
+		kind = "a DELETE"
+	default:
+		kind = "raw SQL"
+	}
+	return fmt.Errorf("%w: expected %s statement, got %s", ErrStatementKind, want, kind)
+}
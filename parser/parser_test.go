@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins/softdelete"
+	"github.com/bawdo/gosbee/schema"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+func TestParseSelectToSQL(t *testing.T) {
+	t.Parallel()
+	mgr, warnings, err := ParseSelect("SELECT id, email FROM users WHERE active = $1 AND age > $2", WithArgs(true, 18))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(warnings), 0)
+
+	sql, params, err := mgr.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT "users"."id", "users"."email" FROM "users" WHERE "users"."active" = $1 AND "users"."age" > $2`)
+	testutil.AssertEqual(t, reflect.DeepEqual(params, []any{true, 18}), true)
+}
+
+func TestParseSelectWithPlugin(t *testing.T) {
+	t.Parallel()
+	mgr, _, err := ParseSelect("SELECT id FROM users WHERE name = 'a' OR name = 'b'")
+	testutil.AssertNoError(t, err)
+	mgr.Use(softdelete.New())
+
+	sql, _, err := mgr.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT "users"."id" FROM "users" WHERE ("users"."name" = 'a' OR "users"."name" = 'b') AND "users"."deleted_at" IS NULL`)
+}
+
+func TestParseNumberedPlaceholders(t *testing.T) {
+	t.Parallel()
+	mgr, _, err := ParseSelect("SELECT id FROM users WHERE b = $2 AND a = $1 AND c = $2", WithArgs("a", "b"))
+	testutil.AssertNoError(t, err)
+	_, params, err := mgr.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, reflect.DeepEqual(params, []any{"b", "a", "b"}), true)
+}
+
+func TestParseRawSQLKeepsPlaceholders(t *testing.T) {
+	t.Parallel()
+	mgr, warnings, err := ParseSelect("SELECT id FROM users WHERE id = ANY($1) AND name = $2", WithArgs([]int{1, 2}, "x"))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(warnings), 1)
+
+	sql, params, err := mgr.ToSQL(visitors.NewMySQLVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "SELECT `users`.`id` FROM `users` WHERE id = ANY(?) AND `users`.`name` = ?")
+	testutil.AssertEqual(t, reflect.DeepEqual(params, []any{[]int{1, 2}, "x"}), true)
+}
+
+func TestParseRawSQLEscapesQuestionMarks(t *testing.T) {
+	t.Parallel()
+	// ?| is a PostgreSQL operator; next to a placeholder it must not be
+	// taken for a marker.
+	mgr, _, err := ParseSelect("SELECT id FROM users WHERE tags ?| $1", WithArgs("x"))
+	testutil.AssertNoError(t, err)
+	sql, params, err := mgr.ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT "users"."id" FROM "users" WHERE tags ?| $1`)
+	testutil.AssertEqual(t, reflect.DeepEqual(params, []any{"x"}), true)
+}
+
+func TestParseMissingArgument(t *testing.T) {
+	t.Parallel()
+	_, err := Parse("SELECT id FROM users WHERE id = $2", WithArgs(1))
+	testutil.AssertError(t, err)
+	var perr *Error
+	testutil.AssertEqual(t, errors.As(err, &perr), true)
+	testutil.AssertEqual(t, perr.Line, 1)
+	testutil.AssertEqual(t, perr.Column, 33)
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		sql  nodes.RawSQL
+		want string
+	}{
+		{"not a statement", "DROP TABLE users", "parser: 1:1: expected SELECT, INSERT, UPDATE or DELETE, found DROP"},
+		{"unterminated string", "SELECT id\nFROM users WHERE name = 'x", "parser: 2:25: unterminated string"},
+		{"unterminated comment", "SELECT 1 /* x", "parser: 1:10: unterminated comment"},
+		{"bad character", "SELECT id FROM users WHERE a = \\", `parser: 1:32: unexpected character '\\'`},
+		{"BETWEEN without AND", "SELECT a FROM t WHERE a BETWEEN 1", "parser: 1:34: expected AND after BETWEEN"},
+		{"BETWEEN at end", "SELECT a BETWEEN", "parser: 1:17: expected AND after BETWEEN"},
+		{"unclosed parenthesis", "SELECT (", "parser: 1:9: unexpected end of input"},
+		{"missing table", "SELECT a FROM", "parser: 1:14: expected an identifier, found end of input"},
+		{"CASE without WHEN", "SELECT CASE END", "parser: 1:13: expected WHEN, found END"},
+		{"simple CASE without WHEN", "SELECT CASE a ELSE 1 END", "parser: 1:15: expected WHEN, found ELSE"},
+		{"empty IN list", "SELECT * FROM t WHERE a IN ()", `parser: 1:29: expected an expression, found ")"`},
+		{"trailing comma", "SELECT a, FROM t", "parser: 1:11: unexpected FROM"},
+		{"unfinished WHERE", "SELECT * FROM t WHERE", "parser: 1:22: unexpected end of input"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse(c.sql)
+			testutil.AssertError(t, err)
+			testutil.AssertEqual(t, err.Error(), c.want)
+		})
+	}
+}
+
+func TestParseUnsupportedStatementIsRaw(t *testing.T) {
+	t.Parallel()
+	res, err := Parse("SELECT id FROM users FOR UPDATE NOWAIT;")
+	testutil.AssertNoError(t, err)
+	lit, ok := res.Statement.(*nodes.SqlLiteral)
+	testutil.AssertEqual(t, ok, true)
+	testutil.AssertEqual(t, string(lit.Raw), "SELECT id FROM users FOR UPDATE NOWAIT")
+	testutil.AssertEqual(t, len(res.Warnings), 1)
+	testutil.AssertEqual(t, res.Warnings[0].String(), "1:33: FOR ... NOWAIT is not supported; the statement is kept as raw SQL")
+
+	_, _, err = ParseSelect("SELECT id FROM users FOR UPDATE NOWAIT")
+	testutil.AssertEqual(t, errors.Is(err, ErrStatementKind), true)
+}
+
+func TestParseStatementKind(t *testing.T) {
+	t.Parallel()
+	_, _, err := ParseSelect("DELETE FROM users")
+	testutil.AssertEqual(t, errors.Is(err, ErrStatementKind), true)
+	testutil.AssertEqual(t, err.Error(), "parser: unexpected statement kind: expected SELECT statement, got a DELETE")
+
+	_, _, err = ParseSelect("SELECT 1 UNION SELECT 2")
+	testutil.AssertEqual(t, errors.Is(err, ErrStatementKind), true)
+
+	ins, _, err := ParseInsert("INSERT INTO users (id) VALUES (1)")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(ins.Statement.Values), 1)
+
+	upd, _, err := ParseUpdate("UPDATE users SET name = 'x'")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(upd.Statement.Assignments), 1)
+
+	del, _, err := ParseDelete("DELETE FROM users WHERE id = 1")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(del.Statement.Wheres), 1)
+}
+
+func TestParseResolvesColumnsWithSchema(t *testing.T) {
+	t.Parallel()
+	const sql = "SELECT email, total FROM users JOIN orders ON user_id = users.id"
+
+	res, err := Parse(sql)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(res.Warnings), 3)
+	testutil.AssertEqual(t, res.Warnings[0].Message, `cannot tell which table column "email" belongs to (use WithSchema); kept as raw SQL`)
+
+	s := &schema.Schema{Tables: []*schema.Table{
+		goldenTable("users", "id", "email"),
+		goldenTable("orders", "id", "user_id", "total"),
+	}}
+	res, err = Parse(sql, WithSchema(s))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(res.Warnings), 0)
+	got := res.Statement.Accept(visitors.NewPostgresVisitor())
+	testutil.AssertEqual(t, got, `SELECT "users"."email", "orders"."total" FROM "users" INNER JOIN "orders" ON "orders"."user_id" = "users"."id"`)
+}
+
+func TestParseAmbiguousColumn(t *testing.T) {
+	t.Parallel()
+	s := &schema.Schema{Tables: []*schema.Table{
+		goldenTable("users", "id"),
+		goldenTable("orders", "id"),
+	}}
+	res, err := Parse("SELECT id FROM users, orders", WithSchema(s))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(res.Warnings), 1)
+	testutil.AssertEqual(t, res.Warnings[0].String(), `1:8: column "id" is ambiguous; kept as raw SQL`)
+}
+
+func TestParseCorrelatedSubquery(t *testing.T) {
+	t.Parallel()
+	res, err := Parse("SELECT id FROM users u WHERE EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id)")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(res.Warnings), 0)
+	core := res.Statement.(*nodes.SelectCore)
+	exists := core.Wheres[0].(*nodes.ExistsNode)
+	sub := exists.Subquery.(*nodes.SelectCore)
+	cmp := sub.Wheres[0].(*nodes.ComparisonNode)
+	// u.id refers to the outer FROM entry.
+	testutil.AssertEqual(t, cmp.Right.(*nodes.Attribute).Relation == core.From, true)
+}
+
+func TestParseIdentifierCase(t *testing.T) {
+	t.Parallel()
+	res, err := Parse(`SELECT Id FROM Users WHERE "Name" = 'x'`)
+	testutil.AssertNoError(t, err)
+	got := res.Statement.Accept(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	testutil.AssertEqual(t, got, `SELECT "users"."id" FROM "users" WHERE "users"."Name" = 'x'`)
+
+	res, err = Parse("SELECT Id FROM Users u WHERE U.id = 1", WithDialect(MySQL))
+	testutil.AssertNoError(t, err)
+	got = res.Statement.Accept(visitors.NewMySQLVisitor(visitors.WithoutParams()))
+	testutil.AssertEqual(t, got, "SELECT `u`.`Id` FROM `Users` AS `u` WHERE `u`.`id` = 1")
+}
+
+func TestParseWarningPosition(t *testing.T) {
+	t.Parallel()
+	res, err := Parse("SELECT id\nFROM users\nWHERE name ILIKE 'a%'")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(res.Warnings), 1)
+	testutil.AssertEqual(t, res.Warnings[0].Line, 3)
+	testutil.AssertEqual(t, res.Warnings[0].Column, 12)
+}
+
+func TestParseDialectString(t *testing.T) {
+	t.Parallel()
+	testutil.AssertEqual(t, Postgres.String(), "PostgreSQL")
+	testutil.AssertEqual(t, MySQL.String(), "MySQL")
+	testutil.AssertEqual(t, SQLite.String(), "SQLite")
+}
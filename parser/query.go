@@ -0,0 +1,632 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// clauseKeywords lists the keywords that end an expression or list item.
+var clauseKeywords = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "WINDOW": true,
+	"ORDER": true, "LIMIT": true, "OFFSET": true, "FETCH": true, "FOR": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "INTO": true,
+	"RETURNING": true, "ON": true, "USING": true, "SET": true, "VALUES": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"CROSS": true, "NATURAL": true, "STRAIGHT_JOIN": true,
+}
+
+// isClause reports whether t starts a clause. LEFT and RIGHT followed by
+// a parenthesis are function calls.
+func (p *parser) isClause(t token) bool {
+	if t.kind != tokIdent || !clauseKeywords[strings.ToUpper(t.text)] {
+		return false
+	}
+	if p.isKeyword(t, "LEFT") || p.isKeyword(t, "RIGHT") {
+		return !p.isOp(p.after(t), "(")
+	}
+	return true
+}
+
+// stopList ends the items of a comma-separated list.
+func stopList(p *parser, t token) bool {
+	return p.isOp(t, ",") || p.isClause(t)
+}
+
+// stopCondition ends the AND/OR terms of a condition.
+func stopCondition(p *parser, t token) bool {
+	if p.isKeyword(t, "AND") || p.isKeyword(t, "OR") {
+		return true
+	}
+	if p.cfg.dialect == MySQL && (p.isOp(t, "&&") || p.isOp(t, "||")) {
+		return true
+	}
+	return p.isClause(t)
+}
+
+// outputInfo holds the output columns of a parsed query.
+type outputInfo struct {
+	columns []string
+	known   bool
+}
+
+// query parses a SELECT statement, optionally with a WITH clause and set
+// operations.
+func (p *parser) query() nodes.Node {
+	p.pushScope()
+	defer p.popScope()
+
+	var ctes []*nodes.CTENode
+	withTok := p.peek()
+	if p.accept("WITH") {
+		ctes = p.with()
+	}
+	n := p.setOperation()
+	if len(ctes) > 0 {
+		core, ok := n.(*nodes.SelectCore)
+		if !ok {
+			p.fail(withTok, "WITH before a set operation is not supported")
+		}
+		core.CTEs = append(ctes, core.CTEs...)
+	}
+	return n
+}
+
+// with parses the entries of a WITH clause and adds them to the scope.
+func (p *parser) with() []*nodes.CTENode {
+	recursive := p.accept("RECURSIVE")
+	var ctes []*nodes.CTENode
+	for {
+		cte := &nodes.CTENode{Name: p.ident(), Recursive: recursive}
+		entry := &relation{name: cte.Name}
+		if p.acceptOp("(") {
+			for {
+				cte.Columns = append(cte.Columns, p.ident())
+				if !p.acceptOp(",") {
+					break
+				}
+			}
+			p.expectOp(")")
+			entry.columns, entry.known = cte.Columns, true
+		}
+		p.expect("AS")
+		if p.at("MATERIALIZED", "NOT") {
+			p.fail(p.peek(), "MATERIALIZED is not supported")
+		}
+		if recursive {
+			p.scope.ctes = append(p.scope.ctes, entry)
+		}
+		p.expectOp("(")
+		cte.Query = p.query()
+		p.expectOp(")")
+		if !entry.known {
+			entry.columns, entry.known = p.queryOutputs(cte.Query)
+		}
+		if !recursive {
+			p.scope.ctes = append(p.scope.ctes, entry)
+		}
+		ctes = append(ctes, cte)
+		if !p.acceptOp(",") {
+			return ctes
+		}
+	}
+}
+
+// setOperation parses queries joined by UNION, INTERSECT and EXCEPT.
+// INTERSECT binds more tightly than UNION and EXCEPT. ORDER BY, LIMIT and
+// OFFSET after the last query apply to the whole result.
+func (p *parser) setOperation() nodes.Node {
+	left := p.intersection(true)
+	for p.at("UNION", "EXCEPT") {
+		typ := nodes.Union
+		if p.advance(); p.isKeyword(p.toks[p.pos-1], "EXCEPT") {
+			typ = nodes.Except
+		}
+		if p.accept("ALL") {
+			typ++ // UnionAll, ExceptAll
+		} else {
+			p.accept("DISTINCT")
+		}
+		left = &nodes.SetOperationNode{Left: left, Right: p.intersection(false), Type: typ}
+	}
+	op, ok := left.(*nodes.SetOperationNode)
+	if !ok {
+		return left
+	}
+	// ORDER BY names the result columns, not those of the FROM entries.
+	cols, known := p.queryOutputs(op)
+	p.scope.outputs, p.scope.anyOutput = cols, !known
+	op.Orders, op.Limit, op.Offset = p.orderAndLimit()
+	return op
+}
+
+// intersection parses queries joined by INTERSECT. first is set for the
+// leftmost query of a statement, which may carry its own ORDER BY and
+// LIMIT when no set operation follows.
+func (p *parser) intersection(first bool) nodes.Node {
+	left := p.operand(first)
+	for p.accept("INTERSECT") {
+		typ := nodes.Intersect
+		if p.accept("ALL") {
+			typ = nodes.IntersectAll
+		} else {
+			p.accept("DISTINCT")
+		}
+		left = &nodes.SetOperationNode{Left: left, Right: p.operand(false), Type: typ}
+	}
+	return left
+}
+
+// operand parses a SELECT or a parenthesised query.
+func (p *parser) operand(first bool) nodes.Node {
+	if p.acceptOp("(") {
+		q := p.query()
+		p.expectOp(")")
+		return q
+	}
+	return p.selectCore(first)
+}
+
+// queryOutputs returns the output columns of a parsed query. A set
+// operation takes its column names from its leftmost query.
+func (p *parser) queryOutputs(n nodes.Node) ([]string, bool) {
+	for {
+		op, ok := n.(*nodes.SetOperationNode)
+		if !ok {
+			break
+		}
+		n = op.Left
+	}
+	core, ok := n.(*nodes.SelectCore)
+	if !ok {
+		return nil, false
+	}
+	info := p.outputsOf[core]
+	return info.columns, info.known
+}
+
+// selectCore parses a single SELECT. The FROM clause is parsed before the
+// projections so that column references in them can be resolved.
+func (p *parser) selectCore(tail bool) *nodes.SelectCore {
+	core := &nodes.SelectCore{}
+	if c, ok := p.commentBefore(p.pos); ok {
+		core.Comment = c
+	}
+	p.expect("SELECT")
+	core.Hints = p.hintsBefore(p.pos)
+	p.pushScope()
+	defer p.popScope()
+
+	start := p.pos
+	p.skip(func(p *parser, t token) bool { return p.isClause(t) && !p.isKeyword(t, "ON") })
+	fromPos, fromEnd := p.pos, -1
+	if p.accept("FROM") {
+		p.from(core)
+		fromEnd = p.pos
+	}
+
+	p.pos = start
+	if p.accept("DISTINCT") {
+		if p.accept("ON") {
+			p.expectOp("(")
+			core.DistinctOn = p.list()
+			p.expectOp(")")
+		} else {
+			core.Distinct = true
+		}
+	} else {
+		p.accept("ALL")
+	}
+	for {
+		core.Projections = append(core.Projections, p.projection())
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	if p.pos != fromPos {
+		t := p.peek()
+		p.fail(t, "unexpected %s", describe(t))
+	}
+	if fromEnd >= 0 {
+		p.pos = fromEnd
+	}
+	if len(core.Projections) == 1 {
+		if star, ok := core.Projections[0].(*nodes.StarNode); ok && star.Table == nil {
+			core.Projections = nil
+		}
+	}
+	cols, known := p.outputs(core.Projections)
+	p.outputsOf[core] = outputInfo{columns: cols, known: known}
+
+	if p.accept("WHERE") {
+		core.Wheres = p.conditions()
+	}
+	for _, proj := range core.Projections {
+		if a, ok := proj.(*nodes.AliasNode); ok {
+			p.scope.outputs = append(p.scope.outputs, a.Name)
+		}
+	}
+	if p.accept("GROUP", "BY") {
+		core.Groups = p.groupBy()
+	}
+	if p.accept("HAVING") {
+		core.Havings = p.conditions()
+	}
+	if p.accept("WINDOW") {
+		core.Windows = p.windows()
+	}
+	if tail {
+		core.Orders, core.Limit, core.Offset = p.orderAndLimit()
+	}
+	if t := p.peek(); p.accept("FOR") {
+		core.Lock, core.SkipLocked = p.lock(t)
+	}
+	return core
+}
+
+// projection parses a SELECT list item.
+func (p *parser) projection() nodes.Node {
+	return p.fallback(stopList, func() nodes.Node {
+		if p.acceptOp("*") {
+			return nodes.Star()
+		}
+		if p.isName(p.peek()) && p.isOp(p.peekAt(1), ".") && p.isOp(p.peekAt(2), "*") {
+			name := p.ident()
+			p.pos += 2
+			return &nodes.StarNode{Table: nodes.NewTable(name)}
+		}
+		return p.aliased(p.expr())
+	})
+}
+
+// aliased parses an optional [AS] alias after e.
+func (p *parser) aliased(e nodes.Node) nodes.Node {
+	if p.accept("AS") || p.isName(p.peek()) {
+		return nodes.NewAliasNode(e, p.ident())
+	}
+	return e
+}
+
+// from parses the FROM list and its joins. Comma-separated entries become
+// cross joins.
+func (p *parser) from(core *nodes.SelectCore) {
+	core.From = p.fallbackFrom(p.pos, stopList, func() nodes.Node { return p.fromItem() })
+	for {
+		if p.acceptOp(",") {
+			start := p.pos
+			lateral := p.accept("LATERAL")
+			right := p.fallbackFrom(start, stopList, func() nodes.Node { return p.fromItem() })
+			core.Joins = append(core.Joins, &nodes.JoinNode{Left: core.From, Right: right, Type: nodes.CrossJoin, Lateral: lateral})
+			continue
+		}
+		if !p.at("JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", "STRAIGHT_JOIN") {
+			return
+		}
+		core.Joins = append(core.Joins, p.join(core.From))
+	}
+}
+
+// fallbackFrom parses a FROM entry like fallback. A raw entry is added to
+// the scope under its trailing name, or makes the scope opaque.
+func (p *parser) fallbackFrom(start int, stop stopFunc, parse func() nodes.Node) nodes.Node {
+	n := p.fallback(stop, parse)
+	if _, ok := n.(*nodes.SqlLiteral); ok {
+		if name := p.lastName(start, p.pos); name != "" {
+			p.scope.relations = append(p.scope.relations, &relation{name: name, node: nodes.NewTable(name)})
+		} else {
+			p.scope.opaque = true
+		}
+	}
+	return n
+}
+
+// fromItem parses a table or derived table with an optional alias, and
+// adds it to the scope.
+func (p *parser) fromItem() nodes.Node {
+	t := p.peek()
+	if p.acceptOp("(") {
+		if !p.at("SELECT", "WITH") && !p.isOp(p.peek(), "(") {
+			p.fail(t, "parenthesised joins are not supported")
+		}
+		q := p.query()
+		p.expectOp(")")
+		p.accept("AS")
+		alias := p.ident()
+		if p.isOp(p.peek(), "(") {
+			p.fail(p.peek(), "column aliases are not supported")
+		}
+		ta := &nodes.TableAlias{Relation: q, AliasName: alias}
+		r := &relation{name: alias, node: ta}
+		r.columns, r.known = p.queryOutputs(q)
+		p.scope.relations = append(p.scope.relations, r)
+		return ta
+	}
+
+	name := p.ident()
+	if p.isOp(p.peek(), ".") {
+		p.fail(t, "schema-qualified table names are not supported")
+	}
+	if p.isOp(p.peek(), "(") {
+		p.fail(t, "table functions are not supported")
+	}
+	r := p.table(name)
+	var n nodes.Node = r.node
+	if p.accept("AS") || p.isName(p.peek()) {
+		alias := p.ident()
+		if p.isOp(p.peek(), "(") {
+			p.fail(p.peek(), "column aliases are not supported")
+		}
+		ta := r.node.(*nodes.Table).Alias(alias)
+		r.name, r.node, n = alias, ta, ta
+	}
+	p.scope.relations = append(p.scope.relations, r)
+	return n
+}
+
+// join parses a JOIN clause. A join that cannot be represented is kept
+// as a raw string join.
+func (p *parser) join(left nodes.Node) *nodes.JoinNode {
+	start, tok := p.pos, p.peek()
+	natural := p.accept("NATURAL")
+	typ := nodes.InnerJoin
+	switch {
+	case p.accept("LEFT"):
+		typ = nodes.LeftOuterJoin
+	case p.accept("RIGHT"):
+		typ = nodes.RightOuterJoin
+	case p.accept("FULL"):
+		typ = nodes.FullOuterJoin
+	case p.accept("CROSS"):
+		typ = nodes.CrossJoin
+	default:
+		p.accept("INNER")
+	}
+	if typ != nodes.InnerJoin && typ != nodes.CrossJoin {
+		p.accept("OUTER")
+	}
+	straight := p.accept("STRAIGHT_JOIN")
+	if !straight {
+		p.expect("JOIN")
+	}
+
+	relations := len(p.scope.relations)
+	n := p.fallbackJoin(start, func() nodes.Node {
+		if natural || straight {
+			p.fail(tok, "%s is not supported", strings.ToUpper(tok.text))
+		}
+		j := &nodes.JoinNode{Left: left, Type: typ, Lateral: p.accept("LATERAL")}
+		j.Right = p.fallbackFrom(p.pos, stopList, func() nodes.Node { return p.fromItem() })
+		if typ == nodes.CrossJoin {
+			return j
+		}
+		if t := p.peek(); p.accept("USING") {
+			p.fail(t, "JOIN ... USING is not supported")
+		}
+		p.expect("ON")
+		j.On = chainAnd(p.conditions())
+		return j
+	})
+	if j, ok := n.(*nodes.JoinNode); ok {
+		return j
+	}
+	if len(p.scope.relations) == relations {
+		p.scope.opaque = true
+	}
+	return &nodes.JoinNode{Left: left, Right: n, Type: nodes.StringJoin}
+}
+
+// fallbackJoin is fallback for a join whose keywords start at token
+// start. Relations the failed attempt added to the scope are kept.
+func (p *parser) fallbackJoin(start int, parse func() nodes.Node) nodes.Node {
+	n := p.fallback(func(p *parser, t token) bool {
+		return p.isOp(t, ",") || p.isClause(t) && !p.isKeyword(t, "ON") && !p.isKeyword(t, "USING")
+	}, parse)
+	if _, ok := n.(*nodes.SqlLiteral); ok {
+		// Include the join keywords, which fallback did not see.
+		n = p.raw(start, p.pos)
+	}
+	return n
+}
+
+// conditions parses a WHERE, HAVING or ON condition into its top-level
+// AND terms, or a single OR. Each term that cannot be represented is kept
+// as raw SQL on its own.
+func (p *parser) conditions() []nodes.Node {
+	term := func() nodes.Node { return p.fallback(stopCondition, p.not) }
+	terms := []nodes.Node{term()}
+	for p.acceptAnd() {
+		terms = append(terms, term())
+	}
+	if !p.atOr() {
+		return terms
+	}
+	left := chainAnd(terms)
+	for p.acceptOr() {
+		right := term()
+		for p.acceptAnd() {
+			right = nodes.NewAndNode(right, term())
+		}
+		left = nodes.NewOrNode(left, right)
+	}
+	// Grouped so that conditions added later, by plugins for instance,
+	// apply to the whole OR.
+	return []nodes.Node{nodes.NewGroupingNode(left)}
+}
+
+// chainAnd combines terms with AND.
+func chainAnd(terms []nodes.Node) nodes.Node {
+	n := terms[0]
+	for _, t := range terms[1:] {
+		n = nodes.NewAndNode(n, t)
+	}
+	return n
+}
+
+// groupBy parses the GROUP BY list.
+func (p *parser) groupBy() []nodes.Node {
+	var groups []nodes.Node
+	for {
+		groups = append(groups, p.fallback(stopList, func() nodes.Node {
+			switch {
+			case p.accept("ROLLUP"):
+				p.expectOp("(")
+				n := nodes.NewRollup(p.list()...)
+				p.expectOp(")")
+				return n
+			case p.accept("CUBE"):
+				p.expectOp("(")
+				n := nodes.NewCube(p.list()...)
+				p.expectOp(")")
+				return n
+			case p.accept("GROUPING", "SETS"):
+				return p.groupingSets()
+			}
+			return p.position(p.expr)
+		}))
+		if !p.acceptOp(",") {
+			return groups
+		}
+	}
+}
+
+// groupingSets parses the sets of GROUPING SETS.
+func (p *parser) groupingSets() nodes.Node {
+	p.expectOp("(")
+	var sets [][]nodes.Node
+	for {
+		p.expectOp("(")
+		var set []nodes.Node
+		if !p.isOp(p.peek(), ")") {
+			set = p.list()
+		}
+		p.expectOp(")")
+		sets = append(sets, set)
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	p.expectOp(")")
+	return nodes.NewGroupingSets(sets...)
+}
+
+// position parses an ORDER BY or GROUP BY item, keeping a column
+// position such as the 2 in ORDER BY 2 as raw SQL: as a literal it would
+// be bound as a parameter and lose its meaning.
+func (p *parser) position(parse func() nodes.Node) nodes.Node {
+	t, next := p.peek(), p.peekAt(1)
+	if t.kind == tokNumber && !t.special && (p.atEnd(next) || stopList(p, next) ||
+		p.isKeyword(next, "ASC") || p.isKeyword(next, "DESC") || p.isKeyword(next, "NULLS")) {
+		p.pos++
+		return nodes.NewSqlLiteral(nodes.RawSQL(t.text))
+	}
+	return parse()
+}
+
+// windows parses the definitions of a WINDOW clause.
+func (p *parser) windows() []*nodes.WindowDefinition {
+	var defs []*nodes.WindowDefinition
+	for {
+		name := p.ident()
+		p.expect("AS")
+		def := p.windowSpec()
+		def.Name = name
+		defs = append(defs, def)
+		if !p.acceptOp(",") {
+			return defs
+		}
+	}
+}
+
+// orderAndLimit parses the ORDER BY, LIMIT, OFFSET and FETCH clauses.
+func (p *parser) orderAndLimit() (orders []nodes.Node, limit, offset nodes.Node) {
+	if p.accept("ORDER", "BY") {
+		orders = p.orderList()
+	}
+	for {
+		switch {
+		case p.accept("LIMIT"):
+			if p.accept("ALL") {
+				continue
+			}
+			limit = p.expr()
+			if p.cfg.dialect != Postgres && p.acceptOp(",") {
+				// MySQL and SQLite: LIMIT offset, count.
+				offset, limit = limit, p.expr()
+			}
+		case p.accept("OFFSET"):
+			offset = p.expr()
+			if !p.accept("ROWS") {
+				p.accept("ROW")
+			}
+		case p.at("FETCH"):
+			t := p.advance()
+			if !p.accept("FIRST") {
+				p.expect("NEXT")
+			}
+			limit = nodes.Literal(1)
+			if !p.at("ROW", "ROWS") {
+				limit = p.expr()
+			}
+			if !p.accept("ROWS") {
+				p.expect("ROW")
+			}
+			if !p.accept("ONLY") {
+				p.fail(t, "FETCH ... WITH TIES is not supported")
+			}
+		default:
+			return orders, limit, offset
+		}
+	}
+}
+
+// orderList parses ORDER BY items.
+func (p *parser) orderList() []nodes.Node {
+	var orders []nodes.Node
+	for {
+		orders = append(orders, p.fallback(stopList, p.ordering))
+		if !p.acceptOp(",") {
+			return orders
+		}
+	}
+}
+
+// ordering parses expr [ASC | DESC] [NULLS FIRST | NULLS LAST].
+func (p *parser) ordering() nodes.Node {
+	expr := p.position(p.expr)
+	dir := nodes.Asc
+	if p.accept("DESC") {
+		dir = nodes.Desc
+	} else {
+		p.accept("ASC")
+	}
+	nulls := nodes.NullsDefault
+	switch {
+	case p.accept("NULLS", "FIRST"):
+		nulls = nodes.NullsFirst
+	case p.accept("NULLS", "LAST"):
+		nulls = nodes.NullsLast
+	}
+	return nodes.NewOrderingNode(expr, dir, nulls)
+}
+
+// lock parses the lock mode after FOR.
+func (p *parser) lock(t token) (nodes.LockMode, bool) {
+	var mode nodes.LockMode
+	switch {
+	case p.accept("UPDATE"):
+		mode = nodes.ForUpdate
+	case p.accept("SHARE"):
+		mode = nodes.ForShare
+	case p.accept("NO", "KEY", "UPDATE"):
+		mode = nodes.ForNoKeyUpdate
+	case p.accept("KEY", "SHARE"):
+		mode = nodes.ForKeyShare
+	default:
+		p.fail(t, "unexpected FOR clause")
+	}
+	skip := p.accept("SKIP", "LOCKED")
+	if p.at("NOWAIT", "OF") {
+		p.fail(p.peek(), "FOR ... %s is not supported", strings.ToUpper(p.peek().text))
+	}
+	return mode, skip
+}
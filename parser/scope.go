@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// relation is a FROM entry that column references can name.
+type relation struct {
+	name    string     // alias, or the table name when not aliased
+	node    nodes.Node // the Attribute.Relation of references to it
+	columns []string   // output columns, when known
+	known   bool
+}
+
+// scope holds the FROM entries and WITH entries of one query. Subqueries
+// get a child scope so that correlated references resolve against the
+// enclosing queries.
+type scope struct {
+	parent    *scope
+	relations []*relation
+	ctes      []*relation
+
+	// outputs holds the projection aliases, which GROUP BY, HAVING and
+	// ORDER BY may name. anyOutput is set when every unqualified name
+	// refers to an output column, as in the ORDER BY of a set operation
+	// whose column names are unknown.
+	outputs   []string
+	anyOutput bool
+
+	// opaque is set when a FROM entry is raw SQL, whose columns are
+	// unknown.
+	opaque bool
+}
+
+func (p *parser) pushScope() { p.scope = &scope{parent: p.scope} }
+
+func (p *parser) popScope() { p.scope = p.scope.parent }
+
+// relation finds a FROM entry by name in the current and enclosing scopes.
+func (p *parser) relation(name string) *relation {
+	for s := p.scope; s != nil; s = s.parent {
+		for _, r := range s.relations {
+			if p.sameName(r.name, name) {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// cte finds a WITH entry by name in the current and enclosing scopes.
+func (p *parser) cte(name string) *relation {
+	for s := p.scope; s != nil; s = s.parent {
+		for _, c := range s.ctes {
+			if p.sameName(c.name, name) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// table returns the relation for a table reference, taking its columns
+// from a WITH entry or the schema when known.
+func (p *parser) table(name string) *relation {
+	r := &relation{name: name, node: nodes.NewTable(name)}
+	if c := p.cte(name); c != nil {
+		r.columns, r.known = c.columns, c.known
+	} else if p.cfg.schema != nil {
+		for _, t := range p.cfg.schema.Tables {
+			if p.sameName(t.Name, name) {
+				r.columns, r.known = t.ColumnNames(), true
+				break
+			}
+		}
+	}
+	return r
+}
+
+func (r *relation) has(col string, same func(a, b string) bool) bool {
+	return slices.ContainsFunc(r.columns, func(c string) bool { return same(c, col) })
+}
+
+// column resolves a column reference. A qualified reference names its
+// FROM entry; an unqualified one is bound to the entry that has the
+// column, searching the current scope before the enclosing ones. When
+// that cannot be decided, the reference is kept as raw SQL.
+func (p *parser) column(t token, qualifier, name string) nodes.Node {
+	if qualifier != "" {
+		if r := p.relation(qualifier); r != nil {
+			return nodes.NewAttribute(r.node, name)
+		}
+		return nodes.NewAttribute(nodes.NewTable(qualifier), name)
+	}
+	for s := p.scope; s != nil; s = s.parent {
+		var matches, unknown []*relation
+		for _, r := range s.relations {
+			switch {
+			case !r.known:
+				unknown = append(unknown, r)
+			case r.has(name, p.sameName):
+				matches = append(matches, r)
+			}
+		}
+		switch {
+		case len(matches) == 1:
+			return nodes.NewAttribute(matches[0].node, name)
+		case len(matches) > 1:
+			p.warn(t, "column %q is ambiguous; kept as raw SQL", name)
+			return nodes.NewSqlLiteral(nodes.RawSQL(t.text))
+		case len(unknown) == 1 && !s.opaque:
+			return nodes.NewAttribute(unknown[0].node, name)
+		case len(unknown) > 0 || s.opaque:
+			p.warn(t, "cannot tell which table column %q belongs to (use WithSchema); kept as raw SQL", name)
+			return nodes.NewSqlLiteral(nodes.RawSQL(t.text))
+		}
+	}
+	p.warn(t, "column %q is not in any FROM entry; kept as raw SQL", name)
+	return nodes.NewSqlLiteral(nodes.RawSQL(t.text))
+}
+
+// output reports whether name is a projection alias of the current query.
+func (p *parser) output(name string) bool {
+	return slices.ContainsFunc(p.scope.outputs, func(o string) bool { return p.sameName(o, name) })
+}
+
+// outputs returns the column names of a query's projections, and whether
+// they are all known.
+func (p *parser) outputs(projections []nodes.Node) ([]string, bool) {
+	var cols []string
+	for _, n := range projections {
+		switch n := n.(type) {
+		case *nodes.AliasNode:
+			cols = append(cols, n.Name)
+		case *nodes.Attribute:
+			cols = append(cols, n.Name)
+		case *nodes.StarNode:
+			for _, r := range p.scope.relations {
+				if n.Table != nil && !p.sameName(n.Table.Name, r.name) {
+					continue
+				}
+				if !r.known {
+					return nil, false
+				}
+				cols = append(cols, r.columns...)
+			}
+			if p.scope.opaque {
+				return nil, false
+			}
+		case *nodes.SqlLiteral:
+			// May expand to any number of columns.
+			return nil, false
+		default:
+			cols = append(cols, "")
+		}
+	}
+	if len(projections) == 0 {
+		return p.outputs([]nodes.Node{nodes.Star()})
+	}
+	return cols, true
+}
+
+// lastName returns the name a raw FROM entry can be referred to by: its
+// trailing identifier, as in "generate_series(1, 3) AS g" or
+// "public.users", or "" when it has none.
+func (p *parser) lastName(from, to int) string {
+	if to-from < 2 {
+		return ""
+	}
+	if t := p.toks[to-1]; p.isName(t) && !strings.EqualFold(t.text, "LATERAL") {
+		return p.name(t)
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+SELECT id, email FROM users WHERE active = TRUE
+
+SELECT 1
+
+select u.id, u.email from users as u where u.id = 1 and u.email like '%@example.com'
+
+SELECT DISTINCT status FROM orders ORDER BY status DESC NULLS LAST LIMIT 10 OFFSET 20
+
+SELECT id FROM users LIMIT 5
+
+SELECT DISTINCT ON (user_id) user_id, total FROM orders ORDER BY user_id, created_at DESC
+
+SELECT count(*) FILTER (WHERE total > 100) FROM orders
+
+SELECT id FROM users WHERE name IS DISTINCT FROM 'x' AND email ~ '^a' AND email !~ 'b$'
+
+SELECT coalesce(name, email), coalesce(name, email, 'none'), upper(name) || '!', concat(name, ' <', email, '>') FROM users
+
+SELECT id FROM users EXCEPT SELECT user_id FROM bans
+
+SELECT id FROM users INTERSECT ALL SELECT user_id FROM orders
+
+SELECT id FROM users FOR UPDATE SKIP LOCKED
+
+SELECT id FROM users FOR SHARE
+
+SELECT u.id, o.total FROM users u CROSS JOIN LATERAL (SELECT total FROM orders WHERE orders.user_id = u.id ORDER BY total DESC LIMIT 1) o
+
+SELECT u.id, o.total FROM users u LEFT JOIN LATERAL (SELECT total FROM orders WHERE orders.user_id = u.id) o ON o.total > 0
+
+WITH RECURSIVE tree (id, parent_id) AS (SELECT id, parent_id FROM categories WHERE parent_id IS NULL UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree t ON c.parent_id = t.id) SELECT id FROM tree
@@ -0,0 +1,19 @@
+INSERT INTO users (email, name) VALUES ('a@example.com', 'a'), ('b@example.com', 'b') RETURNING id
+
+INSERT INTO users (email) SELECT email FROM users WHERE active RETURNING *
+
+UPDATE users SET name = 'x', active = FALSE WHERE id = $1 RETURNING id, name
+
+UPDATE users AS u SET name = lower(u.name) WHERE u.id IN (SELECT user_id FROM orders)
+
+DELETE FROM users WHERE active = FALSE
+
+DELETE FROM orders o WHERE o.total < 0 RETURNING *
+
+INSERT INTO users (email, name) VALUES ('a', 'b') ON CONFLICT (email) DO UPDATE SET name = excluded.name WHERE users.active RETURNING id
+
+INSERT INTO users (email, name) VALUES ('a', 'b'), ('c', 'd') ON CONFLICT (email) DO NOTHING
+
+INSERT INTO users (id, email) SELECT user_id, status FROM orders ON CONFLICT (id, email) DO UPDATE SET email = excluded.email
+
+INSERT INTO users (email) VALUES ('a') ON CONFLICT DO NOTHING
@@ -0,0 +1,29 @@
+SELECT id, email FROM users WHERE active = TRUE
+
+SELECT "Id", "user]name" FROM "Users"
+
+SELECT DISTINCT status FROM orders ORDER BY status DESC LIMIT 10
+
+SELECT id FROM users ORDER BY id LIMIT 10 OFFSET 20
+
+SELECT id FROM users ORDER BY id OFFSET 20
+
+SELECT id FROM users LIMIT 5 OFFSET 10
+
+SELECT id FROM users UNION ALL SELECT user_id FROM orders ORDER BY id LIMIT 5
+
+SELECT id FROM users UNION SELECT user_id FROM orders LIMIT 5
+
+SELECT id FROM users WHERE id = 1 FOR UPDATE
+
+SELECT u.id FROM users u WHERE u.active FOR SHARE SKIP LOCKED
+
+SELECT s.id FROM (SELECT id FROM users) AS s FOR UPDATE
+
+SELECT upper(name) || '!' || email FROM users
+
+SELECT id FROM users WHERE email ~ '^a'
+
+WITH RECURSIVE tree (id, parent_id) AS (SELECT id, parent_id FROM categories WHERE parent_id IS NULL UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree t ON c.parent_id = t.id) SELECT id FROM tree
+
+SELECT id FROM users WHERE name IS DISTINCT FROM 'x' AND id IN (SELECT user_id FROM orders ORDER BY total DESC LIMIT 3)
@@ -0,0 +1,11 @@
+INSERT INTO users (email, name) VALUES (?, ?)
+
+UPDATE users SET name = ? WHERE id = ?
+
+DELETE FROM users WHERE id = ?
+
+INSERT INTO users (email) VALUES ('a') ON DUPLICATE KEY UPDATE email = VALUES(email)
+
+INSERT IGNORE INTO users (email) VALUES ('a')
+
+UPDATE users SET name = 'x' ORDER BY id LIMIT 1
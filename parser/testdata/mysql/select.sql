@@ -0,0 +1,19 @@
+SELECT `id`, email FROM users WHERE active = ? AND name = "bob"
+
+SELECT id FROM users WHERE name = 'a' || name = 'b' && active
+
+SELECT id FROM users WHERE email REGEXP '^a' AND name NOT RLIKE 'x'
+
+SELECT id FROM users ORDER BY id LIMIT 5, 10
+
+SELECT /*+ MAX_EXECUTION_TIME(1000) */ id FROM users # trailing comment
+
+SELECT id ^ 2, id | 4 & 2 FROM users
+
+SELECT u.id, o.total FROM users u LEFT JOIN orders o ON o.user_id = u.id WHERE o.total > ?
+
+SELECT id FROM users WHERE email LIKE 'a\_%'
+
+SELECT id FROM users FOR SHARE
+
+SELECT status, count(*) FROM orders GROUP BY status WITH ROLLUP
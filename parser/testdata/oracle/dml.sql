@@ -0,0 +1,17 @@
+INSERT INTO users (email, name) VALUES ($1, $2) RETURNING id
+
+INSERT INTO users (email, name) VALUES ('a@example.com', 'a'), ('b@example.com', 'b')
+
+INSERT INTO users (email, name) VALUES ('a@example.com', 'a'), ('b@example.com', 'b') RETURNING id
+
+UPDATE users SET name = 'x', active = FALSE WHERE id = $1 RETURNING id, name
+
+UPDATE users AS u SET name = lower(u.name) WHERE u.id IN (SELECT user_id FROM orders)
+
+DELETE FROM orders o WHERE o.total < 0 RETURNING *
+
+INSERT INTO users (email, name) VALUES ('a', 'b') ON CONFLICT (email) DO UPDATE SET name = excluded.name WHERE users.active
+
+INSERT INTO users (email, name) VALUES ('a', 'b'), ('c', 'd') ON CONFLICT (email) DO NOTHING
+
+INSERT INTO users (email) SELECT email FROM users ON CONFLICT (email) DO NOTHING
@@ -0,0 +1,13 @@
+SELECT status, count(*), count(DISTINCT user_id), sum(total), avg(total), min(total), max(total) FROM orders GROUP BY status HAVING count(*) > 1
+
+SELECT user_id, sum(total) FILTER (WHERE status = 'paid') AS paid FROM orders GROUP BY user_id
+
+SELECT status, user_id, count(*) FROM orders GROUP BY ROLLUP (status, user_id)
+
+SELECT status, user_id, count(*) FROM orders GROUP BY CUBE (status, user_id)
+
+SELECT status, user_id, count(*) FROM orders GROUP BY GROUPING SETS ((status), (user_id), ())
+
+SELECT status AS s, count(*) FROM orders GROUP BY s
+
+SELECT string_agg(status, ',') FROM orders
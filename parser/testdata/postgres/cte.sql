@@ -0,0 +1,13 @@
+WITH recent AS (SELECT id, user_id FROM orders WHERE total > 100) SELECT user_id, count(*) AS n FROM recent GROUP BY user_id ORDER BY n DESC
+
+WITH RECURSIVE tree (id, parent_id) AS (SELECT id, parent_id FROM categories WHERE parent_id IS NULL UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree t ON c.parent_id = t.id) SELECT id FROM tree
+
+WITH a AS (SELECT id FROM users), b AS (SELECT id FROM a) SELECT id FROM b
+
+SELECT s.id FROM (SELECT id, email FROM users WHERE active) AS s WHERE email LIKE 'a%'
+
+SELECT id, (SELECT max(total) FROM orders o WHERE o.user_id = users.id) AS top FROM users
+
+SELECT id FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id) AND NOT EXISTS (SELECT 1 FROM bans WHERE bans.user_id = users.id)
+
+SELECT id FROM users WHERE id IN (SELECT user_id FROM orders WHERE total > $1)
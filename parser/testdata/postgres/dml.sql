@@ -0,0 +1,23 @@
+INSERT INTO users (email, name) VALUES ($1, $2)
+
+INSERT INTO users (email, name) VALUES ('a@example.com', 'a'), ('b@example.com', DEFAULT) RETURNING id
+
+INSERT INTO users (email) SELECT email FROM users WHERE active
+
+INSERT INTO users (email, name) VALUES ('a', 'b') ON CONFLICT (email) DO UPDATE SET name = excluded.name WHERE users.active
+
+INSERT INTO users (email) VALUES ('a') ON CONFLICT DO NOTHING
+
+UPDATE users SET name = 'x', active = NOT active WHERE id = $1 RETURNING id, name
+
+UPDATE users AS u SET name = lower(u.name) WHERE u.id IN (SELECT user_id FROM orders)
+
+DELETE FROM users WHERE active = FALSE
+
+DELETE FROM orders o WHERE o.total < 0 RETURNING *
+
+INSERT INTO users DEFAULT VALUES
+
+UPDATE users SET name = o.status FROM orders o WHERE o.user_id = users.id
+
+DELETE FROM users USING bans WHERE bans.user_id = users.id
@@ -0,0 +1,15 @@
+SELECT CASE WHEN total > 100 THEN 'big' WHEN total > 10 THEN 'medium' ELSE 'small' END AS size FROM orders
+
+SELECT CASE status WHEN 'paid' THEN 1 ELSE 0 END FROM orders
+
+SELECT CAST(total AS numeric(10, 2)), total::int, created_at::timestamp with time zone FROM orders
+
+SELECT EXTRACT(YEAR FROM created_at), extract(dow from created_at) FROM orders
+
+SELECT total + 1, total * 2 - 3, (total + 1) * 2, total / 4, -5, 1.25, id & 3, id | 4, id << 1, ~id FROM orders
+
+SELECT coalesce(name, email, 'none'), lower(email), upper(name) || '!' FROM users
+
+SELECT TRUE, FALSE, NULL, 'it''s', CURRENT_TIMESTAMP, CURRENT_DATE FROM users
+
+SELECT id FROM users WHERE $2 > $1
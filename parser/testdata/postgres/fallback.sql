@@ -0,0 +1,23 @@
+SELECT id FROM users WHERE email ILIKE '%@example.com' AND active
+
+SELECT id, total % 2 FROM orders
+
+SELECT id FROM users WHERE id = ANY($1)
+
+SELECT id, data->>'name' FROM users
+
+SELECT g FROM generate_series(1, 3) AS g
+
+SELECT id FROM public.users
+
+SELECT array_agg(id ORDER BY id) FROM users
+
+SELECT id FROM users WHERE created_at > now() - INTERVAL '1 day'
+
+SELECT id FROM users WHERE email = E'a\'b'
+
+SELECT id FROM users FOR UPDATE NOWAIT
+
+SELECT id FROM users WHERE (id, email) = (1, 'a')
+
+SELECT x FROM users u, orders o
@@ -0,0 +1,17 @@
+SELECT u.id, o.total FROM users u JOIN orders o ON o.user_id = u.id
+
+SELECT u.id, o.total FROM users u LEFT OUTER JOIN orders o ON o.user_id = u.id AND o.total > 0 WHERE o.id IS NULL
+
+SELECT u.id, o.total FROM users u RIGHT JOIN orders o ON o.user_id = u.id FULL JOIN items i ON i.order_id = o.id
+
+SELECT email, total FROM users JOIN orders ON orders.user_id = users.id
+
+SELECT u.id, o.id FROM users u CROSS JOIN orders o
+
+SELECT u.id, o.id FROM users u, orders o WHERE o.user_id = u.id
+
+SELECT u.id, t.n FROM users u, LATERAL (SELECT count(*) AS n FROM orders o WHERE o.user_id = u.id) t
+
+SELECT id FROM users JOIN orders USING (id)
+
+SELECT u.id FROM users u NATURAL JOIN orders
@@ -0,0 +1,25 @@
+SELECT id, email FROM users WHERE active = $1
+
+select u.id, u.email from users as u where u.id = 1 and u.email like '%@example.com'
+
+SELECT DISTINCT status FROM orders ORDER BY status DESC NULLS LAST LIMIT 10 OFFSET 20
+
+SELECT DISTINCT ON (user_id) user_id, total FROM orders ORDER BY user_id, created_at DESC
+
+SELECT * FROM users WHERE (name = 'a' OR name = 'b') AND NOT active
+
+SELECT id FROM users WHERE name = 'a' OR name = 'b' AND active
+
+SELECT id FROM users WHERE id IN (1, 2, 3) AND email NOT IN ('x') AND id BETWEEN 1 AND 10 AND name IS NOT NULL
+
+SELECT id FROM users WHERE name IS DISTINCT FROM 'x' AND email ~ '^a' AND email !~ 'b$'
+
+SELECT "Id", "user name" FROM "Users"
+
+SELECT id FROM users FETCH FIRST 5 ROWS ONLY
+
+SELECT id FROM users ORDER BY 1 DESC
+
+SELECT id FROM users FOR UPDATE SKIP LOCKED
+
+/* report */ SELECT id FROM users;
@@ -0,0 +1,7 @@
+SELECT id FROM users UNION SELECT user_id FROM orders
+
+SELECT id FROM users UNION ALL SELECT user_id FROM orders ORDER BY id LIMIT 5
+
+SELECT id FROM users INTERSECT SELECT user_id FROM orders EXCEPT SELECT user_id FROM bans
+
+(SELECT id FROM users) EXCEPT ALL (SELECT user_id FROM bans)
@@ -0,0 +1,13 @@
+SELECT id, row_number() OVER (PARTITION BY user_id ORDER BY created_at DESC) FROM orders
+
+SELECT id, rank() OVER (ORDER BY total), dense_rank() OVER (ORDER BY total), ntile(4) OVER (ORDER BY total) FROM orders
+
+SELECT id, lag(total, 1) OVER w, lead(total) OVER w FROM orders WINDOW w AS (PARTITION BY user_id ORDER BY created_at)
+
+SELECT id, sum(total) OVER (PARTITION BY user_id ORDER BY created_at ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM orders
+
+SELECT id, avg(total) OVER (ORDER BY created_at RANGE BETWEEN 2 PRECEDING AND 2 FOLLOWING) FROM orders
+
+SELECT id, first_value(total) OVER (), cume_dist() OVER (ORDER BY total) FROM orders
+
+SELECT id, sum(total) OVER (w ORDER BY id) FROM orders WINDOW w AS (PARTITION BY user_id)
@@ -0,0 +1,7 @@
+INSERT INTO users (email) VALUES (?) ON CONFLICT (email) DO NOTHING
+
+INSERT OR REPLACE INTO users (email) VALUES ('a')
+
+UPDATE users SET name = ? WHERE id = ? RETURNING id
+
+DELETE FROM users WHERE id = ?
@@ -0,0 +1,13 @@
+SELECT [id], "email", `name` FROM users WHERE id = ?2 AND email = ?1
+
+SELECT id FROM users WHERE name || '!' = 'a!'
+
+SELECT id FROM users LIMIT 10 OFFSET 5
+
+SELECT id FROM users LIMIT 2, 3
+
+SELECT u.id, count(o.id) FROM users u LEFT JOIN orders o ON o.user_id = u.id GROUP BY u.id HAVING count(o.id) > ?
+
+WITH t AS (SELECT id FROM users) SELECT id FROM t
+
+SELECT id FROM users WHERE email GLOB '*@x'
@@ -0,0 +1,218 @@
+// Package audit provides a Transformer that stamps INSERT and UPDATE
+// statements with audit columns: when a row was created and last updated,
+// and by whom.
+//
+// By default every INSERT sets "created_at" and "updated_at" and every
+// UPDATE sets "updated_at", to the database's CURRENT_TIMESTAMP. With an
+// actor, "created_by" and "updated_by" are set too. Columns the statement
+// already sets are left alone.
+//
+// # Basic usage
+//
+//	a := audit.New(audit.WithActor(userID))
+//	m := managers.NewInsertManager(users).Columns(users.Col("name")).Values("alice")
+//	m.Use(a)
+//	// INSERT INTO "users" ("name", "created_at", "updated_at", "created_by", "updated_by")
+//	//   VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, $2, $3)
+//
+// # Timestamps from Go
+//
+// WithClock binds the timestamps as parameters instead, so they come from
+// the application's clock and can be fixed in tests:
+//
+//	a := audit.New(audit.WithClock(time.Now))
+//
+// # Restrict to specific tables
+//
+//	a := audit.New(audit.WithTables("users", "orders"))
+//
+// # Custom columns
+//
+// Pass an empty name to leave a column out:
+//
+//	a := audit.New(
+//	    audit.WithTimestampColumns("inserted_at", "modified_at"),
+//	    audit.WithActorColumns("", "modified_by"),
+//	)
+package audit
+
+import (
+	"slices"
+	"time"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+)
+
+// Audit is a Transformer that sets audit columns on INSERT and UPDATE
+// statements for every table (or a configured subset).
+type Audit struct {
+	plugins.BaseTransformer
+	CreatedAt string           // set on INSERT (default "created_at")
+	UpdatedAt string           // set on INSERT and UPDATE (default "updated_at")
+	CreatedBy string           // set on INSERT when Actor is set (default "created_by")
+	UpdatedBy string           // set on INSERT and UPDATE when Actor is set (default "updated_by")
+	Actor     any              // who is making the change; nil leaves the actor columns out
+	Now       func() time.Time // timestamp source; nil uses CURRENT_TIMESTAMP
+	tables    map[string]bool  // nil means apply to all tables
+}
+
+// Option configures an Audit transformer.
+type Option func(*Audit)
+
+// WithActor sets the value written to the created_by and updated_by
+// columns, typically the ID of the current user.
+func WithActor(actor any) Option {
+	return func(a *Audit) { a.Actor = actor }
+}
+
+// WithClock binds timestamps from now as parameters instead of using the
+// database's CURRENT_TIMESTAMP. It is called once per statement.
+func WithClock(now func() time.Time) Option {
+	return func(a *Audit) { a.Now = now }
+}
+
+// WithTimestampColumns sets the created and updated timestamp column
+// names. An empty name leaves that column out.
+func WithTimestampColumns(created, updated string) Option {
+	return func(a *Audit) { a.CreatedAt, a.UpdatedAt = created, updated }
+}
+
+// WithActorColumns sets the created-by and updated-by column names. An
+// empty name leaves that column out.
+func WithActorColumns(created, updated string) Option {
+	return func(a *Audit) { a.CreatedBy, a.UpdatedBy = created, updated }
+}
+
+// WithTables restricts the plugin to only the named tables.
+// By default, the plugin applies to every table.
+func WithTables(names ...string) Option {
+	return func(a *Audit) {
+		a.tables = make(map[string]bool, len(names))
+		for _, n := range names {
+			a.tables[n] = true
+		}
+	}
+}
+
+// New creates an Audit transformer with the given options.
+func New(opts ...Option) *Audit {
+	a := &Audit{
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		CreatedBy: "created_by",
+		UpdatedBy: "updated_by",
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// TransformInsert adds the created and updated columns to the column list
+// and every row, and the updated columns to an ON CONFLICT DO UPDATE.
+// INSERT ... SELECT statements are left unchanged.
+func (a *Audit) TransformInsert(stmt *nodes.InsertStatement) (*nodes.InsertStatement, error) {
+	ref, ok := plugins.ResolveTable(stmt.Into)
+	if !ok || !a.appliesTo(ref.Name) {
+		return stmt, nil
+	}
+	now := a.timestamp()
+	if stmt.Select == nil {
+		for _, s := range a.stamps(now, true) {
+			if slices.ContainsFunc(stmt.Columns, func(n nodes.Node) bool { return plugins.ColumnName(n) == s.column }) {
+				continue
+			}
+			stmt.Columns = append(stmt.Columns, nodes.NewAttribute(ref.Relation, s.column))
+			for i, row := range stmt.Values {
+				stmt.Values[i] = append(row, s.value)
+			}
+		}
+	}
+	if stmt.OnConflict != nil && stmt.OnConflict.Action == nodes.DoUpdate {
+		oc := *stmt.OnConflict
+		oc.Assignments = a.assign(ref.Relation, slices.Clip(oc.Assignments), now)
+		stmt.OnConflict = &oc
+	}
+	return stmt, nil
+}
+
+// TransformUpdate adds the updated columns to the SET list.
+func (a *Audit) TransformUpdate(stmt *nodes.UpdateStatement) (*nodes.UpdateStatement, error) {
+	ref, ok := plugins.ResolveTable(stmt.Table)
+	if !ok || !a.appliesTo(ref.Name) {
+		return stmt, nil
+	}
+	stmt.Assignments = a.assign(ref.Relation, stmt.Assignments, a.timestamp())
+	return stmt, nil
+}
+
+// stamp is an audit column and the value written to it.
+type stamp struct {
+	column string
+	value  nodes.Node
+}
+
+// stamps returns the audit columns to set, with their values. created
+// selects the INSERT columns as well as the UPDATE ones.
+func (a *Audit) stamps(now nodes.Node, created bool) []stamp {
+	var out []stamp
+	add := func(column string, value nodes.Node) {
+		if column != "" {
+			out = append(out, stamp{column, value})
+		}
+	}
+	if created {
+		add(a.CreatedAt, now)
+	}
+	add(a.UpdatedAt, now)
+	if a.Actor != nil {
+		if created {
+			add(a.CreatedBy, nodes.NewBindParam(a.Actor))
+		}
+		add(a.UpdatedBy, nodes.NewBindParam(a.Actor))
+	}
+	return out
+}
+
+// assign appends the updated columns not already assigned.
+func (a *Audit) assign(relation nodes.Node, assignments []*nodes.AssignmentNode, now nodes.Node) []*nodes.AssignmentNode {
+	for _, s := range a.stamps(now, false) {
+		if slices.ContainsFunc(assignments, func(as *nodes.AssignmentNode) bool { return plugins.ColumnName(as.Left) == s.column }) {
+			continue
+		}
+		assignments = append(assignments, &nodes.AssignmentNode{Left: nodes.NewAttribute(relation, s.column), Right: s.value})
+	}
+	return assignments
+}
+
+// timestamp returns the value for the timestamp columns of one statement.
+func (a *Audit) timestamp() nodes.Node {
+	if a.Now != nil {
+		return nodes.NewBindParam(a.Now())
+	}
+	return currentTimestamp{}
+}
+
+func (a *Audit) appliesTo(tableName string) bool {
+	if a.tables == nil {
+		return true
+	}
+	return a.tables[tableName]
+}
+
+// currentTimestamp renders the database's current time: CURRENT_TIMESTAMP,
+// or now64() in ClickHouse, which keeps sub-second precision.
+type currentTimestamp struct{}
+
+func (n currentTimestamp) Accept(v nodes.Visitor) string { return v.VisitExtension(n) }
+
+func (n currentTimestamp) RenderSQL(ctx nodes.RenderContext) {
+	if ctx.Dialect() == "ClickHouse" {
+		ctx.Write("now64()")
+		return
+	}
+	ctx.Write("CURRENT_TIMESTAMP")
+}
+
+func (n currentTimestamp) Label() string { return "CURRENT_TIMESTAMP" }
@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+var fixed = time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+func clock() time.Time { return fixed }
+
+func toSQL(t *testing.T, m interface {
+	ToSQL(nodes.Visitor) (string, []any, error)
+}, v nodes.Visitor) (string, []any) {
+	t.Helper()
+	sql, params, err := m.ToSQL(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return sql, params
+}
+
+func assertSQL(t *testing.T, got, expected string) {
+	t.Helper()
+	if got != expected {
+		t.Errorf("expected:\n  %s\ngot:\n  %s", expected, got)
+	}
+}
+
+// --- INSERT ---
+
+func TestInsertDefaultColumns(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).Columns(users.Col("name")).Values("alice").Values("bob")
+	m.Use(New())
+
+	sql, params := toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `INSERT INTO "users" ("name", "created_at", "updated_at") VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP), ($2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`)
+	if len(params) != 2 {
+		t.Errorf("expected 2 params, got %v", params)
+	}
+}
+
+func TestInsertWithActorAndClock(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).Columns(users.Col("name")).Values("alice")
+	m.Use(New(WithActor(int64(7)), WithClock(clock)))
+
+	sql, params := toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `INSERT INTO "users" ("name", "created_at", "updated_at", "created_by", "updated_by") VALUES ($1, $2, $3, $4, $5)`)
+	want := []any{"alice", fixed, fixed, int64(7), int64(7)}
+	if len(params) != len(want) {
+		t.Fatalf("expected %v, got %v", want, params)
+	}
+	for i := range want {
+		if params[i] != want[i] {
+			t.Errorf("param %d: expected %v, got %v", i+1, want[i], params[i])
+		}
+	}
+}
+
+func TestInsertLeavesCallerColumns(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).
+		Columns(users.Col("name"), users.Col("created_at")).
+		Values("alice", nodes.NewSqlLiteral("'2020-01-01'"))
+	m.Use(New())
+
+	sql, _ := toSQL(t, m, visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	assertSQL(t, sql, `INSERT INTO "users" ("name", "created_at", "updated_at") VALUES ('alice', '2020-01-01', CURRENT_TIMESTAMP)`)
+}
+
+func TestInsertOnConflictDoUpdate(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).Columns(users.Col("email")).Values("a@example.com")
+	m.OnConflict(users.Col("email")).DoUpdate(&nodes.AssignmentNode{Left: users.Col("email"), Right: nodes.Literal("b@example.com")})
+	m.Use(New(WithActor("svc"), WithActorColumns("", "modified_by")))
+
+	sql, _ := toSQL(t, m, visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	assertSQL(t, sql, `INSERT INTO "users" ("email", "created_at", "updated_at", "modified_by") VALUES ('a@example.com', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'svc') `+
+		`ON CONFLICT ("email") DO UPDATE SET "users"."email" = 'b@example.com', "users"."updated_at" = CURRENT_TIMESTAMP, "users"."modified_by" = 'svc'`)
+
+	// DO NOTHING has no assignments to stamp.
+	m = managers.NewInsertManager(users).Columns(users.Col("email")).Values("a@example.com")
+	m.OnConflict(users.Col("email")).DoNothing()
+	m.Use(New())
+	sql, _ = toSQL(t, m, visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	assertSQL(t, sql, `INSERT INTO "users" ("email", "created_at", "updated_at") VALUES ('a@example.com', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) ON CONFLICT ("email") DO NOTHING`)
+}
+
+func TestInsertFromSelectIsUnchanged(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	archive := nodes.NewTable("archive")
+	m := managers.NewInsertManager(archive).FromSelect(managers.NewSelectManager(users))
+	m.Use(New())
+	sql, _ := toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `INSERT INTO "archive" SELECT * FROM "users"`)
+}
+
+func TestClickHouseTimestamp(t *testing.T) {
+	t.Parallel()
+	events := nodes.NewTable("events")
+	m := managers.NewInsertManager(events).Columns(events.Col("name")).Values("signup")
+	m.Use(New(WithTimestampColumns("created_at", "")))
+	sql, _ := toSQL(t, m, visitors.NewClickHouseVisitor(visitors.WithoutParams()))
+	assertSQL(t, sql, "INSERT INTO `events` (`name`, `created_at`) VALUES ('signup', now64())")
+}
+
+// --- UPDATE ---
+
+func TestUpdateStampsUpdatedColumns(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewUpdateManager(users).Set(users.Col("name"), "alice").Where(users.Col("id").Eq(1))
+	m.Use(New(WithActor(int64(7)), WithClock(clock)))
+
+	sql, params := toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `UPDATE "users" SET "users"."name" = $1, "users"."updated_at" = $2, "users"."updated_by" = $3 WHERE "users"."id" = $4`)
+	if len(params) != 4 || params[1] != fixed || params[2] != int64(7) {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestUpdateLeavesCallerColumns(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewUpdateManager(users).Set(users.Col("updated_at"), nodes.NewSqlLiteral("NOW()"))
+	m.Use(New())
+	sql, _ := toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `UPDATE "users" SET "users"."updated_at" = NOW()`)
+}
+
+func TestWithTables(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	sessions := nodes.NewTable("sessions")
+	a := New(WithTables("users"))
+
+	m := managers.NewUpdateManager(sessions).Set(sessions.Col("token"), "x")
+	m.Use(a)
+	sql, _ := toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `UPDATE "sessions" SET "sessions"."token" = $1`)
+
+	m = managers.NewUpdateManager(users).Set(users.Col("name"), "x")
+	m.Use(a)
+	sql, _ = toSQL(t, m, visitors.NewPostgresVisitor())
+	assertSQL(t, sql, `UPDATE "users" SET "users"."name" = $1, "users"."updated_at" = CURRENT_TIMESTAMP`)
+}
+
+func TestImplementsTransformer(t *testing.T) {
+	t.Parallel()
+	var _ plugins.Transformer = New()
+}
@@ -0,0 +1,132 @@
+// Package optlock provides a Transformer for optimistic locking with a
+// version column.
+//
+// Each UPDATE is given the version the caller last read. The plugin adds
+// "lock_version = <expected>" to the WHERE clause and increments the
+// column in the SET list, so the UPDATE matches no rows when another
+// writer got there first. exec.Exec reports that as an error wrapping
+// ErrStale.
+//
+// # Basic usage
+//
+// Configure the plugin once and attach the expected version to each
+// statement with Expect:
+//
+//	lock := optlock.New()
+//	m := managers.NewUpdateManager(users).
+//	    Set(users.Col("name"), "alice").
+//	    Where(users.Col("id").Eq(1))
+//	m.Use(lock.Expect(u.LockVersion))
+//	// UPDATE "users" SET "users"."name" = $1, "users"."lock_version" = "users"."lock_version" + $2
+//	//   WHERE "users"."id" = $3 AND "users"."lock_version" = $4
+//
+//	_, err := exec.Exec(ctx, db, m)
+//	if errors.Is(err, optlock.ErrStale) {
+//	    // reload and retry, or report a conflict
+//	}
+//
+// # Custom columns
+//
+//	lock := optlock.New(
+//	    optlock.WithColumn("version"),
+//	    optlock.WithTableColumn("orders", "revision"),
+//	)
+package optlock
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+)
+
+// ErrStale is wrapped by the error returned when a locked UPDATE matched
+// no rows: the row was changed or deleted since its version was read.
+var ErrStale = errors.New("optlock: stale object")
+
+// Lock is a Transformer that applies an optimistic lock to UPDATE
+// statements. SELECT, INSERT and DELETE statements are left unchanged.
+type Lock struct {
+	plugins.BaseTransformer
+	Column   string            // version column name (default "lock_version")
+	Columns  map[string]string // per-table column overrides (table name → column name)
+	Expected any               // the version the row must still have; set with Expect
+}
+
+// Option configures a Lock transformer.
+type Option func(*Lock)
+
+// WithColumn sets the version column name. Default is "lock_version".
+func WithColumn(name string) Option {
+	return func(l *Lock) { l.Column = name }
+}
+
+// WithTableColumn sets a per-table column override.
+func WithTableColumn(table, column string) Option {
+	return func(l *Lock) {
+		if l.Columns == nil {
+			l.Columns = make(map[string]string)
+		}
+		l.Columns[table] = column
+	}
+}
+
+// New creates a Lock transformer with the given options. Use Expect to
+// get a copy for each statement.
+func New(opts ...Option) *Lock {
+	l := &Lock{Column: "lock_version"}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Expect returns a copy of l that requires the row to be at version. Pass
+// it to the UpdateManager's Use.
+func (l *Lock) Expect(version any) *Lock {
+	c := *l
+	c.Expected = version
+	return &c
+}
+
+// TransformUpdate adds the version condition and increments the version.
+// It fails when no version is expected or the statement sets the version
+// column itself.
+func (l *Lock) TransformUpdate(stmt *nodes.UpdateStatement) (*nodes.UpdateStatement, error) {
+	ref, ok := plugins.ResolveTable(stmt.Table)
+	if !ok {
+		return nil, fmt.Errorf("optlock: cannot lock an UPDATE of %T", stmt.Table)
+	}
+	col := l.columnFor(ref.Name)
+	if l.Expected == nil {
+		return nil, fmt.Errorf("optlock: no expected %s.%s; use Expect", ref.Name, col)
+	}
+	if slices.ContainsFunc(stmt.Assignments, func(a *nodes.AssignmentNode) bool { return plugins.ColumnName(a.Left) == col }) {
+		return nil, fmt.Errorf("optlock: cannot set %s.%s; it is incremented by the lock", ref.Name, col)
+	}
+	version := nodes.NewAttribute(ref.Relation, col)
+	stmt.Assignments = append(stmt.Assignments, &nodes.AssignmentNode{Left: version, Right: version.Plus(1)})
+	stmt.Wheres = append(stmt.Wheres, version.Eq(l.Expected))
+	return stmt, nil
+}
+
+// CheckRowsAffected implements plugins.RowsChecker. An UPDATE that matched
+// no rows fails with an error wrapping ErrStale; other statements are not
+// locked and never fail.
+func (l *Lock) CheckRowsAffected(stmt nodes.Node, n int64) error {
+	if _, ok := stmt.(*nodes.UpdateStatement); ok && n == 0 {
+		return fmt.Errorf("%w: no row at version %v", ErrStale, l.Expected)
+	}
+	return nil
+}
+
+// columnFor returns the column name to use for the given table.
+// It checks Columns for a per-table override, falling back to Column.
+func (l *Lock) columnFor(tableName string) string {
+	if col, ok := l.Columns[tableName]; ok {
+		return col
+	}
+	return l.Column
+}
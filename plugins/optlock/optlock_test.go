@@ -0,0 +1,126 @@
+package optlock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+func assertSQL(t *testing.T, got, expected string) {
+	t.Helper()
+	if got != expected {
+		t.Errorf("expected:\n  %s\ngot:\n  %s", expected, got)
+	}
+}
+
+func TestUpdateChecksAndIncrementsVersion(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewUpdateManager(users).Set(users.Col("name"), "alice").Where(users.Col("id").Eq(1))
+	m.Use(New().Expect(int64(3)))
+
+	sql, params, err := m.ToSQL(visitors.NewPostgresVisitor())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `UPDATE "users" SET "users"."name" = $1, "users"."lock_version" = "users"."lock_version" + $2 `+
+		`WHERE "users"."id" = $3 AND "users"."lock_version" = $4`)
+	if len(params) != 4 || params[1] != 1 || params[3] != int64(3) {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestCustomColumns(t *testing.T) {
+	t.Parallel()
+	o := nodes.NewTable("orders").Alias("o")
+	lock := New(WithColumn("version"), WithTableColumn("orders", "revision"))
+	m := managers.NewUpdateManager(o).Set(o.Col("status"), "paid")
+	m.Use(lock.Expect(7))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `UPDATE "orders" AS "o" SET "o"."status" = 'paid', "o"."revision" = "o"."revision" + 1 WHERE "o"."revision" = 7`)
+
+	users := nodes.NewTable("users")
+	m = managers.NewUpdateManager(users).Set(users.Col("name"), "bob")
+	m.Use(lock.Expect(2))
+	sql, _, err = m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `UPDATE "users" SET "users"."name" = 'bob', "users"."version" = "users"."version" + 1 WHERE "users"."version" = 2`)
+}
+
+func TestExpectCopiesLock(t *testing.T) {
+	t.Parallel()
+	lock := New()
+	a, b := lock.Expect(1), lock.Expect(2)
+	if lock.Expected != nil || a.Expected != 1 || b.Expected != 2 {
+		t.Errorf("expected independent copies, got %v, %v, %v", lock.Expected, a.Expected, b.Expected)
+	}
+}
+
+func TestUpdateErrors(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+
+	m := managers.NewUpdateManager(users).Set(users.Col("name"), "alice")
+	m.Use(New())
+	_, _, err := m.ToSQL(visitors.NewPostgresVisitor())
+	if err == nil || err.Error() != "optlock: no expected users.lock_version; use Expect" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	m = managers.NewUpdateManager(users).Set(users.Col("lock_version"), 9)
+	m.Use(New().Expect(3))
+	_, _, err = m.ToSQL(visitors.NewPostgresVisitor())
+	if err == nil || err.Error() != "optlock: cannot set users.lock_version; it is incremented by the lock" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestOtherStatementsAreUnchanged(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewDeleteManager(users).Where(users.Col("id").Eq(1))
+	m.Use(New().Expect(3))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `DELETE FROM "users" WHERE "users"."id" = $1`)
+}
+
+func TestCheckRowsAffected(t *testing.T) {
+	t.Parallel()
+	lock := New().Expect(3)
+	update := &nodes.UpdateStatement{Table: nodes.NewTable("users")}
+	if err := lock.CheckRowsAffected(update, 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := lock.CheckRowsAffected(update, 0)
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("expected ErrStale, got %v", err)
+	}
+	if err.Error() != "optlock: stale object: no row at version 3" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Only an UPDATE is locked.
+	for _, stmt := range []nodes.Node{&nodes.DeleteStatement{}, &nodes.InsertStatement{}} {
+		if err := lock.CheckRowsAffected(stmt, 0); err != nil {
+			t.Errorf("unexpected error for %T: %v", stmt, err)
+		}
+	}
+}
+
+func TestImplementsInterfaces(t *testing.T) {
+	t.Parallel()
+	var _ plugins.Transformer = New()
+	var _ plugins.RowsChecker = New()
+}
@@ -26,6 +26,30 @@ func CollectTables(core *nodes.SelectCore) []TableRef {
 	return refs
 }
 
+// ResolveTable returns the TableRef of a table or aliased table, such as
+// the target of an INSERT, UPDATE or DELETE. Unlike CollectTables, it does
+// not treat an aliased subquery as a table.
+func ResolveTable(n nodes.Node) (TableRef, bool) {
+	switch r := n.(type) {
+	case *nodes.Table:
+		return TableRef{Relation: r, Name: r.Name}, true
+	case *nodes.TableAlias:
+		if tbl, ok := r.Relation.(*nodes.Table); ok {
+			return TableRef{Relation: r, Name: tbl.Name}, true
+		}
+	}
+	return TableRef{}, false
+}
+
+// ColumnName returns the name of the column n references, as an INSERT
+// column or SET target, or "" when n is not a column.
+func ColumnName(n nodes.Node) string {
+	if a, ok := n.(*nodes.Attribute); ok {
+		return a.Name
+	}
+	return ""
+}
+
 func extractTableRef(n nodes.Node) (TableRef, bool) {
 	switch r := n.(type) {
 	case *nodes.Table:
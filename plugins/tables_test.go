@@ -89,3 +89,32 @@ func TestCollectTablesNilFrom(t *testing.T) {
 		t.Errorf("expected 0 refs, got %d", len(refs))
 	}
 }
+
+func TestResolveTable(t *testing.T) {
+	users := nodes.NewTable("users")
+	ref, ok := ResolveTable(users)
+	if !ok || ref.Name != "users" || ref.Relation != users {
+		t.Errorf("expected users, got %+v", ref)
+	}
+
+	u := users.Alias("u")
+	ref, ok = ResolveTable(u)
+	if !ok || ref.Name != "users" || ref.Relation != u {
+		t.Errorf("expected users aliased as u, got %+v", ref)
+	}
+
+	sub := &nodes.TableAlias{Relation: &nodes.SelectCore{From: users}, AliasName: "s"}
+	if _, ok := ResolveTable(sub); ok {
+		t.Error("expected an aliased subquery not to resolve")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	users := nodes.NewTable("users")
+	if got := ColumnName(users.Col("email")); got != "email" {
+		t.Errorf("expected email, got %q", got)
+	}
+	if got := ColumnName(nodes.Literal(1)); got != "" {
+		t.Errorf("expected no column name, got %q", got)
+	}
+}
@@ -0,0 +1,414 @@
+// Package tenant provides a Transformer that confines queries to a single
+// tenant of a shared-schema multi-tenant database.
+//
+// Every table is assumed to carry a "tenant_id" column unless it is exempt.
+// The plugin adds "tenant_id = <id>" to SELECT, UPDATE and DELETE
+// statements, sets or checks the column on INSERT rows, and refuses to
+// move rows between tenants.
+//
+// # Basic usage
+//
+//	t := tenant.New(42)
+//	query := managers.NewSelectManager(users)
+//	query.Use(t)
+//	// SELECT * FROM "users" WHERE "users"."tenant_id" = $1   -- [42]
+//
+// # Joins and aliases
+//
+// A joined table gets its condition in the join's ON clause, so LEFT
+// joins keep their meaning, and aliased tables are qualified by their
+// alias:
+//
+//	// SELECT * FROM "users" AS "u" LEFT OUTER JOIN "posts"
+//	//   ON "posts"."user_id" = "u"."id" AND "posts"."tenant_id" = $1
+//	//   WHERE "u"."tenant_id" = $2
+//
+// Derived tables, set operations and CTEs in the FROM clause are scoped
+// too, as are subqueries inside expressions such as IN (SELECT ...) and
+// EXISTS, wherever they appear in the statement. Raw SQL is not.
+//
+// # Writes
+//
+// INSERT rows without the tenant column get it added; rows that set it
+// must set it to the tenant's ID. UPDATE statements and ON CONFLICT DO
+// UPDATE clauses may not assign the tenant column. Violations fail with
+// an error wrapping ErrCrossTenant.
+//
+// # Per-table columns and exempt tables
+//
+//	t := tenant.New(42,
+//	    tenant.WithTableColumn("accounts", "org_id"),
+//	    tenant.WithExemptTables("countries", "currencies"),
+//	)
+//
+// # REPL usage
+//
+//	gosbee> plugin tenant 42
+//	gosbee> plugin tenant 42 column org_id
+//	gosbee> plugin tenant 42 exempt countries currencies
+//	gosbee> plugin off tenant
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+)
+
+// ErrCrossTenant is wrapped by the errors returned for statements that
+// would write another tenant's rows or move rows between tenants.
+var ErrCrossTenant = errors.New("tenant: cross-tenant write")
+
+// Tenant is a Transformer that scopes every statement to one tenant.
+type Tenant struct {
+	plugins.BaseTransformer
+	ID      any               // the tenant's ID, bound as a parameter
+	Column  string            // tenant column name (default "tenant_id")
+	Columns map[string]string // per-table column overrides (table name → column name)
+	exempt  map[string]bool   // tables shared by all tenants
+}
+
+// Option configures a Tenant transformer.
+type Option func(*Tenant)
+
+// WithColumn sets the tenant column name. Default is "tenant_id".
+func WithColumn(name string) Option {
+	return func(t *Tenant) { t.Column = name }
+}
+
+// WithTableColumn sets a per-table column override.
+func WithTableColumn(table, column string) Option {
+	return func(t *Tenant) {
+		if t.Columns == nil {
+			t.Columns = make(map[string]string)
+		}
+		t.Columns[table] = column
+	}
+}
+
+// WithExemptTables leaves the named tables unscoped, for reference data
+// shared by all tenants.
+func WithExemptTables(names ...string) Option {
+	return func(t *Tenant) {
+		if t.exempt == nil {
+			t.exempt = make(map[string]bool, len(names))
+		}
+		for _, n := range names {
+			t.exempt[n] = true
+		}
+	}
+}
+
+// New creates a Tenant transformer for the tenant with the given ID.
+func New(id any, opts ...Option) *Tenant {
+	t := &Tenant{ID: id, Column: "tenant_id"}
+	for _, o := range opts {
+		o(t)
+	}
+	return t
+}
+
+// TransformSelect adds the tenant condition for every scoped table in the
+// FROM clause and joins, including those of derived tables, CTEs and
+// subqueries.
+func (t *Tenant) TransformSelect(core *nodes.SelectCore) (*nodes.SelectCore, error) {
+	return t.scope(core), nil
+}
+
+// TransformInsert sets the tenant column on every row, or checks it when
+// the rows already set it.
+func (t *Tenant) TransformInsert(stmt *nodes.InsertStatement) (*nodes.InsertStatement, error) {
+	stmt.Values = scoped(t, stmt.Values)
+	stmt.OnConflict = scoped(t, stmt.OnConflict)
+	stmt.Returning = scoped(t, stmt.Returning)
+	ref, ok := plugins.ResolveTable(stmt.Into)
+	if !ok || !t.appliesTo(ref.Name) {
+		if stmt.Select != nil {
+			stmt.Select = t.query(stmt.Select)
+		}
+		return stmt, nil
+	}
+	col := t.columnFor(ref.Name)
+	if stmt.OnConflict != nil {
+		if err := t.checkAssignments(ref.Name, col, stmt.OnConflict.Assignments); err != nil {
+			return nil, err
+		}
+		if stmt.OnConflict.Action == nodes.DoUpdate {
+			// Only update a conflicting row that belongs to this tenant.
+			oc := *stmt.OnConflict
+			oc.Wheres = append(slices.Clip(oc.Wheres), t.condition(ref.Relation, col))
+			stmt.OnConflict = &oc
+		}
+	}
+
+	idx := slices.IndexFunc(stmt.Columns, func(n nodes.Node) bool { return plugins.ColumnName(n) == col })
+	if stmt.Select != nil {
+		return nil, fmt.Errorf("%w: cannot check %s.%s of INSERT ... SELECT", ErrCrossTenant, ref.Name, col)
+	}
+	if idx < 0 {
+		stmt.Columns = append(stmt.Columns, nodes.NewAttribute(ref.Relation, col))
+		for i, row := range stmt.Values {
+			stmt.Values[i] = append(row, nodes.Literal(t.ID))
+		}
+		return stmt, nil
+	}
+	for _, row := range stmt.Values {
+		if idx >= len(row) {
+			continue
+		}
+		v, ok := value(row[idx])
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot check %s.%s set to %T", ErrCrossTenant, ref.Name, col, row[idx])
+		}
+		if !t.matches(v) {
+			return nil, fmt.Errorf("%w: %s.%s is %v, not %v", ErrCrossTenant, ref.Name, col, v, t.ID)
+		}
+	}
+	return stmt, nil
+}
+
+// TransformUpdate adds the tenant condition and rejects assignments to the
+// tenant column.
+func (t *Tenant) TransformUpdate(stmt *nodes.UpdateStatement) (*nodes.UpdateStatement, error) {
+	stmt.Assignments = scoped(t, stmt.Assignments)
+	stmt.Wheres = scoped(t, stmt.Wheres)
+	stmt.Returning = scoped(t, stmt.Returning)
+	ref, ok := plugins.ResolveTable(stmt.Table)
+	if !ok || !t.appliesTo(ref.Name) {
+		return stmt, nil
+	}
+	col := t.columnFor(ref.Name)
+	if err := t.checkAssignments(ref.Name, col, stmt.Assignments); err != nil {
+		return nil, err
+	}
+	stmt.Wheres = append(stmt.Wheres, t.condition(ref.Relation, col))
+	return stmt, nil
+}
+
+// TransformDelete adds the tenant condition.
+func (t *Tenant) TransformDelete(stmt *nodes.DeleteStatement) (*nodes.DeleteStatement, error) {
+	stmt.Wheres = scoped(t, stmt.Wheres)
+	stmt.Returning = scoped(t, stmt.Returning)
+	ref, ok := plugins.ResolveTable(stmt.From)
+	if !ok || !t.appliesTo(ref.Name) {
+		return stmt, nil
+	}
+	stmt.Wheres = append(stmt.Wheres, t.condition(ref.Relation, t.columnFor(ref.Name)))
+	return stmt, nil
+}
+
+// scope returns a copy of core with the tenant conditions added. Nodes
+// shared with the caller's query are copied rather than modified.
+func (t *Tenant) scope(core *nodes.SelectCore) *nodes.SelectCore {
+	c := *core
+	c.Projections = scoped(t, core.Projections)
+	c.Wheres = slices.Clone(scoped(t, core.Wheres))
+	c.Groups = scoped(t, core.Groups)
+	c.Havings = scoped(t, core.Havings)
+	c.Windows = scoped(t, core.Windows)
+	c.Qualifies = scoped(t, core.Qualifies)
+	c.Orders = scoped(t, core.Orders)
+
+	var cond nodes.Node
+	c.From, cond = t.relation(core.From)
+	if cond != nil {
+		c.Wheres = append(c.Wheres, cond)
+	}
+
+	c.Joins = make([]*nodes.JoinNode, len(core.Joins))
+	for i, j := range core.Joins {
+		nj := *j
+		nj.On = scoped(t, j.On)
+		nj.Right, cond = t.relation(j.Right)
+		switch {
+		case cond == nil:
+		case nj.On == nil:
+			c.Wheres = append(c.Wheres, cond)
+		default:
+			on := nj.On
+			if _, ok := on.(*nodes.OrNode); ok {
+				on = nodes.NewGroupingNode(on)
+			}
+			nj.On = nodes.NewAndNode(on, cond)
+		}
+		c.Joins[i] = &nj
+	}
+
+	if len(core.CTEs) > 0 {
+		c.CTEs = make([]*nodes.CTENode, len(core.CTEs))
+		for i, cte := range core.CTEs {
+			nc := *cte
+			nc.Query = t.query(cte.Query)
+			c.CTEs[i] = &nc
+		}
+	}
+	return &c
+}
+
+// relation scopes a FROM or JOIN relation. It returns the relation to use
+// and, for a scoped table, the condition that confines it to the tenant.
+func (t *Tenant) relation(n nodes.Node) (nodes.Node, nodes.Node) {
+	if ref, ok := plugins.ResolveTable(n); ok {
+		if t.appliesTo(ref.Name) {
+			return n, t.condition(ref.Relation, t.columnFor(ref.Name))
+		}
+		return n, nil
+	}
+	if a, ok := n.(*nodes.TableAlias); ok {
+		return &nodes.TableAlias{Relation: t.query(a.Relation), AliasName: a.AliasName}, nil
+	}
+	return t.query(n), nil
+}
+
+// query scopes a derived table or CTE body.
+func (t *Tenant) query(n nodes.Node) nodes.Node {
+	switch q := n.(type) {
+	case *nodes.SelectCore:
+		return t.scope(q)
+	case *nodes.SetOperationNode:
+		s := *q
+		s.Left, s.Right = t.query(q.Left), t.query(q.Right)
+		return &s
+	}
+	return n
+}
+
+// scoped returns v with every subquery inside it scoped. Nodes on the path
+// to a subquery are copied; the rest stay shared with the caller's tree.
+func scoped[T any](t *Tenant, v T) T {
+	if out, changed := t.subqueries(reflect.ValueOf(&v).Elem(), make(map[uintptr]bool)); changed {
+		return out.Interface().(T)
+	}
+	return v
+}
+
+var nodeType = reflect.TypeFor[nodes.Node]()
+
+// subqueries walks the exported fields of v and scopes every SELECT, set
+// operation and select manager it finds. It reports whether anything was
+// scoped; if so, the returned value is a copy of v.
+func (t *Tenant) subqueries(v reflect.Value, visited map[uintptr]bool) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || !v.Elem().Type().Implements(nodeType) {
+			return v, false
+		}
+		var n nodes.Node
+		if m, ok := v.Interface().(*managers.SelectManager); ok {
+			n = t.scope(m.Core)
+		} else if e, changed := t.subqueries(v.Elem(), visited); changed {
+			n = e.Interface().(nodes.Node)
+		} else {
+			return v, false
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(reflect.ValueOf(n))
+		return out, true
+	case reflect.Pointer:
+		if v.IsNil() || visited[v.Pointer()] {
+			return v, false
+		}
+		switch q := v.Interface().(type) {
+		case *nodes.SelectCore, *nodes.SetOperationNode:
+			return reflect.ValueOf(t.query(q.(nodes.Node))), true
+		}
+		if v.Elem().Kind() != reflect.Struct {
+			return v, false
+		}
+		visited[v.Pointer()] = true
+		defer delete(visited, v.Pointer())
+		e, changed := t.subqueries(v.Elem(), visited)
+		if !changed {
+			return v, false
+		}
+		out := reflect.New(e.Type())
+		out.Elem().Set(e)
+		return out, true
+	case reflect.Struct:
+		var out reflect.Value
+		for i := range v.NumField() {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			f, changed := t.subqueries(v.Field(i), visited)
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				out = reflect.New(v.Type()).Elem()
+				out.Set(v)
+			}
+			out.Field(i).Set(f)
+		}
+		if out.IsValid() {
+			return out, true
+		}
+	case reflect.Slice:
+		var out reflect.Value
+		for i := range v.Len() {
+			e, changed := t.subqueries(v.Index(i), visited)
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				out = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+				reflect.Copy(out, v)
+			}
+			out.Index(i).Set(e)
+		}
+		if out.IsValid() {
+			return out, true
+		}
+	}
+	return v, false
+}
+
+// condition returns "relation.column = ID".
+func (t *Tenant) condition(relation nodes.Node, column string) nodes.Node {
+	return nodes.NewAttribute(relation, column).Eq(t.ID)
+}
+
+// checkAssignments rejects a SET of the tenant column.
+func (t *Tenant) checkAssignments(table, column string, assignments []*nodes.AssignmentNode) error {
+	for _, a := range assignments {
+		if plugins.ColumnName(a.Left) == column {
+			return fmt.Errorf("%w: cannot update %s.%s", ErrCrossTenant, table, column)
+		}
+	}
+	return nil
+}
+
+// matches reports whether v is the tenant's ID. Values are compared by
+// their formatted form, so int 42 matches int64 42.
+func (t *Tenant) matches(v any) bool {
+	return fmt.Sprint(v) == fmt.Sprint(t.ID)
+}
+
+func (t *Tenant) appliesTo(tableName string) bool {
+	return !t.exempt[tableName]
+}
+
+// columnFor returns the column name to use for the given table.
+// It checks Columns for a per-table override, falling back to Column.
+func (t *Tenant) columnFor(tableName string) string {
+	if col, ok := t.Columns[tableName]; ok {
+		return col
+	}
+	return t.Column
+}
+
+// value returns the Go value of a literal or bind parameter.
+func value(n nodes.Node) (any, bool) {
+	switch v := n.(type) {
+	case *nodes.LiteralNode:
+		return v.Value, true
+	case *nodes.BindParamNode:
+		return v.Value, true
+	}
+	return nil, false
+}
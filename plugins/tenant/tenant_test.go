@@ -0,0 +1,331 @@
+package tenant
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/plugins"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+func toSQL(t *testing.T, n nodes.Node) string {
+	t.Helper()
+	return n.Accept(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+}
+
+func assertSQL(t *testing.T, got, expected string) {
+	t.Helper()
+	if got != expected {
+		t.Errorf("expected:\n  %s\ngot:\n  %s", expected, got)
+	}
+}
+
+func assertCrossTenant(t *testing.T, err error, msg string) {
+	t.Helper()
+	if !errors.Is(err, ErrCrossTenant) {
+		t.Fatalf("expected ErrCrossTenant, got %v", err)
+	}
+	if err.Error() != msg {
+		t.Errorf("expected error %q, got %q", msg, err.Error())
+	}
+}
+
+// --- SELECT ---
+
+func TestSelectAddsCondition(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	q := managers.NewSelectManager(users).Where(users.Col("active").Eq(true))
+	q.Use(New(42))
+
+	sql, params, err := q.ToSQL(visitors.NewPostgresVisitor())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `SELECT * FROM "users" WHERE "users"."active" = $1 AND "users"."tenant_id" = $2`)
+	if len(params) != 2 || params[1] != 42 {
+		t.Errorf("expected tenant ID as the second parameter, got %v", params)
+	}
+}
+
+func TestSelectJoinsAndAliases(t *testing.T) {
+	t.Parallel()
+	u := nodes.NewTable("users").Alias("u")
+	posts := nodes.NewTable("posts")
+	countries := nodes.NewTable("countries")
+	core := &nodes.SelectCore{
+		From: u,
+		Joins: []*nodes.JoinNode{
+			{Type: nodes.LeftOuterJoin, Right: posts, On: posts.Col("user_id").Eq(u.Col("id"))},
+			{Type: nodes.InnerJoin, Right: countries, On: countries.Col("code").Eq(u.Col("country"))},
+		},
+	}
+
+	result, err := New(42, WithExemptTables("countries")).TransformSelect(core)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, toSQL(t, result), `SELECT * FROM "users" AS "u" `+
+		`LEFT OUTER JOIN "posts" ON "posts"."user_id" = "u"."id" AND "posts"."tenant_id" = 42 `+
+		`INNER JOIN "countries" ON "countries"."code" = "u"."country" `+
+		`WHERE "u"."tenant_id" = 42`)
+
+	// The original query is not modified.
+	assertSQL(t, toSQL(t, core), `SELECT * FROM "users" AS "u" `+
+		`LEFT OUTER JOIN "posts" ON "posts"."user_id" = "u"."id" `+
+		`INNER JOIN "countries" ON "countries"."code" = "u"."country"`)
+}
+
+func TestSelectJoinWithoutOn(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	teams := nodes.NewTable("teams")
+	core := &nodes.SelectCore{
+		From:  users,
+		Joins: []*nodes.JoinNode{{Type: nodes.CrossJoin, Right: teams}},
+	}
+	result, _ := New(7).TransformSelect(core)
+	assertSQL(t, toSQL(t, result), `SELECT * FROM "users" CROSS JOIN "teams" WHERE "users"."tenant_id" = 7 AND "teams"."tenant_id" = 7`)
+}
+
+func TestSelectDerivedTablesAndCTEs(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	orders := nodes.NewTable("orders")
+	recent := nodes.NewTable("recent")
+	derived := &nodes.TableAlias{
+		Relation:  &nodes.SelectCore{From: orders, Projections: []nodes.Node{orders.Col("user_id")}},
+		AliasName: "o",
+	}
+	core := &nodes.SelectCore{
+		From: recent,
+		Joins: []*nodes.JoinNode{
+			{Type: nodes.InnerJoin, Right: derived, On: nodes.NewTable("o").Col("user_id").Eq(recent.Col("user_id"))},
+		},
+		CTEs: []*nodes.CTENode{{Name: "recent", Query: &nodes.SelectCore{From: users}}},
+	}
+
+	result, _ := New(1, WithExemptTables("recent")).TransformSelect(core)
+	assertSQL(t, toSQL(t, result), `WITH "recent" AS (SELECT * FROM "users" WHERE "users"."tenant_id" = 1) `+
+		`SELECT * FROM "recent" INNER JOIN (SELECT "orders"."user_id" FROM "orders" WHERE "orders"."tenant_id" = 1) AS "o" `+
+		`ON "o"."user_id" = "recent"."user_id"`)
+}
+
+func TestSelectSubqueriesInExpressions(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	orders := nodes.NewTable("orders")
+	paid := managers.NewSelectManager(orders).Select(orders.Col("user_id")).Where(orders.Col("paid").Eq(true))
+	core := &nodes.SelectCore{
+		From: users,
+		Projections: []nodes.Node{
+			users.Col("id"),
+			nodes.NewGroupingNode(&nodes.SelectCore{From: orders, Projections: []nodes.Node{nodes.Count(nil)}, Wheres: []nodes.Node{orders.Col("user_id").Eq(users.Col("id"))}}),
+		},
+		Wheres:  []nodes.Node{users.Col("id").In(paid)},
+		Groups:  []nodes.Node{users.Col("id")},
+		Havings: []nodes.Node{nodes.Exists(&nodes.SelectCore{From: orders})},
+	}
+
+	result, _ := New(42).TransformSelect(core)
+	assertSQL(t, toSQL(t, result), `SELECT "users"."id", (SELECT COUNT(*) FROM "orders" WHERE "orders"."user_id" = "users"."id" AND "orders"."tenant_id" = 42) `+
+		`FROM "users" WHERE "users"."id" IN (SELECT "orders"."user_id" FROM "orders" WHERE "orders"."paid" = TRUE AND "orders"."tenant_id" = 42) `+
+		`AND "users"."tenant_id" = 42 GROUP BY "users"."id" HAVING EXISTS (SELECT * FROM "orders" WHERE "orders"."tenant_id" = 42)`)
+
+	// The original query and subqueries are not modified.
+	assertSQL(t, toSQL(t, core), `SELECT "users"."id", (SELECT COUNT(*) FROM "orders" WHERE "orders"."user_id" = "users"."id") `+
+		`FROM "users" WHERE "users"."id" IN (SELECT "orders"."user_id" FROM "orders" WHERE "orders"."paid" = TRUE) `+
+		`GROUP BY "users"."id" HAVING EXISTS (SELECT * FROM "orders")`)
+}
+
+func TestSelectSubqueryInJoinOn(t *testing.T) {
+	t.Parallel()
+	countries := nodes.NewTable("countries")
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From: countries,
+		Joins: []*nodes.JoinNode{{
+			Type:  nodes.InnerJoin,
+			Right: nodes.NewTable("regions"),
+			On:    nodes.Exists(&nodes.SelectCore{From: users, Wheres: []nodes.Node{users.Col("country").Eq(countries.Col("code"))}}),
+		}},
+	}
+	result, _ := New(42, WithExemptTables("countries", "regions")).TransformSelect(core)
+	assertSQL(t, toSQL(t, result), `SELECT * FROM "countries" INNER JOIN "regions" ON EXISTS (SELECT * FROM "users" `+
+		`WHERE "users"."country" = "countries"."code" AND "users"."tenant_id" = 42)`)
+}
+
+func TestPerTableColumn(t *testing.T) {
+	t.Parallel()
+	accounts := nodes.NewTable("accounts")
+	core := &nodes.SelectCore{From: accounts}
+	result, _ := New("acme", WithTableColumn("accounts", "org_id"), WithColumn("tid")).TransformSelect(core)
+	assertSQL(t, toSQL(t, result), `SELECT * FROM "accounts" WHERE "accounts"."org_id" = 'acme'`)
+
+	users := nodes.NewTable("users")
+	result, _ = New("acme", WithTableColumn("accounts", "org_id"), WithColumn("tid")).TransformSelect(&nodes.SelectCore{From: users})
+	assertSQL(t, toSQL(t, result), `SELECT * FROM "users" WHERE "users"."tid" = 'acme'`)
+}
+
+// --- INSERT ---
+
+func TestInsertSetsTenantColumn(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).
+		Columns(users.Col("name")).
+		Values("alice").
+		Values("bob")
+	m.Use(New(42))
+
+	sql, params, err := m.ToSQL(visitors.NewPostgresVisitor())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `INSERT INTO "users" ("name", "tenant_id") VALUES ($1, $2), ($3, $4)`)
+	if len(params) != 4 {
+		t.Errorf("expected 4 params, got %v", params)
+	}
+}
+
+func TestInsertChecksTenantColumn(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).
+		Columns(users.Col("name"), users.Col("tenant_id")).
+		Values("alice", int64(42))
+	m.Use(New(42))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `INSERT INTO "users" ("name", "tenant_id") VALUES ('alice', 42)`)
+
+	m = managers.NewInsertManager(users).
+		Columns(users.Col("name"), users.Col("tenant_id")).
+		Values("alice", 42).
+		Values("mallory", 7)
+	m.Use(New(42))
+	_, _, err = m.ToSQL(visitors.NewPostgresVisitor())
+	assertCrossTenant(t, err, "tenant: cross-tenant write: users.tenant_id is 7, not 42")
+
+	m = managers.NewInsertManager(users).
+		Columns(users.Col("tenant_id")).
+		Values(nodes.Named("tenant"))
+	m.Use(New(42))
+	_, _, err = m.ToSQL(visitors.NewPostgresVisitor())
+	assertCrossTenant(t, err, "tenant: cross-tenant write: cannot check users.tenant_id set to *nodes.NamedParamNode")
+}
+
+func TestInsertFromSelectIsRejected(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	archive := nodes.NewTable("archive")
+	m := managers.NewInsertManager(archive).FromSelect(managers.NewSelectManager(users))
+	m.Use(New(42))
+	_, _, err := m.ToSQL(visitors.NewPostgresVisitor())
+	assertCrossTenant(t, err, "tenant: cross-tenant write: cannot check archive.tenant_id of INSERT ... SELECT")
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewInsertManager(users).
+		Columns(users.Col("email")).
+		Values("a@example.com")
+	m.OnConflict(users.Col("email")).DoUpdate(&nodes.AssignmentNode{Left: users.Col("email"), Right: nodes.Literal("b@example.com")})
+	m.Use(New(42))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `INSERT INTO "users" ("email", "tenant_id") VALUES ('a@example.com', 42) `+
+		`ON CONFLICT ("email") DO UPDATE SET "users"."email" = 'b@example.com' WHERE "users"."tenant_id" = 42`)
+
+	m = managers.NewInsertManager(users).
+		Columns(users.Col("email")).
+		Values("a@example.com")
+	m.OnConflict(users.Col("email")).DoUpdate(&nodes.AssignmentNode{Left: users.Col("tenant_id"), Right: nodes.Literal(7)})
+	m.Use(New(42))
+	_, _, err = m.ToSQL(visitors.NewPostgresVisitor())
+	assertCrossTenant(t, err, "tenant: cross-tenant write: cannot update users.tenant_id")
+}
+
+func TestInsertExemptTable(t *testing.T) {
+	t.Parallel()
+	countries := nodes.NewTable("countries")
+	m := managers.NewInsertManager(countries).Columns(countries.Col("code")).Values("NZ")
+	m.Use(New(42, WithExemptTables("countries")))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `INSERT INTO "countries" ("code") VALUES ('NZ')`)
+}
+
+// --- UPDATE and DELETE ---
+
+func TestUpdateAddsCondition(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewUpdateManager(users).Set(users.Col("name"), "alice").Where(users.Col("id").Eq(1))
+	m.Use(New(42))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `UPDATE "users" SET "users"."name" = 'alice' WHERE "users"."id" = 1 AND "users"."tenant_id" = 42`)
+}
+
+func TestUpdateTenantColumnIsRejected(t *testing.T) {
+	t.Parallel()
+	accounts := nodes.NewTable("accounts")
+	m := managers.NewUpdateManager(accounts).Set(accounts.Col("org_id"), 7)
+	m.Use(New(42, WithTableColumn("accounts", "org_id")))
+	_, _, err := m.ToSQL(visitors.NewPostgresVisitor())
+	assertCrossTenant(t, err, "tenant: cross-tenant write: cannot update accounts.org_id")
+}
+
+func TestDeleteAddsCondition(t *testing.T) {
+	t.Parallel()
+	u := nodes.NewTable("users").Alias("u")
+	m := managers.NewDeleteManager(u).Where(u.Col("id").Eq(1))
+	m.Use(New(42))
+	sql, _, err := m.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `DELETE FROM "users" AS "u" WHERE "u"."id" = 1 AND "u"."tenant_id" = 42`)
+}
+
+func TestUpdateAndDeleteScopeSubqueries(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	banned := nodes.NewTable("banned")
+	sub := &nodes.SelectCore{From: banned, Projections: []nodes.Node{banned.Col("user_id")}}
+
+	u := managers.NewUpdateManager(users).Set(users.Col("active"), false).Where(users.Col("id").In(sub))
+	u.Use(New(42))
+	sql, _, err := u.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `UPDATE "users" SET "users"."active" = FALSE WHERE "users"."id" IN `+
+		`(SELECT "banned"."user_id" FROM "banned" WHERE "banned"."tenant_id" = 42) AND "users"."tenant_id" = 42`)
+
+	d := managers.NewDeleteManager(users).Where(nodes.Exists(sub))
+	d.Use(New(42, WithExemptTables("users")))
+	sql, _, err = d.ToSQL(visitors.NewPostgresVisitor(visitors.WithoutParams()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSQL(t, sql, `DELETE FROM "users" WHERE EXISTS `+
+		`(SELECT "banned"."user_id" FROM "banned" WHERE "banned"."tenant_id" = 42)`)
+}
+
+func TestImplementsTransformer(t *testing.T) {
+	t.Parallel()
+	var _ plugins.Transformer = New(1)
+}
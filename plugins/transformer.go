@@ -28,3 +28,12 @@ func (BaseTransformer) TransformUpdate(s *nodes.UpdateStatement) (*nodes.UpdateS
 func (BaseTransformer) TransformDelete(s *nodes.DeleteStatement) (*nodes.DeleteStatement, error) {
 	return s, nil
 }
+
+// RowsChecker is implemented by transformers that expect the statements
+// they transform to affect rows, such as an optimistic lock. exec.Exec
+// calls CheckRowsAffected with the manager's statement, before
+// transformation, and the driver's count after running it, and returns
+// its error. A checker ignores statement kinds it does not apply to.
+type RowsChecker interface {
+	CheckRowsAffected(stmt nodes.Node, n int64) error
+}
@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bawdo/gosbee/internal/drivers"
+)
+
+// Load introspects db. engine is "postgres", "mysql" or "sqlite"; an empty
+// engine is detected from the driver.
+func Load(ctx context.Context, db *sql.DB, engine string) (*Schema, error) {
+	if engine == "" {
+		engine = drivers.Engine(db.Driver())
+	}
+	var (
+		tables []*Table
+		err    error
+	)
+	switch engine {
+	case "postgres":
+		tables, err = loadPostgres(ctx, db)
+	case "mysql":
+		tables, err = loadMySQL(ctx, db)
+	case "sqlite":
+		tables, err = loadSQLite(ctx, db)
+	default:
+		return nil, fmt.Errorf("schema: unsupported engine %q", engine)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return New(tables...), nil
+}
+
+// queryRows runs query and calls scan for every row.
+func queryRows(ctx context.Context, db *sql.DB, query string, scan func(*sql.Rows) error, args ...any) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// catalog collects rows from the information_schema style queries of the
+// server engines, where each query covers every table at once.
+type catalog struct {
+	tables []*Table
+	byName map[string]*Table
+}
+
+func (c *catalog) add(name string, view bool) {
+	t := &Table{Name: name, View: view}
+	c.tables = append(c.tables, t)
+	c.byName[name] = t
+}
+
+// uniqueNamed returns the named unique constraint of t, creating it if needed.
+func uniqueNamed(t *Table, name string) *Unique {
+	for i := range t.Uniques {
+		if t.Uniques[i].Name == name {
+			return &t.Uniques[i]
+		}
+	}
+	t.Uniques = append(t.Uniques, Unique{Name: name})
+	return &t.Uniques[len(t.Uniques)-1]
+}
+
+// foreignKeyNamed returns the named foreign key of t, creating it if needed.
+func foreignKeyNamed(t *Table, name string) *ForeignKey {
+	for i := range t.ForeignKeys {
+		if t.ForeignKeys[i].Name == name {
+			return &t.ForeignKeys[i]
+		}
+	}
+	t.ForeignKeys = append(t.ForeignKeys, ForeignKey{Name: name})
+	return &t.ForeignKeys[len(t.ForeignKeys)-1]
+}
+
+// indexNamed returns the named index of t, creating it if needed.
+func indexNamed(t *Table, name string, unique bool) *Index {
+	for i := range t.Indexes {
+		if t.Indexes[i].Name == name {
+			return &t.Indexes[i]
+		}
+	}
+	t.Indexes = append(t.Indexes, Index{Name: name, Unique: unique})
+	return &t.Indexes[len(t.Indexes)-1]
+}
+
+// load runs the engine's queries in order: tables, columns, constraint
+// columns and index columns.
+func (c *catalog) load(ctx context.Context, db *sql.DB, q catalogQueries) ([]*Table, error) {
+	c.byName = make(map[string]*Table)
+	err := queryRows(ctx, db, q.tables, func(rows *sql.Rows) error {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return err
+		}
+		c.add(name, typ == "VIEW")
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tables: %w", err)
+	}
+
+	err = queryRows(ctx, db, q.columns, func(rows *sql.Rows) error {
+		var table, name, typ, nullable string
+		var def sql.NullString
+		if err := rows.Scan(&table, &name, &typ, &nullable, &def); err != nil {
+			return err
+		}
+		if t := c.byName[table]; t != nil {
+			col := &Column{Name: name, Type: typ, Nullable: nullable == "YES"}
+			if def.Valid {
+				col.Default = &def.String
+			}
+			t.Columns = append(t.Columns, col)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("columns: %w", err)
+	}
+
+	err = queryRows(ctx, db, q.constraints, func(rows *sql.Rows) error {
+		var table, name, kind, column string
+		var refTable, refColumn, onDelete, onUpdate sql.NullString
+		if err := rows.Scan(&table, &name, &kind, &column, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			return err
+		}
+		t := c.byName[table]
+		if t == nil {
+			return nil
+		}
+		switch kind {
+		case "PRIMARY KEY":
+			t.PrimaryKey = append(t.PrimaryKey, column)
+		case "UNIQUE":
+			u := uniqueNamed(t, name)
+			u.Columns = append(u.Columns, column)
+		case "FOREIGN KEY":
+			fk := foreignKeyNamed(t, name)
+			fk.Columns = append(fk.Columns, column)
+			fk.RefTable = refTable.String
+			fk.RefColumns = append(fk.RefColumns, refColumn.String)
+			fk.OnDelete, fk.OnUpdate = onDelete.String, onUpdate.String
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("constraints: %w", err)
+	}
+
+	err = queryRows(ctx, db, q.indexes, func(rows *sql.Rows) error {
+		var table, name, column string
+		var unique bool
+		if err := rows.Scan(&table, &name, &unique, &column); err != nil {
+			return err
+		}
+		if t := c.byName[table]; t != nil {
+			idx := indexNamed(t, name, unique)
+			idx.Columns = append(idx.Columns, column)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexes: %w", err)
+	}
+	return c.tables, nil
+}
+
+// catalogQueries are the queries catalog.load runs. Each returns rows
+// ordered so that multi-column keys arrive in key order:
+//
+//	tables:      name, 'BASE TABLE' | 'VIEW'
+//	columns:     table, column, type, 'YES' | 'NO', default
+//	constraints: table, constraint, kind, column, ref table, ref column, on delete, on update
+//	indexes:     table, index, unique, column
+type catalogQueries struct {
+	tables, columns, constraints, indexes string
+}
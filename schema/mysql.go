@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+)
+
+var mysqlQueries = catalogQueries{
+	tables: `SELECT table_name, table_type FROM information_schema.tables
+		WHERE table_schema = DATABASE()
+		ORDER BY table_name`,
+
+	columns: `SELECT table_name, column_name, column_type, is_nullable, column_default
+		FROM information_schema.columns
+		WHERE table_schema = DATABASE()
+		ORDER BY table_name, ordinal_position`,
+
+	constraints: `SELECT k.table_name, k.constraint_name, c.constraint_type, k.column_name,
+			k.referenced_table_name, k.referenced_column_name, r.delete_rule, r.update_rule
+		FROM information_schema.key_column_usage k
+		JOIN information_schema.table_constraints c
+			ON c.constraint_schema = k.constraint_schema
+			AND c.table_name = k.table_name
+			AND c.constraint_name = k.constraint_name
+		LEFT JOIN information_schema.referential_constraints r
+			ON r.constraint_schema = k.constraint_schema
+			AND r.constraint_name = k.constraint_name
+		WHERE k.table_schema = DATABASE()
+		ORDER BY k.table_name, k.constraint_name, k.ordinal_position`,
+
+	indexes: `SELECT s.table_name, s.index_name, s.non_unique = 0, s.column_name
+		FROM information_schema.statistics s
+		WHERE s.table_schema = DATABASE()
+			AND s.index_name <> 'PRIMARY'
+			AND s.column_name IS NOT NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM information_schema.table_constraints c
+				WHERE c.table_schema = s.table_schema
+					AND c.table_name = s.table_name
+					AND c.constraint_name = s.index_name
+					AND c.constraint_type = 'UNIQUE')
+		ORDER BY s.table_name, s.index_name, s.seq_in_index`,
+}
+
+// loadMySQL reads the current database from information_schema.
+func loadMySQL(ctx context.Context, db *sql.DB) ([]*Table, error) {
+	var c catalog
+	return c.load(ctx, db, mysqlQueries)
+}
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+)
+
+var postgresQueries = catalogQueries{
+	tables: `SELECT table_name, table_type FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type IN ('BASE TABLE', 'VIEW')
+		ORDER BY table_name`,
+
+	columns: `SELECT table_name, column_name,
+			CASE WHEN data_type = 'USER-DEFINED' THEN udt_name ELSE data_type END,
+			is_nullable, column_default
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+		ORDER BY table_name, ordinal_position`,
+
+	constraints: `SELECT rel.relname, con.conname,
+			CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' WHEN 'u' THEN 'UNIQUE' ELSE 'FOREIGN KEY' END,
+			att.attname, frel.relname, fatt.attname,
+			CASE con.confdeltype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' WHEN 'a' THEN 'NO ACTION' END,
+			CASE con.confupdtype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' WHEN 'a' THEN 'NO ACTION' END
+		FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_namespace ns ON ns.oid = rel.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = k.attnum
+		LEFT JOIN pg_class frel ON frel.oid = con.confrelid
+		LEFT JOIN pg_attribute fatt ON fatt.attrelid = con.confrelid AND fatt.attnum = con.confkey[k.ord::int]
+		WHERE ns.nspname = current_schema() AND con.contype IN ('p', 'u', 'f')
+		ORDER BY rel.relname, con.conname, k.ord`,
+
+	indexes: `SELECT t.relname, i.relname, ix.indisunique, a.attname
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = current_schema()
+			AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = ix.indexrelid AND c.contype IN ('p', 'u'))
+		ORDER BY t.relname, i.relname, k.ord`,
+}
+
+// loadPostgres reads the current schema from pg_catalog and
+// information_schema.
+func loadPostgres(ctx context.Context, db *sql.DB) ([]*Table, error) {
+	var c catalog
+	return c.load(ctx, db, postgresQueries)
+}
@@ -0,0 +1,164 @@
+// Package schema introspects a database into a model of its tables,
+// columns, keys and indexes.
+//
+//	s, err := schema.Load(ctx, db, "")
+//	users := s.Table("users")
+//
+// PostgreSQL (the current schema), MySQL (the current database) and SQLite
+// are supported. The model also serves as an opa.ColumnResolver and as a
+// source of completions for table and column names.
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bawdo/gosbee/plugins/opa"
+)
+
+// Schema is the introspected model of a database.
+type Schema struct {
+	// Tables holds the tables and views, sorted by name.
+	Tables []*Table
+
+	byName map[string]*Table
+}
+
+// Table describes a table or view.
+type Table struct {
+	Name string
+	View bool
+
+	// Columns are in declaration order.
+	Columns []*Column
+
+	// PrimaryKey lists the primary key columns in key order.
+	PrimaryKey []string
+
+	Uniques     []Unique
+	ForeignKeys []ForeignKey
+
+	// Indexes lists the indexes created explicitly, not those backing the
+	// primary key or unique constraints. Expression index parts are left
+	// out of Columns.
+	Indexes []Index
+}
+
+// Column describes a table column.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+
+	// Default is the column default as the database reports it, or nil.
+	Default *string
+}
+
+// Unique is a UNIQUE constraint.
+type Unique struct {
+	Name    string
+	Columns []string
+}
+
+// ForeignKey is a FOREIGN KEY constraint.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
+
+// Index is a table index.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// New builds a Schema from tables, sorting them by name. The caller's
+// slice is left in its original order.
+func New(tables ...*Table) *Schema {
+	s := &Schema{Tables: slices.Clone(tables), byName: make(map[string]*Table, len(tables))}
+	slices.SortFunc(s.Tables, func(a, b *Table) int { return strings.Compare(a.Name, b.Name) })
+	for _, t := range tables {
+		s.byName[t.Name] = t
+	}
+	return s
+}
+
+// Table returns the named table, or nil.
+func (s *Schema) Table(name string) *Table {
+	return s.byName[name]
+}
+
+// TableNames returns the table and view names in order.
+func (s *Schema) TableNames() []string {
+	names := make([]string, len(s.Tables))
+	for i, t := range s.Tables {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// ColumnNames returns the columns of the named table, or nil when there
+// is no such table.
+func (s *Schema) ColumnNames(table string) []string {
+	t := s.Table(table)
+	if t == nil {
+		return nil
+	}
+	return t.ColumnNames()
+}
+
+// ColumnResolver returns an opa.ColumnResolver answering from the model.
+func (s *Schema) ColumnResolver() opa.ColumnResolver {
+	return func(table string) ([]string, error) {
+		t := s.Table(table)
+		if t == nil {
+			return nil, fmt.Errorf("no schema for table %q", table)
+		}
+		return t.ColumnNames(), nil
+	}
+}
+
+// Complete returns completions for prefix: table names, or "table.column"
+// names once prefix contains a dot.
+func (s *Schema) Complete(prefix string) []string {
+	var candidates []string
+	if table, _, ok := strings.Cut(prefix, "."); ok {
+		for _, c := range s.ColumnNames(table) {
+			candidates = append(candidates, table+"."+c)
+		}
+	} else {
+		candidates = s.TableNames()
+	}
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Column returns the named column, or nil.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// ColumnNames returns the column names in declaration order.
+func (t *Table) ColumnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteDDL = `
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY,
+	email TEXT NOT NULL UNIQUE,
+	name TEXT DEFAULT 'anon'
+);
+CREATE TABLE posts (
+	id INTEGER PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users ON DELETE CASCADE,
+	slug TEXT NOT NULL,
+	title TEXT,
+	CONSTRAINT posts_user_slug UNIQUE (user_id, slug)
+);
+CREATE TABLE tags (
+	post_id INTEGER REFERENCES posts (id),
+	tag TEXT,
+	PRIMARY KEY (tag, post_id)
+);
+CREATE INDEX posts_title ON posts (title);
+CREATE VIEW active_users AS SELECT id, email FROM users;
+`
+
+func loadTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	testutil.AssertNoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(sqliteDDL)
+	testutil.AssertNoError(t, err)
+
+	s, err := Load(context.Background(), db, "")
+	testutil.AssertNoError(t, err)
+	return s
+}
+
+func TestLoadSQLiteTables(t *testing.T) {
+	t.Parallel()
+	s := loadTestSchema(t)
+	testutil.AssertEqual(t, reflect.DeepEqual(s.TableNames(), []string{"active_users", "posts", "tags", "users"}), true)
+	testutil.AssertEqual(t, s.Table("active_users").View, true)
+	testutil.AssertEqual(t, s.Table("users").View, false)
+	if s.Table("missing") != nil {
+		t.Error("expected nil for unknown table")
+	}
+}
+
+func TestLoadSQLiteColumns(t *testing.T) {
+	t.Parallel()
+	users := loadTestSchema(t).Table("users")
+	testutil.AssertEqual(t, reflect.DeepEqual(users.ColumnNames(), []string{"id", "email", "name"}), true)
+
+	email := users.Column("email")
+	testutil.AssertEqual(t, email.Type, "TEXT")
+	testutil.AssertEqual(t, email.Nullable, false)
+	if email.Default != nil {
+		t.Errorf("expected no default, got %q", *email.Default)
+	}
+	name := users.Column("name")
+	testutil.AssertEqual(t, name.Nullable, true)
+	testutil.AssertEqual(t, *name.Default, "'anon'")
+	testutil.AssertEqual(t, users.Column("id").Nullable, false)
+}
+
+func TestLoadSQLiteKeys(t *testing.T) {
+	t.Parallel()
+	s := loadTestSchema(t)
+
+	testutil.AssertEqual(t, reflect.DeepEqual(s.Table("users").PrimaryKey, []string{"id"}), true)
+	testutil.AssertEqual(t, reflect.DeepEqual(s.Table("tags").PrimaryKey, []string{"tag", "post_id"}), true)
+
+	posts := s.Table("posts")
+	testutil.AssertEqual(t, len(posts.ForeignKeys), 1)
+	fk := posts.ForeignKeys[0]
+	testutil.AssertEqual(t, fk.RefTable, "users")
+	testutil.AssertEqual(t, reflect.DeepEqual(fk.Columns, []string{"user_id"}), true)
+	testutil.AssertEqual(t, reflect.DeepEqual(fk.RefColumns, []string{"id"}), true)
+	testutil.AssertEqual(t, fk.OnDelete, "CASCADE")
+
+	var uniques [][]string
+	for _, u := range posts.Uniques {
+		uniques = append(uniques, u.Columns)
+	}
+	testutil.AssertEqual(t, reflect.DeepEqual(uniques, [][]string{{"user_id", "slug"}}), true)
+	testutil.AssertEqual(t, len(s.Table("users").Uniques), 1)
+}
+
+func TestLoadSQLiteIndexes(t *testing.T) {
+	t.Parallel()
+	posts := loadTestSchema(t).Table("posts")
+	testutil.AssertEqual(t, len(posts.Indexes), 1)
+	testutil.AssertEqual(t, posts.Indexes[0].Name, "posts_title")
+	testutil.AssertEqual(t, posts.Indexes[0].Unique, false)
+	testutil.AssertEqual(t, reflect.DeepEqual(posts.Indexes[0].Columns, []string{"title"}), true)
+}
+
+func TestColumnResolver(t *testing.T) {
+	t.Parallel()
+	resolve := loadTestSchema(t).ColumnResolver()
+	cols, err := resolve("posts")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, reflect.DeepEqual(cols, []string{"id", "user_id", "slug", "title"}), true)
+	_, err = resolve("missing")
+	testutil.AssertError(t, err)
+}
+
+func TestNewKeepsCallerOrder(t *testing.T) {
+	t.Parallel()
+	tables := []*Table{{Name: "users"}, {Name: "posts"}}
+	s := New(tables...)
+	testutil.AssertEqual(t, s.Tables[0].Name, "posts")
+	testutil.AssertEqual(t, tables[0].Name, "users")
+}
+
+func TestComplete(t *testing.T) {
+	t.Parallel()
+	s := New(
+		&Table{Name: "users", Columns: []*Column{{Name: "id"}, {Name: "email"}}},
+		&Table{Name: "posts", Columns: []*Column{{Name: "id"}}},
+	)
+	testutil.AssertEqual(t, reflect.DeepEqual(s.Complete("u"), []string{"users"}), true)
+	testutil.AssertEqual(t, reflect.DeepEqual(s.Complete(""), []string{"posts", "users"}), true)
+	testutil.AssertEqual(t, reflect.DeepEqual(s.Complete("users.e"), []string{"users.email"}), true)
+	testutil.AssertEqual(t, len(s.Complete("nope.")), 0)
+}
+
+func TestLoadUnsupportedEngine(t *testing.T) {
+	t.Parallel()
+	db, err := sql.Open("sqlite", ":memory:")
+	testutil.AssertNoError(t, err)
+	defer func() { _ = db.Close() }()
+	_, err = Load(context.Background(), db, "oracle")
+	testutil.AssertError(t, err)
+}
@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+)
+
+// loadSQLite reads the schema with sqlite_master and the table-valued
+// pragma functions. SQLite does not name foreign keys, and unique
+// constraints declared inline have no name either.
+func loadSQLite(ctx context.Context, db *sql.DB) ([]*Table, error) {
+	var tables []*Table
+	err := queryRows(ctx, db, `SELECT name, type FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`, func(rows *sql.Rows) error {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return err
+		}
+		tables = append(tables, &Table{Name: name, View: typ == "view"})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if err := loadSQLiteTable(ctx, db, t); err != nil {
+			return nil, err
+		}
+	}
+	resolveSQLiteRefs(tables)
+	return tables, nil
+}
+
+func loadSQLiteTable(ctx context.Context, db *sql.DB, t *Table) error {
+	type pkCol struct {
+		pos  int
+		name string
+	}
+	var pk []pkCol
+	err := queryRows(ctx, db, `SELECT name, type, "notnull", dflt_value, pk
+		FROM pragma_table_info(?) ORDER BY cid`, func(rows *sql.Rows) error {
+		var name, typ string
+		var notNull, pkPos int
+		var def sql.NullString
+		if err := rows.Scan(&name, &typ, &notNull, &def, &pkPos); err != nil {
+			return err
+		}
+		col := &Column{Name: name, Type: typ, Nullable: notNull == 0 && pkPos == 0}
+		if def.Valid {
+			col.Default = &def.String
+		}
+		t.Columns = append(t.Columns, col)
+		if pkPos > 0 {
+			pk = append(pk, pkCol{pkPos, name})
+		}
+		return nil
+	}, t.Name)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(pk, func(a, b pkCol) int { return a.pos - b.pos })
+	for _, c := range pk {
+		t.PrimaryKey = append(t.PrimaryKey, c.name)
+	}
+
+	lastID := -1
+	err = queryRows(ctx, db, `SELECT id, "table", "from", "to", on_update, on_delete
+		FROM pragma_foreign_key_list(?) ORDER BY id, seq`, func(rows *sql.Rows) error {
+		var id int
+		var refTable, from, onUpdate, onDelete string
+		var to sql.NullString
+		if err := rows.Scan(&id, &refTable, &from, &to, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		if id != lastID {
+			t.ForeignKeys = append(t.ForeignKeys, ForeignKey{RefTable: refTable, OnDelete: onDelete, OnUpdate: onUpdate})
+			lastID = id
+		}
+		fk := &t.ForeignKeys[len(t.ForeignKeys)-1]
+		fk.Columns = append(fk.Columns, from)
+		if to.Valid {
+			fk.RefColumns = append(fk.RefColumns, to.String)
+		}
+		return nil
+	}, t.Name)
+	if err != nil {
+		return err
+	}
+
+	type indexInfo struct {
+		name, origin string
+		unique       bool
+	}
+	var indexes []indexInfo
+	err = queryRows(ctx, db, `SELECT name, "unique", origin FROM pragma_index_list(?) ORDER BY name`,
+		func(rows *sql.Rows) error {
+			var ix indexInfo
+			if err := rows.Scan(&ix.name, &ix.unique, &ix.origin); err != nil {
+				return err
+			}
+			indexes = append(indexes, ix)
+			return nil
+		}, t.Name)
+	if err != nil {
+		return err
+	}
+	for _, ix := range indexes {
+		if ix.origin == "pk" {
+			continue
+		}
+		var cols []string
+		err := queryRows(ctx, db, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`, func(rows *sql.Rows) error {
+			var name sql.NullString
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			if name.Valid {
+				cols = append(cols, name.String)
+			}
+			return nil
+		}, ix.name)
+		if err != nil {
+			return err
+		}
+		if ix.origin == "u" {
+			name := ix.name
+			if strings.HasPrefix(name, "sqlite_autoindex_") {
+				name = ""
+			}
+			t.Uniques = append(t.Uniques, Unique{Name: name, Columns: cols})
+			continue
+		}
+		t.Indexes = append(t.Indexes, Index{Name: ix.name, Columns: cols, Unique: ix.unique})
+	}
+	return nil
+}
+
+// resolveSQLiteRefs fills the referenced columns of foreign keys declared
+// without them, which refer to the primary key of the parent table.
+func resolveSQLiteRefs(tables []*Table) {
+	byName := make(map[string]*Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	for _, t := range tables {
+		for i := range t.ForeignKeys {
+			fk := &t.ForeignKeys[i]
+			if len(fk.RefColumns) == 0 {
+				if parent := byName[fk.RefTable]; parent != nil {
+					fk.RefColumns = append([]string(nil), parent.PrimaryKey...)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package validate
+
+import (
+	"slices"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/schema"
+)
+
+// relation is a FROM entry visible to column references.
+type relation struct {
+	name    string   // alias, or the table name when not aliased
+	columns []string // output columns; unnamed expressions are ""
+	known   bool     // whether columns is complete
+	table   *schema.Table
+}
+
+func (r *relation) has(col string) bool {
+	return slices.Contains(r.columns, col)
+}
+
+type cteInfo struct {
+	columns []string
+	known   bool
+}
+
+// scope holds the FROM entries of one query. Subqueries get a child scope
+// so that correlated references resolve against the enclosing queries.
+type scope struct {
+	parent    *scope
+	relations []*relation
+	ctes      map[string]cteInfo
+
+	// opaque is set when a FROM entry is raw SQL, whose names cannot be
+	// resolved.
+	opaque bool
+}
+
+func (s *scope) addCTE(name string, cols []string, known bool) {
+	if s.ctes == nil {
+		s.ctes = make(map[string]cteInfo)
+	}
+	s.ctes[name] = cteInfo{columns: cols, known: known}
+}
+
+// cte looks up a WITH entry in s and its enclosing scopes.
+func (s *scope) cte(name string) (cteInfo, bool) {
+	for ; s != nil; s = s.parent {
+		if info, ok := s.ctes[name]; ok {
+			return info, true
+		}
+	}
+	return cteInfo{}, false
+}
+
+func (s *scope) isOpaque() bool {
+	for ; s != nil; s = s.parent {
+		if s.opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// outputs returns the columns a query with the given projections returns.
+// An empty projection list selects every column.
+func (s *scope) outputs(projections []nodes.Node) ([]string, bool) {
+	if len(projections) == 0 {
+		projections = []nodes.Node{nodes.Star()}
+	}
+	var cols []string
+	for _, p := range projections {
+		switch p := p.(type) {
+		case *nodes.AliasNode:
+			cols = append(cols, p.Name)
+		case *nodes.Attribute:
+			cols = append(cols, p.Name)
+		case *nodes.StarNode:
+			if s.opaque {
+				return nil, false
+			}
+			for _, r := range s.relations {
+				if p.Table != nil && r.name != p.Table.Name {
+					continue
+				}
+				if !r.known {
+					return nil, false
+				}
+				for _, col := range r.columns {
+					if !slices.Contains(p.Exclude, col) {
+						cols = append(cols, col)
+					}
+				}
+			}
+		case *nodes.SqlLiteral:
+			// May expand to any number of columns.
+			return nil, false
+		default:
+			cols = append(cols, "")
+		}
+	}
+	return cols, true
+}
+
+// children returns the subexpressions of an expression node. Subqueries
+// are not included; they are validated in scopes of their own.
+func children(n nodes.Node) []nodes.Node {
+	var out []nodes.Node
+	switch n := n.(type) {
+	case *nodes.ComparisonNode:
+		out = []nodes.Node{n.Left, n.Right}
+	case *nodes.InNode:
+		out = append([]nodes.Node{n.Expr}, n.Vals...)
+	case *nodes.BetweenNode:
+		out = []nodes.Node{n.Expr, n.Low, n.High}
+	case *nodes.AndNode:
+		out = []nodes.Node{n.Left, n.Right}
+	case *nodes.OrNode:
+		out = []nodes.Node{n.Left, n.Right}
+	case *nodes.InfixNode:
+		out = []nodes.Node{n.Left, n.Right}
+	case *nodes.NotNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.UnaryNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.UnaryMathNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.GroupingNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.ExtractNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.AliasNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.OrderingNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.AggregateNode:
+		out = []nodes.Node{n.Expr, n.Filter}
+	case *nodes.NamedFunctionNode:
+		out = n.Args
+	case *nodes.WindowFuncNode:
+		out = n.Args
+	case *nodes.OverNode:
+		out = []nodes.Node{n.Expr}
+	case *nodes.CaseNode:
+		out = []nodes.Node{n.Operand}
+		for _, w := range n.Whens {
+			out = append(out, w.Condition, w.Result)
+		}
+		out = append(out, n.ElseVal)
+	case *nodes.GroupingSetNode:
+		out = slices.Clone(n.Columns)
+		for _, set := range n.Sets {
+			out = append(out, set...)
+		}
+	case *nodes.CastedNode:
+		if v, ok := n.Value.(nodes.Node); ok {
+			out = []nodes.Node{v}
+		}
+	case nodes.Parent:
+		out = slices.Clone(n.Children())
+	}
+	return slices.DeleteFunc(out, func(n nodes.Node) bool { return n == nil })
+}
+
+// groupBy reports projections and HAVING terms that reference columns of
+// the query without grouping or aggregating them. A table whose primary
+// key is grouped may have any of its columns projected.
+func (c *checker) groupBy(sc *scope, core *nodes.SelectCore) {
+	if len(core.Groups) == 0 && !hasAggregate(core.Projections) && !hasAggregate(core.Havings) {
+		return
+	}
+	var groups []nodes.Node
+	for _, g := range core.Groups {
+		if gs, ok := g.(*nodes.GroupingSetNode); ok {
+			groups = append(groups, children(gs)...)
+		} else {
+			groups = append(groups, g)
+		}
+	}
+	keyed := make(map[string]bool)
+	for _, r := range sc.relations {
+		if r.table != nil && len(r.table.PrimaryKey) > 0 && keyGrouped(r, groups) {
+			keyed[r.name] = true
+		}
+	}
+
+	var check func(n nodes.Node)
+	check = func(n nodes.Node) {
+		if n == nil || isGrouped(n, groups) {
+			return
+		}
+		switch n := n.(type) {
+		case *nodes.AggregateNode, *nodes.OverNode,
+			*nodes.SelectCore, *nodes.SetOperationNode, *nodes.ExistsNode:
+			return
+		case *nodes.StarNode:
+			c.report(GroupBy, "SELECT * is not allowed with GROUP BY or aggregates")
+			return
+		case *nodes.Attribute:
+			name := nodes.RelationName(n.Relation)
+			if keyed[name] || !sc.local(name) {
+				return
+			}
+			c.report(GroupBy, "column %s must appear in GROUP BY or be used in an aggregate", describe(n))
+			return
+		}
+		for _, child := range children(n) {
+			check(child)
+		}
+	}
+	projections := core.Projections
+	if len(projections) == 0 {
+		projections = []nodes.Node{nodes.Star()}
+	}
+	for _, p := range projections {
+		check(p)
+	}
+	for _, h := range core.Havings {
+		check(h)
+	}
+}
+
+// local reports whether a qualifier names a FROM entry of s itself, as
+// opposed to an enclosing query.
+func (s *scope) local(name string) bool {
+	if name == "" {
+		return len(s.relations) > 0
+	}
+	for _, r := range s.relations {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isGrouped reports whether n is one of the GROUP BY expressions.
+// Attributes match by qualifier and name, whatever their TypeName.
+func isGrouped(n nodes.Node, groups []nodes.Node) bool {
+	a, isAttr := n.(*nodes.Attribute)
+	for _, g := range groups {
+		if ga, ok := g.(*nodes.Attribute); ok && isAttr {
+			if ga.Name == a.Name && nodes.RelationName(ga.Relation) == nodes.RelationName(a.Relation) {
+				return true
+			}
+			continue
+		}
+		if nodes.Equal(n, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyGrouped reports whether every primary key column of r is grouped.
+func keyGrouped(r *relation, groups []nodes.Node) bool {
+	for _, col := range r.table.PrimaryKey {
+		if !isGrouped(nodes.NewAttribute(&nodes.Table{Name: r.name}, col), groups) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAggregate reports whether an aggregate is applied directly in list,
+// outside subqueries and window functions.
+func hasAggregate(list []nodes.Node) bool {
+	var found func(n nodes.Node) bool
+	found = func(n nodes.Node) bool {
+		switch n.(type) {
+		case *nodes.AggregateNode:
+			return true
+		case *nodes.OverNode:
+			return false
+		}
+		return slices.ContainsFunc(children(n), found)
+	}
+	return slices.ContainsFunc(list, found)
+}
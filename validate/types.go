@@ -0,0 +1,87 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// typeClass groups SQL types whose values can be compared with each other.
+type typeClass int
+
+const (
+	anyType typeClass = iota // unknown, or coerced to whatever it is compared with
+	numericType
+	textType
+	boolType
+	timeType
+	binaryType
+	uuidType
+	jsonType
+)
+
+var typeArgs = regexp.MustCompile(`\s*\(.*\)`)
+
+// classify returns the class of a SQL type name. Unrecognised names fall
+// back to SQLite's type affinity rules, then to anyType.
+func classify(sqlType string) typeClass {
+	raw := strings.ToLower(strings.TrimSpace(sqlType))
+	if raw == "tinyint(1)" {
+		// MySQL's BOOLEAN.
+		return boolType
+	}
+	t := typeArgs.ReplaceAllString(raw, "")
+	t = strings.TrimSuffix(t, " unsigned")
+	switch t {
+	case "bool", "boolean":
+		return boolType
+	case "smallint", "integer", "int", "bigint", "tinyint", "mediumint", "int2", "int4", "int8",
+		"serial", "smallserial", "bigserial", "real", "float", "float4", "float8", "double",
+		"double precision", "numeric", "decimal", "year", "signed", "unsigned":
+		return numericType
+	case "text", "varchar", "char", "character", "character varying", "nvarchar",
+		"tinytext", "mediumtext", "longtext", "citext", "enum", "set", "name":
+		return textType
+	case "date", "datetime", "timestamp", "timestamptz", "time", "timetz",
+		"timestamp with time zone", "timestamp without time zone",
+		"time with time zone", "time without time zone":
+		return timeType
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return binaryType
+	case "uuid":
+		return uuidType
+	case "json", "jsonb":
+		return jsonType
+	case "interval", "point":
+		// Not integers, despite the affinity rule below.
+		return anyType
+	}
+	switch {
+	case strings.Contains(t, "int"):
+		return numericType
+	case strings.Contains(t, "char"), strings.Contains(t, "clob"), strings.Contains(t, "text"):
+		return textType
+	case strings.Contains(t, "real"), strings.Contains(t, "floa"), strings.Contains(t, "doub"):
+		return numericType
+	}
+	return anyType
+}
+
+// classifyValue returns the class of a Go value bound as a literal.
+// Strings are anyType: SQL coerces quoted literals to the type they are
+// compared with.
+func classifyValue(v any) (typeClass, string) {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return numericType, "integer"
+	case float32, float64:
+		return numericType, "float"
+	case bool:
+		return boolType, "boolean"
+	case time.Time:
+		return timeType, "timestamp"
+	case []byte:
+		return binaryType, "binary"
+	}
+	return anyType, ""
+}
@@ -0,0 +1,585 @@
+// Package validate checks statements against a schema model before they
+// are sent to the database.
+//
+//	v := validate.New(sch)
+//	for _, p := range v.Check(query.Core) {
+//	    fmt.Println(p)
+//	}
+//
+// It reports unknown tables and columns, ambiguous references, INSERT
+// column/value count mismatches, set operations whose sides return
+// different numbers of columns, projections that are neither grouped nor
+// aggregated, and comparisons between incompatible types. Types come from
+// Attribute.TypeName, falling back to the column type in the schema.
+//
+// A Validator is also a plugins.Transformer. Registered with Use, it makes
+// ToSQL fail with an *Error instead of rendering a statement with problems:
+//
+//	query.Use(validate.New(sch))
+//
+// The checks are deliberately conservative: references that cannot be
+// resolved with certainty, such as columns of raw SQL fragments, are not
+// reported.
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/schema"
+)
+
+// Kind classifies a Problem.
+type Kind int
+
+const (
+	UnknownTable       Kind = iota // table not in the schema or not in FROM
+	UnknownColumn                  // column not in its table
+	AmbiguousReference             // name matches more than one FROM entry
+	ColumnCount                    // INSERT or CTE column list does not match its values
+	SetOperationArity              // sides of a set operation return different column counts
+	GroupBy                        // projection neither grouped nor aggregated
+	TypeMismatch                   // comparison between incompatible types
+)
+
+var kindNames = [...]string{
+	UnknownTable:       "unknown table",
+	UnknownColumn:      "unknown column",
+	AmbiguousReference: "ambiguous reference",
+	ColumnCount:        "column count",
+	SetOperationArity:  "set operation arity",
+	GroupBy:            "group by",
+	TypeMismatch:       "type mismatch",
+}
+
+// String returns a short description of the kind.
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Problem is a mistake found in a statement.
+type Problem struct {
+	Kind    Kind
+	Message string
+}
+
+func (p Problem) String() string { return p.Message }
+
+// Error is returned by the Transformer methods when a statement has
+// problems.
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Message
+	}
+	return "validate: " + strings.Join(msgs, "; ")
+}
+
+// Validator checks statements against a schema.
+type Validator struct {
+	schema *schema.Schema
+}
+
+// New returns a Validator for s.
+func New(s *schema.Schema) *Validator {
+	return &Validator{schema: s}
+}
+
+// Check validates a statement: a *nodes.SelectCore, *nodes.SetOperationNode,
+// *nodes.InsertStatement, *nodes.UpdateStatement or *nodes.DeleteStatement.
+// Other nodes have no problems.
+func (v *Validator) Check(n nodes.Node) []Problem {
+	c := &checker{v: v, seen: make(map[string]bool)}
+	switch n := n.(type) {
+	case *nodes.InsertStatement:
+		c.insert(n)
+	case *nodes.UpdateStatement:
+		c.update(n)
+	case *nodes.DeleteStatement:
+		c.delete(n)
+	default:
+		c.query(nil, n)
+	}
+	return c.problems
+}
+
+func (v *Validator) check(n nodes.Node) error {
+	if problems := v.Check(n); len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+// TransformSelect returns core unchanged, or an *Error when it has problems.
+func (v *Validator) TransformSelect(core *nodes.SelectCore) (*nodes.SelectCore, error) {
+	if err := v.check(core); err != nil {
+		return nil, err
+	}
+	return core, nil
+}
+
+// TransformInsert returns stmt unchanged, or an *Error when it has problems.
+func (v *Validator) TransformInsert(stmt *nodes.InsertStatement) (*nodes.InsertStatement, error) {
+	if err := v.check(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// TransformUpdate returns stmt unchanged, or an *Error when it has problems.
+func (v *Validator) TransformUpdate(stmt *nodes.UpdateStatement) (*nodes.UpdateStatement, error) {
+	if err := v.check(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// TransformDelete returns stmt unchanged, or an *Error when it has problems.
+func (v *Validator) TransformDelete(stmt *nodes.DeleteStatement) (*nodes.DeleteStatement, error) {
+	if err := v.check(stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// checker accumulates the problems of one statement.
+type checker struct {
+	v        *Validator
+	problems []Problem
+	seen     map[string]bool
+}
+
+func (c *checker) report(kind Kind, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if c.seen[msg] {
+		return
+	}
+	c.seen[msg] = true
+	c.problems = append(c.problems, Problem{Kind: kind, Message: msg})
+}
+
+// query validates a SELECT or set operation and returns its output
+// columns. known is false when the number of columns cannot be told;
+// unnamed expressions have an empty name.
+func (c *checker) query(parent *scope, n nodes.Node) (cols []string, known bool) {
+	switch n := n.(type) {
+	case *nodes.SelectCore:
+		return c.selectCore(parent, n)
+	case *nodes.SetOperationNode:
+		left, leftKnown := c.query(parent, n.Left)
+		right, rightKnown := c.query(parent, n.Right)
+		if leftKnown && rightKnown && len(left) != len(right) {
+			c.report(SetOperationArity, "%s: left query returns %d columns, right query returns %d",
+				n.Type, len(left), len(right))
+		}
+		empty := &scope{parent: parent}
+		c.expr(empty, n.Limit)
+		c.expr(empty, n.Offset)
+		if leftKnown {
+			return left, true
+		}
+		return right, rightKnown
+	case *nodes.GroupingNode:
+		return c.query(parent, n.Expr)
+	case subquery:
+		return c.selectCore(parent, n.CloneCore())
+	}
+	return nil, false
+}
+
+// subquery is implemented by managers.SelectManager, which may stand in
+// for its SelectCore anywhere in a tree.
+type subquery interface {
+	CloneCore() *nodes.SelectCore
+}
+
+func (c *checker) selectCore(parent *scope, core *nodes.SelectCore) ([]string, bool) {
+	sc := &scope{parent: parent}
+	for _, cte := range core.CTEs {
+		c.cte(sc, cte)
+	}
+	c.fromItem(sc, core.From, false)
+	for _, j := range core.Joins {
+		if !isArrayJoin(j) {
+			c.fromItem(sc, j.Right, j.Lateral)
+		}
+	}
+	for _, j := range core.Joins {
+		if isArrayJoin(j) {
+			c.arrayJoin(sc, j.Right)
+		}
+		c.expr(sc, j.On)
+	}
+	c.exprs(sc, core.Projections)
+	c.exprs(sc, core.DistinctOn)
+	c.exprs(sc, core.Wheres)
+	c.exprs(sc, core.Groups)
+	c.exprs(sc, core.Havings)
+	for _, w := range core.Windows {
+		c.window(sc, w)
+	}
+	c.exprs(sc, core.Qualifies)
+	c.exprs(sc, core.Orders)
+	if lb := core.LimitBy; lb != nil {
+		c.expr(sc, lb.Limit)
+		c.expr(sc, lb.Offset)
+		c.exprs(sc, lb.Columns)
+	}
+	c.expr(sc, core.Limit)
+	c.expr(sc, core.Offset)
+	c.groupBy(sc, core)
+	return sc.outputs(core.Projections)
+}
+
+// cte validates a WITH entry and makes it visible to the rest of the query.
+func (c *checker) cte(sc *scope, cte *nodes.CTENode) {
+	if cte.Recursive {
+		// The query refers to itself; its columns are only known when
+		// listed.
+		sc.addCTE(cte.Name, cte.Columns, len(cte.Columns) > 0)
+	}
+	cols, known := c.query(sc, cte.Query)
+	if len(cte.Columns) > 0 {
+		if known && len(cols) != len(cte.Columns) {
+			c.report(ColumnCount, "WITH %q lists %d columns but its query returns %d",
+				cte.Name, len(cte.Columns), len(cols))
+		}
+		cols, known = cte.Columns, true
+	}
+	sc.addCTE(cte.Name, cols, known)
+}
+
+// fromItem adds a FROM or JOIN source to sc. Derived tables see the
+// enclosing scopes, and also sc itself when lateral.
+func (c *checker) fromItem(sc *scope, n nodes.Node, lateral bool) {
+	switch n := n.(type) {
+	case nil:
+	case *nodes.Table:
+		sc.relations = append(sc.relations, c.table(sc, n.Name, n.Name))
+	case *nodes.TableAlias:
+		if t, ok := n.Relation.(*nodes.Table); ok {
+			sc.relations = append(sc.relations, c.table(sc, t.Name, n.AliasName))
+			return
+		}
+		outer := sc.parent
+		if lateral {
+			outer = sc
+		}
+		cols, known := c.query(outer, n.Relation)
+		sc.relations = append(sc.relations, &relation{name: n.AliasName, columns: cols, known: known})
+	default:
+		// Raw SQL and other sources expose names the validator cannot see.
+		sc.opaque = true
+	}
+}
+
+func isArrayJoin(j *nodes.JoinNode) bool {
+	return j.Type == nodes.ArrayJoin || j.Type == nodes.LeftArrayJoin
+}
+
+// arrayJoin validates the array unfolded by an ARRAY JOIN. An alias
+// names the elements, which the validator cannot resolve.
+func (c *checker) arrayJoin(sc *scope, n nodes.Node) {
+	if a, ok := n.(*nodes.AliasNode); ok {
+		sc.opaque = true
+		n = a.Expr
+	}
+	c.expr(sc, n)
+}
+
+// table resolves a table name against the CTEs in scope and the schema.
+func (c *checker) table(sc *scope, name, exposed string) *relation {
+	if cte, ok := sc.cte(name); ok {
+		return &relation{name: exposed, columns: cte.columns, known: cte.known}
+	}
+	if t := c.v.schema.Table(name); t != nil {
+		return &relation{name: exposed, columns: t.ColumnNames(), known: true, table: t}
+	}
+	c.report(UnknownTable, "table %q does not exist", name)
+	return &relation{name: exposed}
+}
+
+func (c *checker) window(sc *scope, w *nodes.WindowDefinition) {
+	if w == nil {
+		return
+	}
+	c.exprs(sc, w.PartitionBy)
+	c.exprs(sc, w.OrderBy)
+	if w.Frame != nil {
+		c.expr(sc, w.Frame.Start.Offset)
+		if w.Frame.End != nil {
+			c.expr(sc, w.Frame.End.Offset)
+		}
+	}
+}
+
+func (c *checker) exprs(sc *scope, list []nodes.Node) {
+	for _, n := range list {
+		c.expr(sc, n)
+	}
+}
+
+// expr validates the references and comparisons in an expression.
+func (c *checker) expr(sc *scope, n nodes.Node) {
+	switch n := n.(type) {
+	case nil:
+		return
+	case *nodes.Attribute:
+		c.attribute(sc, n)
+		return
+	case *nodes.StarNode:
+		if n.Table != nil {
+			c.relation(sc, n.Table.Name)
+		}
+		for _, r := range n.Replace {
+			c.expr(sc, r.Expr)
+		}
+		return
+	case *nodes.SelectCore, *nodes.SetOperationNode, subquery:
+		c.query(sc, n)
+		return
+	case *nodes.ExistsNode:
+		c.query(sc, n.Subquery)
+		return
+	case *nodes.OverNode:
+		c.window(sc, n.Window)
+	case *nodes.ComparisonNode:
+		if comparable(n.Op) {
+			c.compare(sc, n.Left, n.Right)
+		}
+	case *nodes.InNode:
+		for _, val := range n.Vals {
+			c.compare(sc, n.Expr, val)
+		}
+	case *nodes.BetweenNode:
+		c.compare(sc, n.Expr, n.Low)
+		c.compare(sc, n.Expr, n.High)
+	}
+	for _, child := range children(n) {
+		c.expr(sc, child)
+	}
+}
+
+// attribute checks that a column reference resolves.
+func (c *checker) attribute(sc *scope, a *nodes.Attribute) {
+	if a.Relation == nil {
+		c.unqualified(sc, a.Name)
+		return
+	}
+	name := nodes.RelationName(a.Relation)
+	if name == "" {
+		return
+	}
+	rel := c.relation(sc, name)
+	if rel != nil && rel.known && !rel.has(a.Name) {
+		c.report(UnknownColumn, "column %q.%q does not exist", rel.name, a.Name)
+	}
+}
+
+// relation resolves a qualifier, reporting it when it names no FROM entry
+// or more than one.
+func (c *checker) relation(sc *scope, name string) *relation {
+	for s := sc; s != nil; s = s.parent {
+		var found []*relation
+		for _, r := range s.relations {
+			if r.name == name {
+				found = append(found, r)
+			}
+		}
+		if len(found) > 1 {
+			c.report(AmbiguousReference, "table name %q is specified more than once", name)
+		}
+		if len(found) > 0 {
+			return found[0]
+		}
+	}
+	if !sc.isOpaque() {
+		c.report(UnknownTable, "table %q is not in the FROM clause", name)
+	}
+	return nil
+}
+
+// unqualified resolves a column reference without a table.
+func (c *checker) unqualified(sc *scope, name string) {
+	complete := !sc.isOpaque()
+	for s := sc; s != nil; s = s.parent {
+		var found []string
+		for _, r := range s.relations {
+			if !r.known {
+				complete = false
+			} else if r.has(name) {
+				found = append(found, r.name)
+			}
+		}
+		if len(found) > 1 {
+			c.report(AmbiguousReference, "column %q is ambiguous: it is in %s", name, strings.Join(found, ", "))
+		}
+		if len(found) > 0 {
+			return
+		}
+	}
+	if complete {
+		c.report(UnknownColumn, "column %q does not exist", name)
+	}
+}
+
+// compare reports a comparison between values of incompatible types.
+func (c *checker) compare(sc *scope, left, right nodes.Node) {
+	lt, lname := c.typeOf(sc, left)
+	rt, rname := c.typeOf(sc, right)
+	if lt != anyType && rt != anyType && lt != rt {
+		c.report(TypeMismatch, "cannot compare %s (%s) with %s (%s)", describe(left), lname, describe(right), rname)
+	}
+}
+
+// typeOf returns the type class of an expression and the type name it was
+// derived from.
+func (c *checker) typeOf(sc *scope, n nodes.Node) (typeClass, string) {
+	switch n := n.(type) {
+	case *nodes.Attribute:
+		if n.TypeName != "" {
+			return classify(n.TypeName), n.TypeName
+		}
+		if n.Relation == nil {
+			return anyType, ""
+		}
+		for s := sc; s != nil; s = s.parent {
+			for _, r := range s.relations {
+				if r.name == nodes.RelationName(n.Relation) && r.table != nil {
+					if col := r.table.Column(n.Name); col != nil {
+						return classify(col.Type), strings.ToLower(col.Type)
+					}
+					return anyType, ""
+				}
+			}
+		}
+	case *nodes.CastedNode:
+		return classify(n.TypeName), n.TypeName
+	case *nodes.LiteralNode:
+		return classifyValue(n.Value)
+	case *nodes.BindParamNode:
+		return classifyValue(n.Value)
+	case *nodes.GroupingNode:
+		return c.typeOf(sc, n.Expr)
+	}
+	return anyType, ""
+}
+
+// describe names an expression in a problem message.
+func describe(n nodes.Node) string {
+	switch n := n.(type) {
+	case *nodes.Attribute:
+		if q := nodes.RelationName(n.Relation); q != "" {
+			return q + "." + n.Name
+		}
+		return n.Name
+	case *nodes.LiteralNode:
+		return fmt.Sprintf("%#v", n.Value)
+	case *nodes.BindParamNode:
+		return fmt.Sprintf("%#v", n.Value)
+	case *nodes.CastedNode:
+		return fmt.Sprintf("CAST(%#v AS %s)", n.Value, n.TypeName)
+	}
+	return "expression"
+}
+
+// comparable reports whether op compares values of the same type.
+func comparable(op nodes.ComparisonOp) bool {
+	switch op {
+	case nodes.OpEq, nodes.OpNotEq, nodes.OpGt, nodes.OpGtEq, nodes.OpLt, nodes.OpLtEq,
+		nodes.OpDistinctFrom, nodes.OpNotDistinctFrom:
+		return true
+	}
+	return false
+}
+
+func (c *checker) insert(stmt *nodes.InsertStatement) {
+	sc := &scope{}
+	c.fromItem(sc, stmt.Into, false)
+	var target *relation
+	if len(sc.relations) == 1 {
+		target = sc.relations[0]
+	}
+	c.targetColumns(target, stmt.Columns)
+
+	want := len(stmt.Columns)
+	if want == 0 {
+		want = -1
+		if target != nil && target.known {
+			want = len(target.columns)
+		}
+	}
+	empty := &scope{}
+	for i, row := range stmt.Values {
+		if want >= 0 && len(row) != want {
+			c.report(ColumnCount, "INSERT row %d has %d values for %d columns", i+1, len(row), want)
+		}
+		c.exprs(empty, row)
+	}
+	if stmt.Select != nil {
+		cols, known := c.query(nil, stmt.Select)
+		if known && want >= 0 && len(cols) != want {
+			c.report(ColumnCount, "INSERT has %d columns but its SELECT returns %d", want, len(cols))
+		}
+	}
+	c.exprs(sc, stmt.Returning)
+
+	if oc := stmt.OnConflict; oc != nil {
+		c.targetColumns(target, oc.Columns)
+		// DO UPDATE sees the proposed row as "excluded".
+		if target != nil {
+			excluded := *target
+			excluded.name = "excluded"
+			sc.relations = append(sc.relations, &excluded)
+		}
+		c.assignments(sc, target, oc.Assignments)
+		c.exprs(sc, oc.Wheres)
+	}
+}
+
+func (c *checker) update(stmt *nodes.UpdateStatement) {
+	sc := &scope{}
+	c.fromItem(sc, stmt.Table, false)
+	var target *relation
+	if len(sc.relations) == 1 {
+		target = sc.relations[0]
+	}
+	c.assignments(sc, target, stmt.Assignments)
+	c.exprs(sc, stmt.Wheres)
+	c.exprs(sc, stmt.Returning)
+}
+
+func (c *checker) delete(stmt *nodes.DeleteStatement) {
+	sc := &scope{}
+	c.fromItem(sc, stmt.From, false)
+	c.exprs(sc, stmt.Wheres)
+	c.exprs(sc, stmt.Returning)
+}
+
+// targetColumns checks the columns an INSERT or SET writes to. Only the
+// column name matters; SQL does not qualify them.
+func (c *checker) targetColumns(target *relation, cols []nodes.Node) {
+	if target == nil || !target.known {
+		return
+	}
+	for _, col := range cols {
+		if a, ok := col.(*nodes.Attribute); ok && !target.has(a.Name) {
+			c.report(UnknownColumn, "column %q of table %q does not exist", a.Name, target.name)
+		}
+	}
+}
+
+func (c *checker) assignments(sc *scope, target *relation, list []*nodes.AssignmentNode) {
+	for _, a := range list {
+		c.targetColumns(target, []nodes.Node{a.Left})
+		c.expr(sc, a.Right)
+	}
+}
@@ -0,0 +1,271 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+	"github.com/bawdo/gosbee/schema"
+	"github.com/bawdo/gosbee/visitors"
+)
+
+var (
+	users = nodes.NewTable("users")
+	posts = nodes.NewTable("posts")
+)
+
+func testValidator() *Validator {
+	return New(schema.New(
+		&schema.Table{
+			Name: "users",
+			Columns: []*schema.Column{
+				{Name: "id", Type: "integer"},
+				{Name: "email", Type: "text"},
+				{Name: "active", Type: "boolean"},
+			},
+			PrimaryKey: []string{"id"},
+		},
+		&schema.Table{
+			Name: "posts",
+			Columns: []*schema.Column{
+				{Name: "id", Type: "integer"},
+				{Name: "user_id", Type: "integer"},
+				{Name: "title", Type: "text"},
+			},
+			PrimaryKey: []string{"id"},
+		},
+	))
+}
+
+// assertProblems checks that problems holds exactly the given kinds, and
+// that each message contains the matching fragment.
+func assertProblems(t *testing.T, problems []Problem, want ...any) {
+	t.Helper()
+	if len(problems) != len(want)/2 {
+		t.Fatalf("expected %d problems, got %d: %v", len(want)/2, len(problems), problems)
+	}
+	for i, p := range problems {
+		kind, fragment := want[2*i].(Kind), want[2*i+1].(string)
+		if p.Kind != kind || !strings.Contains(p.Message, fragment) {
+			t.Errorf("problem %d: expected %s containing %q, got %s: %q", i, kind, fragment, p.Kind, p.Message)
+		}
+	}
+}
+
+func TestValidQuery(t *testing.T) {
+	t.Parallel()
+	sub := managers.NewSelectManager(posts).Select(posts.Col("user_id")).Where(posts.Col("user_id").Eq(users.Col("id")))
+	q := managers.NewSelectManager(users).
+		Select(users.Col("id"), users.Col("email")).
+		Where(users.Col("active").Eq(true), nodes.Exists(sub.Core), users.Col("email").Eq("a@example.com")).
+		Order(users.Col("email").Asc())
+	assertProblems(t, testValidator().Check(q.Core))
+}
+
+func TestUnknownTableAndColumn(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	q := managers.NewSelectManager(nodes.NewTable("people"))
+	assertProblems(t, v.Check(q.Core), UnknownTable, `table "people" does not exist`)
+
+	q = managers.NewSelectManager(users).Select(users.Col("emial"))
+	assertProblems(t, v.Check(q.Core), UnknownColumn, `column "users"."emial" does not exist`)
+
+	q = managers.NewSelectManager(users).Select(posts.Col("title"))
+	assertProblems(t, v.Check(q.Core), UnknownTable, `table "posts" is not in the FROM clause`)
+
+	u := users.Alias("u")
+	q = managers.NewSelectManager(u).Select(u.Col("email"), users.Col("id"))
+	assertProblems(t, v.Check(q.Core), UnknownTable, `table "users" is not in the FROM clause`)
+}
+
+func TestAmbiguousReferences(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	q := managers.NewSelectManager(users).Join(posts).On(posts.Col("user_id").Eq(users.Col("id"))).
+		Select(nodes.NewAttribute(nil, "id"), nodes.NewAttribute(nil, "title"))
+	assertProblems(t, v.Check(q.Core), AmbiguousReference, `column "id" is ambiguous: it is in users, posts`)
+
+	q = managers.NewSelectManager(users).Join(users).On(users.Col("id").Eq(users.Col("id")))
+	assertProblems(t, v.Check(q.Core), AmbiguousReference, `table name "users" is specified more than once`)
+
+	q = managers.NewSelectManager(users).Select(nodes.NewAttribute(nil, "nope"))
+	assertProblems(t, v.Check(q.Core), UnknownColumn, `column "nope" does not exist`)
+}
+
+func TestDerivedTablesAndCTEs(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	recent := managers.NewSelectManager(posts).Select(posts.Col("user_id"), nodes.Count(nil).As("n")).Group(posts.Col("user_id"))
+	r := recent.As("r")
+	q := managers.NewSelectManager(r).Select(r.Col("user_id"), r.Col("n"), r.Col("title"))
+	assertProblems(t, v.Check(q.Core), UnknownColumn, `column "r"."title" does not exist`)
+
+	cte := nodes.NewTable("active")
+	q = managers.NewSelectManager(cte).
+		With("active", managers.NewSelectManager(users).Select(users.Col("id")).Where(users.Col("active").Eq(true)).Core).
+		Select(cte.Col("id"), cte.Col("email"))
+	assertProblems(t, v.Check(q.Core), UnknownColumn, `column "active"."email" does not exist`)
+}
+
+func TestAnalyticalClauses(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	inner := managers.NewSelectManager(users).Select(users.Star().Excluding("email"))
+	d := inner.As("d")
+	q := managers.NewSelectManager(d).Select(d.Col("id"), d.Col("email"))
+	assertProblems(t, v.Check(q.Core), UnknownColumn, `column "d"."email" does not exist`)
+
+	q = managers.NewSelectManager(users).
+		Select(users.Star().Replacing(nodes.Lower(users.Col("emial")).As("email"))).
+		Qualify(users.Col("nope").Eq(1)).
+		LimitBy(1, users.Col("active"))
+	assertProblems(t, v.Check(q.Core),
+		UnknownColumn, `column "users"."emial" does not exist`,
+		UnknownColumn, `column "users"."nope" does not exist`)
+
+	q = managers.NewSelectManager(posts).ArrayJoin(posts.Col("tags"))
+	assertProblems(t, v.Check(q.Core), UnknownColumn, `column "posts"."tags" does not exist`)
+}
+
+// within is an extension node exposing its children through nodes.Parent.
+type within struct{ geom, point nodes.Node }
+
+func (n *within) Accept(v nodes.Visitor) string     { return v.VisitExtension(n) }
+func (n *within) RenderSQL(ctx nodes.RenderContext) {}
+func (n *within) Children() []nodes.Node            { return []nodes.Node{n.geom, n.point} }
+
+func TestExtensionChildren(t *testing.T) {
+	t.Parallel()
+	q := managers.NewSelectManager(users).Where(&within{users.Col("location"), nodes.Named("here")})
+	assertProblems(t, testValidator().Check(q.Core), UnknownColumn, `column "users"."location" does not exist`)
+}
+
+func TestRawSQLIsNotReported(t *testing.T) {
+	t.Parallel()
+	q := managers.NewSelectManager(nodes.NewSqlLiteral("generate_series(1, 3) AS g")).
+		Select(nodes.NewTable("g").Col("n"))
+	assertProblems(t, testValidator().Check(q.Core))
+}
+
+func TestInsertColumnCounts(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	m := managers.NewInsertManager(users).Columns(users.Col("id"), users.Col("email")).Values(1, "a").Values(2)
+	assertProblems(t, v.Check(m.Statement), ColumnCount, "INSERT row 2 has 1 values for 2 columns")
+
+	m = managers.NewInsertManager(users).Values(1, "a")
+	assertProblems(t, v.Check(m.Statement), ColumnCount, "INSERT row 1 has 2 values for 3 columns")
+
+	m = managers.NewInsertManager(users).Columns(users.Col("id"), users.Col("mail")).Values(1, "a")
+	assertProblems(t, v.Check(m.Statement), UnknownColumn, `column "mail" of table "users" does not exist`)
+
+	m = managers.NewInsertManager(posts).Columns(posts.Col("user_id"), posts.Col("title")).
+		FromSelect(managers.NewSelectManager(users).Select(users.Col("id")))
+	assertProblems(t, v.Check(m.Statement), ColumnCount, "INSERT has 2 columns but its SELECT returns 1")
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	t.Parallel()
+	excluded := nodes.NewTable("excluded")
+	m := managers.NewInsertManager(users).Columns(users.Col("id"), users.Col("email")).Values(1, "a")
+	m.OnConflict(users.Col("id")).DoUpdate(&nodes.AssignmentNode{Left: users.Col("email"), Right: excluded.Col("email")})
+	assertProblems(t, testValidator().Check(m.Statement))
+
+	m.OnConflict(users.Col("id")).DoUpdate(&nodes.AssignmentNode{Left: users.Col("email"), Right: excluded.Col("mail")})
+	assertProblems(t, testValidator().Check(m.Statement), UnknownColumn, `column "excluded"."mail" does not exist`)
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	u := managers.NewUpdateManager(users).Set(users.Col("name"), "x").Where(users.Col("id").Eq(1))
+	assertProblems(t, v.Check(u.Statement), UnknownColumn, `column "name" of table "users" does not exist`)
+
+	d := managers.NewDeleteManager(posts).Where(posts.Col("author").Eq(1))
+	assertProblems(t, v.Check(d.Statement), UnknownColumn, `column "posts"."author" does not exist`)
+}
+
+func TestSetOperationArity(t *testing.T) {
+	t.Parallel()
+	left := managers.NewSelectManager(users).Select(users.Col("id"), users.Col("email"))
+	right := managers.NewSelectManager(posts).Select(posts.Col("id"))
+	assertProblems(t, testValidator().Check(left.Union(right)),
+		SetOperationArity, "UNION: left query returns 2 columns, right query returns 1")
+
+	// SELECT * expands to the table's columns.
+	all := managers.NewSelectManager(users)
+	assertProblems(t, testValidator().Check(all.Except(managers.NewSelectManager(posts))))
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	q := managers.NewSelectManager(posts).Select(posts.Col("user_id"), posts.Col("title"), nodes.Count(nil)).Group(posts.Col("user_id"))
+	assertProblems(t, v.Check(q.Core), GroupBy, "column posts.title must appear in GROUP BY")
+
+	q = managers.NewSelectManager(posts).Select(posts.Col("title"), nodes.Max(posts.Col("id")))
+	assertProblems(t, v.Check(q.Core), GroupBy, "column posts.title must appear in GROUP BY")
+
+	q = managers.NewSelectManager(posts).Select(nodes.Count(nil), nodes.Max(posts.Col("id")))
+	assertProblems(t, v.Check(q.Core))
+
+	// Grouping by the primary key makes every column of the table usable.
+	q = managers.NewSelectManager(users).Join(posts).On(posts.Col("user_id").Eq(users.Col("id"))).
+		Select(users.Col("email"), nodes.Count(posts.Col("id"))).Group(users.Col("id"))
+	assertProblems(t, v.Check(q.Core))
+
+	q = managers.NewSelectManager(users).Group(users.Col("email"))
+	assertProblems(t, v.Check(q.Core), GroupBy, "SELECT * is not allowed")
+}
+
+func TestTypeMismatch(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	q := managers.NewSelectManager(users).Where(users.Col("email").Eq(42))
+	assertProblems(t, v.Check(q.Core), TypeMismatch, "cannot compare users.email (text) with 42 (integer)")
+
+	q = managers.NewSelectManager(users).Join(posts).On(posts.Col("title").Eq(users.Col("id")))
+	assertProblems(t, v.Check(q.Core), TypeMismatch, "cannot compare posts.title (text) with users.id (integer)")
+
+	// TypeName on the attribute takes precedence over the schema.
+	token := users.Col("email").Typed("uuid")
+	q = managers.NewSelectManager(users).Join(posts).On(token.In(posts.Col("title")))
+	assertProblems(t, v.Check(q.Core), TypeMismatch, "cannot compare users.email (uuid) with posts.title (text)")
+
+	// Quoted literals are coerced, so strings compare with anything.
+	q = managers.NewSelectManager(users).Where(users.Col("id").Eq("42"), users.Col("active").Between(false, true))
+	assertProblems(t, v.Check(q.Core))
+}
+
+func TestTransformer(t *testing.T) {
+	t.Parallel()
+	v := testValidator()
+
+	q := managers.NewSelectManager(users).Select(users.Col("emial")).Use(v)
+	_, _, err := q.ToSQL(visitors.NewPostgresVisitor())
+	var verr *Error
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	testutil.AssertEqual(t, len(verr.Problems), 1)
+	testutil.AssertEqual(t, err.Error(), `validate: column "users"."emial" does not exist`)
+
+	sql, _, err := managers.NewSelectManager(users).Select(users.Col("email")).Use(v).ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT "users"."email" FROM "users"`)
+
+	_, _, err = managers.NewDeleteManager(users).Where(users.Col("x").Eq(1)).Use(v).ToSQL(visitors.NewPostgresVisitor())
+	testutil.AssertError(t, err)
+}
@@ -0,0 +1,170 @@
+package visitors
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// analyticsCases are the analytical queries rendered by
+// TestAnalyticsGolden.
+func analyticsCases() []struct {
+	name string
+	stmt nodes.Node
+} {
+	events := nodes.NewTable("events")
+	e := events.Alias("e")
+	users := nodes.NewTable("users")
+	rank := nodes.RowNumber().Over(&nodes.WindowDefinition{
+		PartitionBy: []nodes.Node{e.Col("user_id")},
+		OrderBy:     []nodes.Node{e.Col("at").Desc()},
+	})
+	return []struct {
+		name string
+		stmt nodes.Node
+	}{
+		{"final", &nodes.SelectCore{
+			From:        events,
+			Final:       true,
+			Projections: []nodes.Node{events.Col("id")},
+		}},
+		{"sample ratio", &nodes.SelectCore{
+			From:        e,
+			Sample:      &nodes.SampleClause{Ratio: 0.1},
+			Projections: []nodes.Node{nodes.Count(nil)},
+			Joins:       []*nodes.JoinNode{{Type: nodes.InnerJoin, Right: users, On: users.Col("id").Eq(e.Col("user_id"))}},
+		}},
+		{"sample rows", &nodes.SelectCore{
+			From:   events,
+			Sample: &nodes.SampleClause{Rows: 100000},
+		}},
+		{"sample offset", &nodes.SelectCore{
+			From:   events,
+			Sample: &nodes.SampleClause{Ratio: 0.25, Offset: 0.5},
+		}},
+		{"sample method", &nodes.SelectCore{
+			From:   events,
+			Sample: &nodes.SampleClause{Ratio: 0.01, Method: "system"},
+		}},
+		{"limit by", &nodes.SelectCore{
+			From:        events,
+			Projections: []nodes.Node{events.Col("user_id"), events.Col("url")},
+			Orders:      []nodes.Node{events.Col("at").Desc()},
+			LimitBy:     &nodes.LimitByClause{Limit: nodes.Literal(3), Columns: []nodes.Node{events.Col("user_id")}},
+			Limit:       nodes.Literal(100),
+		}},
+		{"limit offset by", &nodes.SelectCore{
+			From:    events,
+			LimitBy: &nodes.LimitByClause{Limit: nodes.Literal(1), Offset: nodes.Literal(1), Columns: []nodes.Node{events.Col("user_id"), events.Col("day")}},
+		}},
+		{"array join", &nodes.SelectCore{
+			From:        events,
+			Projections: []nodes.Node{events.Col("id"), events.Col("tags")},
+			Joins:       []*nodes.JoinNode{{Type: nodes.ArrayJoin, Right: events.Col("tags")}},
+		}},
+		{"left array join", &nodes.SelectCore{
+			From:  events,
+			Joins: []*nodes.JoinNode{{Type: nodes.LeftArrayJoin, Right: events.Col("tags").As("tag")}},
+		}},
+		{"qualify", &nodes.SelectCore{
+			From:        e,
+			Projections: []nodes.Node{e.Col("user_id"), e.Col("url")},
+			Qualifies:   []nodes.Node{rank.Eq(1)},
+		}},
+		{"star exclude", &nodes.SelectCore{
+			From:        events,
+			Projections: []nodes.Node{nodes.Star().Excluding("payload", "ip")},
+		}},
+		{"star replace", &nodes.SelectCore{
+			From: events,
+			Projections: []nodes.Node{events.Star().Excluding("ip").Replacing(
+				nodes.Lower(events.Col("url")).As("url"),
+			)},
+		}},
+		{"aggregates", &nodes.SelectCore{
+			From: events,
+			Projections: []nodes.Node{
+				events.Col("user_id"),
+				nodes.ArgMax(events.Col("url"), events.Col("at")).As("last_url"),
+				nodes.ArgMin(events.Col("url"), events.Col("at")).As("first_url"),
+				nodes.AnyValue(events.Col("country")),
+				nodes.ApproxCountDistinct(events.Col("session_id")),
+			},
+			Groups: []nodes.Node{events.Col("user_id")},
+		}},
+		{"sample qualify exclude", &nodes.SelectCore{
+			From:        e,
+			Sample:      &nodes.SampleClause{Ratio: 0.2},
+			Projections: []nodes.Node{nodes.Star().Excluding("payload")},
+			Wheres:      []nodes.Node{e.Col("day").GtEq("2026-01-01")},
+			Qualifies:   []nodes.Node{rank.LtEq(5)},
+			Orders:      []nodes.Node{e.Col("user_id").Asc()},
+			Limit:       nodes.Literal(50),
+		}},
+		{"everything", &nodes.SelectCore{
+			From:        events,
+			Final:       true,
+			Sample:      &nodes.SampleClause{Ratio: 0.5},
+			Projections: []nodes.Node{events.Col("user_id"), nodes.Count(nil)},
+			Wheres:      []nodes.Node{events.Col("day").GtEq("2026-01-01")},
+			Groups:      []nodes.Node{events.Col("user_id")},
+			Qualifies:   []nodes.Node{nodes.Count(nil).Gt(10)},
+			Orders:      []nodes.Node{events.Col("user_id").Asc()},
+			LimitBy:     &nodes.LimitByClause{Limit: nodes.Literal(1), Columns: []nodes.Node{events.Col("user_id")}},
+		}},
+	}
+}
+
+// TestAnalyticsGolden renders analyticsCases in the ClickHouse and DuckDB
+// dialects and compares the output with testdata/analytics.golden. Run
+// with -update to rewrite the golden file.
+func TestAnalyticsGolden(t *testing.T) {
+	t.Parallel()
+	dialects := []struct {
+		name string
+		d    *Dialect
+	}{
+		{"clickhouse", NewClickHouseVisitor(WithoutParams()).Dialect()},
+		{"duckdb", NewDuckDBVisitor(WithoutParams()).Dialect()},
+	}
+	var out strings.Builder
+	for i, c := range analyticsCases() {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "-- %s\n", c.name)
+		for _, d := range dialects {
+			sql, err := renderDDL(d.d, c.stmt)
+			if err != nil {
+				fmt.Fprintf(&out, "%s: error: %v\n", d.name, err)
+				continue
+			}
+			fmt.Fprintf(&out, "%s: %s\n", d.name, sql)
+		}
+	}
+
+	golden := filepath.Join("testdata", "analytics.golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(out.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(want) {
+		t.Errorf("output differs from %s (run with -update to rewrite):\n%s", golden, out.String())
+	}
+}
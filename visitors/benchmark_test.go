@@ -123,3 +123,21 @@ func BenchmarkMySQL(b *testing.B) {
 		_, _, _ = m.ToSQL(v)
 	}
 }
+
+// BenchmarkSharedDialectParallel benchmarks one Dialect shared by goroutines.
+func BenchmarkSharedDialectParallel(b *testing.B) {
+	users := nodes.NewTable("users")
+	m := managers.NewSelectManager(users).
+		Select(users.Col("id"), users.Col("name")).
+		Where(users.Col("active").Eq(true)).
+		Where(users.Col("role").In("admin", "editor")).
+		Limit(10)
+	d := NewPostgresVisitor().Dialect()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _, _ = m.ToSQL(d)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package visitors
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// ClickHouseVisitor generates ClickHouse SQL.
+// Identifiers are quoted with backticks: `table`.`column`.
+//
+// Bind parameters are typed server-side placeholders named after their
+// position, {p1:String}, {p2:Int64}, or after a named parameter,
+// {from:Date}; the type is taken from the Go value, or from the TypeName
+// of a named parameter. The returned params are sql.NamedArg values
+// carrying the placeholder names. SELECT supports FINAL,
+// SAMPLE, ARRAY JOIN, QUALIFY and LIMIT n BY. Row locks, RETURNING,
+// ON CONFLICT and LATERAL joins are rejected.
+type ClickHouseVisitor struct {
+	*BaseVisitor
+}
+
+// NewClickHouseVisitor creates a ClickHouseVisitor ready for use.
+// Parameterized mode is enabled by default for SQL injection protection.
+// Pass WithoutParams() to disable (not recommended for production).
+func NewClickHouseVisitor(opts ...Option) *ClickHouseVisitor {
+	v := &ClickHouseVisitor{}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "ClickHouse",
+		quote:            writeBacktickQuoted,
+		plainIdent:       isMixedIdent,
+		reserved:         clickHouseReserved,
+		writePlaceholder: writeClickHousePlaceholder,
+		numberedParams:   true,
+		namedArgs:        true,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			noPartialIndex:     true,
+			noDropCascade:      true,
+			viewIfNotExists:    true,
+			noGeneratedColumns: true,
+		},
+		comparison:    clickHouseComparison,
+		explain:       explainClickHouse,
+		function:      clickHouseFunction,
+		upsert:        clickHouseUpsert,
+		finalModifier: true,
+		sample:        clickHouseSample,
+		arrayJoin:     true,
+		qualify:       true,
+		limitBy:       true,
+		starExclude:   "EXCEPT",
+		noLocks:       true,
+		noReturning:   true,
+		noLateral:     true,
+	}}
+	v.applyOptions(opts)
+	return v
+}
+
+// writeClickHousePlaceholder writes a typed placeholder, {p1:String} for
+// the value at index 1 or {from:Date} for the named parameter "from".
+func writeClickHousePlaceholder(sb *strings.Builder, index int, val any) {
+	typ := clickHouseType(val)
+	if typ == "" {
+		feature := fmt.Sprintf("bind parameters of type %T", val)
+		if _, ok := val.(*nodes.NamedParamNode); ok {
+			feature = "named parameters without a type"
+		}
+		panic(&nodes.UnsupportedError{Dialect: "ClickHouse", Feature: feature})
+	}
+	if n, ok := val.(*nodes.NamedParamNode); ok && isPositionalName(n.Name) {
+		renderErrorf("named parameter %q clashes with the positional placeholder names", n.Name)
+	}
+	sb.WriteByte('{')
+	sb.WriteString(placeholderName(index, val))
+	sb.WriteByte(':')
+	sb.WriteString(typ)
+	sb.WriteByte('}')
+}
+
+// isPositionalName reports whether name has the p<n> form given to
+// positional placeholders.
+func isPositionalName(name string) bool {
+	_, err := strconv.Atoi(strings.TrimPrefix(name, "p"))
+	return len(name) > 1 && name[0] == 'p' && err == nil
+}
+
+// clickHouseType returns the ClickHouse type of a bound value, or "" when
+// there is none.
+func clickHouseType(val any) string {
+	switch v := val.(type) {
+	case *nodes.NamedParamNode:
+		return v.TypeName
+	case string, []byte:
+		return "String"
+	case bool:
+		return "Bool"
+	case int, int64:
+		return "Int64"
+	case int8:
+		return "Int8"
+	case int16:
+		return "Int16"
+	case int32:
+		return "Int32"
+	case uint, uint64:
+		return "UInt64"
+	case uint8:
+		return "UInt8"
+	case uint16:
+		return "UInt16"
+	case uint32:
+		return "UInt32"
+	case float32:
+		return "Float32"
+	case float64:
+		return "Float64"
+	case time.Time:
+		return "DateTime64(6)"
+	}
+	if t := reflect.TypeOf(val); t != nil && t.Kind() == reflect.Slice {
+		if elem := clickHouseType(reflect.Zero(t.Elem()).Interface()); elem != "" {
+			return "Array(" + elem + ")"
+		}
+	}
+	return ""
+}
+
+// clickHouseComparison renders the comparison operators ClickHouse spells
+// differently or lacks.
+func clickHouseComparison(r *renderer, n *nodes.ComparisonNode) bool {
+	switch n.Op {
+	case nodes.OpRegexp, nodes.OpNotRegexp:
+		if n.Op == nodes.OpNotRegexp {
+			r.write("NOT ")
+		}
+		r.write("match(")
+		r.node(n.Left)
+		r.write(", ")
+		r.node(n.Right)
+		r.write(")")
+	case nodes.OpContains, nodes.OpOverlaps:
+		r.unsupported(comparisonOpSQL[n.Op] + " operator")
+	default:
+		return false
+	}
+	return true
+}
+
+// clickHouseFunctions maps the portable names of aggregate functions to
+// their ClickHouse spellings, which are case-sensitive.
+var clickHouseFunctions = map[string]string{
+	"ARG_MAX":               "argMax",
+	"ARG_MIN":               "argMin",
+	"ANY_VALUE":             "any",
+	"APPROX_COUNT_DISTINCT": "uniq",
+}
+
+// clickHouseFunction renames the functions listed in clickHouseFunctions.
+func clickHouseFunction(r *renderer, n *nodes.NamedFunctionNode) bool {
+	name, ok := clickHouseFunctions[strings.ToUpper(n.Name)]
+	if !ok || n.Distinct {
+		return false
+	}
+	r.write(name)
+	r.write("(")
+	r.list(n.Args, ", ")
+	r.write(")")
+	return true
+}
+
+// explainClickHouse writes EXPLAIN, which shows the query plan. The plan
+// can be written as JSON; ClickHouse has no EXPLAIN ANALYZE.
+func explainClickHouse(r *renderer, o nodes.ExplainOptions) {
+	switch {
+	case o.Analyze:
+		r.unsupported("EXPLAIN ANALYZE")
+	case o.Buffers:
+		r.unsupported("EXPLAIN BUFFERS")
+	case o.Verbose:
+		r.unsupported("EXPLAIN VERBOSE")
+	}
+	r.write("EXPLAIN ")
+	if o.Format == nodes.ExplainJSON {
+		r.write("json = 1 ")
+	}
+}
+
+// clickHouseUpsert rejects ON CONFLICT. ClickHouse has no unique
+// constraints to conflict on; tables deduplicate rows with a
+// ReplacingMergeTree engine instead.
+func clickHouseUpsert(r *renderer, _ *nodes.InsertStatement) {
+	r.unsupported("ON CONFLICT")
+}
+
+// clickHouseSample writes SAMPLE k [OFFSET m], where k is a ratio of the
+// rows or an approximate number of rows.
+func clickHouseSample(r *renderer, s *nodes.SampleClause) {
+	if s.Method != "" {
+		r.unsupported("sampling methods")
+	}
+	r.write(" SAMPLE ")
+	if s.Rows > 0 {
+		r.write(strconv.FormatInt(s.Rows, 10))
+	} else {
+		r.write(formatRatio(s.Ratio))
+	}
+	if s.Offset > 0 {
+		r.write(" OFFSET ")
+		r.write(formatRatio(s.Offset))
+	}
+}
+
+// formatRatio writes a sampling ratio without binary rounding noise, so
+// that 0.1 * 100 is written as 10.
+func formatRatio(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e9)/1e9, 'g', -1, 64)
+}
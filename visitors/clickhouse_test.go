@@ -0,0 +1,147 @@
+package visitors
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// Statements translated from PostgreSQL are covered by the golden files in
+// parser/testdata/clickhouse, and the analytical clauses by
+// testdata/analytics.golden.
+
+func TestClickHousePlaceholderTypes(t *testing.T) {
+	t.Parallel()
+	events := nodes.NewTable("events")
+	core := &nodes.SelectCore{
+		From: events,
+		Wheres: []nodes.Node{
+			events.Col("name").Eq("signup"),
+			events.Col("user_id").Eq(int32(7)),
+			events.Col("score").Gt(0.5),
+			events.Col("at").GtEq(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+			events.Col("tag").In("a", "b"),
+			events.Col("ids").Eq([]uint16{1, 2}),
+			events.Col("test").Eq(false),
+		},
+	}
+	sql, params, err := NewClickHouseVisitor().Dialect().Render(core)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "SELECT * FROM `events` WHERE `events`.`name` = {p1:String} AND `events`.`user_id` = {p2:Int32} "+
+		"AND `events`.`score` > {p3:Float64} AND `events`.`at` >= {p4:DateTime64(6)} AND `events`.`tag` IN ({p5:String}, {p6:String}) "+
+		"AND `events`.`ids` = {p7:Array(UInt16)} AND `events`.`test` = {p8:Bool}")
+	testutil.AssertEqual(t, len(params), 8)
+}
+
+func TestClickHouseNamedParams(t *testing.T) {
+	t.Parallel()
+	events := nodes.NewTable("events")
+	from := nodes.Named("from").WithType("Date")
+	core := &nodes.SelectCore{
+		From: events,
+		Wheres: []nodes.Node{
+			events.Col("day").GtEq(from),
+			events.Col("first_seen").GtEq(from),
+		},
+	}
+	core.Wheres = append(core.Wheres, events.Col("name").Eq("signup"))
+	query, params, err := NewClickHouseVisitor().Dialect().Render(core)
+	testutil.AssertNoError(t, err)
+	// A repeated name reuses its placeholder; other values are named
+	// after their position.
+	testutil.AssertEqual(t, query, "SELECT * FROM `events` WHERE `events`.`day` >= {from:Date} AND `events`.`first_seen` >= {from:Date} "+
+		"AND `events`.`name` = {p2:String}")
+	testutil.AssertEqual(t, len(params), 2)
+	testutil.AssertEqual(t, params[0], any(sql.Named("from", from)))
+	testutil.AssertEqual(t, params[1], any(sql.Named("p2", "signup")))
+
+	_, err = renderDDL(NewClickHouseVisitor().Dialect(), &nodes.SelectCore{
+		From:   events,
+		Wheres: []nodes.Node{events.Col("day").Eq(nodes.Named("day"))},
+	})
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+	}
+	testutil.AssertEqual(t, ue.Feature, "named parameters without a type")
+
+	_, err = renderDDL(NewClickHouseVisitor().Dialect(), &nodes.SelectCore{
+		From:   events,
+		Wheres: []nodes.Node{events.Col("day").Eq(nodes.Named("p2").WithType("Date"))},
+	})
+	var re *nodes.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected *nodes.RenderError, got %v", err)
+	}
+}
+
+func TestClickHouseFunctions(t *testing.T) {
+	t.Parallel()
+	v := NewClickHouseVisitor(WithoutParams())
+	events := nodes.NewTable("events")
+	core := &nodes.SelectCore{
+		From: events,
+		Projections: []nodes.Node{
+			nodes.ArgMax(events.Col("url"), events.Col("at")),
+			nodes.ArgMin(events.Col("url"), events.Col("at")),
+			nodes.AnyValue(events.Col("country")),
+			nodes.ApproxCountDistinct(events.Col("user_id")),
+		},
+		Wheres: []nodes.Node{events.Col("url").MatchesRegexp("^/docs")},
+		Groups: []nodes.Node{events.Col("session_id")},
+	}
+	testutil.AssertSQL(t, v, core, "SELECT argMax(`events`.`url`, `events`.`at`), argMin(`events`.`url`, `events`.`at`), "+
+		"any(`events`.`country`), uniq(`events`.`user_id`) FROM `events` WHERE match(`events`.`url`, '^/docs') GROUP BY `events`.`session_id`")
+}
+
+func TestClickHouseExplain(t *testing.T) {
+	t.Parallel()
+	d := NewClickHouseVisitor().Dialect()
+	sql, err := renderDDL(d, &nodes.ExplainStatement{
+		Statement: &nodes.SelectCore{From: nodes.NewTable("events")},
+		Options:   nodes.ExplainOptions{Format: nodes.ExplainJSON},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "EXPLAIN json = 1 SELECT * FROM `events`")
+}
+
+func TestClickHouseUnsupported(t *testing.T) {
+	t.Parallel()
+	events := nodes.NewTable("events")
+	tests := []struct {
+		name    string
+		stmt    nodes.Node
+		feature string
+	}{
+		{"row lock", &nodes.SelectCore{From: events, Lock: nodes.ForUpdate}, "row locks"},
+		{"returning", &nodes.DeleteStatement{From: events, Returning: []nodes.Node{events.Col("id")}}, "RETURNING"},
+		{"upsert", &nodes.InsertStatement{
+			Into:       events,
+			Columns:    []nodes.Node{events.Col("id")},
+			Values:     [][]nodes.Node{{nodes.Literal(1)}},
+			OnConflict: &nodes.OnConflictNode{Action: nodes.DoNothing},
+		}, "ON CONFLICT"},
+		{"lateral", &nodes.SelectCore{From: events, Joins: []*nodes.JoinNode{{
+			Type: nodes.CrossJoin, Right: &nodes.TableAlias{Relation: &nodes.SelectCore{}, AliasName: "x"}, Lateral: true,
+		}}}, "LATERAL joins"},
+		{"sample method", &nodes.SelectCore{From: events, Sample: &nodes.SampleClause{Ratio: 0.1, Method: "bernoulli"}}, "sampling methods"},
+		{"explain analyze", &nodes.ExplainStatement{Statement: &nodes.SelectCore{From: events}, Options: nodes.ExplainOptions{Analyze: true}}, "EXPLAIN ANALYZE"},
+		{"unknown type", &nodes.SelectCore{From: events, Wheres: []nodes.Node{events.Col("a").Eq(struct{}{})}}, "bind parameters of type struct {}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := renderDDL(NewClickHouseVisitor().Dialect(), tt.stmt)
+			var ue *nodes.UnsupportedError
+			if !errors.As(err, &ue) {
+				t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+			}
+			testutil.AssertEqual(t, ue.Feature, tt.feature)
+			testutil.AssertEqual(t, ue.Dialect, "ClickHouse")
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package visitors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// renderDDL renders n with d, dropping the parameters.
+func renderDDL(d *Dialect, n nodes.Node) (string, error) {
+	sql, _, err := d.Render(n)
+	return sql, err
+}
+
+// --- CREATE TABLE ---
+
+func TestCreateTableGeneratedColumns(t *testing.T) {
+	t.Parallel()
+	items := nodes.NewTable("items")
+	stmt := &nodes.CreateTableStatement{
+		Table: items,
+		Columns: []*nodes.ColumnDef{
+			nodes.NewColumnDef("price", "numeric", nodes.NotNull()),
+			nodes.NewColumnDef("qty", "integer", nodes.Default(1), nodes.Check(items.Col("qty").Gt(0))),
+			nodes.NewColumnDef("total", "numeric", nodes.GeneratedAs(items.Col("price").Multiply(items.Col("qty")))),
+			nodes.NewColumnDef("label", "text", nodes.GeneratedVirtual(nodes.Lower(items.Col("name")))),
+		},
+		Constraints: []*nodes.TableConstraint{
+			nodes.UniqueConstraint("price", "qty").Named("items_price_qty"),
+		},
+	}
+
+	sql, err := renderDDL(NewPostgresVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE TABLE "items" (`+
+		`"price" numeric NOT NULL, `+
+		`"qty" integer DEFAULT 1 CHECK ("qty" > 0), `+
+		`"total" numeric GENERATED ALWAYS AS ("price" * "qty") STORED, `+
+		`"label" text GENERATED ALWAYS AS (LOWER("name")) VIRTUAL, `+
+		`CONSTRAINT "items_price_qty" UNIQUE ("price", "qty"))`)
+}
+
+func TestCreateTableForeignKeyActions(t *testing.T) {
+	t.Parallel()
+	posts := nodes.NewTable("posts")
+	users := nodes.NewTable("users")
+	stmt := &nodes.CreateTableStatement{
+		Table: posts,
+		Columns: []*nodes.ColumnDef{
+			nodes.NewColumnDef("author_id", "bigint"),
+		},
+		Constraints: []*nodes.TableConstraint{
+			nodes.ForeignKeyConstraint([]string{"author_id"}, users, "id").
+				OnDelete(nodes.ActionSetNull).
+				OnUpdate(nodes.ActionRestrict),
+		},
+	}
+
+	sql, err := renderDDL(NewSQLiteVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE TABLE "posts" ("author_id" bigint, `+
+		`FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE SET NULL ON UPDATE RESTRICT)`)
+}
+
+func TestCreateTableRejectsActionsOnOtherConstraints(t *testing.T) {
+	t.Parallel()
+	stmt := &nodes.CreateTableStatement{
+		Table:   nodes.NewTable("posts"),
+		Columns: []*nodes.ColumnDef{nodes.NewColumnDef("id", "bigint")},
+		Constraints: []*nodes.TableConstraint{
+			nodes.PrimaryKeyConstraint("id").OnDelete(nodes.ActionCascade),
+		},
+	}
+	_, err := renderDDL(NewPostgresVisitor().Dialect(), stmt)
+	var re *nodes.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected *nodes.RenderError, got %v", err)
+	}
+}
+
+func TestDDLRejectsBoundSqlLiterals(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	create := &nodes.CreateTableStatement{
+		Table:   users,
+		Columns: []*nodes.ColumnDef{nodes.NewColumnDef("name", "text", nodes.Default(nodes.NewBoundSqlLiteral("lower(?)", "A")))},
+	}
+	view := &nodes.CreateViewStatement{
+		Name:  "active_users",
+		Query: &nodes.SelectCore{From: users, Wheres: []nodes.Node{nodes.NewBoundSqlLiteral("status = ?", "active")}},
+	}
+	for _, n := range []nodes.Node{create, view} {
+		sql, params, err := NewPostgresVisitor().Dialect().Render(n)
+		var re *nodes.RenderError
+		if !errors.As(err, &re) {
+			t.Fatalf("expected *nodes.RenderError, got %q %v %v", sql, params, err)
+		}
+	}
+}
+
+func TestCreateTableRejectsBadTypeName(t *testing.T) {
+	t.Parallel()
+	stmt := &nodes.CreateTableStatement{
+		Table:   nodes.NewTable("t"),
+		Columns: []*nodes.ColumnDef{nodes.NewColumnDef("a", "int); DROP TABLE x; --")},
+	}
+	_, err := renderDDL(NewPostgresVisitor().Dialect(), stmt)
+	var re *nodes.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected *nodes.RenderError, got %v", err)
+	}
+}
+
+// --- ALTER TABLE ---
+
+func TestAlterTablePostgresSplitsRename(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.AlterTableStatement{
+		Table: users,
+		Actions: []*nodes.AlterAction{
+			{Kind: nodes.AlterAddColumn, Column: nodes.NewColumnDef("age", "integer")},
+			{Kind: nodes.AlterDropColumn, Name: "legacy"},
+			{Kind: nodes.AlterRenameColumn, Name: "mail", NewName: "email"},
+		},
+	}
+
+	sql, err := renderDDL(NewPostgresVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `ALTER TABLE "users" ADD COLUMN "age" integer, DROP COLUMN "legacy"; `+
+		`ALTER TABLE "users" RENAME COLUMN "mail" TO "email"`)
+}
+
+func TestAlterTableMySQLAddColumnWithForeignKey(t *testing.T) {
+	t.Parallel()
+	posts := nodes.NewTable("posts")
+	users := nodes.NewTable("users")
+	stmt := &nodes.AlterTableStatement{
+		Table: posts,
+		Actions: []*nodes.AlterAction{
+			{Kind: nodes.AlterAddColumn, Column: nodes.NewColumnDef("user_id", "bigint", nodes.References(users, "id"))},
+		},
+	}
+
+	sql, err := renderDDL(NewMySQLVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "ALTER TABLE `posts` ADD COLUMN `user_id` bigint, "+
+		"ADD FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)")
+}
+
+// --- CREATE INDEX ---
+
+func TestCreateIndexMySQLOnline(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.CreateIndexStatement{
+		Name:         "users_created_idx",
+		Table:        users,
+		Columns:      []nodes.Node{users.Col("org_id"), users.Col("created_at").Desc()},
+		Concurrently: true,
+	}
+
+	sql, err := renderDDL(NewMySQLVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "CREATE INDEX `users_created_idx` ON `users` (`org_id`, `created_at` DESC) ALGORITHM=INPLACE LOCK=NONE")
+}
+
+func TestCreateIndexUnsupportedFeatures(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	tests := []struct {
+		name    string
+		dialect *Dialect
+		stmt    *nodes.CreateIndexStatement
+		feature string
+	}{
+		{"mysql partial", NewMySQLVisitor().Dialect(), &nodes.CreateIndexStatement{
+			Name: "i", Table: users, Columns: []nodes.Node{users.Col("a")},
+			Wheres: []nodes.Node{users.Col("a").IsNull()},
+		}, "partial indexes"},
+		{"mysql if not exists", NewMySQLVisitor().Dialect(), &nodes.CreateIndexStatement{
+			Name: "i", Table: users, Columns: []nodes.Node{users.Col("a")}, IfNotExists: true,
+		}, "CREATE INDEX IF NOT EXISTS"},
+		{"sqlite concurrently", NewSQLiteVisitor().Dialect(), &nodes.CreateIndexStatement{
+			Name: "i", Table: users, Columns: []nodes.Node{users.Col("a")}, Concurrently: true,
+		}, "concurrent index builds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := renderDDL(tt.dialect, tt.stmt)
+			var ue *nodes.UnsupportedError
+			if !errors.As(err, &ue) {
+				t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+			}
+			testutil.AssertEqual(t, ue.Feature, tt.feature)
+		})
+	}
+}
+
+func TestCreateIndexSQLitePartial(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.CreateIndexStatement{
+		Name:        "active_users",
+		Table:       users,
+		Columns:     []nodes.Node{users.Col("email")},
+		IfNotExists: true,
+		Wheres:      []nodes.Node{users.Col("active").Eq(true), users.Col("role").Eq("admin")},
+	}
+
+	sql, err := renderDDL(NewSQLiteVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE INDEX IF NOT EXISTS "active_users" ON "users" ("email") WHERE "active" = TRUE AND "role" = 'admin'`)
+}
+
+// --- DROP ---
+
+func TestDropIndexPostgresConcurrently(t *testing.T) {
+	t.Parallel()
+	stmt := &nodes.DropStatement{Kind: nodes.DropIndex, Name: "users_email_idx", Concurrently: true, IfExists: true}
+	sql, err := renderDDL(NewPostgresVisitor().Dialect(), stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `DROP INDEX CONCURRENTLY IF EXISTS "users_email_idx"`)
+}
+
+func TestDropTableSQLiteRejectsCascade(t *testing.T) {
+	t.Parallel()
+	stmt := &nodes.DropStatement{Kind: nodes.DropTable, Name: "users", Cascade: true}
+	_, err := renderDDL(NewSQLiteVisitor().Dialect(), stmt)
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, err.Error(), "gosbee: SQLite does not support DROP ... CASCADE")
+}
+
+// --- DOT ---
+
+func TestDotVisitCreateTable(t *testing.T) {
+	dv := NewDotVisitor()
+	users := nodes.NewTable("users")
+	stmt := &nodes.CreateTableStatement{
+		Table:       users,
+		Columns:     []*nodes.ColumnDef{nodes.NewColumnDef("id", "bigint", nodes.PrimaryKey())},
+		Constraints: []*nodes.TableConstraint{nodes.UniqueConstraint("id")},
+	}
+	stmt.Accept(dv)
+	dot := dv.ToDot()
+	assertContains(t, dot, `CreateTable\nusers`)
+	assertContains(t, dot, `COLUMN[0]`)
+	assertContains(t, dot, `CONSTRAINT[0]`)
+	assertContains(t, dot, `fillcolor="#F4A460"`)
+}
+
+func TestDotVisitCreateView(t *testing.T) {
+	dv := NewDotVisitor()
+	users := nodes.NewTable("users")
+	stmt := &nodes.CreateViewStatement{
+		Name:         "v",
+		Materialized: true,
+		Query:        &nodes.SelectCore{From: users},
+	}
+	stmt.Accept(dv)
+	dot := dv.ToDot()
+	assertContains(t, dot, `CreateMaterializedView\nv`)
+	assertContains(t, dot, `label="AS"`)
+}
@@ -0,0 +1,271 @@
+package visitors
+
+import (
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// Dialect is the immutable rendering configuration of a SQL dialect. It is
+// built once by a visitor constructor and never modified afterwards, so a
+// single Dialect can be shared by any number of goroutines:
+//
+//	pg := visitors.NewPostgresVisitor().Dialect()
+//	sql, params, err := query.ToSQL(pg) // safe from any goroutine
+//
+// Each call renders with its own per-call context. Dialect also implements
+// nodes.Visitor, but each Visit call then renders its node on its own and
+// discards the bind parameters; use Render to obtain them.
+type Dialect struct {
+	// name is the dialect's display name, used in error messages.
+	name string
+
+	// quote writes a quoted SQL identifier (table name, column name).
+	quote func(sb *strings.Builder, name string)
+
+	// plainIdent reports whether a name can be written without quotes,
+	// before reserved words are considered.
+	plainIdent func(name string) bool
+
+	// reserved holds the dialect's upper-case reserved words.
+	reserved map[string]bool
+
+	// maxIdentLen is the longest identifier the dialect accepts, in bytes;
+	// zero means unlimited.
+	maxIdentLen int
+
+	// quoting, foldLower and checkLength hold the identifier policy set by
+	// WithQuotePolicy, WithLowerCaseIdentifiers and WithIdentifierLengthCheck.
+	quoting     QuotePolicy
+	foldLower   bool
+	checkLength bool
+
+	// writePlaceholder writes the bind placeholder for a 1-based index and
+	// the value bound to it. PostgreSQL uses $1, $2; MySQL/SQLite use ?;
+	// ClickHouse names the value's type, as in {p1:String} or {from:Date}.
+	writePlaceholder func(sb *strings.Builder, index int, val any)
+
+	// numberedParams is true when placeholders carry their index ($1, $2),
+	// allowing a repeated named parameter to reuse its first slot.
+	numberedParams bool
+
+	// namedArgs returns each parameter as a sql.NamedArg named after its
+	// placeholder, for dialects whose placeholders are bound by name.
+	namedArgs bool
+
+	// parameterize enables bind-parameter mode.
+	parameterize bool
+
+	// normalize renders the query shape for fingerprinting: comments are
+	// dropped and value-only IN lists are collapsed.
+	normalize bool
+
+	// ddl describes the dialect's DDL differences.
+	ddl ddlRules
+
+	// explain writes the EXPLAIN prefix for the given options; nil uses
+	// the PostgreSQL form.
+	explain func(r *renderer, o nodes.ExplainOptions)
+
+	// comparison renders dialect-specific comparison operators. It reports
+	// false when the default rendering should be used instead.
+	comparison func(r *renderer, n *nodes.ComparisonNode) bool
+
+	// concat is the string concatenation operator; empty means ||.
+	concat string
+
+	// numericBools writes inline boolean literals as 1 and 0 (SQL Server).
+	numericBools bool
+
+	// plainRecursive writes WITH instead of WITH RECURSIVE (SQL Server).
+	plainRecursive bool
+
+	// limitStyle selects how LIMIT and OFFSET are written.
+	limitStyle limitStyle
+
+	// lockHint writes the row-locking mode of a SELECT as a table hint
+	// after its FROM relation; nil uses a trailing FOR UPDATE clause.
+	lockHint func(r *renderer, n *nodes.SelectCore)
+
+	// outputClause renders RETURNING as an OUTPUT clause reading the
+	// INSERTED and DELETED pseudo-tables (SQL Server).
+	outputClause bool
+
+	// aliasedTargets makes UPDATE and DELETE name an aliased target by its
+	// alias and declare the alias in a FROM clause (SQL Server).
+	aliasedTargets bool
+
+	// upsert renders an INSERT that has an ON CONFLICT clause; nil uses
+	// the PostgreSQL form.
+	upsert func(r *renderer, n *nodes.InsertStatement)
+
+	// returningInto writes RETURNING ... INTO with an OutParam bound for
+	// each returned column (Oracle).
+	returningInto bool
+
+	// function renders dialect-specific spellings of named functions. It
+	// reports false when the default rendering should be used instead.
+	function func(r *renderer, n *nodes.NamedFunctionNode) bool
+
+	// bareTableAlias writes table aliases without AS (Oracle).
+	bareTableAlias bool
+
+	// fromDual adds FROM DUAL to a SELECT without a FROM clause (Oracle).
+	fromDual bool
+
+	// singleRowValues writes a multi-row INSERT as a UNION ALL of
+	// single-row SELECTs, for dialects whose VALUES takes one row (Oracle).
+	singleRowValues bool
+
+	// applyLateral writes LATERAL joins as CROSS APPLY and OUTER APPLY
+	// (Oracle).
+	applyLateral bool
+
+	// minusExcept writes EXCEPT as MINUS and rejects INTERSECT ALL and
+	// EXCEPT ALL (Oracle).
+	minusExcept bool
+
+	// forUpdateOnly rejects locking modes other than FOR UPDATE (Oracle).
+	forUpdateOnly bool
+
+	// noDistinctOn and noAggregateFilter reject DISTINCT ON and aggregate
+	// FILTER clauses (Oracle).
+	noDistinctOn      bool
+	noAggregateFilter bool
+
+	// finalModifier allows FINAL after the FROM table (ClickHouse).
+	finalModifier bool
+
+	// sample writes the SAMPLE clause of a SELECT; nil rejects it.
+	// sampleAfterJoins writes it after the joins rather than straight
+	// after the FROM relation (DuckDB's USING SAMPLE).
+	sample           func(r *renderer, s *nodes.SampleClause)
+	sampleAfterJoins bool
+
+	// arrayJoin allows ARRAY JOIN and LEFT ARRAY JOIN (ClickHouse).
+	arrayJoin bool
+
+	// qualify allows QUALIFY conditions (ClickHouse, DuckDB).
+	qualify bool
+
+	// limitBy allows LIMIT n BY (ClickHouse).
+	limitBy bool
+
+	// starExclude is the keyword that leaves columns out of a star:
+	// EXCLUDE (DuckDB) or EXCEPT (ClickHouse). Empty rejects star
+	// EXCLUDE and REPLACE.
+	starExclude string
+
+	// noLocks rejects row-locking clauses (ClickHouse, DuckDB).
+	noLocks bool
+
+	// noReturning rejects RETURNING clauses (ClickHouse).
+	noReturning bool
+
+	// noLateral rejects LATERAL joins (ClickHouse).
+	noLateral bool
+
+	// overrides are the node renderers added by Override, tried from
+	// last to first before the built-in rendering.
+	overrides []func(r *renderer, n nodes.Node) bool
+}
+
+// limitStyle is the way a dialect limits the rows of a query.
+type limitStyle int
+
+const (
+	// limitKeyword writes LIMIT n OFFSET m.
+	limitKeyword limitStyle = iota
+
+	// limitTop writes TOP (n) after SELECT, or OFFSET m ROWS FETCH NEXT n
+	// ROWS ONLY after ORDER BY when there is an offset (SQL Server).
+	limitTop
+
+	// limitFetch writes OFFSET m ROWS FETCH FIRST n ROWS ONLY (Oracle).
+	limitFetch
+)
+
+// quoteIdent returns name quoted for this dialect.
+func (d *Dialect) quoteIdent(name string) string {
+	var sb strings.Builder
+	d.writeIdent(&sb, name)
+	return sb.String()
+}
+
+// Render generates the SQL and bind parameters for n in a single pass.
+// It implements nodes.Renderer and is safe for concurrent use. A node the
+// dialect cannot render is reported as a *nodes.UnsupportedError or
+// *nodes.RenderError.
+func (d *Dialect) Render(n nodes.Node) (sql string, params []any, err error) {
+	defer catchRenderError(&err)
+	sql, params = d.render(n)
+	return sql, params, nil
+}
+
+// render generates the SQL and bind parameters for n, panicking on a node
+// it cannot render.
+func (d *Dialect) render(n nodes.Node) (string, []any) {
+	r := newRenderer(d)
+	r.node(n)
+	return r.String(), r.params
+}
+
+// visit renders n on its own for the nodes.Visitor methods.
+func (d *Dialect) visit(n nodes.Node) string {
+	sql, _ := d.render(n)
+	return sql
+}
+
+// --- nodes.Visitor implementation ---
+
+func (d *Dialect) VisitTable(n *nodes.Table) string                      { return d.visit(n) }
+func (d *Dialect) VisitTableAlias(n *nodes.TableAlias) string            { return d.visit(n) }
+func (d *Dialect) VisitAttribute(n *nodes.Attribute) string              { return d.visit(n) }
+func (d *Dialect) VisitLiteral(n *nodes.LiteralNode) string              { return d.visit(n) }
+func (d *Dialect) VisitStar(n *nodes.StarNode) string                    { return d.visit(n) }
+func (d *Dialect) VisitSqlLiteral(n *nodes.SqlLiteral) string            { return d.visit(n) }
+func (d *Dialect) VisitComparison(n *nodes.ComparisonNode) string        { return d.visit(n) }
+func (d *Dialect) VisitUnary(n *nodes.UnaryNode) string                  { return d.visit(n) }
+func (d *Dialect) VisitAnd(n *nodes.AndNode) string                      { return d.visit(n) }
+func (d *Dialect) VisitOr(n *nodes.OrNode) string                        { return d.visit(n) }
+func (d *Dialect) VisitNot(n *nodes.NotNode) string                      { return d.visit(n) }
+func (d *Dialect) VisitIn(n *nodes.InNode) string                        { return d.visit(n) }
+func (d *Dialect) VisitBetween(n *nodes.BetweenNode) string              { return d.visit(n) }
+func (d *Dialect) VisitGrouping(n *nodes.GroupingNode) string            { return d.visit(n) }
+func (d *Dialect) VisitJoin(n *nodes.JoinNode) string                    { return d.visit(n) }
+func (d *Dialect) VisitOrdering(n *nodes.OrderingNode) string            { return d.visit(n) }
+func (d *Dialect) VisitSelectCore(n *nodes.SelectCore) string            { return d.visit(n) }
+func (d *Dialect) VisitInsertStatement(n *nodes.InsertStatement) string  { return d.visit(n) }
+func (d *Dialect) VisitUpdateStatement(n *nodes.UpdateStatement) string  { return d.visit(n) }
+func (d *Dialect) VisitDeleteStatement(n *nodes.DeleteStatement) string  { return d.visit(n) }
+func (d *Dialect) VisitAssignment(n *nodes.AssignmentNode) string        { return d.visit(n) }
+func (d *Dialect) VisitOnConflict(n *nodes.OnConflictNode) string        { return d.visit(n) }
+func (d *Dialect) VisitInfix(n *nodes.InfixNode) string                  { return d.visit(n) }
+func (d *Dialect) VisitUnaryMath(n *nodes.UnaryMathNode) string          { return d.visit(n) }
+func (d *Dialect) VisitAggregate(n *nodes.AggregateNode) string          { return d.visit(n) }
+func (d *Dialect) VisitExtract(n *nodes.ExtractNode) string              { return d.visit(n) }
+func (d *Dialect) VisitWindowFunction(n *nodes.WindowFuncNode) string    { return d.visit(n) }
+func (d *Dialect) VisitOver(n *nodes.OverNode) string                    { return d.visit(n) }
+func (d *Dialect) VisitExists(n *nodes.ExistsNode) string                { return d.visit(n) }
+func (d *Dialect) VisitSetOperation(n *nodes.SetOperationNode) string    { return d.visit(n) }
+func (d *Dialect) VisitCTE(n *nodes.CTENode) string                      { return d.visit(n) }
+func (d *Dialect) VisitNamedFunction(n *nodes.NamedFunctionNode) string  { return d.visit(n) }
+func (d *Dialect) VisitCase(n *nodes.CaseNode) string                    { return d.visit(n) }
+func (d *Dialect) VisitGroupingSet(n *nodes.GroupingSetNode) string      { return d.visit(n) }
+func (d *Dialect) VisitAlias(n *nodes.AliasNode) string                  { return d.visit(n) }
+func (d *Dialect) VisitBindParam(n *nodes.BindParamNode) string          { return d.visit(n) }
+func (d *Dialect) VisitCasted(n *nodes.CastedNode) string                { return d.visit(n) }
+func (d *Dialect) VisitNamedParam(n *nodes.NamedParamNode) string        { return d.visit(n) }
+func (d *Dialect) VisitCreateTable(n *nodes.CreateTableStatement) string { return d.visit(n) }
+func (d *Dialect) VisitAlterTable(n *nodes.AlterTableStatement) string   { return d.visit(n) }
+func (d *Dialect) VisitCreateIndex(n *nodes.CreateIndexStatement) string { return d.visit(n) }
+func (d *Dialect) VisitDrop(n *nodes.DropStatement) string               { return d.visit(n) }
+func (d *Dialect) VisitCreateView(n *nodes.CreateViewStatement) string   { return d.visit(n) }
+func (d *Dialect) VisitCreateTableAs(n *nodes.CreateTableAsStatement) string {
+	return d.visit(n)
+}
+func (d *Dialect) VisitRefreshMaterializedView(n *nodes.RefreshMaterializedViewStatement) string {
+	return d.visit(n)
+}
+func (d *Dialect) VisitExplain(n *nodes.ExplainStatement) string { return d.visit(n) }
+func (d *Dialect) VisitExtension(n nodes.Extension) string       { return d.visit(n) }
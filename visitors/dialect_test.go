@@ -0,0 +1,131 @@
+package visitors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func TestDialectRender(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	n := users.Col("name").Eq(nodes.NewBindParam("Alice")).And(users.Col("age").Gt(nodes.NewBindParam(18)))
+
+	v := NewPostgresVisitor()
+	sql, params, err := v.Dialect().Render(n)
+	testutil.AssertNoError(t, err)
+	if sql != `"users"."name" = $1 AND "users"."age" > $2` {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+	if len(params) != 2 || params[0] != "Alice" || params[1] != 18 {
+		t.Errorf("unexpected params: %v", params)
+	}
+	if len(v.Params()) != 0 {
+		t.Errorf("Render should not touch visitor params, got %v", v.Params())
+	}
+
+	// A second render starts numbering from $1 again.
+	sql, _, err = v.Dialect().Render(n)
+	testutil.AssertNoError(t, err)
+	if sql != `"users"."name" = $1 AND "users"."age" > $2` {
+		t.Errorf("unexpected SQL on second render: %s", sql)
+	}
+}
+
+func TestDialectRenderReturnsErrors(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+
+	sql, params, err := NewPostgresVisitor(WithoutParams()).Dialect().Render(users.Col("id").Eq(struct{}{}))
+	var re *nodes.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected *nodes.RenderError, got %v", err)
+	}
+	testutil.AssertEqual(t, sql, "")
+	testutil.AssertEqual(t, len(params), 0)
+
+	_, _, err = NewMSSQLVisitor().Dialect().Render(&nodes.ExplainStatement{Statement: &nodes.SelectCore{From: users}})
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+	}
+}
+
+func TestDialectWithoutParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	sql, params, err := NewMySQLVisitor(WithoutParams()).Dialect().Render(users.Col("name").Eq("O'Brien"))
+	testutil.AssertNoError(t, err)
+	if sql != "`users`.`name` = 'O''Brien'" {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+	if len(params) != 0 {
+		t.Errorf("expected no params, got %v", params)
+	}
+}
+
+func TestDialectAcceptDiscardsParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	got := users.Col("id").Eq(1).Accept(NewSQLiteVisitor().Dialect())
+	if got != `"users"."id" = ?` {
+		t.Errorf("unexpected SQL: %s", got)
+	}
+}
+
+func TestDialectToSQL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewSelectManager(users).
+		Select(users.Col("id")).
+		Where(users.Col("role").In("admin", "editor"))
+
+	sql, params, err := m.ToSQL(NewPostgresVisitor().Dialect())
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+	want := `SELECT "users"."id" FROM "users" WHERE "users"."role" IN ($1, $2)`
+	if sql != want {
+		t.Errorf("expected:\n  %s\ngot:\n  %s", want, sql)
+	}
+	if len(params) != 2 {
+		t.Errorf("expected 2 params, got %v", params)
+	}
+}
+
+func TestDialectConcurrentToSQL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	d := NewPostgresVisitor().Dialect()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := range 64 {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			m := managers.NewSelectManager(users).
+				Where(users.Col("id").Eq(id)).
+				Where(users.Col("name").Eq(fmt.Sprint("user", id)))
+			sql, params, err := m.ToSQL(d)
+			if err != nil {
+				errs <- err
+				return
+			}
+			want := `SELECT * FROM "users" WHERE "users"."id" = $1 AND "users"."name" = $2`
+			if sql != want || len(params) != 2 || params[0] != id {
+				errs <- fmt.Errorf("goroutine %d: got %s %v", id, sql, params)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
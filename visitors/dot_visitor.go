@@ -20,6 +20,7 @@ const (
 	colorAssignment = "#FF6961" // red — assignments, DML
 	colorArithmetic = "#98FB98" // mint green — arithmetic, math
 	colorFunction   = "#87CEEB" // sky blue — aggregates, functions
+	colorDDL        = "#F4A460" // sandy brown — DDL statements, column definitions
 )
 
 // dotNode represents a single node in the DOT graph.
@@ -411,14 +412,27 @@ func (dv *DotVisitor) VisitStar(n *nodes.StarNode) string {
 	} else {
 		label = "Star\\n*"
 	}
+	if len(n.Exclude) > 0 {
+		label += "\\nEXCLUDE " + strings.Join(n.Exclude, ", ")
+	}
 	id := dv.addNode(label, colorAttribute)
 	dv.connectToParent(id)
+	for i, a := range n.Replace {
+		dv.visitChild(id, fmt.Sprintf("REPLACE[%d]", i), a)
+	}
 	return id
 }
 
 func (dv *DotVisitor) VisitSqlLiteral(n *nodes.SqlLiteral) string {
 	id := dv.addNode("SqlLiteral\\n"+string(n.Raw), colorLiteral)
 	dv.connectToParent(id)
+	if n.Placeholders {
+		for i, arg := range n.Binds {
+			if child, ok := arg.(nodes.Node); ok {
+				dv.visitChild(id, fmt.Sprintf("ARG[%d]", i), child)
+			}
+		}
+	}
 	return id
 }
 
@@ -568,14 +582,30 @@ func (dv *DotVisitor) VisitSelectCore(n *nodes.SelectCore) string {
 	if n.From != nil {
 		dv.visitChild(id, "FROM", n.From)
 	}
+	if n.Final {
+		finalID := dv.addNode("FINAL", colorLogical)
+		dv.addEdge(id, finalID, "FINAL")
+	}
+	dv.visitDotSample(id, n.Sample)
 
 	dv.visitChildList(id, "SELECT", n.Projections)
 	dv.visitJoinsWithProvenance(id, n.Joins, n.Wheres)
 	dv.visitChildList(id, "GROUP", n.Groups)
 	dv.visitChildList(id, "HAVING", n.Havings)
 	dv.visitDotWindows(id, n.Windows)
+	dv.visitChildList(id, "QUALIFY", n.Qualifies)
 	dv.visitChildList(id, "ORDER", n.Orders)
 
+	if lb := n.LimitBy; lb != nil {
+		lbID := dv.addNode("LIMIT BY", colorLogical)
+		dv.addEdge(id, lbID, "LIMIT BY")
+		dv.visitChild(lbID, "LIMIT", lb.Limit)
+		if lb.Offset != nil {
+			dv.visitChild(lbID, "OFFSET", lb.Offset)
+		}
+		dv.visitChildList(lbID, "BY", lb.Columns)
+	}
+
 	if n.Limit != nil {
 		dv.visitChild(id, "LIMIT", n.Limit)
 	}
@@ -609,6 +639,24 @@ func (dv *DotVisitor) visitDotHints(parentID string, hints []string) {
 	}
 }
 
+func (dv *DotVisitor) visitDotSample(parentID string, s *nodes.SampleClause) {
+	if s == nil {
+		return
+	}
+	label := fmt.Sprintf("Sample\\n%v", s.Ratio)
+	if s.Rows > 0 {
+		label = fmt.Sprintf("Sample\\n%d rows", s.Rows)
+	}
+	if s.Offset > 0 {
+		label += fmt.Sprintf("\\nOFFSET %v", s.Offset)
+	}
+	if s.Method != "" {
+		label += "\\n" + s.Method
+	}
+	sampleID := dv.addNode(label, colorLogical)
+	dv.addEdge(parentID, sampleID, "SAMPLE")
+}
+
 func (dv *DotVisitor) visitDotDistinct(parentID string, distinct bool, distinctOn []nodes.Node) {
 	if len(distinctOn) > 0 {
 		distinctOnID := dv.addNode("DISTINCT ON", colorLogical)
@@ -1046,3 +1094,219 @@ func (dv *DotVisitor) VisitCasted(n *nodes.CastedNode) string {
 	dv.connectToParent(id)
 	return id
 }
+
+func (dv *DotVisitor) VisitNamedParam(n *nodes.NamedParamNode) string {
+	label := "NamedParam\\n:" + n.Name
+	if n.TypeName != "" {
+		label += " " + n.TypeName
+	}
+	id := dv.addNode(label, colorLiteral)
+	dv.connectToParent(id)
+	return id
+}
+
+// --- DDL ---
+
+func (dv *DotVisitor) VisitCreateTable(n *nodes.CreateTableStatement) string {
+	label := "CreateTable\\n" + n.Table.Name
+	if n.IfNotExists {
+		label += "\\nIF NOT EXISTS"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	for i, c := range n.Columns {
+		dv.addColumnDef(id, fmt.Sprintf("COLUMN[%d]", i), c)
+	}
+	for i, c := range n.Constraints {
+		dv.addConstraint(id, fmt.Sprintf("CONSTRAINT[%d]", i), c)
+	}
+	return id
+}
+
+func (dv *DotVisitor) VisitAlterTable(n *nodes.AlterTableStatement) string {
+	id := dv.addNode("AlterTable\\n"+n.Table.Name, colorDDL)
+	dv.connectToParent(id)
+	for i, a := range n.Actions {
+		edge := fmt.Sprintf("ACTION[%d]", i)
+		switch a.Kind {
+		case nodes.AlterAddColumn:
+			dv.addColumnDef(id, edge, a.Column)
+		case nodes.AlterDropColumn:
+			dv.addEdge(id, dv.addNode("DropColumn\\n"+a.Name, colorDDL), edge)
+		case nodes.AlterRenameColumn:
+			dv.addEdge(id, dv.addNode("RenameColumn\\n"+a.Name+" → "+a.NewName, colorDDL), edge)
+		case nodes.AlterAddConstraint:
+			dv.addConstraint(id, edge, a.Constraint)
+		}
+	}
+	return id
+}
+
+func (dv *DotVisitor) VisitCreateIndex(n *nodes.CreateIndexStatement) string {
+	label := "CreateIndex\\n" + n.Name + " ON " + n.Table.Name
+	if n.Unique {
+		label += "\\nUNIQUE"
+	}
+	if n.Concurrently {
+		label += "\\nCONCURRENTLY"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	dv.visitChildList(id, "COLUMN", n.Columns)
+	dv.visitChildList(id, "WHERE", n.Wheres)
+	return id
+}
+
+func (dv *DotVisitor) VisitDrop(n *nodes.DropStatement) string {
+	kind := "DropTable"
+	if n.Kind == nodes.DropIndex {
+		kind = "DropIndex"
+	}
+	label := kind + "\\n" + n.Name
+	if n.IfExists {
+		label += "\\nIF EXISTS"
+	}
+	if n.Cascade {
+		label += "\\nCASCADE"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	if n.On != nil {
+		dv.visitChild(id, "ON", n.On)
+	}
+	return id
+}
+
+func (dv *DotVisitor) VisitCreateView(n *nodes.CreateViewStatement) string {
+	kind := "CreateView"
+	if n.Materialized {
+		kind = "CreateMaterializedView"
+	}
+	label := kind + "\\n" + n.Name
+	if len(n.Columns) > 0 {
+		label += " (" + strings.Join(n.Columns, ", ") + ")"
+	}
+	if n.OrReplace {
+		label += "\\nOR REPLACE"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	dv.visitChild(id, "AS", n.Query)
+	return id
+}
+
+func (dv *DotVisitor) VisitCreateTableAs(n *nodes.CreateTableAsStatement) string {
+	label := "CreateTableAs\\n" + n.Table.Name
+	if len(n.Columns) > 0 {
+		label += " (" + strings.Join(n.Columns, ", ") + ")"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	dv.visitChild(id, "AS", n.Query)
+	return id
+}
+
+func (dv *DotVisitor) VisitRefreshMaterializedView(n *nodes.RefreshMaterializedViewStatement) string {
+	label := "RefreshMaterializedView\\n" + n.Name
+	if n.Concurrently {
+		label += "\\nCONCURRENTLY"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	return id
+}
+
+func (dv *DotVisitor) VisitExplain(n *nodes.ExplainStatement) string {
+	label := "Explain"
+	o := n.Options
+	if o.Analyze {
+		label += "\\nANALYZE"
+	}
+	if o.Buffers {
+		label += "\\nBUFFERS"
+	}
+	if o.Verbose {
+		label += "\\nVERBOSE"
+	}
+	if o.Format == nodes.ExplainJSON {
+		label += "\\nFORMAT JSON"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.connectToParent(id)
+	dv.visitChild(id, "STATEMENT", n.Statement)
+	return id
+}
+
+// VisitExtension draws a custom node under its Label, or its Go type when
+// it has none, with the children it exposes through nodes.Parent.
+func (dv *DotVisitor) VisitExtension(n nodes.Extension) string {
+	label := strings.TrimPrefix(fmt.Sprintf("%T", n), "*")
+	if l, ok := n.(nodes.Labeler); ok {
+		label = l.Label()
+	}
+	id := dv.addNode(label, colorFunction)
+	dv.connectToParent(id)
+	if p, ok := n.(nodes.Parent); ok {
+		for i, child := range p.Children() {
+			dv.visitChild(id, fmt.Sprintf("CHILD[%d]", i), child)
+		}
+	}
+	return id
+}
+
+// addColumnDef adds a column definition node with its expression children.
+func (dv *DotVisitor) addColumnDef(parentID, edge string, c *nodes.ColumnDef) {
+	label := "Column\\n" + c.Name + " " + c.Type
+	if c.NotNull {
+		label += "\\nNOT NULL"
+	}
+	if c.PrimaryKey {
+		label += "\\nPRIMARY KEY"
+	}
+	if c.Unique {
+		label += "\\nUNIQUE"
+	}
+	if c.References != nil && c.References.Table != nil {
+		label += "\\nREFERENCES " + c.References.Table.Name
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.addEdge(parentID, id, edge)
+	if c.Default != nil {
+		dv.visitChild(id, "DEFAULT", c.Default)
+	}
+	if c.Check != nil {
+		dv.visitChild(id, "CHECK", c.Check)
+	}
+	if c.Generated != nil {
+		dv.visitChild(id, "GENERATED", c.Generated)
+	}
+}
+
+// addConstraint adds a table constraint node.
+func (dv *DotVisitor) addConstraint(parentID, edge string, c *nodes.TableConstraint) {
+	var label string
+	switch c.Kind {
+	case nodes.ConstraintPrimaryKey:
+		label = "PrimaryKey"
+	case nodes.ConstraintUnique:
+		label = "Unique"
+	case nodes.ConstraintCheck:
+		label = "Check"
+	case nodes.ConstraintForeignKey:
+		label = "ForeignKey"
+	}
+	if c.Name != "" {
+		label += "\\n" + c.Name
+	}
+	if len(c.Columns) > 0 {
+		label += "\\n(" + strings.Join(c.Columns, ", ") + ")"
+	}
+	if c.References != nil && c.References.Table != nil {
+		label += "\\n→ " + c.References.Table.Name + "(" + strings.Join(c.References.Columns, ", ") + ")"
+	}
+	id := dv.addNode(label, colorDDL)
+	dv.addEdge(parentID, id, edge)
+	if c.Check != nil {
+		dv.visitChild(id, "CHECK", c.Check)
+	}
+}
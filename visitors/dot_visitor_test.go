@@ -298,6 +298,26 @@ func TestDotVisitSelectCoreDistinct(t *testing.T) {
 
 // --- Task 5: DML statement tests ---
 
+func TestDotVisitSelectCoreAnalytical(t *testing.T) {
+	dv := NewDotVisitor()
+	events := nodes.NewTable("events")
+	core := &nodes.SelectCore{
+		From:        events,
+		Final:       true,
+		Sample:      &nodes.SampleClause{Ratio: 0.1},
+		Projections: []nodes.Node{nodes.Star().Excluding("payload")},
+		Qualifies:   []nodes.Node{events.Col("n").Eq(1)},
+		LimitBy:     &nodes.LimitByClause{Limit: nodes.Literal(3), Columns: []nodes.Node{events.Col("user_id")}},
+	}
+	core.Accept(dv)
+	dot := dv.ToDot()
+	for _, want := range []string{`label="FINAL"`, `label="Sample\n0.1"`, `label="Star\n*\nEXCLUDE payload"`, `label="QUALIFY[0]"`, `label="LIMIT BY"`, `label="BY[0]"`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected %s, got:\n%s", want, dot)
+		}
+	}
+}
+
 func TestDotVisitInsertStatement(t *testing.T) {
 	dv := NewDotVisitor()
 	users := nodes.NewTable("users")
@@ -0,0 +1,113 @@
+package visitors
+
+import (
+	"fmt"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// DuckDBVisitor generates DuckDB SQL.
+// Identifiers are quoted with double quotes: "table"."column".
+//
+// DuckDB follows PostgreSQL syntax, with $1 placeholders, and adds
+// QUALIFY, USING SAMPLE and EXCLUDE/REPLACE in star projections. Row locks
+// are rejected.
+type DuckDBVisitor struct {
+	*BaseVisitor
+}
+
+// NewDuckDBVisitor creates a DuckDBVisitor ready for use.
+// Parameterized mode is enabled by default for SQL injection protection.
+// Pass WithoutParams() to disable (not recommended for production).
+func NewDuckDBVisitor(opts ...Option) *DuckDBVisitor {
+	v := &DuckDBVisitor{}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "DuckDB",
+		quote:            writeDoubleQuoted,
+		plainIdent:       isLowerIdent,
+		reserved:         duckDBReserved,
+		writePlaceholder: writeDollarPlaceholder,
+		numberedParams:   true,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			alterSingleAction: true,
+			noPartialIndex:    true,
+			noStoredGenerated: true,
+			viewIfNotExists:   true,
+		},
+		comparison:       duckDBComparison,
+		explain:          explainDuckDB,
+		sample:           duckDBSample,
+		sampleAfterJoins: true,
+		qualify:          true,
+		starExclude:      "EXCLUDE",
+		noLocks:          true,
+	}}
+	v.applyOptions(opts)
+	return v
+}
+
+// duckDBComparison renders regular expression matches with
+// regexp_matches, since DuckDB's ~ operator must match the whole string.
+func duckDBComparison(r *renderer, n *nodes.ComparisonNode) bool {
+	if n.Op != nodes.OpRegexp && n.Op != nodes.OpNotRegexp {
+		return false
+	}
+	if n.Op == nodes.OpNotRegexp {
+		r.write("NOT ")
+	}
+	r.write("regexp_matches(")
+	r.node(n.Left)
+	r.write(", ")
+	r.node(n.Right)
+	r.write(")")
+	return true
+}
+
+// explainDuckDB writes EXPLAIN ANALYZE or EXPLAIN (FORMAT JSON). DuckDB has
+// no buffer or verbose output.
+func explainDuckDB(r *renderer, o nodes.ExplainOptions) {
+	switch {
+	case o.Buffers:
+		r.unsupported("EXPLAIN BUFFERS")
+	case o.Verbose:
+		r.unsupported("EXPLAIN VERBOSE")
+	case o.Analyze && o.Format == nodes.ExplainJSON:
+		r.unsupported("EXPLAIN ANALYZE with FORMAT JSON")
+	}
+	switch {
+	case o.Analyze:
+		r.write("EXPLAIN ANALYZE ")
+	case o.Format == nodes.ExplainJSON:
+		r.write("EXPLAIN (FORMAT JSON) ")
+	default:
+		r.write("EXPLAIN ")
+	}
+}
+
+// duckDBSampleMethods lists the sampling methods DuckDB accepts.
+var duckDBSampleMethods = map[string]bool{"reservoir": true, "bernoulli": true, "system": true}
+
+// duckDBSample writes USING SAMPLE k% or USING SAMPLE k ROWS, followed by
+// the sampling method when one is given. DuckDB samples the result of the
+// FROM clause, so the clause follows the joins.
+func duckDBSample(r *renderer, s *nodes.SampleClause) {
+	if s.Offset > 0 {
+		r.unsupported("SAMPLE OFFSET")
+	}
+	if s.Method != "" && !duckDBSampleMethods[s.Method] {
+		r.unsupported(fmt.Sprintf("the %q sampling method", s.Method))
+	}
+	r.write(" USING SAMPLE ")
+	if s.Rows > 0 {
+		r.write(fmt.Sprintf("%d ROWS", s.Rows))
+	} else {
+		r.write(formatRatio(s.Ratio * 100))
+		r.write("%")
+	}
+	if s.Method != "" {
+		r.write(" (")
+		r.write(s.Method)
+		r.write(")")
+	}
+}
@@ -0,0 +1,119 @@
+package visitors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// Statements translated from PostgreSQL are covered by the golden files in
+// parser/testdata/duckdb, and the analytical clauses by
+// testdata/analytics.golden.
+
+func TestDuckDBParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Wheres: []nodes.Node{
+			users.Col("name").Eq(nodes.Named("name")),
+			users.Col("email").Eq(nodes.Named("name")),
+		},
+		Limit: nodes.Literal(10),
+	}
+	sql, params, err := NewDuckDBVisitor().Dialect().Render(core)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT "users"."id" FROM "users" WHERE "users"."name" = $1 AND "users"."email" = $1 LIMIT $2`)
+	testutil.AssertEqual(t, len(params), 2)
+}
+
+func TestDuckDBRegexp(t *testing.T) {
+	t.Parallel()
+	v := NewDuckDBVisitor(WithoutParams())
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From:   users,
+		Wheres: []nodes.Node{users.Col("email").DoesNotMatchRegexp("@example[.]com$")},
+	}
+	testutil.AssertSQL(t, v, core, `SELECT * FROM "users" WHERE NOT regexp_matches("users"."email", '@example[.]com$')`)
+}
+
+func TestDuckDBSample(t *testing.T) {
+	t.Parallel()
+	v := NewDuckDBVisitor(WithoutParams())
+	users := nodes.NewTable("users")
+	tests := []struct {
+		sample *nodes.SampleClause
+		want   string
+	}{
+		{&nodes.SampleClause{Ratio: 0.1}, `SELECT * FROM "users" USING SAMPLE 10%`},
+		{&nodes.SampleClause{Ratio: 0.005, Method: "bernoulli"}, `SELECT * FROM "users" USING SAMPLE 0.5% (bernoulli)`},
+		{&nodes.SampleClause{Rows: 1000, Method: "reservoir"}, `SELECT * FROM "users" USING SAMPLE 1000 ROWS (reservoir)`},
+	}
+	for _, tt := range tests {
+		testutil.AssertSQL(t, v, &nodes.SelectCore{From: users, Sample: tt.sample}, tt.want)
+	}
+}
+
+func TestDuckDBDDL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	d := NewDuckDBVisitor().Dialect()
+
+	sql, err := renderDDL(d, &nodes.AlterTableStatement{
+		Table: users,
+		Actions: []*nodes.AlterAction{
+			{Kind: nodes.AlterAddColumn, Column: nodes.NewColumnDef("active", "BOOLEAN", nodes.Default(true))},
+			{Kind: nodes.AlterRenameColumn, Name: "mail", NewName: "email"},
+		},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `ALTER TABLE "users" ADD COLUMN "active" BOOLEAN DEFAULT TRUE; ALTER TABLE "users" RENAME COLUMN "mail" TO "email"`)
+
+	sql, err = renderDDL(d, &nodes.ExplainStatement{
+		Statement: &nodes.SelectCore{From: users},
+		Options:   nodes.ExplainOptions{Format: nodes.ExplainJSON},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `EXPLAIN (FORMAT JSON) SELECT * FROM "users"`)
+}
+
+func TestDuckDBUnsupported(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	tests := []struct {
+		name    string
+		stmt    nodes.Node
+		feature string
+	}{
+		{"row lock", &nodes.SelectCore{From: users, Lock: nodes.ForShare}, "row locks"},
+		{"final", &nodes.SelectCore{From: users, Final: true}, "FINAL"},
+		{"limit by", &nodes.SelectCore{From: users, LimitBy: &nodes.LimitByClause{
+			Limit: nodes.Literal(1), Columns: []nodes.Node{users.Col("org_id")},
+		}}, "LIMIT BY"},
+		{"array join", &nodes.SelectCore{From: users, Joins: []*nodes.JoinNode{{
+			Type: nodes.ArrayJoin, Right: users.Col("tags"),
+		}}}, "ARRAY JOIN"},
+		{"sample offset", &nodes.SelectCore{From: users, Sample: &nodes.SampleClause{Ratio: 0.1, Offset: 0.5}}, "SAMPLE OFFSET"},
+		{"sample method", &nodes.SelectCore{From: users, Sample: &nodes.SampleClause{Ratio: 0.1, Method: "block"}}, `the "block" sampling method`},
+		{"stored generated column", &nodes.CreateTableStatement{Table: users, Columns: []*nodes.ColumnDef{
+			nodes.NewColumnDef("total", "INTEGER", nodes.GeneratedAs(users.Col("a").Plus(1))),
+		}}, "stored generated columns"},
+		{"explain buffers", &nodes.ExplainStatement{Statement: &nodes.SelectCore{From: users}, Options: nodes.ExplainOptions{Buffers: true}}, "EXPLAIN BUFFERS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := renderDDL(NewDuckDBVisitor().Dialect(), tt.stmt)
+			var ue *nodes.UnsupportedError
+			if !errors.As(err, &ue) {
+				t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+			}
+			testutil.AssertEqual(t, ue.Feature, tt.feature)
+			testutil.AssertEqual(t, ue.Dialect, "DuckDB")
+		})
+	}
+}
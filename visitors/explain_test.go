@@ -0,0 +1,90 @@
+package visitors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func explainUsers(opts nodes.ExplainOptions) *nodes.ExplainStatement {
+	users := nodes.NewTable("users")
+	return nodes.Explain(&nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+	}, opts)
+}
+
+func TestExplainPostgres(t *testing.T) {
+	t.Parallel()
+	d := NewPostgresVisitor().Dialect()
+	cases := []struct {
+		opts nodes.ExplainOptions
+		want string
+	}{
+		{nodes.ExplainOptions{}, `EXPLAIN SELECT "users"."id" FROM "users"`},
+		{nodes.ExplainOptions{Analyze: true, Buffers: true, Format: nodes.ExplainJSON},
+			`EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) SELECT "users"."id" FROM "users"`},
+		{nodes.ExplainOptions{Verbose: true}, `EXPLAIN (VERBOSE) SELECT "users"."id" FROM "users"`},
+	}
+	for _, c := range cases {
+		sql, err := renderDDL(d, explainUsers(c.opts))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, sql, c.want)
+	}
+}
+
+func TestExplainMySQL(t *testing.T) {
+	t.Parallel()
+	d := NewMySQLVisitor().Dialect()
+	cases := []struct {
+		opts nodes.ExplainOptions
+		want string
+	}{
+		{nodes.ExplainOptions{}, "EXPLAIN SELECT `users`.`id` FROM `users`"},
+		{nodes.ExplainOptions{Format: nodes.ExplainJSON}, "EXPLAIN FORMAT=JSON SELECT `users`.`id` FROM `users`"},
+		{nodes.ExplainOptions{Analyze: true}, "EXPLAIN ANALYZE SELECT `users`.`id` FROM `users`"},
+	}
+	for _, c := range cases {
+		sql, err := renderDDL(d, explainUsers(c.opts))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, sql, c.want)
+	}
+}
+
+func TestExplainSQLite(t *testing.T) {
+	t.Parallel()
+	sql, err := renderDDL(NewSQLiteVisitor().Dialect(), explainUsers(nodes.ExplainOptions{}))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `EXPLAIN QUERY PLAN SELECT "users"."id" FROM "users"`)
+}
+
+func TestExplainUnsupportedOptions(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		d    *Dialect
+		opts nodes.ExplainOptions
+	}{
+		{"mysql buffers", NewMySQLVisitor().Dialect(), nodes.ExplainOptions{Buffers: true}},
+		{"mysql analyze json", NewMySQLVisitor().Dialect(), nodes.ExplainOptions{Analyze: true, Format: nodes.ExplainJSON}},
+		{"sqlite analyze", NewSQLiteVisitor().Dialect(), nodes.ExplainOptions{Analyze: true}},
+		{"sqlite json", NewSQLiteVisitor().Dialect(), nodes.ExplainOptions{Format: nodes.ExplainJSON}},
+	}
+	for _, c := range cases {
+		_, err := renderDDL(c.d, explainUsers(c.opts))
+		var ue *nodes.UnsupportedError
+		if !errors.As(err, &ue) {
+			t.Errorf("%s: expected UnsupportedError, got %v", c.name, err)
+		}
+	}
+}
+
+func TestDotVisitExplain(t *testing.T) {
+	dv := NewDotVisitor()
+	explainUsers(nodes.ExplainOptions{Analyze: true, Format: nodes.ExplainJSON}).Accept(dv)
+	dot := dv.ToDot()
+	assertContains(t, dot, `Explain\nANALYZE\nFORMAT JSON`)
+	assertContains(t, dot, `label="STATEMENT"`)
+}
@@ -0,0 +1,168 @@
+package visitors
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// DialectOption adapts a dialect copied with Extend.
+type DialectOption func(*Dialect)
+
+// Extend returns a copy of d named name, with opts applied. It is the
+// starting point for a dialect defined outside this package, which reuses
+// everything d does and overrides only what differs:
+//
+//	crdb := visitors.NewPostgresVisitor().Dialect().Extend("CockroachDB",
+//		visitors.ReservedWords("FAMILY", "INTERLEAVE"),
+//		visitors.Override(func(ctx nodes.RenderContext, n *nodes.ExplainStatement) bool {
+//			ctx.Write("EXPLAIN (DISTSQL) ")
+//			ctx.Node(n.Statement)
+//			return true
+//		}),
+//	)
+//
+// d itself is not modified. Use NewBaseVisitor to build a visitor around
+// the result.
+func (d *Dialect) Extend(name string, opts ...DialectOption) *Dialect {
+	e := *d
+	e.name = name
+	for _, o := range opts {
+		o(&e)
+	}
+	return &e
+}
+
+// Name returns the dialect's display name, as used in UnsupportedError.
+func (d *Dialect) Name() string {
+	return d.name
+}
+
+// QuoteIdentifiers quotes identifiers between open and close, doubling any
+// close characters inside the name.
+func QuoteIdentifiers(open, close string) DialectOption {
+	return func(d *Dialect) {
+		d.quote = func(sb *strings.Builder, name string) {
+			sb.WriteString(open)
+			sb.WriteString(strings.ReplaceAll(name, close, close+close))
+			sb.WriteString(close)
+		}
+	}
+}
+
+// PlainIdentifiers sets the test for names that QuoteWhenNeeded may write
+// without quotes, before reserved words are considered.
+func PlainIdentifiers(plain func(name string) bool) DialectOption {
+	return func(d *Dialect) {
+		d.plainIdent = plain
+	}
+}
+
+// ReservedWords adds reserved words, which QuoteWhenNeeded always quotes.
+func ReservedWords(words ...string) DialectOption {
+	return func(d *Dialect) {
+		reserved := maps.Clone(d.reserved)
+		if reserved == nil {
+			reserved = make(map[string]bool, len(words))
+		}
+		for _, w := range words {
+			reserved[strings.ToUpper(w)] = true
+		}
+		d.reserved = reserved
+	}
+}
+
+// MaxIdentifierLength sets the longest identifier, in bytes, accepted by
+// WithIdentifierLengthCheck; zero means unlimited.
+func MaxIdentifierLength(n int) DialectOption {
+	return func(d *Dialect) {
+		d.maxIdentLen = n
+	}
+}
+
+// Placeholders sets how bind placeholders are written. write receives the
+// 1-based parameter index and the bound value. numbered reports whether
+// the placeholder carries its index, so that a repeated named parameter
+// can reuse its first slot.
+func Placeholders(write func(sb *strings.Builder, index int, val any), numbered bool) DialectOption {
+	return func(d *Dialect) {
+		d.writePlaceholder = write
+		d.numberedParams = numbered
+	}
+}
+
+// Override renders nodes of type T with fn, which reports false to fall
+// back to the dialect's own rendering. Later overrides are tried first.
+// Rendering n itself through ctx.Node inside fn uses the dialect's own
+// rendering, so fn can wrap it:
+//
+//	visitors.Override(func(ctx nodes.RenderContext, n *nodes.NamedFunctionNode) bool {
+//		if n.Name != "NOW" {
+//			return false
+//		}
+//		ctx.Write("CURRENT_TIMESTAMP")
+//		return true
+//	})
+func Override[T nodes.Node](fn func(ctx nodes.RenderContext, n T) bool) DialectOption {
+	return func(d *Dialect) {
+		d.overrides = append(slices.Clip(d.overrides), func(r *renderer, n nodes.Node) bool {
+			t, ok := n.(T)
+			return ok && fn(r, t)
+		})
+	}
+}
+
+// OverrideExplain sets the writer for the EXPLAIN prefix of a statement.
+func OverrideExplain(fn func(ctx nodes.RenderContext, o nodes.ExplainOptions)) DialectOption {
+	return func(d *Dialect) {
+		d.explain = func(r *renderer, o nodes.ExplainOptions) { fn(r, o) }
+	}
+}
+
+// OverrideSample sets the writer for the SAMPLE clause of a SELECT. The
+// clause is written straight after the FROM relation.
+func OverrideSample(fn func(ctx nodes.RenderContext, s *nodes.SampleClause)) DialectOption {
+	return func(d *Dialect) {
+		d.sample = func(r *renderer, s *nodes.SampleClause) { fn(r, s) }
+		d.sampleAfterJoins = false
+	}
+}
+
+// override gives the dialect's overrides the first chance to render n.
+// While an override is rendering n, n itself renders without them.
+func (r *renderer) override(n nodes.Node) bool {
+	if n == r.overriding {
+		return false
+	}
+	saved := r.overriding
+	r.overriding = n
+	defer func() { r.overriding = saved }()
+	for i := len(r.d.overrides) - 1; i >= 0; i-- {
+		if r.d.overrides[i](r, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- nodes.RenderContext implementation ---
+
+// Dialect returns the name of the dialect being rendered.
+func (r *renderer) Dialect() string { return r.d.name }
+
+// Write appends raw SQL text.
+func (r *renderer) Write(sql string) { r.write(sql) }
+
+// Ident writes an identifier under the dialect's quoting policy.
+func (r *renderer) Ident(name string) { r.ident(name) }
+
+// Node renders a child node.
+func (r *renderer) Node(n nodes.Node) { r.node(n) }
+
+// Value writes v as a bind placeholder, or inline when not parameterizing.
+func (r *renderer) Value(v any) { r.literal(v) }
+
+// Unsupported aborts rendering with a *nodes.UnsupportedError.
+func (r *renderer) Unsupported(feature string) { r.unsupported(feature) }
@@ -0,0 +1,184 @@
+package visitors
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// distanceNode is a PostGIS-style extension node: Left <-> Right within
+// Radius.
+type distanceNode struct {
+	Left, Right nodes.Node
+	Radius      float64
+}
+
+func (n *distanceNode) Accept(v nodes.Visitor) string { return v.VisitExtension(n) }
+
+func (n *distanceNode) RenderSQL(ctx nodes.RenderContext) {
+	switch ctx.Dialect() {
+	case "PostgreSQL":
+		ctx.Node(n.Left)
+		ctx.Write(" <-> ")
+		ctx.Node(n.Right)
+		ctx.Write(" < ")
+		ctx.Value(n.Radius)
+	case "DuckDB":
+		ctx.Write("ST_Distance(")
+		ctx.Node(n.Left)
+		ctx.Write(", ")
+		ctx.Node(n.Right)
+		ctx.Write(") < ")
+		ctx.Value(n.Radius)
+	default:
+		ctx.Unsupported("distance operator")
+	}
+}
+
+func (n *distanceNode) Label() string          { return "Distance" }
+func (n *distanceNode) Children() []nodes.Node { return []nodes.Node{n.Left, n.Right} }
+
+func distanceQuery() *nodes.SelectCore {
+	shops := nodes.NewTable("shops")
+	return &nodes.SelectCore{
+		From:        shops,
+		Projections: []nodes.Node{shops.Col("name")},
+		Wheres: []nodes.Node{
+			shops.Col("open").Eq(true),
+			&distanceNode{Left: shops.Col("geom"), Right: nodes.Named("here"), Radius: 500},
+		},
+	}
+}
+
+func TestExtensionRenderSQL(t *testing.T) {
+	t.Parallel()
+	sql, params, err := NewPostgresVisitor().Dialect().Render(distanceQuery())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT "shops"."name" FROM "shops" WHERE "shops"."open" = $1 AND "shops"."geom" <-> $2 < $3`)
+	testutil.AssertEqual(t, len(params), 3)
+	testutil.AssertEqual(t, params[2], any(float64(500)))
+
+	testutil.AssertSQL(t, NewDuckDBVisitor(WithoutParams()), distanceQuery(),
+		`SELECT "shops"."name" FROM "shops" WHERE "shops"."open" = TRUE AND ST_Distance("shops"."geom", $1) < 500`)
+
+	_, err = renderDDL(NewMySQLVisitor().Dialect(), distanceQuery())
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+	}
+	testutil.AssertEqual(t, ue.Dialect, "MySQL")
+	testutil.AssertEqual(t, ue.Feature, "distance operator")
+}
+
+func TestExtensionFallbacks(t *testing.T) {
+	t.Parallel()
+	q := distanceQuery()
+
+	f := NewFormattingVisitor(NewPostgresVisitor(WithoutParams()))
+	testutil.AssertEqual(t, q.Accept(f), "SELECT \"shops\".\"name\"\nFROM \"shops\"\nWHERE \"shops\".\"open\" = TRUE\n\tAND \"shops\".\"geom\" <-> $1 < 500")
+
+	dv := NewDotVisitor()
+	q.Accept(dv)
+	dot := dv.ToDot()
+	for _, want := range []string{`"Distance"`, `"CHILD[0]"`, `"CHILD[1]"`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected %s in DOT output, got:\n%s", want, dot)
+		}
+	}
+}
+
+// cockroachVisitor is a dialect built the way one outside this package
+// would be.
+type cockroachVisitor struct {
+	*BaseVisitor
+}
+
+func newCockroachVisitor(opts ...Option) *cockroachVisitor {
+	d := NewPostgresVisitor().Dialect().Extend("CockroachDB",
+		ReservedWords("family"),
+		Override(func(ctx nodes.RenderContext, n *nodes.ExplainStatement) bool {
+			ctx.Write("EXPLAIN (DISTSQL) ")
+			ctx.Node(n.Statement)
+			return true
+		}),
+	)
+	return &cockroachVisitor{NewBaseVisitor(d, opts...)}
+}
+
+func TestExtendDialect(t *testing.T) {
+	t.Parallel()
+	pg := NewPostgresVisitor(WithQuotePolicy(QuoteWhenNeeded))
+	v := newCockroachVisitor(WithQuotePolicy(QuoteWhenNeeded))
+	testutil.AssertEqual(t, v.Dialect().Name(), "CockroachDB")
+
+	t1 := nodes.NewTable("family")
+	core := &nodes.SelectCore{From: t1, Projections: []nodes.Node{t1.Col("id")}}
+	testutil.AssertSQL(t, v, core, `SELECT "family".id FROM "family"`)
+	// The base dialect is unchanged.
+	testutil.AssertSQL(t, pg, core, `SELECT family.id FROM family`)
+
+	testutil.AssertSQL(t, v, &nodes.ExplainStatement{Statement: core}, `EXPLAIN (DISTSQL) SELECT "family".id FROM "family"`)
+
+	_, err := renderDDL(v.Dialect(), &distanceNode{Left: t1.Col("a"), Right: t1.Col("b")})
+	var ue *nodes.UnsupportedError
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+	}
+	testutil.AssertEqual(t, ue.Dialect, "CockroachDB")
+}
+
+func TestExtendQuotingAndPlaceholders(t *testing.T) {
+	t.Parallel()
+	d := NewMySQLVisitor().Dialect().Extend("Snowflake",
+		QuoteIdentifiers(`"`, `"`),
+		Placeholders(func(sb *strings.Builder, index int, _ any) {
+			sb.WriteString(":")
+			sb.WriteString(strconv.Itoa(index))
+		}, true),
+	)
+	users := nodes.NewTable(`my"users`)
+	sql, params, err := d.Render(&nodes.SelectCore{
+		From:   users,
+		Wheres: []nodes.Node{users.Col("a").Eq(nodes.Named("x")), users.Col("b").Eq(nodes.Named("x"))},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT * FROM "my""users" WHERE "my""users"."a" = :1 AND "my""users"."b" = :1`)
+	testutil.AssertEqual(t, len(params), 1)
+}
+
+func TestOverrideFallsBack(t *testing.T) {
+	t.Parallel()
+	d := NewPostgresVisitor(WithoutParams()).Dialect().Extend("Custom",
+		Override(func(ctx nodes.RenderContext, n *nodes.NamedFunctionNode) bool {
+			if n.Name != "NOW" {
+				return false
+			}
+			ctx.Write("CURRENT_TIMESTAMP")
+			return true
+		}),
+		// Later overrides run first and may wrap the default rendering.
+		Override(func(ctx nodes.RenderContext, n *nodes.NamedFunctionNode) bool {
+			if n.Name != "LOWER" {
+				return false
+			}
+			ctx.Write("/* ci */ ")
+			ctx.Node(n)
+			return true
+		}),
+	)
+	users := nodes.NewTable("users")
+	sql, _, err := d.Render(&nodes.SelectCore{
+		From: users,
+		Projections: []nodes.Node{
+			nodes.NewNamedFunction("NOW"),
+			nodes.Lower(users.Col("name")),
+			nodes.Upper(users.Col("name")),
+		},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `SELECT CURRENT_TIMESTAMP, /* ci */ LOWER("users"."name"), UPPER("users"."name") FROM "users"`)
+}
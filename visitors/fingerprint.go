@@ -0,0 +1,82 @@
+package visitors
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// normalizer renders the dialect-independent form used by Fingerprint.
+// Every value becomes a ? placeholder, so the output depends only on the
+// shape of the query.
+var normalizer = &Dialect{
+	name:             "normalised SQL",
+	quote:            writeDoubleQuoted,
+	plainIdent:       isLowerIdent,
+	quoting:          QuoteWhenNeeded,
+	writePlaceholder: writeQuestionPlaceholder,
+	parameterize:     true,
+	normalize:        true,
+	finalModifier:    true,
+	sample:           normalSample,
+	arrayJoin:        true,
+	qualify:          true,
+	limitBy:          true,
+	starExclude:      "EXCLUDE",
+}
+
+// Fingerprint returns a stable 64-bit hash and the normalised SQL of n,
+// identifying queries that share a shape regardless of their values.
+//
+// The normalised SQL replaces literal, bind and named parameter values with
+// ?, collapses IN lists made only of values to IN (...), drops query
+// comments, and quotes identifiers only when they are not plain lower-case
+// names, so the result is the same for every dialect. It is meant for
+// logging and aggregation and is not guaranteed to be executable.
+//
+// The hash is the 64-bit FNV-1a of the normalised SQL. Transformers are not
+// applied; fingerprint a manager's transformed statement to include them.
+// A tree that cannot be rendered, such as one with an invalid type or
+// function name, returns a *nodes.RenderError or *nodes.UnsupportedError.
+func Fingerprint(n nodes.Node) (uint64, string, error) {
+	sql, _, err := normalizer.Render(n)
+	if err != nil {
+		return 0, "", err
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sql))
+	return h.Sum64(), sql, nil
+}
+
+// normalSample writes the SAMPLE clause in the normalised form. The
+// sampling method is left out, since it is dialect-specific and does not
+// change the shape of the query.
+func normalSample(r *renderer, s *nodes.SampleClause) {
+	r.write(" SAMPLE ")
+	if s.Rows > 0 {
+		r.write(strconv.FormatInt(s.Rows, 10))
+	} else {
+		r.write(formatRatio(s.Ratio))
+	}
+	if s.Offset > 0 {
+		r.write(" OFFSET ")
+		r.write(formatRatio(s.Offset))
+	}
+}
+
+// valuesOnly reports whether every item is a literal, bind or named
+// parameter value, as opposed to a column, expression or subquery.
+func valuesOnly(items []nodes.Node) bool {
+	if len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		switch item.(type) {
+		case *nodes.LiteralNode, *nodes.BindParamNode, *nodes.NamedParamNode:
+		default:
+			return false
+		}
+	}
+	return true
+}
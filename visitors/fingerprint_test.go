@@ -0,0 +1,111 @@
+package visitors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func TestFingerprintIgnoresValues(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	a := managers.NewSelectManager(users).
+		Where(users.Col("name").Eq("Alice")).
+		Where(users.Col("age").Gt(nodes.NewBindParam(18))).
+		Limit(10)
+	b := managers.NewSelectManager(users).
+		Where(users.Col("name").Eq("Bob")).
+		Where(users.Col("age").Gt(nodes.NewBindParam(65))).
+		Limit(50)
+
+	ha, sqlA, err := Fingerprint(a.Core)
+	testutil.AssertNoError(t, err)
+	hb, sqlB, err := Fingerprint(b.Core)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sqlA, "SELECT * FROM users WHERE users.name = ? AND users.age > ? LIMIT ?")
+	testutil.AssertEqual(t, sqlA, sqlB)
+	testutil.AssertEqual(t, ha, hb)
+}
+
+func TestFingerprintCollapsesInLists(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, two, err := Fingerprint(users.Col("id").In(1, 2))
+	testutil.AssertNoError(t, err)
+	_, five, err := Fingerprint(users.Col("id").In(1, 2, 3, 4, 5))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, two, "users.id IN (...)")
+	testutil.AssertEqual(t, two, five)
+
+	_, notIn, err := Fingerprint(users.Col("id").NotIn(nodes.NewBindParam(1)))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, notIn, "users.id NOT IN (...)")
+}
+
+func TestFingerprintKeepsColumnInLists(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, sql, err := Fingerprint(users.Col("id").In(users.Col("owner_id"), 1))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "users.id IN (users.owner_id, ?)")
+}
+
+func TestFingerprintDialectIndependent(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("Users")
+	n := users.Col("select").Eq(nodes.Named("v"))
+	_, sql, err := Fingerprint(n)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `"Users".select = ?`)
+}
+
+func TestFingerprintDiffersByShape(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	h1, _, err := Fingerprint(users.Col("id").Eq(1))
+	testutil.AssertNoError(t, err)
+	h2, _, err := Fingerprint(users.Col("id").Gt(1))
+	testutil.AssertNoError(t, err)
+	if h1 == h2 {
+		t.Error("expected different fingerprints for = and >")
+	}
+}
+
+func TestFingerprintDropsComments(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	m := managers.NewSelectManager(users).Comment("request 42")
+	_, sql, err := Fingerprint(m.Core)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "SELECT * FROM users")
+}
+
+func TestFingerprintKeepsNull(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, sql, err := Fingerprint(users.Col("deleted_at").IsNull())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "users.deleted_at IS NULL")
+}
+
+func TestFingerprintIgnoresSampleMethod(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	n := &nodes.SelectCore{From: users, Sample: &nodes.SampleClause{Ratio: 0.1, Method: "bernoulli"}}
+	_, sql, err := Fingerprint(n)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "SELECT * FROM users SAMPLE 0.1")
+}
+
+func TestFingerprintReturnsRenderErrors(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	_, _, err := Fingerprint(nodes.NewCasted(users.Col("id"), "int; DROP"))
+	var re *nodes.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected *nodes.RenderError, got %v", err)
+	}
+}
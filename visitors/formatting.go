@@ -172,6 +172,48 @@ func (f *FormattingVisitor) VisitCasted(node *nodes.CastedNode) string {
 	return f.inner.VisitCasted(node)
 }
 
+func (f *FormattingVisitor) VisitNamedParam(node *nodes.NamedParamNode) string {
+	return f.inner.VisitNamedParam(node)
+}
+
+func (f *FormattingVisitor) VisitCreateTable(node *nodes.CreateTableStatement) string {
+	return f.inner.VisitCreateTable(node)
+}
+
+func (f *FormattingVisitor) VisitAlterTable(node *nodes.AlterTableStatement) string {
+	return f.inner.VisitAlterTable(node)
+}
+
+func (f *FormattingVisitor) VisitCreateIndex(node *nodes.CreateIndexStatement) string {
+	return f.inner.VisitCreateIndex(node)
+}
+
+func (f *FormattingVisitor) VisitDrop(node *nodes.DropStatement) string {
+	return f.inner.VisitDrop(node)
+}
+
+func (f *FormattingVisitor) VisitCreateView(node *nodes.CreateViewStatement) string {
+	return f.inner.VisitCreateView(node)
+}
+
+func (f *FormattingVisitor) VisitCreateTableAs(node *nodes.CreateTableAsStatement) string {
+	return f.inner.VisitCreateTableAs(node)
+}
+
+func (f *FormattingVisitor) VisitRefreshMaterializedView(node *nodes.RefreshMaterializedViewStatement) string {
+	return f.inner.VisitRefreshMaterializedView(node)
+}
+
+func (f *FormattingVisitor) VisitExplain(node *nodes.ExplainStatement) string {
+	return f.inner.VisitExplain(node)
+}
+
+// VisitExtension renders a custom node with the inner visitor, which for
+// the built-in dialects calls its RenderSQL method.
+func (f *FormattingVisitor) VisitExtension(node nodes.Extension) string {
+	return f.inner.VisitExtension(node)
+}
+
 // --- Structural overrides ---
 
 // VisitSelectCore renders a SELECT statement in multi-line formatted style.
@@ -251,10 +293,17 @@ func (f *FormattingVisitor) VisitSelectCore(node *nodes.SelectCore) string {
 		}
 	}
 
-	// FROM
+	// FROM, with FINAL and SAMPLE modifiers
+	sample, sampleAfterJoins := f.sampleSQL(node.Sample)
 	if node.From != nil {
 		sb.WriteString("\nFROM ")
 		sb.WriteString(node.From.Accept(f.inner))
+		if node.Final {
+			sb.WriteString(" FINAL")
+		}
+		if !sampleAfterJoins {
+			sb.WriteString(sample)
+		}
 	}
 
 	// JOINs
@@ -262,6 +311,10 @@ func (f *FormattingVisitor) VisitSelectCore(node *nodes.SelectCore) string {
 		sb.WriteString("\n")
 		sb.WriteString(j.Accept(f.inner))
 	}
+	if sampleAfterJoins {
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimPrefix(sample, " "))
+	}
 
 	// WHERE
 	if len(node.Wheres) > 0 {
@@ -312,6 +365,16 @@ func (f *FormattingVisitor) VisitSelectCore(node *nodes.SelectCore) string {
 		}
 	}
 
+	// QUALIFY
+	if len(node.Qualifies) > 0 {
+		sb.WriteString("\nQUALIFY ")
+		sb.WriteString(node.Qualifies[0].Accept(f.inner))
+		for _, q := range node.Qualifies[1:] {
+			sb.WriteString("\n\tAND ")
+			sb.WriteString(q.Accept(f.inner))
+		}
+	}
+
 	// ORDER BY — leading-comma style
 	if len(node.Orders) > 0 {
 		sb.WriteString("\nORDER BY ")
@@ -322,6 +385,23 @@ func (f *FormattingVisitor) VisitSelectCore(node *nodes.SelectCore) string {
 		}
 	}
 
+	// LIMIT n BY
+	if lb := node.LimitBy; lb != nil {
+		sb.WriteString("\nLIMIT ")
+		sb.WriteString(lb.Limit.Accept(f.inner))
+		if lb.Offset != nil {
+			sb.WriteString(" OFFSET ")
+			sb.WriteString(lb.Offset.Accept(f.inner))
+		}
+		sb.WriteString(" BY ")
+		for i, c := range lb.Columns {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(c.Accept(f.inner))
+		}
+	}
+
 	// LIMIT
 	if node.Limit != nil {
 		sb.WriteString("\nLIMIT ")
@@ -346,6 +426,22 @@ func (f *FormattingVisitor) VisitSelectCore(node *nodes.SelectCore) string {
 	return sb.String()
 }
 
+// sampleSQL renders a SAMPLE clause in the inner visitor's dialect and
+// reports whether it follows the joins. Visitors that are not built on a
+// Dialect get the ClickHouse spelling.
+func (f *FormattingVisitor) sampleSQL(s *nodes.SampleClause) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	d := normalizer
+	if dv, ok := f.inner.(interface{ Dialect() *Dialect }); ok {
+		d = dv.Dialect()
+	}
+	r := newRenderer(d)
+	r.sample(s)
+	return r.String(), d.sampleAfterJoins
+}
+
 // VisitSetOperation renders each leg of a set operation in parentheses with
 // the operator keyword on its own line between them. Any ORDER BY, LIMIT, or
 // OFFSET on the node apply to the combined result and are rendered afterwards.
@@ -389,8 +485,11 @@ func (f *FormattingVisitor) VisitInsertStatement(n *nodes.InsertStatement) strin
 			if i > 0 {
 				sb.WriteString(", ")
 			}
-			attr := c.(*nodes.Attribute)
-			sb.WriteString(nodes.NewTable(attr.Name).Accept(f.inner))
+			if attr, ok := c.(*nodes.Attribute); ok {
+				sb.WriteString(nodes.NewTable(attr.Name).Accept(f.inner))
+			} else {
+				sb.WriteString(c.Accept(f))
+			}
 		}
 		sb.WriteString(")")
 	}
@@ -725,3 +725,31 @@ func TestFormattingMultipleCTEs(t *testing.T) {
 		t.Errorf("expected both CTEs in SQL, got:\n%s", got)
 	}
 }
+
+func TestFormattingClickHouseClauses(t *testing.T) {
+	t.Parallel()
+	fv := NewFormattingVisitor(NewClickHouseVisitor(WithoutParams()))
+	events := nodes.NewTable("events")
+	m := managers.NewSelectManager(events).
+		Select(events.Col("user_id"), events.Col("url")).
+		Final().
+		Sample(0.1).
+		ArrayJoin(events.Col("tags")).
+		Qualify(nodes.RowNumber().Over(&nodes.WindowDefinition{PartitionBy: []nodes.Node{events.Col("user_id")}}).Eq(1)).
+		Order(events.Col("at").Desc()).
+		LimitBy(3, events.Col("user_id"))
+	expected := "SELECT `events`.`user_id`\n\t,`events`.`url`\nFROM `events` FINAL SAMPLE 0.1\nARRAY JOIN `events`.`tags`\n" +
+		"QUALIFY ROW_NUMBER() OVER (PARTITION BY `events`.`user_id`) = 1\nORDER BY `events`.`at` DESC\nLIMIT 3 BY `events`.`user_id`"
+	testutil.AssertSQL(t, fv, m.Core, expected)
+}
+
+func TestFormattingDuckDBSample(t *testing.T) {
+	t.Parallel()
+	fv := NewFormattingVisitor(NewDuckDBVisitor(WithoutParams()))
+	users := nodes.NewTable("users")
+	orders := nodes.NewTable("orders")
+	m := managers.NewSelectManager(users).SampleRows(100)
+	m.Join(orders).On(orders.Col("user_id").Eq(users.Col("id")))
+	expected := "SELECT *\nFROM \"users\"\nINNER JOIN \"orders\" ON \"orders\".\"user_id\" = \"users\".\"id\"\nUSING SAMPLE 100 ROWS"
+	testutil.AssertSQL(t, fv, m.Core, expected)
+}
@@ -0,0 +1,272 @@
+package visitors
+
+import "strings"
+
+// QuotePolicy controls when a visitor quotes identifiers.
+type QuotePolicy int
+
+const (
+	// QuoteAlways quotes every identifier. This is the default and keeps
+	// the exact case of names in every dialect.
+	QuoteAlways QuotePolicy = iota
+
+	// QuoteWhenNeeded quotes only identifiers that are reserved words in
+	// the dialect, contain special characters, or (in PostgreSQL) contain
+	// upper-case letters that would otherwise be folded.
+	QuoteWhenNeeded
+
+	// QuoteNever writes identifiers bare. An identifier that would need
+	// quoting makes ToSQL return a *nodes.RenderError instead of SQL that
+	// means something else.
+	QuoteNever
+)
+
+// WithQuotePolicy sets when identifiers are quoted. The default is
+// QuoteAlways.
+func WithQuotePolicy(p QuotePolicy) Option {
+	return func(b *BaseVisitor) {
+		b.d.quoting = p
+	}
+}
+
+// WithLowerCaseIdentifiers folds every identifier to lower case before it
+// is quoted, so mixed-case names in Go code match unquoted lower-case
+// names in the database.
+func WithLowerCaseIdentifiers() Option {
+	return func(b *BaseVisitor) {
+		b.d.foldLower = true
+	}
+}
+
+// WithIdentifierLengthCheck makes ToSQL return a *nodes.RenderError when
+// an identifier is longer than the dialect allows (63 bytes in
+// PostgreSQL, 64 in MySQL, 128 in SQL Server and Oracle) instead of
+// letting the database truncate or reject it. SQLite, ClickHouse and
+// DuckDB have no limit.
+func WithIdentifierLengthCheck() Option {
+	return func(b *BaseVisitor) {
+		b.d.checkLength = true
+	}
+}
+
+// writeIdent writes name according to the dialect's quoting policy.
+func (d *Dialect) writeIdent(sb *strings.Builder, name string) {
+	if d.foldLower {
+		name = strings.ToLower(name)
+	}
+	if d.checkLength && d.maxIdentLen > 0 && len(name) > d.maxIdentLen {
+		renderErrorf("identifier %q is %d bytes, exceeding the %s limit of %d", name, len(name), d.name, d.maxIdentLen)
+	}
+	switch d.quoting {
+	case QuoteWhenNeeded:
+		if d.needsQuote(name) {
+			d.quote(sb, name)
+		} else {
+			sb.WriteString(name)
+		}
+	case QuoteNever:
+		if d.needsQuote(name) {
+			renderErrorf("identifier %q must be quoted in %s", name, d.name)
+		}
+		sb.WriteString(name)
+	default:
+		d.quote(sb, name)
+	}
+}
+
+// needsQuote reports whether name is unsafe to write bare.
+func (d *Dialect) needsQuote(name string) bool {
+	return !d.plainIdent(name) || d.reserved[strings.ToUpper(name)]
+}
+
+// isLowerIdent reports whether name matches [a-z_][a-z0-9_$]*, the names
+// PostgreSQL leaves unchanged when unquoted.
+func isLowerIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c == '_':
+		case (c >= '0' && c <= '9' || c == '$') && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isMixedIdent reports whether name matches [A-Za-z_][A-Za-z0-9_$]*.
+func isMixedIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case (c >= '0' && c <= '9' || c == '$') && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isUpperIdent reports whether name matches [A-Z][A-Z0-9_$#]*, the names
+// Oracle leaves unchanged when unquoted.
+func isUpperIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c >= 'A' && c <= 'Z':
+		case (c >= '0' && c <= '9' || c == '_' || c == '$' || c == '#') && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// keywordSet builds a reserved-word lookup from a space-separated list.
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// postgresReserved lists the PostgreSQL keywords that are reserved or
+// cannot be used as column names without quoting.
+var postgresReserved = keywordSet(`
+	ALL ANALYSE ANALYZE AND ANY ARRAY AS ASC ASYMMETRIC AUTHORIZATION BINARY
+	BOTH CASE CAST CHECK COLLATE COLLATION COLUMN CONCURRENTLY CONSTRAINT
+	CREATE CROSS CURRENT_CATALOG CURRENT_DATE CURRENT_ROLE CURRENT_SCHEMA
+	CURRENT_TIME CURRENT_TIMESTAMP CURRENT_USER DEFAULT DEFERRABLE DESC
+	DISTINCT DO ELSE END EXCEPT FALSE FETCH FOR FOREIGN FREEZE FROM FULL
+	GRANT GROUP HAVING ILIKE IN INITIALLY INNER INTERSECT INTO IS ISNULL JOIN
+	LATERAL LEADING LEFT LIKE LIMIT LOCALTIME LOCALTIMESTAMP NATURAL NOT
+	NOTNULL NULL OFFSET ON ONLY OR ORDER OUTER OVERLAPS PLACING PRIMARY
+	REFERENCES RETURNING RIGHT SELECT SESSION_USER SIMILAR SOME SYMMETRIC
+	SYSTEM_USER TABLE TABLESAMPLE THEN TO TRAILING TRUE UNION UNIQUE USER
+	USING VARIADIC VERBOSE WHEN WHERE WINDOW WITH
+`)
+
+// mysqlReserved lists the MySQL 8.0 reserved words.
+var mysqlReserved = keywordSet(`
+	ACCESSIBLE ADD ALL ALTER ANALYZE AND AS ASC ASENSITIVE BEFORE BETWEEN
+	BIGINT BINARY BLOB BOTH BY CALL CASCADE CASE CHANGE CHAR CHARACTER CHECK
+	COLLATE COLUMN CONDITION CONSTRAINT CONTINUE CONVERT CREATE CROSS CUBE
+	CUME_DIST CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP CURRENT_USER CURSOR
+	DATABASE DATABASES DAY_HOUR DAY_MICROSECOND DAY_MINUTE DAY_SECOND DEC
+	DECIMAL DECLARE DEFAULT DELAYED DELETE DENSE_RANK DESC DESCRIBE
+	DETERMINISTIC DISTINCT DISTINCTROW DIV DOUBLE DROP DUAL EACH ELSE ELSEIF
+	EMPTY ENCLOSED ESCAPED EXCEPT EXISTS EXIT EXPLAIN FALSE FETCH FIRST_VALUE
+	FLOAT FLOAT4 FLOAT8 FOR FORCE FOREIGN FROM FULLTEXT FUNCTION GENERATED
+	GET GRANT GROUP GROUPING GROUPS HAVING HIGH_PRIORITY HOUR_MICROSECOND
+	HOUR_MINUTE HOUR_SECOND IF IGNORE IN INDEX INFILE INNER INOUT INSENSITIVE
+	INSERT INT INT1 INT2 INT3 INT4 INT8 INTEGER INTERSECT INTERVAL INTO
+	IO_AFTER_GTIDS IO_BEFORE_GTIDS IS ITERATE JOIN JSON_TABLE KEY KEYS KILL
+	LAG LAST_VALUE LATERAL LEAD LEADING LEAVE LEFT LIKE LIMIT LINEAR LINES
+	LOAD LOCALTIME LOCALTIMESTAMP LOCK LONG LONGBLOB LONGTEXT LOOP
+	LOW_PRIORITY MASTER_BIND MASTER_SSL_VERIFY_SERVER_CERT MATCH MAXVALUE
+	MEDIUMBLOB MEDIUMINT MEDIUMTEXT MIDDLEINT MINUTE_MICROSECOND
+	MINUTE_SECOND MOD MODIFIES NATURAL NOT NO_WRITE_TO_BINLOG NTH_VALUE NTILE
+	NULL NUMERIC OF ON OPTIMIZE OPTIMIZER_COSTS OPTION OPTIONALLY OR ORDER
+	OUT OUTER OUTFILE OVER PARTITION PERCENT_RANK PRECISION PRIMARY
+	PROCEDURE PURGE RANGE RANK READ READS READ_WRITE REAL RECURSIVE
+	REFERENCES REGEXP RELEASE RENAME REPEAT REPLACE REQUIRE RESIGNAL
+	RESTRICT RETURN REVOKE RIGHT RLIKE ROW ROWS ROW_NUMBER SCHEMA SCHEMAS
+	SECOND_MICROSECOND SELECT SENSITIVE SEPARATOR SET SHOW SIGNAL SMALLINT
+	SPATIAL SPECIFIC SQL SQLEXCEPTION SQLSTATE SQLWARNING SQL_BIG_RESULT
+	SQL_CALC_FOUND_ROWS SQL_SMALL_RESULT SSL STARTING STORED STRAIGHT_JOIN
+	SYSTEM TABLE TERMINATED THEN TINYBLOB TINYINT TINYTEXT TO TRAILING
+	TRIGGER TRUE UNDO UNION UNIQUE UNLOCK UNSIGNED UPDATE USAGE USE USING
+	UTC_DATE UTC_TIME UTC_TIMESTAMP VALUES VARBINARY VARCHAR VARCHARACTER
+	VARYING VIRTUAL WHEN WHERE WHILE WINDOW WITH WRITE XOR YEAR_MONTH
+	ZEROFILL
+`)
+
+// sqliteReserved lists the SQLite keywords.
+var sqliteReserved = keywordSet(`
+	ABORT ACTION ADD AFTER ALL ALTER ALWAYS ANALYZE AND AS ASC ATTACH
+	AUTOINCREMENT BEFORE BEGIN BETWEEN BY CASCADE CASE CAST CHECK COLLATE
+	COLUMN COMMIT CONFLICT CONSTRAINT CREATE CROSS CURRENT CURRENT_DATE
+	CURRENT_TIME CURRENT_TIMESTAMP DATABASE DEFAULT DEFERRABLE DEFERRED
+	DELETE DESC DETACH DISTINCT DO DROP EACH ELSE END ESCAPE EXCEPT EXCLUDE
+	EXCLUSIVE EXISTS EXPLAIN FAIL FILTER FIRST FOLLOWING FOR FOREIGN FROM
+	FULL GENERATED GLOB GROUP GROUPS HAVING IF IGNORE IMMEDIATE IN INDEX
+	INDEXED INITIALLY INNER INSERT INSTEAD INTERSECT INTO IS ISNULL JOIN KEY
+	LAST LEFT LIKE LIMIT MATCH MATERIALIZED NATURAL NO NOT NOTHING NOTNULL
+	NULL NULLS OF OFFSET ON OR ORDER OTHERS OUTER OVER PARTITION PLAN PRAGMA
+	PRECEDING PRIMARY QUERY RAISE RANGE RECURSIVE REFERENCES REGEXP REINDEX
+	RELEASE RENAME REPLACE RESTRICT RETURNING RIGHT ROLLBACK ROW ROWS
+	SAVEPOINT SELECT SET TABLE TEMP TEMPORARY THEN TIES TO TRANSACTION
+	TRIGGER UNBOUNDED UNION UNIQUE UPDATE USING VACUUM VALUES VIEW VIRTUAL
+	WHEN WHERE WINDOW WITH WITHOUT
+`)
+
+// mssqlReserved lists the SQL Server (Transact-SQL) reserved keywords.
+var mssqlReserved = keywordSet(`
+	ADD ALL ALTER AND ANY AS ASC AUTHORIZATION BACKUP BEGIN BETWEEN BREAK
+	BROWSE BULK BY CASCADE CASE CHECK CHECKPOINT CLOSE CLUSTERED COALESCE
+	COLLATE COLUMN COMMIT COMPUTE CONSTRAINT CONTAINS CONTAINSTABLE CONTINUE
+	CONVERT CREATE CROSS CURRENT CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP
+	CURRENT_USER CURSOR DATABASE DBCC DEALLOCATE DECLARE DEFAULT DELETE DENY
+	DESC DISK DISTINCT DISTRIBUTED DOUBLE DROP DUMP ELSE END ERRLVL ESCAPE
+	EXCEPT EXEC EXECUTE EXISTS EXIT EXTERNAL FETCH FILE FILLFACTOR FOR
+	FOREIGN FREETEXT FREETEXTTABLE FROM FULL FUNCTION GOTO GRANT GROUP
+	HAVING HOLDLOCK IDENTITY IDENTITY_INSERT IDENTITYCOL IF IN INDEX INNER
+	INSERT INTERSECT INTO IS JOIN KEY KILL LEFT LIKE LINENO LOAD MERGE
+	NATIONAL NOCHECK NONCLUSTERED NOT NULL NULLIF OF OFF OFFSETS ON OPEN
+	OPENDATASOURCE OPENQUERY OPENROWSET OPENXML OPTION OR ORDER OUTER OVER
+	PERCENT PIVOT PLAN PRECISION PRIMARY PRINT PROC PROCEDURE PUBLIC
+	RAISERROR READ READTEXT RECONFIGURE REFERENCES REPLICATION RESTORE
+	RESTRICT RETURN REVERT REVOKE RIGHT ROLLBACK ROWCOUNT ROWGUIDCOL RULE
+	SAVE SCHEMA SECURITYAUDIT SELECT SEMANTICKEYPHRASETABLE
+	SEMANTICSIMILARITYDETAILSTABLE SEMANTICSIMILARITYTABLE SESSION_USER SET
+	SETUSER SHUTDOWN SOME STATISTICS SYSTEM_USER TABLE TABLESAMPLE TEXTSIZE
+	THEN TO TOP TRAN TRANSACTION TRIGGER TRUNCATE TRY_CONVERT TSEQUAL UNION
+	UNIQUE UNPIVOT UPDATE UPDATETEXT USE USER VALUES VARYING VIEW WAITFOR
+	WHEN WHERE WHILE WITH WITHIN WRITETEXT
+`)
+
+// oracleReserved lists the Oracle reserved words.
+var oracleReserved = keywordSet(`
+	ACCESS ADD ALL ALTER AND ANY AS ASC AUDIT BETWEEN BY CHAR CHECK CLUSTER
+	COLUMN COMMENT COMPRESS CONNECT CREATE CURRENT DATE DECIMAL DEFAULT
+	DELETE DESC DISTINCT DROP ELSE EXCLUSIVE EXISTS FILE FLOAT FOR FROM
+	GRANT GROUP HAVING IDENTIFIED IMMEDIATE IN INCREMENT INDEX INITIAL
+	INSERT INTEGER INTERSECT INTO IS LEVEL LIKE LOCK LONG MAXEXTENTS MINUS
+	MLSLABEL MODE MODIFY NOAUDIT NOCOMPRESS NOT NOWAIT NULL NUMBER OF
+	OFFLINE ON ONLINE OPTION OR ORDER PCTFREE PRIOR PUBLIC RAW RENAME
+	RESOURCE REVOKE ROW ROWID ROWNUM ROWS SELECT SESSION SET SHARE SIZE
+	SMALLINT START SUCCESSFUL SYNONYM SYSDATE TABLE THEN TO TRIGGER UID
+	UNION UNIQUE UPDATE USER VALIDATE VALUES VARCHAR VARCHAR2 VIEW WHENEVER
+	WHERE WITH
+`)
+
+// clickHouseReserved lists the ClickHouse keywords that cannot be used as
+// unquoted aliases or column names in clause positions.
+var clickHouseReserved = keywordSet(`
+	ALL AND ANTI ANY ARRAY AS ASC ASOF BETWEEN BY CASE CAST CROSS DESC
+	DISTINCT ELSE END EXCEPT FINAL FORMAT FROM FULL GLOBAL GROUP HAVING
+	ILIKE IN INNER INTERSECT INTERVAL INTO IS JOIN LEFT LIKE LIMIT NOT NULL
+	OFFSET ON OR ORDER OUTER PREWHERE QUALIFY RIGHT SAMPLE SELECT SEMI
+	SETTINGS THEN TO UNION USING WHEN WHERE WINDOW WITH
+`)
+
+// duckDBReserved lists the DuckDB reserved keywords.
+var duckDBReserved = keywordSet(`
+	ALL ANALYSE ANALYZE AND ANTI ANY ARRAY AS ASC ASOF ASYMMETRIC BOTH CASE
+	CAST CHECK COLLATE COLUMN CONSTRAINT CREATE CROSS DEFAULT DEFERRABLE
+	DESC DESCRIBE DISTINCT DO ELSE END EXCEPT FALSE FETCH FOR FOREIGN FROM
+	FULL GLOB GRANT GROUP HAVING ILIKE IN INITIALLY INNER INTERSECT INTO IS
+	ISNULL JOIN LATERAL LEADING LEFT LIKE LIMIT MAP NATURAL NOT NOTNULL
+	NULL OFFSET ON ONLY OR ORDER OUTER PIVOT POSITIONAL PRIMARY QUALIFY
+	REFERENCES RETURNING RIGHT SELECT SEMI SHOW SIMILAR SOME STRUCT
+	SUMMARIZE SYMMETRIC TABLE THEN TO TRAILING TRUE TRY_CAST UNION UNIQUE
+	UNPIVOT USING VARIADIC WHEN WHERE WINDOW WITH
+`)
@@ -0,0 +1,94 @@
+package visitors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/managers"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func TestQuoteAlwaysIsDefault(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	testutil.AssertSQL(t, NewPostgresVisitor(), users.Col("id"), `"users"."id"`)
+}
+
+func TestQuoteWhenNeededPostgres(t *testing.T) {
+	t.Parallel()
+	v := NewPostgresVisitor(WithQuotePolicy(QuoteWhenNeeded))
+	tests := []struct {
+		table, col string
+		want       string
+	}{
+		{"users", "id", `users.id`},
+		{"users", "order", `users."order"`},
+		{"users", "Order", `users."Order"`},
+		{"Users", "id", `"Users".id`},
+		{"users", "first name", `users."first name"`},
+		{"users", "2fa", `users."2fa"`},
+		{"users", "col$1", `users.col$1`},
+	}
+	for _, tt := range tests {
+		testutil.AssertSQL(t, v, nodes.NewTable(tt.table).Col(tt.col), tt.want)
+	}
+}
+
+func TestQuoteWhenNeededPerDialectReservedWords(t *testing.T) {
+	t.Parallel()
+	t1 := nodes.NewTable("t")
+	// "key" is reserved in MySQL and SQLite but not in PostgreSQL.
+	testutil.AssertSQL(t, NewPostgresVisitor(WithQuotePolicy(QuoteWhenNeeded)), t1.Col("key"), `t.key`)
+	testutil.AssertSQL(t, NewMySQLVisitor(WithQuotePolicy(QuoteWhenNeeded)), t1.Col("key"), "t.`key`")
+	testutil.AssertSQL(t, NewSQLiteVisitor(WithQuotePolicy(QuoteWhenNeeded)), t1.Col("key"), `t."key"`)
+	// Mixed case is only quoted where the dialect would fold it.
+	testutil.AssertSQL(t, NewMySQLVisitor(WithQuotePolicy(QuoteWhenNeeded)), t1.Col("UserId"), "t.UserId")
+}
+
+func TestQuoteNeverValidates(t *testing.T) {
+	t.Parallel()
+	v := NewPostgresVisitor(WithQuotePolicy(QuoteNever))
+	testutil.AssertSQL(t, v, nodes.NewTable("users").Col("id"), `users.id`)
+
+	for _, name := range []string{"select", "first name", "Users"} {
+		_, _, err := managers.NewSelectManager(nodes.NewTable(name)).ToSQL(v)
+		var re *nodes.RenderError
+		if !errors.As(err, &re) || !strings.Contains(err.Error(), "must be quoted") {
+			t.Errorf("expected a render error for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestLowerCaseIdentifiers(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("Users")
+	testutil.AssertSQL(t, NewPostgresVisitor(WithLowerCaseIdentifiers()), users.Col("UserID"), `"users"."userid"`)
+	testutil.AssertSQL(t,
+		NewPostgresVisitor(WithLowerCaseIdentifiers(), WithQuotePolicy(QuoteNever)),
+		users.Col("UserID"), `users.userid`)
+}
+
+func TestIdentifierLengthCheck(t *testing.T) {
+	t.Parallel()
+	name63 := strings.Repeat("a", 63)
+	name64 := strings.Repeat("a", 64)
+
+	v := NewPostgresVisitor(WithIdentifierLengthCheck())
+	testutil.AssertSQL(t, v, nodes.NewTable(name63), `"`+name63+`"`)
+	testutil.AssertSQL(t, NewMySQLVisitor(WithIdentifierLengthCheck()), nodes.NewTable(name64), "`"+name64+"`")
+
+	_, _, err := managers.NewSelectManager(nodes.NewTable(name64)).ToSQL(v)
+	var re *nodes.RenderError
+	if !errors.As(err, &re) || !strings.Contains(err.Error(), "PostgreSQL limit of 63") {
+		t.Errorf("expected a length error, got %v", err)
+	}
+}
+
+func TestIdentifierLengthUncheckedByDefault(t *testing.T) {
+	t.Parallel()
+	name := strings.Repeat("a", 100)
+	testutil.AssertSQL(t, NewPostgresVisitor(), nodes.NewTable(name), `"`+name+`"`)
+	testutil.AssertSQL(t, NewSQLiteVisitor(WithIdentifierLengthCheck()), nodes.NewTable(name), `"`+name+`"`)
+}
@@ -0,0 +1,104 @@
+package visitors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MermaidVisitor walks the AST and produces a Mermaid flowchart, which
+// Markdown renderers such as GitHub's draw without Graphviz. It builds the
+// same graph as DotVisitor, with the same colours, and draws plugin
+// clusters as dashed subgraphs. It implements nodes.Visitor.
+type MermaidVisitor struct {
+	*DotVisitor
+}
+
+// NewMermaidVisitor creates a new MermaidVisitor ready to walk an AST.
+func NewMermaidVisitor() *MermaidVisitor {
+	return &MermaidVisitor{DotVisitor: NewDotVisitor()}
+}
+
+// ToMermaid generates the complete Mermaid flowchart text.
+func (mv *MermaidVisitor) ToMermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+
+	// One class per colour, named after the colour.
+	seen := make(map[string]bool)
+	for _, n := range mv.nodes {
+		if !seen[n.color] {
+			seen[n.color] = true
+			fmt.Fprintf(&sb, "  classDef %s fill:%s,stroke:#555,color:#000\n", mermaidClass(n.color), n.color)
+		}
+	}
+
+	clustered := make(map[string]bool)
+	for _, c := range mv.clusters {
+		for _, id := range c.nodeIDs {
+			clustered[id] = true
+		}
+	}
+
+	for _, n := range mv.nodes {
+		if !clustered[n.id] {
+			fmt.Fprintf(&sb, "  %s\n", mermaidNode(n))
+		}
+	}
+
+	for i, c := range mv.clusters {
+		fmt.Fprintf(&sb, "  subgraph cluster_%d[\"%s\"]\n", i, escapeMermaid(c.name))
+		for _, id := range c.nodeIDs {
+			for _, n := range mv.nodes {
+				if n.id == id {
+					fmt.Fprintf(&sb, "    %s\n", mermaidNode(n))
+					break
+				}
+			}
+		}
+		sb.WriteString("  end\n")
+		fmt.Fprintf(&sb, "  style cluster_%d fill:none,stroke:%s,stroke-dasharray:5 5\n", i, c.color)
+	}
+
+	for _, e := range mv.edges {
+		if e.label != "" {
+			fmt.Fprintf(&sb, "  %s -->|\"%s\"| %s\n", e.from, escapeMermaid(e.label), e.to)
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s\n", e.from, e.to)
+		}
+	}
+
+	// Invisible links keep clause subtrees in SQL reading order, as in ToDot.
+	for _, e := range mv.clauseChainEdges() {
+		fmt.Fprintf(&sb, "  %s ~~~ %s\n", e.from, e.to)
+	}
+	return sb.String()
+}
+
+// mermaidNode returns the declaration of a node with its label and class.
+func mermaidNode(n dotNode) string {
+	return fmt.Sprintf("%s[\"%s\"]:::%s", n.id, escapeMermaid(n.label), mermaidClass(n.color))
+}
+
+// mermaidClass returns the class name for a colour such as "#6CA6CD".
+func mermaidClass(color string) string {
+	return "c" + strings.TrimPrefix(color, "#")
+}
+
+// escapeMermaid turns DOT line breaks into <br/> and writes the characters
+// that Mermaid would interpret in a quoted label as entity codes.
+func escapeMermaid(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == 'n':
+			sb.WriteString("<br/>")
+			i++
+		case c == '"' || c == '#' || c == '<' || c == '>' || c == '&' || c == '|':
+			fmt.Fprintf(&sb, "#%d;", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
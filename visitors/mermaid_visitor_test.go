@@ -0,0 +1,85 @@
+package visitors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func TestMermaidVisitTable(t *testing.T) {
+	mv := NewMermaidVisitor()
+	nodes.NewTable("users").Accept(mv)
+	out := mv.ToMermaid()
+
+	if !strings.HasPrefix(out, "flowchart LR\n") {
+		t.Errorf("expected flowchart header, got:\n%s", out)
+	}
+	if !strings.Contains(out, `n0["Table<br/>users"]:::c6CA6CD`) {
+		t.Errorf("expected table node with class, got:\n%s", out)
+	}
+	if !strings.Contains(out, "classDef c6CA6CD fill:#6CA6CD") {
+		t.Errorf("expected table colour class, got:\n%s", out)
+	}
+}
+
+func TestMermaidEdgesAndClauseOrder(t *testing.T) {
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From:   users,
+		Wheres: []nodes.Node{users.Col("active").Eq(nodes.Literal(true))},
+	}
+	mv := NewMermaidVisitor()
+	core.Accept(mv)
+	out := mv.ToMermaid()
+
+	for _, want := range []string{
+		`n0 -->|"FROM"| n1`,
+		`n0 -->|"WHERE[0]"| n2`,
+		`n2 -->|"LEFT"| n3`,
+		"n1 ~~~ n2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMermaidEscapesLabels(t *testing.T) {
+	mv := NewMermaidVisitor()
+	nodes.Literal(`a "b" <c> | #d`).Accept(mv)
+	out := mv.ToMermaid()
+
+	if !strings.Contains(out, `Literal<br/>a #34;b#34; #60;c#62; #124; #35;d`) {
+		t.Errorf("expected escaped label, got:\n%s", out)
+	}
+}
+
+func TestMermaidProvenanceSubgraph(t *testing.T) {
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From: users,
+		Wheres: []nodes.Node{
+			users.Col("active").Eq(nodes.Literal(true)),
+			users.Col("deleted_at").IsNull(),
+		},
+	}
+	prov := NewPluginProvenance()
+	prov.AddWhere("softdelete", "#CC6666", 1)
+
+	mv := NewMermaidVisitor()
+	mv.SetProvenance(prov)
+	core.Accept(mv)
+	out := mv.ToMermaid()
+
+	if !strings.Contains(out, "subgraph cluster_0[\"softdelete\"]\n    n5[\"Unary<br/>IS NULL\"]") {
+		t.Errorf("expected softdelete subgraph, got:\n%s", out)
+	}
+	if !strings.Contains(out, "style cluster_0 fill:none,stroke:#CC6666,stroke-dasharray:5 5") {
+		t.Errorf("expected dashed cluster style, got:\n%s", out)
+	}
+	// Clustered nodes are declared only inside their subgraph.
+	if strings.Count(out, `n5["`) != 1 {
+		t.Errorf("expected n5 to be declared once, got:\n%s", out)
+	}
+}
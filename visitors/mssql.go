@@ -0,0 +1,206 @@
+package visitors
+
+import "github.com/bawdo/gosbee/nodes"
+
+// MSSQLVisitor generates SQL Server (Transact-SQL) SQL.
+// Identifiers are quoted with brackets: [table].[column].
+//
+// LIMIT becomes TOP (n), or OFFSET ... ROWS FETCH NEXT ... ROWS ONLY when
+// there is an offset, which SQL Server only accepts after ORDER BY.
+// RETURNING becomes an OUTPUT clause, row locks become table hints, and
+// ON CONFLICT becomes a MERGE statement.
+type MSSQLVisitor struct {
+	*BaseVisitor
+}
+
+// NewMSSQLVisitor creates an MSSQLVisitor ready for use.
+// Parameterized mode is enabled by default for SQL injection protection.
+// Pass WithoutParams() to disable (not recommended for production).
+func NewMSSQLVisitor(opts ...Option) *MSSQLVisitor {
+	v := &MSSQLVisitor{}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "SQL Server",
+		quote:            writeBracketQuoted,
+		plainIdent:       isMixedIdent,
+		reserved:         mssqlReserved,
+		maxIdentLen:      128,
+		writePlaceholder: writeAtPlaceholder,
+		numberedParams:   true,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			noIndexIfNotExists: true,
+			onlineIndex:        " WITH (ONLINE = ON)",
+			dropIndexOnTable:   true,
+			noDropCascade:      true,
+			noOrReplaceView:    true,
+			noCreateTableAs:    true,
+			noTableIfNotExists: true,
+			noGeneratedColumns: true,
+			addWithoutColumn:   true,
+			noRenameColumn:     true,
+		},
+		comparison:     mssqlComparison,
+		explain:        explainMSSQL,
+		concat:         "+",
+		numericBools:   true,
+		plainRecursive: true,
+		limitStyle:     limitTop,
+		lockHint:       mssqlLockHint,
+		outputClause:   true,
+		aliasedTargets: true,
+		upsert:         mssqlUpsert,
+	}}
+	v.applyOptions(opts)
+	return v
+}
+
+// mssqlComparison renders the comparison operators SQL Server spells
+// differently or lacks.
+func mssqlComparison(r *renderer, n *nodes.ComparisonNode) bool {
+	var collation string
+	switch n.Op {
+	case nodes.OpRegexp, nodes.OpNotRegexp:
+		r.unsupported("regular expression matching")
+	case nodes.OpContains, nodes.OpOverlaps:
+		r.unsupported(comparisonOpSQL[n.Op] + " operator")
+	case nodes.OpCaseSensitiveEq:
+		collation = " COLLATE Latin1_General_CS_AS"
+	case nodes.OpCaseInsensitiveEq:
+		collation = " COLLATE Latin1_General_CI_AS"
+	default:
+		return false
+	}
+	r.node(n.Left)
+	r.write(" = ")
+	r.node(n.Right)
+	r.write(collation)
+	return true
+}
+
+// explainMSSQL rejects EXPLAIN. SQL Server returns plans through session
+// settings such as SET SHOWPLAN_XML, not a statement prefix.
+func explainMSSQL(r *renderer, _ nodes.ExplainOptions) {
+	r.unsupported("EXPLAIN")
+}
+
+// mssqlLockHint writes the locking mode of a SELECT as a hint on its FROM
+// table: FOR UPDATE becomes WITH (UPDLOCK, ROWLOCK) and FOR SHARE
+// WITH (REPEATABLEREAD, ROWLOCK). SKIP LOCKED adds READPAST.
+func mssqlLockHint(r *renderer, n *nodes.SelectCore) {
+	switch from := n.From.(type) {
+	case *nodes.Table:
+	case *nodes.TableAlias:
+		if _, ok := from.Relation.(*nodes.Table); !ok {
+			r.unsupported("locking a derived table")
+		}
+	default:
+		r.unsupported("locking without a FROM table")
+	}
+	switch n.Lock {
+	case nodes.ForUpdate:
+		r.write(" WITH (UPDLOCK, ROWLOCK")
+	case nodes.ForShare:
+		r.write(" WITH (REPEATABLEREAD, ROWLOCK")
+	default:
+		r.unsupported(lockModeSQL[n.Lock])
+	}
+	if n.SkipLocked {
+		r.write(", READPAST")
+	}
+	r.write(")")
+}
+
+// mssqlUpsert renders an INSERT ... ON CONFLICT as a MERGE statement. The
+// proposed rows become the source table "excluded", so assignments that
+// read excluded columns mean the same as in PostgreSQL:
+//
+//	MERGE INTO [users] WITH (HOLDLOCK)
+//	USING (VALUES (@p1, @p2)) AS [excluded] ([email], [name])
+//	ON [users].[email] = [excluded].[email]
+//	WHEN MATCHED THEN UPDATE SET [users].[name] = [excluded].[name]
+//	WHEN NOT MATCHED THEN INSERT ([email], [name])
+//	VALUES ([excluded].[email], [excluded].[name]);
+//
+// HOLDLOCK stops a concurrent MERGE from inserting the same key between
+// the match and the insert.
+func mssqlUpsert(r *renderer, n *nodes.InsertStatement) {
+	oc := n.OnConflict
+	switch {
+	case len(oc.Columns) == 0:
+		r.unsupported("MERGE upsert without conflict columns")
+	case len(n.Columns) == 0:
+		r.unsupported("MERGE upsert without an insert column list")
+	case n.Select == nil && len(n.Values) == 0:
+		r.unsupported("MERGE upsert without values")
+	}
+	cols, keys := r.mergeNames(n.Columns), r.mergeNames(oc.Columns)
+
+	r.write("MERGE INTO ")
+	if alias, ok := n.Into.(*nodes.TableAlias); ok {
+		r.node(alias.Relation)
+		r.write(" WITH (HOLDLOCK) AS ")
+		r.ident(alias.AliasName)
+	} else {
+		r.node(n.Into)
+		r.write(" WITH (HOLDLOCK)")
+	}
+
+	r.write(" USING (")
+	if n.Select != nil {
+		r.node(n.Select)
+	} else {
+		r.write("VALUES ")
+		for i, row := range n.Values {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.write("(")
+			r.list(row, ", ")
+			r.write(")")
+		}
+	}
+	r.write(") AS ")
+	r.ident("excluded")
+	r.write(" ")
+	r.columnNames(n.Columns)
+
+	target := nodes.RelationName(n.Into)
+	r.write(" ON ")
+	for i, name := range keys {
+		if i > 0 {
+			r.write(" AND ")
+		}
+		r.ident(target)
+		r.write(".")
+		r.ident(name)
+		r.write(" = ")
+		mssqlExcluded(r, name)
+	}
+
+	if oc.Action == nodes.DoUpdate {
+		r.write(" WHEN MATCHED")
+		r.clause(" AND ", oc.Wheres, " AND ")
+		r.write(" THEN UPDATE")
+		r.assignments(" SET ", oc.Assignments)
+	}
+	r.write(" WHEN NOT MATCHED THEN INSERT ")
+	r.columnNames(n.Columns)
+	r.write(" VALUES (")
+	for i, name := range cols {
+		if i > 0 {
+			r.write(", ")
+		}
+		mssqlExcluded(r, name)
+	}
+	r.write(")")
+	r.output("INSERTED", n.Returning)
+	// MERGE is the one statement SQL Server requires to be terminated.
+	r.write(";")
+}
+
+// mssqlExcluded writes a column of the MERGE source table.
+func mssqlExcluded(r *renderer, name string) {
+	r.ident("excluded")
+	r.write(".")
+	r.ident(name)
+}
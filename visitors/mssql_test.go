@@ -0,0 +1,158 @@
+package visitors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// SQL Server has no server in CI; statements translated from PostgreSQL
+// are covered by the golden files in parser/testdata/mssql.
+
+func TestMSSQLParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Wheres: []nodes.Node{
+			users.Col("name").Eq(nodes.Named("name")),
+			users.Col("active").Eq(true),
+			users.Col("email").Eq(nodes.Named("name")),
+		},
+		Limit: nodes.Literal(10),
+	}
+	sql, params, err := NewMSSQLVisitor().Dialect().Render(core)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "SELECT TOP (@p1) [users].[id] FROM [users] WHERE [users].[name] = @p2 AND [users].[active] = @p3 AND [users].[email] = @p2")
+	testutil.AssertEqual(t, len(params), 3)
+	testutil.AssertEqual(t, params[2], any(true))
+}
+
+func TestMSSQLQuoting(t *testing.T) {
+	t.Parallel()
+	t.Run("escapes closing brackets", func(t *testing.T) {
+		t.Parallel()
+		testutil.AssertEqual(t, NewMSSQLVisitor().Dialect().quoteIdent("a]b"), "[a]]b]")
+	})
+	t.Run("quotes reserved words when needed", func(t *testing.T) {
+		t.Parallel()
+		v := NewMSSQLVisitor(WithQuotePolicy(QuoteWhenNeeded), WithoutParams())
+		plans := nodes.NewTable("plans")
+		core := &nodes.SelectCore{
+			From:        plans,
+			Projections: []nodes.Node{plans.Col("top"), plans.Col("name")},
+		}
+		testutil.AssertSQL(t, v, core, "SELECT plans.[top], plans.name FROM plans")
+	})
+}
+
+func TestMSSQLUpsertParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	u := &nodes.TableAlias{Relation: users, AliasName: "u"}
+	excluded := nodes.NewTable("excluded")
+	stmt := &nodes.InsertStatement{
+		Into:    u,
+		Columns: []nodes.Node{users.Col("email"), users.Col("visits")},
+		Values:  [][]nodes.Node{{nodes.Literal("a@b.com"), nodes.Literal(1)}},
+		OnConflict: &nodes.OnConflictNode{
+			Columns: []nodes.Node{users.Col("email")},
+			Action:  nodes.DoUpdate,
+			Assignments: []*nodes.AssignmentNode{{
+				Left:  nodes.NewAttribute(u, "visits"),
+				Right: nodes.NewAttribute(u, "visits").Plus(excluded.Col("visits")),
+			}},
+			Wheres: []nodes.Node{nodes.NewAttribute(u, "locked").Eq(false)},
+		},
+		Returning: []nodes.Node{&nodes.AliasNode{Expr: users.Col("id"), Name: "user_id"}},
+	}
+	sql, params, err := NewMSSQLVisitor().Dialect().Render(stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "MERGE INTO [users] WITH (HOLDLOCK) AS [u] USING (VALUES (@p1, @p2)) AS [excluded] ([email], [visits]) "+
+		"ON [u].[email] = [excluded].[email] WHEN MATCHED AND [u].[locked] = @p3 "+
+		"THEN UPDATE SET [u].[visits] = [u].[visits] + [excluded].[visits] "+
+		"WHEN NOT MATCHED THEN INSERT ([email], [visits]) VALUES ([excluded].[email], [excluded].[visits]) "+
+		"OUTPUT INSERTED.[id] AS [user_id];")
+	testutil.AssertEqual(t, len(params), 3)
+}
+
+func TestMSSQLDDL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	d := NewMSSQLVisitor().Dialect()
+
+	sql, err := renderDDL(d, &nodes.AlterTableStatement{
+		Table: users,
+		Actions: []*nodes.AlterAction{
+			{Kind: nodes.AlterAddColumn, Column: nodes.NewColumnDef("active", "bit", nodes.NotNull(), nodes.Default(true))},
+			{Kind: nodes.AlterDropColumn, Name: "legacy"},
+		},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "ALTER TABLE [users] ADD [active] bit NOT NULL DEFAULT 1, DROP COLUMN [legacy]")
+
+	sql, err = renderDDL(d, &nodes.CreateIndexStatement{
+		Name:         "active_users",
+		Table:        users,
+		Columns:      []nodes.Node{users.Col("email")},
+		Concurrently: true,
+		Wheres:       []nodes.Node{users.Col("active").Eq(true)},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "CREATE INDEX [active_users] ON [users] ([email]) WHERE [active] = 1 WITH (ONLINE = ON)")
+
+	sql, err = renderDDL(d, &nodes.DropStatement{Kind: nodes.DropIndex, Name: "active_users", On: users, IfExists: true})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, "DROP INDEX IF EXISTS [active_users] ON [users]")
+}
+
+func TestMSSQLUnsupported(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	query := &nodes.SelectCore{From: users}
+	tests := []struct {
+		name    string
+		stmt    nodes.Node
+		feature string
+	}{
+		{"explain", &nodes.ExplainStatement{Statement: query}, "EXPLAIN"},
+		{"key share lock", &nodes.SelectCore{From: users, Lock: nodes.ForKeyShare}, "FOR KEY SHARE"},
+		{"create table if not exists", &nodes.CreateTableStatement{
+			Table: users, IfNotExists: true, Columns: []*nodes.ColumnDef{nodes.NewColumnDef("id", "int")},
+		}, "CREATE TABLE IF NOT EXISTS"},
+		{"create table as", &nodes.CreateTableAsStatement{Table: users, Query: query}, "CREATE TABLE AS"},
+		{"rename column", &nodes.AlterTableStatement{Table: users, Actions: []*nodes.AlterAction{
+			{Kind: nodes.AlterRenameColumn, Name: "mail", NewName: "email"},
+		}}, "ALTER TABLE RENAME COLUMN"},
+		{"generated column", &nodes.CreateTableStatement{Table: users, Columns: []*nodes.ColumnDef{
+			nodes.NewColumnDef("total", "int", nodes.GeneratedAs(users.Col("a").Plus(1))),
+		}}, "generated columns"},
+		{"upsert on a conflict expression", &nodes.InsertStatement{
+			Into:       users,
+			Columns:    []nodes.Node{users.Col("email")},
+			Values:     [][]nodes.Node{{nodes.Literal("a@b.com")}},
+			OnConflict: &nodes.OnConflictNode{Columns: []nodes.Node{nodes.NewSqlLiteral("lower(email)")}},
+		}, "MERGE upsert on a column expression"},
+		{"upsert of a raw insert column", &nodes.InsertStatement{
+			Into:       users,
+			Columns:    []nodes.Node{nodes.NewSqlLiteral("email")},
+			Values:     [][]nodes.Node{{nodes.Literal("a@b.com")}},
+			OnConflict: &nodes.OnConflictNode{Columns: []nodes.Node{users.Col("email")}},
+		}, "MERGE upsert on a column expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := renderDDL(NewMSSQLVisitor().Dialect(), tt.stmt)
+			var ue *nodes.UnsupportedError
+			if !errors.As(err, &ue) {
+				t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+			}
+			testutil.AssertEqual(t, ue.Feature, tt.feature)
+			testutil.AssertEqual(t, ue.Dialect, "SQL Server")
+		})
+	}
+}
@@ -1,14 +1,11 @@
 package visitors
 
-import (
-	"github.com/bawdo/gosbee/internal/quoting"
-	"github.com/bawdo/gosbee/nodes"
-)
+import "github.com/bawdo/gosbee/nodes"
 
 // MySQLVisitor generates MySQL-dialect SQL.
 // Identifiers are quoted with backticks: `table`.`column`.
 type MySQLVisitor struct {
-	*baseVisitor
+	*BaseVisitor
 }
 
 // NewMySQLVisitor creates a MySQLVisitor ready for use.
@@ -16,27 +13,66 @@ type MySQLVisitor struct {
 // Pass WithoutParams() to disable (not recommended for production).
 func NewMySQLVisitor(opts ...Option) *MySQLVisitor {
 	v := &MySQLVisitor{}
-	v.baseVisitor = &baseVisitor{
-		outer:        v,
-		quoteIdent:   quoting.Backtick,
-		placeholder:  func(_ int) string { return "?" },
-		parameterize: true, // Enable by default
-	}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "MySQL",
+		quote:            writeBacktickQuoted,
+		plainIdent:       isMixedIdent,
+		reserved:         mysqlReserved,
+		maxIdentLen:      64,
+		writePlaceholder: writeQuestionPlaceholder,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			hoistForeignKeys:   true,
+			noPartialIndex:     true,
+			noIndexIfNotExists: true,
+			onlineIndex:        " ALGORITHM=INPLACE LOCK=NONE",
+			dropIndexOnTable:   true,
+		},
+		comparison: mysqlComparison,
+		explain:    explainMySQL,
+	}}
 	v.applyOptions(opts)
 	return v
 }
 
-func (v *MySQLVisitor) VisitComparison(n *nodes.ComparisonNode) string {
+// mysqlComparison renders the comparison operators MySQL spells differently.
+func mysqlComparison(r *renderer, n *nodes.ComparisonNode) bool {
+	var op string
 	switch n.Op {
 	case nodes.OpRegexp:
-		return n.Left.Accept(v) + " REGEXP " + n.Right.Accept(v)
+		op = " REGEXP "
 	case nodes.OpNotRegexp:
-		return n.Left.Accept(v) + " NOT REGEXP " + n.Right.Accept(v)
+		op = " NOT REGEXP "
 	case nodes.OpCaseSensitiveEq:
-		return n.Left.Accept(v) + " = BINARY " + n.Right.Accept(v)
+		op = " = BINARY "
 	case nodes.OpCaseInsensitiveEq:
-		return n.Left.Accept(v) + " = " + n.Right.Accept(v)
+		op = " = "
+	default:
+		return false
+	}
+	r.node(n.Left)
+	r.write(op)
+	r.node(n.Right)
+	return true
+}
+
+// explainMySQL writes EXPLAIN ANALYZE or EXPLAIN FORMAT=JSON. MySQL has no
+// buffer or verbose output, and EXPLAIN ANALYZE only produces a text tree.
+func explainMySQL(r *renderer, o nodes.ExplainOptions) {
+	switch {
+	case o.Buffers:
+		r.unsupported("EXPLAIN BUFFERS")
+	case o.Verbose:
+		r.unsupported("EXPLAIN VERBOSE")
+	case o.Analyze && o.Format == nodes.ExplainJSON:
+		r.unsupported("EXPLAIN ANALYZE with FORMAT=JSON")
+	}
+	switch {
+	case o.Analyze:
+		r.write("EXPLAIN ANALYZE ")
+	case o.Format == nodes.ExplainJSON:
+		r.write("EXPLAIN FORMAT=JSON ")
 	default:
-		return v.baseVisitor.VisitComparison(n)
+		r.write("EXPLAIN ")
 	}
 }
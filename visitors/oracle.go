@@ -0,0 +1,243 @@
+package visitors
+
+import (
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// OracleVisitor generates Oracle SQL for Oracle Database 12c and later.
+// Identifiers are quoted with double quotes: "table"."column".
+//
+// LIMIT and OFFSET become OFFSET n ROWS FETCH FIRST m ROWS ONLY, table
+// aliases are written without AS, and a SELECT without a FROM clause reads
+// from DUAL. RETURNING becomes RETURNING ... INTO with an OutParam bound for
+// each returned column, and ON CONFLICT becomes a MERGE statement.
+type OracleVisitor struct {
+	*BaseVisitor
+}
+
+// OutParam is the bind parameter recorded for each column of an Oracle
+// RETURNING ... INTO clause. Replace it with a sql.Out whose Dest receives
+// the returned value before executing the statement.
+type OutParam struct {
+	Column string // the returned column, or its alias
+}
+
+// NewOracleVisitor creates an OracleVisitor ready for use.
+// Parameterized mode is enabled by default for SQL injection protection.
+// Pass WithoutParams() to disable (not recommended for production).
+func NewOracleVisitor(opts ...Option) *OracleVisitor {
+	v := &OracleVisitor{}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "Oracle",
+		quote:            writeDoubleQuoted,
+		plainIdent:       isUpperIdent,
+		reserved:         oracleReserved,
+		maxIdentLen:      128,
+		writePlaceholder: writeColonPlaceholder,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			alterSingleAction:  true,
+			addWithoutColumn:   true,
+			noPartialIndex:     true,
+			noIndexIfNotExists: true,
+			onlineIndex:        " ONLINE",
+			noDropCascade:      true,
+			noDropIfExists:     true,
+			noTableIfNotExists: true,
+			noStoredGenerated:  true,
+			ctasColumnNames:    true,
+		},
+		comparison:        oracleComparison,
+		explain:           explainOracle,
+		function:          oracleFunction,
+		upsert:            oracleUpsert,
+		numericBools:      true,
+		plainRecursive:    true,
+		limitStyle:        limitFetch,
+		returningInto:     true,
+		bareTableAlias:    true,
+		fromDual:          true,
+		singleRowValues:   true,
+		applyLateral:      true,
+		minusExcept:       true,
+		forUpdateOnly:     true,
+		noDistinctOn:      true,
+		noAggregateFilter: true,
+	}}
+	v.applyOptions(opts)
+	return v
+}
+
+// oracleComparison renders the comparison operators Oracle spells
+// differently or lacks. IS DISTINCT FROM becomes DECODE, which treats two
+// NULLs as equal.
+func oracleComparison(r *renderer, n *nodes.ComparisonNode) bool {
+	switch n.Op {
+	case nodes.OpRegexp, nodes.OpNotRegexp:
+		if n.Op == nodes.OpNotRegexp {
+			r.write("NOT ")
+		}
+		r.write("REGEXP_LIKE(")
+		r.node(n.Left)
+		r.write(", ")
+		r.node(n.Right)
+		r.write(")")
+	case nodes.OpDistinctFrom, nodes.OpNotDistinctFrom:
+		r.write("DECODE(")
+		r.node(n.Left)
+		r.write(", ")
+		r.node(n.Right)
+		if n.Op == nodes.OpDistinctFrom {
+			r.write(", 0, 1) = 1")
+		} else {
+			r.write(", 1, 0) = 1")
+		}
+	case nodes.OpContains, nodes.OpOverlaps:
+		r.unsupported(comparisonOpSQL[n.Op] + " operator")
+	default:
+		return false
+	}
+	return true
+}
+
+// oracleFunction maps functions to their Oracle spellings: two-argument
+// COALESCE and IFNULL become NVL, SUBSTRING becomes SUBSTR, and CONCAT,
+// which Oracle limits to two arguments, becomes a chain of ||.
+func oracleFunction(r *renderer, n *nodes.NamedFunctionNode) bool {
+	if n.Distinct {
+		return false
+	}
+	switch strings.ToUpper(n.Name) {
+	case "COALESCE", "IFNULL":
+		if len(n.Args) != 2 {
+			return false
+		}
+		r.write("NVL(")
+		r.list(n.Args, ", ")
+		r.write(")")
+	case "SUBSTRING":
+		r.write("SUBSTR(")
+		r.list(n.Args, ", ")
+		r.write(")")
+	case "CONCAT":
+		if len(n.Args) < 2 {
+			return false
+		}
+		r.write("(")
+		for i, a := range n.Args {
+			if i > 0 {
+				r.write(" || ")
+			}
+			r.operand(a)
+		}
+		r.write(")")
+	default:
+		return false
+	}
+	return true
+}
+
+// explainOracle writes EXPLAIN PLAN FOR, which stores the plan in
+// PLAN_TABLE rather than returning it; it takes no options.
+func explainOracle(r *renderer, o nodes.ExplainOptions) {
+	switch {
+	case o.Analyze:
+		r.unsupported("EXPLAIN ANALYZE")
+	case o.Buffers:
+		r.unsupported("EXPLAIN BUFFERS")
+	case o.Verbose:
+		r.unsupported("EXPLAIN VERBOSE")
+	case o.Format == nodes.ExplainJSON:
+		r.unsupported("EXPLAIN FORMAT JSON")
+	}
+	r.write("EXPLAIN PLAN FOR ")
+}
+
+// oracleUpsert renders an INSERT ... ON CONFLICT as a MERGE statement. The
+// proposed rows become the source table "excluded", selected from DUAL
+// with one column per insert column:
+//
+//	MERGE INTO "users"
+//	USING (SELECT :1 AS "email", :2 AS "name" FROM DUAL) "excluded"
+//	ON ("users"."email" = "excluded"."email")
+//	WHEN MATCHED THEN UPDATE SET "users"."name" = "excluded"."name"
+//	WHEN NOT MATCHED THEN INSERT ("email", "name")
+//	VALUES ("excluded"."email", "excluded"."name")
+func oracleUpsert(r *renderer, n *nodes.InsertStatement) {
+	oc := n.OnConflict
+	switch {
+	case len(oc.Columns) == 0:
+		r.unsupported("MERGE upsert without conflict columns")
+	case len(n.Columns) == 0:
+		r.unsupported("MERGE upsert without an insert column list")
+	case n.Select != nil:
+		r.unsupported("MERGE upsert from a SELECT")
+	case len(n.Values) == 0:
+		r.unsupported("MERGE upsert without values")
+	case len(n.Returning) > 0:
+		r.unsupported("RETURNING in a MERGE upsert")
+	}
+	cols, keys := r.mergeNames(n.Columns), r.mergeNames(oc.Columns)
+	for i, row := range n.Values {
+		if len(row) != len(cols) {
+			renderErrorf("INSERT row %d has %d values for %d columns", i, len(row), len(cols))
+		}
+	}
+
+	r.write("MERGE INTO ")
+	r.node(n.Into)
+	r.write(" USING (")
+	for i, row := range n.Values {
+		if i > 0 {
+			r.write(" UNION ALL ")
+		}
+		r.write("SELECT ")
+		for j, val := range row {
+			if j > 0 {
+				r.write(", ")
+			}
+			r.node(val)
+			r.write(" AS ")
+			r.ident(cols[j])
+		}
+		r.write(" FROM DUAL")
+	}
+	r.write(") ")
+	r.ident("excluded")
+
+	target := nodes.RelationName(n.Into)
+	r.write(" ON (")
+	for i, name := range keys {
+		if i > 0 {
+			r.write(" AND ")
+		}
+		r.ident(target)
+		r.write(".")
+		r.ident(name)
+		r.write(" = ")
+		r.ident("excluded")
+		r.write(".")
+		r.ident(name)
+	}
+	r.write(")")
+
+	if oc.Action == nodes.DoUpdate {
+		r.write(" WHEN MATCHED THEN UPDATE")
+		r.assignments(" SET ", oc.Assignments)
+		r.clause(" WHERE ", oc.Wheres, " AND ")
+	}
+	r.write(" WHEN NOT MATCHED THEN INSERT ")
+	r.columnNames(n.Columns)
+	r.write(" VALUES (")
+	for i, name := range cols {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.ident("excluded")
+		r.write(".")
+		r.ident(name)
+	}
+	r.write(")")
+}
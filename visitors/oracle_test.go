@@ -0,0 +1,180 @@
+package visitors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bawdo/gosbee/internal/testutil"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// Statements translated from PostgreSQL are covered by the golden files in
+// parser/testdata/oracle.
+
+func TestOracleParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From:        users,
+		Projections: []nodes.Node{users.Col("id")},
+		Wheres: []nodes.Node{
+			users.Col("name").Eq(nodes.Named("name")),
+			users.Col("email").Eq(nodes.Named("name")),
+		},
+		Limit: nodes.Literal(10),
+	}
+	sql, params, err := NewOracleVisitor().Dialect().Render(core)
+	testutil.AssertNoError(t, err)
+	// Oracle binds by position, so a repeated name takes a new slot.
+	testutil.AssertEqual(t, sql, `SELECT "users"."id" FROM "users" WHERE "users"."name" = :1 AND "users"."email" = :2 FETCH FIRST :3 ROWS ONLY`)
+	testutil.AssertEqual(t, len(params), 3)
+}
+
+func TestOracleReturningInto(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.InsertStatement{
+		Into:      users,
+		Columns:   []nodes.Node{users.Col("email")},
+		Values:    [][]nodes.Node{{nodes.Literal("a@b.com")}},
+		Returning: []nodes.Node{users.Col("id"), &nodes.AliasNode{Expr: nodes.Upper(users.Col("email")), Name: "shout"}},
+	}
+	for _, v := range []*OracleVisitor{NewOracleVisitor(), NewOracleVisitor(WithoutParams())} {
+		sql, params, err := v.Dialect().Render(stmt)
+		testutil.AssertNoError(t, err)
+		if v.d.parameterize {
+			testutil.AssertEqual(t, sql, `INSERT INTO "users" ("email") VALUES (:1) RETURNING "id", UPPER("users"."email") INTO :2, :3`)
+			testutil.AssertEqual(t, len(params), 3)
+			testutil.AssertEqual(t, params[1], any(OutParam{Column: "id"}))
+			testutil.AssertEqual(t, params[2], any(OutParam{Column: "shout"}))
+		} else {
+			testutil.AssertEqual(t, sql, `INSERT INTO "users" ("email") VALUES ('a@b.com') RETURNING "id", UPPER("users"."email") INTO :1, :2`)
+			testutil.AssertEqual(t, len(params), 2)
+			testutil.AssertEqual(t, params[0], any(OutParam{Column: "id"}))
+			testutil.AssertEqual(t, params[1], any(OutParam{Column: "shout"}))
+		}
+	}
+}
+
+func TestOracleQuoteWhenNeeded(t *testing.T) {
+	t.Parallel()
+	v := NewOracleVisitor(WithQuotePolicy(QuoteWhenNeeded), WithoutParams())
+	tbl := nodes.NewTable("ORDERS")
+	core := &nodes.SelectCore{
+		From:        tbl,
+		Projections: []nodes.Node{tbl.Col("ID"), tbl.Col("LEVEL"), tbl.Col("total")},
+	}
+	testutil.AssertSQL(t, v, core, `SELECT ORDERS.ID, ORDERS."LEVEL", ORDERS."total" FROM ORDERS`)
+}
+
+func TestOracleUpsertParams(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.InsertStatement{
+		Into:    users,
+		Columns: []nodes.Node{users.Col("email"), users.Col("visits")},
+		Values:  [][]nodes.Node{{nodes.Literal("a@b.com"), nodes.Literal(1)}},
+		OnConflict: &nodes.OnConflictNode{
+			Columns: []nodes.Node{users.Col("email")},
+			Action:  nodes.DoUpdate,
+			Assignments: []*nodes.AssignmentNode{{
+				Left:  users.Col("visits"),
+				Right: users.Col("visits").Plus(nodes.NewTable("excluded").Col("visits")),
+			}},
+		},
+	}
+	sql, params, err := NewOracleVisitor().Dialect().Render(stmt)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `MERGE INTO "users" USING (SELECT :1 AS "email", :2 AS "visits" FROM DUAL) "excluded" `+
+		`ON ("users"."email" = "excluded"."email") `+
+		`WHEN MATCHED THEN UPDATE SET "users"."visits" = "users"."visits" + "excluded"."visits" `+
+		`WHEN NOT MATCHED THEN INSERT ("email", "visits") VALUES ("excluded"."email", "excluded"."visits")`)
+	testutil.AssertEqual(t, len(params), 2)
+	testutil.AssertEqual(t, params[0], any("a@b.com"))
+}
+
+func TestOracleDDL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	d := NewOracleVisitor().Dialect()
+
+	sql, err := renderDDL(d, &nodes.AlterTableStatement{
+		Table: users,
+		Actions: []*nodes.AlterAction{
+			{Kind: nodes.AlterAddColumn, Column: nodes.NewColumnDef("active", "NUMBER(1)", nodes.Default(true))},
+			{Kind: nodes.AlterRenameColumn, Name: "mail", NewName: "email"},
+		},
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `ALTER TABLE "users" ADD "active" NUMBER(1) DEFAULT 1; ALTER TABLE "users" RENAME COLUMN "mail" TO "email"`)
+
+	sql, err = renderDDL(d, &nodes.CreateIndexStatement{
+		Name: "users_email", Table: users, Columns: []nodes.Node{users.Col("email")}, Concurrently: true,
+	})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `CREATE INDEX "users_email" ON "users" ("email") ONLINE`)
+
+	sql, err = renderDDL(d, &nodes.ExplainStatement{Statement: &nodes.SelectCore{From: users}})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, sql, `EXPLAIN PLAN FOR SELECT * FROM "users"`)
+}
+
+func TestOracleUnsupported(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	tests := []struct {
+		name    string
+		stmt    nodes.Node
+		feature string
+	}{
+		{"explain analyze", &nodes.ExplainStatement{Statement: &nodes.SelectCore{From: users}, Options: nodes.ExplainOptions{Analyze: true}}, "EXPLAIN ANALYZE"},
+		{"drop if exists", &nodes.DropStatement{Kind: nodes.DropTable, Name: "users", IfExists: true}, "DROP ... IF EXISTS"},
+		{"stored generated column", &nodes.CreateTableStatement{Table: users, Columns: []*nodes.ColumnDef{
+			nodes.NewColumnDef("total", "NUMBER", nodes.GeneratedAs(users.Col("a").Plus(1))),
+		}}, "stored generated columns"},
+		{"overlaps", &nodes.SelectCore{From: users, Wheres: []nodes.Node{
+			&nodes.ComparisonNode{Left: users.Col("a"), Right: users.Col("b"), Op: nodes.OpOverlaps},
+		}}, "&& operator"},
+		{"upsert on a conflict expression", &nodes.InsertStatement{
+			Into:       users,
+			Columns:    []nodes.Node{users.Col("email")},
+			Values:     [][]nodes.Node{{nodes.Literal("a@b.com")}},
+			OnConflict: &nodes.OnConflictNode{Columns: []nodes.Node{nodes.NewSqlLiteral("lower(email)")}},
+		}, "MERGE upsert on a column expression"},
+		{"upsert of a raw insert column", &nodes.InsertStatement{
+			Into:       users,
+			Columns:    []nodes.Node{nodes.NewSqlLiteral("email")},
+			Values:     [][]nodes.Node{{nodes.Literal("a@b.com")}},
+			OnConflict: &nodes.OnConflictNode{Columns: []nodes.Node{users.Col("email")}},
+		}, "MERGE upsert on a column expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := renderDDL(NewOracleVisitor().Dialect(), tt.stmt)
+			var ue *nodes.UnsupportedError
+			if !errors.As(err, &ue) {
+				t.Fatalf("expected *nodes.UnsupportedError, got %v", err)
+			}
+			testutil.AssertEqual(t, ue.Feature, tt.feature)
+			testutil.AssertEqual(t, ue.Dialect, "Oracle")
+		})
+	}
+}
+
+func TestOracleUpsertRowLength(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.InsertStatement{
+		Into:       users,
+		Columns:    []nodes.Node{users.Col("email")},
+		Values:     [][]nodes.Node{{nodes.Literal("a@b.com"), nodes.Literal("Alice")}},
+		OnConflict: &nodes.OnConflictNode{Columns: []nodes.Node{users.Col("email")}},
+	}
+	_, err := renderDDL(NewOracleVisitor().Dialect(), stmt)
+	var re *nodes.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected *nodes.RenderError, got %v", err)
+	}
+	testutil.AssertEqual(t, err.Error(), "gosbee: INSERT row 0 has 2 values for 1 columns")
+}
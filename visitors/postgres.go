@@ -1,15 +1,9 @@
 package visitors
 
-import (
-	"fmt"
-
-	"github.com/bawdo/gosbee/internal/quoting"
-)
-
 // PostgresVisitor generates PostgreSQL-dialect SQL.
 // Identifiers are quoted with double quotes: "table"."column".
 type PostgresVisitor struct {
-	*baseVisitor
+	*BaseVisitor
 }
 
 // NewPostgresVisitor creates a PostgresVisitor ready for use.
@@ -17,12 +11,22 @@ type PostgresVisitor struct {
 // Pass WithoutParams() to disable (not recommended for production).
 func NewPostgresVisitor(opts ...Option) *PostgresVisitor {
 	v := &PostgresVisitor{}
-	v.baseVisitor = &baseVisitor{
-		outer:        v,
-		quoteIdent:   quoting.DoubleQuote,
-		placeholder:  func(i int) string { return fmt.Sprintf("$%d", i) },
-		parameterize: true, // Enable by default
-	}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "PostgreSQL",
+		quote:            writeDoubleQuoted,
+		plainIdent:       isLowerIdent,
+		reserved:         postgresReserved,
+		maxIdentLen:      63,
+		writePlaceholder: writeDollarPlaceholder,
+		numberedParams:   true,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			alterSeparateRename: true,
+			concurrentIndex:     true,
+			materializedViews:   true,
+			ctasColumnNames:     true,
+		},
+	}}
 	v.applyOptions(opts)
 	return v
 }
@@ -0,0 +1,1332 @@
+package visitors
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/bawdo/gosbee/internal/quoting"
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// writeDoubleQuoted writes a double-quoted identifier (PostgreSQL, SQLite).
+func writeDoubleQuoted(sb *strings.Builder, name string) {
+	sb.WriteByte('"')
+	if strings.IndexByte(name, '"') >= 0 {
+		name = strings.ReplaceAll(name, `"`, `""`)
+	}
+	sb.WriteString(name)
+	sb.WriteByte('"')
+}
+
+// writeBacktickQuoted writes a backtick-quoted identifier (MySQL).
+func writeBacktickQuoted(sb *strings.Builder, name string) {
+	sb.WriteByte('`')
+	if strings.IndexByte(name, '`') >= 0 {
+		name = strings.ReplaceAll(name, "`", "``")
+	}
+	sb.WriteString(name)
+	sb.WriteByte('`')
+}
+
+// writeBracketQuoted writes a bracket-quoted identifier (SQL Server).
+func writeBracketQuoted(sb *strings.Builder, name string) {
+	sb.WriteByte('[')
+	if strings.IndexByte(name, ']') >= 0 {
+		name = strings.ReplaceAll(name, "]", "]]")
+	}
+	sb.WriteString(name)
+	sb.WriteByte(']')
+}
+
+// writeDollarPlaceholder writes a PostgreSQL-style numbered placeholder.
+func writeDollarPlaceholder(sb *strings.Builder, index int, _ any) {
+	sb.WriteByte('$')
+	sb.WriteString(strconv.Itoa(index))
+}
+
+// writeAtPlaceholder writes a SQL Server-style numbered placeholder.
+func writeAtPlaceholder(sb *strings.Builder, index int, _ any) {
+	sb.WriteString("@p")
+	sb.WriteString(strconv.Itoa(index))
+}
+
+// writeColonPlaceholder writes an Oracle-style numbered placeholder.
+func writeColonPlaceholder(sb *strings.Builder, index int, _ any) {
+	sb.WriteByte(':')
+	sb.WriteString(strconv.Itoa(index))
+}
+
+// writeQuestionPlaceholder writes a positional ? placeholder.
+func writeQuestionPlaceholder(sb *strings.Builder, _ int, _ any) {
+	sb.WriteByte('?')
+}
+
+// renderer is the per-call render context. It walks the AST once, writing
+// SQL into a single builder and collecting bind parameters. A renderer is
+// created for every rendering call and is never shared between goroutines.
+type renderer struct {
+	d          *Dialect
+	sb         strings.Builder
+	buf        *strings.Builder // current output; usually &sb
+	params     []any
+	paramIndex int
+	namedIndex map[string]int
+	shim       *BaseVisitor // lazily built Visitor bound to this renderer
+	inDDL      bool         // rendering a DDL expression; see ddlExpr
+	inline     bool         // write values inline instead of binding them
+	overriding nodes.Node   // node being rendered by a dialect override
+}
+
+// newRenderer returns a renderer writing into its own builder.
+func newRenderer(d *Dialect) *renderer {
+	r := &renderer{d: d}
+	r.buf = &r.sb
+	r.sb.Grow(256)
+	return r
+}
+
+// String returns everything written so far.
+func (r *renderer) String() string {
+	return r.buf.String()
+}
+
+// write appends raw SQL text.
+func (r *renderer) write(s string) {
+	r.buf.WriteString(s)
+}
+
+// ident writes a quoted identifier.
+func (r *renderer) ident(name string) {
+	r.d.writeIdent(r.buf, name)
+}
+
+// bind records a parameter value and writes its placeholder.
+func (r *renderer) bind(val any) {
+	r.paramIndex++
+	if r.d.namedArgs {
+		r.params = append(r.params, sql.Named(placeholderName(r.paramIndex, val), val))
+	} else {
+		r.params = append(r.params, val)
+	}
+	r.d.writePlaceholder(r.buf, r.paramIndex, val)
+}
+
+// placeholderName returns the name of a by-name placeholder: the name of
+// a named parameter, or p<index> for any other value.
+func placeholderName(index int, val any) string {
+	if n, ok := val.(*nodes.NamedParamNode); ok {
+		return n.Name
+	}
+	return "p" + strconv.Itoa(index)
+}
+
+// capture renders n into a separate buffer and returns the text, sharing
+// the parameter state with the enclosing render.
+func (r *renderer) capture(n nodes.Node) string {
+	saved := r.buf
+	var sb strings.Builder
+	r.buf = &sb
+	r.node(n)
+	r.buf = saved
+	return sb.String()
+}
+
+// visitor returns a nodes.Visitor that renders through this renderer.
+// It is used for node types the renderer does not know about, which can
+// only be rendered by calling their Accept method.
+func (r *renderer) visitor() nodes.Visitor {
+	if r.shim == nil {
+		r.shim = &BaseVisitor{d: r.d, active: r}
+	}
+	return r.shim
+}
+
+// node dispatches on the concrete node type and writes its SQL.
+func (r *renderer) node(n nodes.Node) {
+	if len(r.d.overrides) > 0 && r.override(n) {
+		return
+	}
+	switch n := n.(type) {
+	case *nodes.Table:
+		r.ident(n.Name)
+	case *nodes.TableAlias:
+		r.tableAlias(n)
+	case *nodes.Attribute:
+		r.attribute(n)
+	case *nodes.LiteralNode:
+		r.literal(n.Value)
+	case *nodes.StarNode:
+		r.star(n)
+	case *nodes.SqlLiteral:
+		r.sqlLiteral(n)
+	case *nodes.ComparisonNode:
+		r.comparison(n)
+	case *nodes.UnaryNode:
+		r.unary(n)
+	case *nodes.AndNode:
+		r.node(n.Left)
+		r.write(" AND ")
+		r.node(n.Right)
+	case *nodes.OrNode:
+		r.node(n.Left)
+		r.write(" OR ")
+		r.node(n.Right)
+	case *nodes.NotNode:
+		r.write("NOT (")
+		r.node(n.Expr)
+		r.write(")")
+	case *nodes.InNode:
+		r.in(n)
+	case *nodes.BetweenNode:
+		r.between(n)
+	case *nodes.GroupingNode:
+		r.write("(")
+		r.node(n.Expr)
+		r.write(")")
+	case *nodes.JoinNode:
+		r.join(n)
+	case *nodes.OrderingNode:
+		r.ordering(n)
+	case *nodes.SelectCore:
+		r.selectCore(n)
+	case *nodes.InsertStatement:
+		r.insertStatement(n)
+	case *nodes.UpdateStatement:
+		r.updateStatement(n)
+	case *nodes.DeleteStatement:
+		r.deleteStatement(n)
+	case *nodes.AssignmentNode:
+		r.node(n.Left)
+		r.write(" = ")
+		r.node(n.Right)
+	case *nodes.OnConflictNode:
+		r.onConflict(n)
+	case *nodes.InfixNode:
+		r.infix(n)
+	case *nodes.UnaryMathNode:
+		r.write("~")
+		r.operand(n.Expr)
+	case *nodes.AggregateNode:
+		r.aggregate(n)
+	case *nodes.ExtractNode:
+		r.write("EXTRACT(")
+		r.write(extractFieldSQL[n.Field])
+		r.write(" FROM ")
+		r.node(n.Expr)
+		r.write(")")
+	case *nodes.WindowFuncNode:
+		r.write(windowFuncSQL[n.Func])
+		r.write("(")
+		r.list(n.Args, ", ")
+		r.write(")")
+	case *nodes.OverNode:
+		r.over(n)
+	case *nodes.ExistsNode:
+		if n.Negated {
+			r.write("NOT ")
+		}
+		r.write("EXISTS (")
+		r.node(n.Subquery)
+		r.write(")")
+	case *nodes.SetOperationNode:
+		r.setOperation(n)
+	case *nodes.CTENode:
+		r.cte(n)
+	case *nodes.NamedFunctionNode:
+		r.namedFunction(n)
+	case *nodes.CaseNode:
+		r.caseExpr(n)
+	case *nodes.GroupingSetNode:
+		r.groupingSet(n)
+	case *nodes.AliasNode:
+		r.node(n.Expr)
+		r.write(" AS ")
+		r.ident(n.Name)
+	case *nodes.BindParamNode:
+		// Always parameterize if in param mode, otherwise render as literal.
+		if r.d.parameterize && !r.inline {
+			r.bind(n.Value)
+		} else {
+			r.literal(n.Value)
+		}
+	case *nodes.CastedNode:
+		r.casted(n)
+	case *nodes.NamedParamNode:
+		r.namedParam(n)
+	case *nodes.CreateTableStatement:
+		r.createTable(n)
+	case *nodes.AlterTableStatement:
+		r.alterTable(n)
+	case *nodes.CreateIndexStatement:
+		r.createIndex(n)
+	case *nodes.DropStatement:
+		r.drop(n)
+	case *nodes.CreateViewStatement:
+		r.createView(n)
+	case *nodes.CreateTableAsStatement:
+		r.createTableAs(n)
+	case *nodes.RefreshMaterializedViewStatement:
+		r.refreshMaterializedView(n)
+	case *nodes.ExplainStatement:
+		r.explain(n)
+	case nodes.Extension:
+		n.RenderSQL(r)
+	default:
+		r.write(n.Accept(r.visitor()))
+	}
+}
+
+// list writes items separated by sep.
+func (r *renderer) list(items []nodes.Node, sep string) {
+	for i, item := range items {
+		if i > 0 {
+			r.write(sep)
+		}
+		r.node(item)
+	}
+}
+
+// clause writes "keyword item1 sep item2 sep ..." if items is non-empty.
+func (r *renderer) clause(keyword string, items []nodes.Node, sep string) {
+	if len(items) == 0 {
+		return
+	}
+	r.write(keyword)
+	r.list(items, sep)
+}
+
+// nodeClause writes "keyword node" if node is non-nil.
+func (r *renderer) nodeClause(keyword string, n nodes.Node) {
+	if n != nil {
+		r.write(keyword)
+		r.node(n)
+	}
+}
+
+// operand writes n, wrapped in parentheses when it is itself an infix or
+// unary math expression.
+func (r *renderer) operand(n nodes.Node) {
+	if needsParens(n) {
+		r.write("(")
+		r.node(n)
+		r.write(")")
+		return
+	}
+	r.node(n)
+}
+
+func (r *renderer) tableAlias(n *nodes.TableAlias) {
+	if tbl, ok := n.Relation.(*nodes.Table); ok {
+		r.ident(tbl.Name)
+	} else {
+		r.write("(")
+		r.node(n.Relation)
+		r.write(")")
+	}
+	if r.d.bareTableAlias {
+		r.write(" ")
+	} else {
+		r.write(" AS ")
+	}
+	r.ident(n.AliasName)
+}
+
+func (r *renderer) attribute(n *nodes.Attribute) {
+	if r.inDDL {
+		r.ident(n.Name)
+		return
+	}
+	r.ident(nodes.RelationName(n.Relation))
+	r.write(".")
+	r.ident(n.Name)
+}
+
+func (r *renderer) star(n *nodes.StarNode) {
+	if n.Table != nil {
+		r.ident(n.Table.Name)
+		r.write(".")
+	}
+	r.write("*")
+	if len(n.Exclude) == 0 && len(n.Replace) == 0 {
+		return
+	}
+	if r.d.starExclude == "" {
+		r.unsupported("star EXCLUDE and REPLACE")
+	}
+	if len(n.Exclude) > 0 {
+		r.write(" ")
+		r.write(r.d.starExclude)
+		r.write(" (")
+		r.identList(n.Exclude)
+		r.write(")")
+	}
+	if len(n.Replace) > 0 {
+		r.write(" REPLACE (")
+		for i, a := range n.Replace {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.node(a)
+		}
+		r.write(")")
+	}
+}
+
+func (r *renderer) literal(val any) {
+	// nil always renders as NULL keyword, never parameterized.
+	if val == nil {
+		r.write("NULL")
+		return
+	}
+
+	// In parameterize mode, emit a placeholder and collect the value.
+	if r.d.parameterize && !r.inline {
+		r.bind(val)
+		return
+	}
+
+	switch v := val.(type) {
+	case string:
+		r.write("'")
+		r.write(quoting.EscapeString(v))
+		r.write("'")
+	case bool:
+		switch {
+		case r.d.numericBools && v:
+			r.write("1")
+		case r.d.numericBools:
+			r.write("0")
+		case v:
+			r.write("TRUE")
+		default:
+			r.write("FALSE")
+		}
+	case int:
+		r.write(strconv.FormatInt(int64(v), 10))
+	case int8:
+		r.write(strconv.FormatInt(int64(v), 10))
+	case int16:
+		r.write(strconv.FormatInt(int64(v), 10))
+	case int32:
+		r.write(strconv.FormatInt(int64(v), 10))
+	case int64:
+		r.write(strconv.FormatInt(v, 10))
+	case uint:
+		r.write(strconv.FormatUint(uint64(v), 10))
+	case uint8:
+		r.write(strconv.FormatUint(uint64(v), 10))
+	case uint16:
+		r.write(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		r.write(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		r.write(strconv.FormatUint(v, 10))
+	case float32:
+		r.write(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		r.write(strconv.FormatFloat(v, 'g', -1, 64))
+	default:
+		renderErrorf("unsupported literal type %T", v)
+	}
+}
+
+func (r *renderer) sqlLiteral(n *nodes.SqlLiteral) {
+	if n.Placeholders {
+		r.fragment(n)
+		return
+	}
+	if r.inline && len(n.Binds) > 0 {
+		// The placeholders in Raw cannot be filled in without params.
+		renderErrorf("SQL literal %q binds %d argument(s), which DDL cannot take", string(n.Raw), len(n.Binds))
+	}
+	if r.d.parameterize && len(n.Binds) > 0 {
+		r.params = append(r.params, n.Binds...)
+		r.paramIndex += len(n.Binds)
+	}
+	r.write(string(n.Raw))
+}
+
+// fragment writes a placeholder-aware SqlLiteral, replacing each marker
+// with its argument: nodes are rendered in place and other values are
+// bound (or inlined when parameterisation is off).
+func (r *renderer) fragment(n *nodes.SqlLiteral) {
+	parts := nodes.SplitFragment(n.Raw)
+	if len(parts)-1 != len(n.Binds) {
+		renderErrorf("SQL fragment %q has %d placeholder(s) but %d argument(s)", string(n.Raw), len(parts)-1, len(n.Binds))
+	}
+	for i, part := range parts {
+		r.write(part)
+		if i == len(n.Binds) {
+			break
+		}
+		if arg, ok := n.Binds[i].(nodes.Node); ok {
+			r.node(arg)
+		} else {
+			r.literal(n.Binds[i])
+		}
+	}
+}
+
+func (r *renderer) comparison(n *nodes.ComparisonNode) {
+	if r.d.comparison != nil && r.d.comparison(r, n) {
+		return
+	}
+	if n.Op == nodes.OpCaseInsensitiveEq {
+		r.write("LOWER(")
+		r.node(n.Left)
+		r.write(") = LOWER(")
+		r.node(n.Right)
+		r.write(")")
+		return
+	}
+	r.node(n.Left)
+	r.write(" ")
+	r.write(comparisonOpSQL[n.Op])
+	r.write(" ")
+	r.node(n.Right)
+}
+
+func (r *renderer) unary(n *nodes.UnaryNode) {
+	r.node(n.Expr)
+	switch n.Op {
+	case nodes.OpIsNull:
+		r.write(" IS NULL")
+	case nodes.OpIsNotNull:
+		r.write(" IS NOT NULL")
+	}
+}
+
+func (r *renderer) in(n *nodes.InNode) {
+	r.node(n.Expr)
+	if n.Negate {
+		r.write(" NOT IN (")
+	} else {
+		r.write(" IN (")
+	}
+	if r.d.normalize && valuesOnly(n.Vals) {
+		r.write("...")
+	} else {
+		r.list(n.Vals, ", ")
+	}
+	r.write(")")
+}
+
+func (r *renderer) between(n *nodes.BetweenNode) {
+	r.node(n.Expr)
+	if n.Negate {
+		r.write(" NOT BETWEEN ")
+	} else {
+		r.write(" BETWEEN ")
+	}
+	r.node(n.Low)
+	r.write(" AND ")
+	r.node(n.High)
+}
+
+func (r *renderer) ordering(n *nodes.OrderingNode) {
+	r.node(n.Expr)
+	r.orderingSuffix(n)
+}
+
+// orderingSuffix writes the direction and NULLS placement of an ordering.
+func (r *renderer) orderingSuffix(n *nodes.OrderingNode) {
+	if n.Direction == nodes.Desc {
+		r.write(" DESC")
+	} else {
+		r.write(" ASC")
+	}
+	switch n.Nulls {
+	case nodes.NullsFirst:
+		r.write(" NULLS FIRST")
+	case nodes.NullsLast:
+		r.write(" NULLS LAST")
+	}
+}
+
+func (r *renderer) join(n *nodes.JoinNode) {
+	// StringJoin: raw SQL fragment, output directly.
+	if n.Type == nodes.StringJoin {
+		r.node(n.Right)
+		return
+	}
+
+	if n.Type == nodes.ArrayJoin || n.Type == nodes.LeftArrayJoin {
+		r.arrayJoin(n)
+		return
+	}
+	if n.Lateral && r.d.noLateral {
+		r.unsupported("LATERAL joins")
+	}
+	if n.Lateral && r.d.applyLateral {
+		r.applyJoin(n)
+		return
+	}
+
+	r.write(joinTypeSQL[n.Type])
+	if n.Lateral {
+		r.write(" LATERAL")
+	}
+	r.write(" ")
+
+	// Wrap subqueries in parentheses.
+	if _, ok := n.Right.(*nodes.SelectCore); ok {
+		r.write("(")
+		r.node(n.Right)
+		r.write(")")
+	} else {
+		r.node(n.Right)
+	}
+
+	r.nodeClause(" ON ", n.On)
+}
+
+// applyJoin writes a LATERAL join as CROSS APPLY or OUTER APPLY. APPLY
+// takes no ON clause, so only joins without one can be written.
+func (r *renderer) applyJoin(n *nodes.JoinNode) {
+	switch {
+	case n.On != nil:
+		r.unsupported("LATERAL join with ON")
+	case n.Type == nodes.CrossJoin, n.Type == nodes.InnerJoin:
+		r.write("CROSS APPLY ")
+	case n.Type == nodes.LeftOuterJoin:
+		r.write("OUTER APPLY ")
+	default:
+		r.unsupported("LATERAL " + joinTypeSQL[n.Type])
+	}
+	if _, ok := n.Right.(*nodes.SelectCore); ok {
+		r.write("(")
+		r.node(n.Right)
+		r.write(")")
+	} else {
+		r.node(n.Right)
+	}
+}
+
+// arrayJoin writes an ARRAY JOIN, which unfolds the array on its right
+// into one row per element and takes no ON clause.
+func (r *renderer) arrayJoin(n *nodes.JoinNode) {
+	switch {
+	case !r.d.arrayJoin:
+		r.unsupported(joinTypeSQL[n.Type])
+	case n.On != nil:
+		r.unsupported(joinTypeSQL[n.Type] + " with ON")
+	}
+	r.write(joinTypeSQL[n.Type])
+	r.write(" ")
+	r.node(n.Right)
+}
+
+// mergeNames returns the names of an upsert's insert or conflict columns
+// for dialects that write it as MERGE, which refers to each column by name
+// through the source alias. Column expressions cannot be referred to.
+func (r *renderer) mergeNames(cols []nodes.Node) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		a, ok := c.(*nodes.Attribute)
+		if !ok {
+			r.unsupported("MERGE upsert on a column expression")
+		}
+		names[i] = a.Name
+	}
+	return names
+}
+
+// columnNames writes a parenthesised list of bare column names. Other
+// nodes, such as a SqlLiteral expression index target, are rendered as is.
+func (r *renderer) columnNames(cols []nodes.Node) {
+	r.write("(")
+	for i, c := range cols {
+		if i > 0 {
+			r.write(", ")
+		}
+		if a, ok := c.(*nodes.Attribute); ok {
+			r.ident(a.Name)
+		} else {
+			r.node(c)
+		}
+	}
+	r.write(")")
+}
+
+func (r *renderer) insertStatement(n *nodes.InsertStatement) {
+	if n.OnConflict != nil && r.d.upsert != nil {
+		r.d.upsert(r, n)
+		return
+	}
+
+	r.write("INSERT INTO ")
+	r.node(n.Into)
+
+	// Columns
+	if len(n.Columns) > 0 {
+		r.write(" ")
+		r.columnNames(n.Columns)
+	}
+	r.output("INSERTED", n.Returning)
+	if r.d.returningInto && len(n.Returning) > 0 && (n.Select != nil || len(n.Values) > 1) {
+		r.unsupported("RETURNING ... INTO for a multi-row INSERT")
+	}
+
+	// INSERT FROM SELECT
+	if n.Select != nil {
+		r.write(" ")
+		r.node(n.Select)
+	} else if len(n.Values) > 1 && r.d.singleRowValues {
+		for i, row := range n.Values {
+			if i > 0 {
+				r.write(" UNION ALL")
+			}
+			r.write(" SELECT ")
+			r.list(row, ", ")
+			if r.d.fromDual {
+				r.write(" FROM DUAL")
+			}
+		}
+	} else if len(n.Values) > 0 {
+		r.write(" VALUES ")
+		for i, row := range n.Values {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.write("(")
+			r.list(row, ", ")
+			r.write(")")
+		}
+	}
+
+	// ON CONFLICT
+	if n.OnConflict != nil {
+		r.write(" ")
+		r.onConflict(n.OnConflict)
+	}
+
+	r.returning(n.Returning)
+}
+
+func (r *renderer) updateStatement(n *nodes.UpdateStatement) {
+	r.write("UPDATE ")
+	alias, aliased := n.Table.(*nodes.TableAlias)
+	aliased = aliased && r.d.aliasedTargets
+	if aliased {
+		r.ident(alias.AliasName)
+	} else {
+		r.node(n.Table)
+	}
+	r.assignments(" SET ", n.Assignments)
+	r.output("INSERTED", n.Returning)
+	if aliased {
+		r.write(" FROM ")
+		r.tableAlias(alias)
+	}
+	r.clause(" WHERE ", n.Wheres, " AND ")
+	r.returning(n.Returning)
+}
+
+func (r *renderer) deleteStatement(n *nodes.DeleteStatement) {
+	if alias, ok := n.From.(*nodes.TableAlias); ok && r.d.aliasedTargets {
+		r.write("DELETE ")
+		r.ident(alias.AliasName)
+		r.output("DELETED", n.Returning)
+		r.write(" FROM ")
+		r.tableAlias(alias)
+	} else {
+		r.write("DELETE FROM ")
+		r.node(n.From)
+		r.output("DELETED", n.Returning)
+	}
+	r.clause(" WHERE ", n.Wheres, " AND ")
+	r.returning(n.Returning)
+}
+
+// returning writes the RETURNING clause, unless the dialect returns rows
+// with an OUTPUT clause instead.
+func (r *renderer) returning(items []nodes.Node) {
+	switch {
+	case len(items) > 0 && r.d.noReturning:
+		r.unsupported("RETURNING")
+	case r.d.outputClause:
+	case r.d.returningInto:
+		r.returningInto(items)
+	default:
+		r.clause(" RETURNING ", items, ", ")
+	}
+}
+
+// returningInto writes RETURNING ... INTO, binding an OutParam for each
+// returned value. Columns are written unqualified and aliases name the
+// OutParam instead of the expression.
+func (r *renderer) returningInto(items []nodes.Node) {
+	if len(items) == 0 {
+		return
+	}
+	out := make([]OutParam, len(items))
+	r.write(" RETURNING ")
+	for i, item := range items {
+		if i > 0 {
+			r.write(", ")
+		}
+		if a, ok := item.(*nodes.AliasNode); ok {
+			out[i].Column = a.Name
+			item = a.Expr
+		}
+		switch n := item.(type) {
+		case *nodes.Attribute:
+			if out[i].Column == "" {
+				out[i].Column = n.Name
+			}
+			r.ident(n.Name)
+		case *nodes.StarNode:
+			r.unsupported("RETURNING *")
+		default:
+			r.node(n)
+		}
+	}
+	r.write(" INTO ")
+	for i, p := range out {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.bind(p)
+	}
+}
+
+// output writes an OUTPUT clause for dialects that have one. Columns are
+// read from the given pseudo-table: INSERTED for the new row values,
+// DELETED for the old.
+func (r *renderer) output(pseudo string, items []nodes.Node) {
+	if !r.d.outputClause || len(items) == 0 {
+		return
+	}
+	r.write(" OUTPUT ")
+	for i, item := range items {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.outputItem(pseudo, item)
+	}
+}
+
+func (r *renderer) outputItem(pseudo string, n nodes.Node) {
+	switch n := n.(type) {
+	case *nodes.Attribute:
+		r.write(pseudo)
+		r.write(".")
+		r.ident(n.Name)
+	case *nodes.StarNode:
+		r.write(pseudo)
+		r.write(".*")
+	case *nodes.AliasNode:
+		r.outputItem(pseudo, n.Expr)
+		r.write(" AS ")
+		r.ident(n.Name)
+	default:
+		r.node(n)
+	}
+}
+
+// assignments writes "keyword a1, a2, ..." if assigns is non-empty.
+func (r *renderer) assignments(keyword string, assigns []*nodes.AssignmentNode) {
+	if len(assigns) == 0 {
+		return
+	}
+	r.write(keyword)
+	for i, a := range assigns {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.node(a)
+	}
+}
+
+func (r *renderer) onConflict(n *nodes.OnConflictNode) {
+	r.write("ON CONFLICT")
+
+	if len(n.Columns) > 0 {
+		r.write(" ")
+		r.columnNames(n.Columns)
+	}
+
+	if n.Action == nodes.DoNothing {
+		r.write(" DO NOTHING")
+		return
+	}
+	r.write(" DO UPDATE SET ")
+	for i, a := range n.Assignments {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.node(a)
+	}
+	r.clause(" WHERE ", n.Wheres, " AND ")
+}
+
+func (r *renderer) infix(n *nodes.InfixNode) {
+	r.operand(n.Left)
+	r.write(" ")
+	if n.Op == nodes.OpConcat && r.d.concat != "" {
+		r.write(r.d.concat)
+	} else {
+		r.write(infixOpSQL[n.Op])
+	}
+	r.write(" ")
+	r.operand(n.Right)
+}
+
+func (r *renderer) aggregate(n *nodes.AggregateNode) {
+	if n.Filter != nil && r.d.noAggregateFilter {
+		r.unsupported("aggregate FILTER")
+	}
+	r.write(aggregateFuncSQL[n.Func])
+	r.write("(")
+	if n.Distinct {
+		r.write("DISTINCT ")
+	}
+	if n.Expr == nil {
+		r.write("*")
+	} else {
+		r.node(n.Expr)
+	}
+	r.write(")")
+	if n.Filter != nil {
+		r.write(" FILTER (WHERE ")
+		r.node(n.Filter)
+		r.write(")")
+	}
+}
+
+func (r *renderer) over(n *nodes.OverNode) {
+	r.node(n.Expr)
+	r.write(" OVER ")
+	if n.WindowName != "" {
+		r.ident(n.WindowName)
+	} else {
+		r.windowDef(n.Window)
+	}
+}
+
+// windowDef writes a window definition: (PARTITION BY ... ORDER BY ... ROWS/RANGE ...)
+func (r *renderer) windowDef(w *nodes.WindowDefinition) {
+	if w == nil {
+		r.write("()")
+		return
+	}
+	r.write("(")
+	needSpace := false
+	if len(w.PartitionBy) > 0 {
+		r.write("PARTITION BY ")
+		r.list(w.PartitionBy, ", ")
+		needSpace = true
+	}
+	if len(w.OrderBy) > 0 {
+		if needSpace {
+			r.write(" ")
+		}
+		r.write("ORDER BY ")
+		r.list(w.OrderBy, ", ")
+		needSpace = true
+	}
+	if w.Frame != nil {
+		if needSpace {
+			r.write(" ")
+		}
+		r.frame(w.Frame)
+	}
+	r.write(")")
+}
+
+func (r *renderer) frame(f *nodes.WindowFrame) {
+	r.write(frameTypeSQL[f.Type])
+	if f.End != nil {
+		r.write(" BETWEEN ")
+		r.frameBound(f.Start)
+		r.write(" AND ")
+		r.frameBound(*f.End)
+	} else {
+		r.write(" ")
+		r.frameBound(f.Start)
+	}
+}
+
+func (r *renderer) frameBound(fb nodes.FrameBound) {
+	switch fb.Type {
+	case nodes.BoundUnboundedPreceding:
+		r.write("UNBOUNDED PRECEDING")
+	case nodes.BoundPreceding:
+		r.node(fb.Offset)
+		r.write(" PRECEDING")
+	case nodes.BoundCurrentRow:
+		r.write("CURRENT ROW")
+	case nodes.BoundFollowing:
+		r.node(fb.Offset)
+		r.write(" FOLLOWING")
+	case nodes.BoundUnboundedFollowing:
+		r.write("UNBOUNDED FOLLOWING")
+	}
+}
+
+func (r *renderer) setOperation(n *nodes.SetOperationNode) {
+	op := setOpTypeSQL[n.Type]
+	if r.d.minusExcept {
+		switch n.Type {
+		case nodes.Except:
+			op = "MINUS"
+		case nodes.IntersectAll, nodes.ExceptAll:
+			r.unsupported(op)
+		}
+	}
+	r.write("(")
+	r.node(n.Left)
+	r.write(") ")
+	r.write(op)
+	r.write(" (")
+	r.node(n.Right)
+	r.write(")")
+	r.clause(" ORDER BY ", n.Orders, ", ")
+	r.limitOffset(len(n.Orders) > 0, n.Limit, n.Offset)
+}
+
+func (r *renderer) cte(n *nodes.CTENode) {
+	r.ident(n.Name)
+	if len(n.Columns) > 0 {
+		r.write(" (")
+		for i, c := range n.Columns {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.ident(c)
+		}
+		r.write(")")
+	}
+	r.write(" AS (")
+	r.node(n.Query)
+	r.write(")")
+}
+
+func (r *renderer) namedFunction(n *nodes.NamedFunctionNode) {
+	validateSQLFunctionName(n.Name)
+	if r.d.function != nil && r.d.function(r, n) {
+		return
+	}
+	// Special case: CAST(expr AS type)
+	if n.Name == "CAST" && len(n.Args) == 2 {
+		r.write("CAST(")
+		r.node(n.Args[0])
+		r.write(" AS ")
+		r.node(n.Args[1])
+		r.write(")")
+		return
+	}
+	r.write(n.Name)
+	r.write("(")
+	if n.Distinct {
+		r.write("DISTINCT ")
+	}
+	r.list(n.Args, ", ")
+	r.write(")")
+}
+
+func (r *renderer) caseExpr(n *nodes.CaseNode) {
+	r.write("CASE")
+	if n.Operand != nil {
+		r.write(" ")
+		r.node(n.Operand)
+	}
+	for _, w := range n.Whens {
+		r.write(" WHEN ")
+		r.node(w.Condition)
+		r.write(" THEN ")
+		r.node(w.Result)
+	}
+	r.nodeClause(" ELSE ", n.ElseVal)
+	r.write(" END")
+}
+
+func (r *renderer) groupingSet(n *nodes.GroupingSetNode) {
+	r.write(groupingSetTypeSQL[n.Type])
+	r.write("(")
+	if n.Type == nodes.GroupingSets {
+		// GROUPING SETS ((col1, col2), (col3), ())
+		for i, set := range n.Sets {
+			if i > 0 {
+				r.write(", ")
+			}
+			r.write("(")
+			r.list(set, ", ")
+			r.write(")")
+		}
+	} else {
+		// CUBE(col1, col2) or ROLLUP(col1, col2)
+		r.list(n.Columns, ", ")
+	}
+	r.write(")")
+}
+
+func (r *renderer) casted(n *nodes.CastedNode) {
+	if n.TypeName == "" {
+		r.literal(n.Value)
+		return
+	}
+	validateSQLTypeName(n.TypeName)
+	r.write("CAST(")
+	r.literal(n.Value)
+	r.write(" AS ")
+	r.write(n.TypeName)
+	r.write(")")
+}
+
+// namedParam always emits a bind placeholder, regardless of the
+// parameterize setting, and records the node itself in the parameter list
+// so that a compiled template can substitute the value by name. Dialects
+// with numbered placeholders reuse the first index for repeated names.
+func (r *renderer) namedParam(n *nodes.NamedParamNode) {
+	if r.inline {
+		r.unsupported("named parameters in DDL")
+	}
+	if r.d.numberedParams {
+		if idx, ok := r.namedIndex[n.Name]; ok {
+			r.d.writePlaceholder(r.buf, idx, n)
+			return
+		}
+	}
+	r.bind(n)
+	if r.d.numberedParams {
+		if r.namedIndex == nil {
+			r.namedIndex = make(map[string]int)
+		}
+		r.namedIndex[n.Name] = r.paramIndex
+	}
+}
+
+func (r *renderer) selectCore(n *nodes.SelectCore) {
+	r.ctes(n.CTEs)
+	r.comment(n.Comment)
+	r.write("SELECT ")
+	r.hints(n.Hints)
+	r.distinct(n.Distinct, n.DistinctOn)
+	top := r.d.limitStyle == limitTop && n.Limit != nil && n.Offset == nil
+	if top {
+		r.write("TOP (")
+		r.node(n.Limit)
+		r.write(") ")
+	}
+	r.projections(n.Projections)
+	r.nodeClause(" FROM ", n.From)
+	if n.From == nil && r.d.fromDual {
+		r.write(" FROM DUAL")
+	}
+	if n.Final {
+		r.final(n.From)
+	}
+	if n.Sample != nil && !r.d.sampleAfterJoins {
+		r.sample(n.Sample)
+	}
+	if n.Lock != nodes.NoLock && r.d.lockHint != nil {
+		r.d.lockHint(r, n)
+	}
+	for _, j := range n.Joins {
+		r.write(" ")
+		r.join(j)
+	}
+	if n.Sample != nil && r.d.sampleAfterJoins {
+		r.sample(n.Sample)
+	}
+	r.clause(" WHERE ", n.Wheres, " AND ")
+	r.clause(" GROUP BY ", n.Groups, ", ")
+	r.clause(" HAVING ", n.Havings, " AND ")
+	r.windowClause(n.Windows)
+	if len(n.Qualifies) > 0 && !r.d.qualify {
+		r.unsupported("QUALIFY")
+	}
+	r.clause(" QUALIFY ", n.Qualifies, " AND ")
+	r.clause(" ORDER BY ", n.Orders, ", ")
+	r.limitBy(n.LimitBy)
+	if !top {
+		r.limitOffset(len(n.Orders) > 0, n.Limit, n.Offset)
+	}
+	if r.d.lockHint == nil {
+		r.lock(n.Lock, n.SkipLocked)
+	}
+}
+
+// final writes the FINAL modifier, which makes ClickHouse merge the rows
+// of a table's parts before reading them.
+func (r *renderer) final(from nodes.Node) {
+	switch {
+	case !r.d.finalModifier:
+		r.unsupported("FINAL")
+	case from == nil:
+		r.unsupported("FINAL without a FROM table")
+	}
+	r.write(" FINAL")
+}
+
+// sample writes the dialect's SAMPLE clause. A sample reads either an
+// approximate number of rows or a ratio of them.
+func (r *renderer) sample(s *nodes.SampleClause) {
+	if r.d.sample == nil {
+		r.unsupported("SAMPLE")
+	}
+	if s.Rows == 0 && (s.Ratio <= 0 || s.Ratio > 1) {
+		renderErrorf("sample ratio %v is not in (0, 1]", s.Ratio)
+	}
+	r.d.sample(r, s)
+}
+
+// limitBy writes LIMIT n [OFFSET m] BY columns.
+func (r *renderer) limitBy(lb *nodes.LimitByClause) {
+	if lb == nil {
+		return
+	}
+	if !r.d.limitBy {
+		r.unsupported("LIMIT BY")
+	}
+	r.nodeClause(" LIMIT ", lb.Limit)
+	r.nodeClause(" OFFSET ", lb.Offset)
+	r.clause(" BY ", lb.Columns, ", ")
+}
+
+// limitOffset writes the clauses after ORDER BY that limit the rows
+// returned. ordered reports whether the query has an ORDER BY, which
+// OFFSET ... FETCH requires.
+func (r *renderer) limitOffset(ordered bool, limit, offset nodes.Node) {
+	switch r.d.limitStyle {
+	case limitKeyword:
+		r.nodeClause(" LIMIT ", limit)
+		r.nodeClause(" OFFSET ", offset)
+		return
+	case limitFetch:
+		if offset != nil {
+			r.write(" OFFSET ")
+			r.node(offset)
+			r.write(" ROWS")
+		}
+		if limit != nil {
+			r.write(" FETCH FIRST ")
+			r.node(limit)
+			r.write(" ROWS ONLY")
+		}
+		return
+	}
+	if limit == nil && offset == nil {
+		return
+	}
+	if !ordered {
+		r.unsupported("OFFSET ... FETCH without ORDER BY")
+	}
+	r.write(" OFFSET ")
+	if offset != nil {
+		r.node(offset)
+	} else {
+		r.write("0")
+	}
+	r.write(" ROWS")
+	if limit != nil {
+		r.write(" FETCH NEXT ")
+		r.node(limit)
+		r.write(" ROWS ONLY")
+	}
+}
+
+func (r *renderer) ctes(ctes []*nodes.CTENode) {
+	if len(ctes) == 0 {
+		return
+	}
+	hasRecursive := false
+	for _, cte := range ctes {
+		if cte.Recursive {
+			hasRecursive = true
+			break
+		}
+	}
+	if hasRecursive && !r.d.plainRecursive {
+		r.write("WITH RECURSIVE ")
+	} else {
+		r.write("WITH ")
+	}
+	for i, cte := range ctes {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.cte(cte)
+	}
+	r.write(" ")
+}
+
+func (r *renderer) comment(comment string) {
+	if comment != "" && !r.d.normalize {
+		r.write("/* ")
+		r.write(strings.ReplaceAll(comment, "*/", "* /"))
+		r.write(" */ ")
+	}
+}
+
+func (r *renderer) hints(hints []string) {
+	if len(hints) == 0 {
+		return
+	}
+	r.write("/*+ ")
+	for i, h := range hints {
+		if i > 0 {
+			r.write(" ")
+		}
+		r.write(strings.ReplaceAll(h, "*/", "* /"))
+	}
+	r.write(" */ ")
+}
+
+func (r *renderer) distinct(distinct bool, distinctOn []nodes.Node) {
+	if len(distinctOn) > 0 && r.d.noDistinctOn {
+		r.unsupported("DISTINCT ON")
+	}
+	if len(distinctOn) > 0 {
+		r.write("DISTINCT ON (")
+		r.list(distinctOn, ", ")
+		r.write(") ")
+	} else if distinct {
+		r.write("DISTINCT ")
+	}
+}
+
+func (r *renderer) projections(projections []nodes.Node) {
+	if len(projections) == 0 {
+		r.write("*")
+		return
+	}
+	r.list(projections, ", ")
+}
+
+func (r *renderer) windowClause(windows []*nodes.WindowDefinition) {
+	if len(windows) == 0 {
+		return
+	}
+	r.write(" WINDOW ")
+	for i, w := range windows {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.ident(w.Name)
+		r.write(" AS ")
+		r.windowDef(&nodes.WindowDefinition{
+			PartitionBy: w.PartitionBy,
+			OrderBy:     w.OrderBy,
+			Frame:       w.Frame,
+		})
+	}
+}
+
+func (r *renderer) lock(lock nodes.LockMode, skipLocked bool) {
+	if lock != nodes.NoLock && r.d.noLocks {
+		r.unsupported("row locks")
+	}
+	if lock != nodes.NoLock && lock != nodes.ForUpdate && r.d.forUpdateOnly {
+		r.unsupported(lockModeSQL[lock])
+	}
+	if lock != nodes.NoLock {
+		r.write(" ")
+		r.write(lockModeSQL[lock])
+		if skipLocked {
+			r.write(" SKIP LOCKED")
+		}
+	}
+}
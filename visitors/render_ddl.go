@@ -0,0 +1,501 @@
+package visitors
+
+import (
+	"github.com/bawdo/gosbee/nodes"
+)
+
+// ddlRules captures how a dialect's DDL differs from the PostgreSQL form.
+type ddlRules struct {
+	// hoistForeignKeys renders column-level REFERENCES as table-level
+	// FOREIGN KEY constraints (MySQL ignores inline REFERENCES).
+	hoistForeignKeys bool
+
+	// alterSingleAction renders each ALTER TABLE action as its own
+	// statement (SQLite).
+	alterSingleAction bool
+
+	// alterSeparateRename renders RENAME COLUMN as its own statement,
+	// since it cannot be combined with other actions (PostgreSQL).
+	alterSeparateRename bool
+
+	// noAddConstraint rejects ALTER TABLE ADD CONSTRAINT (SQLite).
+	noAddConstraint bool
+
+	// noPartialIndex rejects CREATE INDEX ... WHERE (MySQL).
+	noPartialIndex bool
+
+	// noIndexIfNotExists rejects CREATE INDEX IF NOT EXISTS (MySQL).
+	noIndexIfNotExists bool
+
+	// onlineIndex is the suffix that builds an index without blocking
+	// writes; empty when CONCURRENTLY is used instead (PostgreSQL) or the
+	// dialect has no equivalent (SQLite).
+	onlineIndex string
+
+	// concurrentIndex allows CREATE/DROP INDEX CONCURRENTLY (PostgreSQL).
+	concurrentIndex bool
+
+	// dropIndexOnTable renders DROP INDEX name ON table (MySQL).
+	dropIndexOnTable bool
+
+	// noDropCascade rejects DROP ... CASCADE (SQLite).
+	noDropCascade bool
+
+	// materializedViews allows materialized views and REFRESH
+	// (PostgreSQL).
+	materializedViews bool
+
+	// noOrReplaceView rejects CREATE OR REPLACE VIEW (SQLite).
+	noOrReplaceView bool
+
+	// viewIfNotExists allows CREATE VIEW IF NOT EXISTS (SQLite); other
+	// dialects accept it only for materialized views.
+	viewIfNotExists bool
+
+	// ctasColumnNames allows a column name list in CREATE TABLE AS
+	// (PostgreSQL).
+	ctasColumnNames bool
+
+	// noCreateTableAs rejects CREATE TABLE AS (SQL Server, which uses
+	// SELECT ... INTO).
+	noCreateTableAs bool
+
+	// noTableIfNotExists rejects CREATE TABLE IF NOT EXISTS (SQL Server).
+	noTableIfNotExists bool
+
+	// noGeneratedColumns rejects GENERATED ALWAYS AS columns (SQL Server,
+	// whose computed columns use a different syntax).
+	noGeneratedColumns bool
+
+	// addWithoutColumn writes ALTER TABLE ... ADD without the COLUMN
+	// keyword (SQL Server).
+	addWithoutColumn bool
+
+	// noRenameColumn rejects ALTER TABLE RENAME COLUMN (SQL Server, which
+	// renames with sp_rename).
+	noRenameColumn bool
+
+	// noStoredGenerated rejects stored generated columns; only virtual
+	// ones are accepted (Oracle).
+	noStoredGenerated bool
+
+	// noDropIfExists rejects DROP ... IF EXISTS (Oracle).
+	noDropIfExists bool
+}
+
+// unsupported aborts rendering with a *nodes.UnsupportedError, which
+// manager ToSQL methods return as an error.
+func (r *renderer) unsupported(feature string) {
+	panic(&nodes.UnsupportedError{Dialect: r.d.name, Feature: feature})
+}
+
+// ddlExpr renders an expression inside a DDL statement: column references
+// are unqualified and values are always inlined, since DDL cannot take
+// bind parameters.
+func (r *renderer) ddlExpr(n nodes.Node) {
+	savedDDL, savedInline := r.inDDL, r.inline
+	r.inDDL, r.inline = true, true
+	r.node(n)
+	r.inDDL, r.inline = savedDDL, savedInline
+}
+
+// identList writes a comma-separated list of quoted identifiers.
+func (r *renderer) identList(names []string) {
+	for i, name := range names {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.ident(name)
+	}
+}
+
+func (r *renderer) createTable(n *nodes.CreateTableStatement) {
+	if n.IfNotExists && r.d.ddl.noTableIfNotExists {
+		r.unsupported("CREATE TABLE IF NOT EXISTS")
+	}
+	r.write("CREATE TABLE ")
+	if n.IfNotExists {
+		r.write("IF NOT EXISTS ")
+	}
+	r.ident(n.Table.Name)
+	r.write(" (")
+	for i, c := range n.Columns {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.columnDef(c)
+	}
+	for _, c := range n.Constraints {
+		r.write(", ")
+		r.constraint(c)
+	}
+	if r.d.ddl.hoistForeignKeys {
+		for _, c := range n.Columns {
+			if c.References != nil {
+				r.write(", ")
+				r.constraint(&nodes.TableConstraint{
+					Kind:       nodes.ConstraintForeignKey,
+					Columns:    []string{c.Name},
+					References: c.References,
+				})
+			}
+		}
+	}
+	r.write(")")
+}
+
+func (r *renderer) columnDef(c *nodes.ColumnDef) {
+	r.ident(c.Name)
+	if c.Type != "" {
+		validateSQLTypeName(c.Type)
+		r.write(" ")
+		r.write(c.Type)
+	}
+	if c.Generated != nil {
+		if r.d.ddl.noGeneratedColumns {
+			r.unsupported("generated columns")
+		}
+		if !c.Virtual && r.d.ddl.noStoredGenerated {
+			r.unsupported("stored generated columns")
+		}
+		r.write(" GENERATED ALWAYS AS (")
+		r.ddlExpr(c.Generated)
+		if c.Virtual {
+			r.write(") VIRTUAL")
+		} else {
+			r.write(") STORED")
+		}
+	}
+	if c.NotNull {
+		r.write(" NOT NULL")
+	}
+	if c.Default != nil {
+		r.write(" DEFAULT ")
+		r.ddlExpr(c.Default)
+	}
+	if c.PrimaryKey {
+		r.write(" PRIMARY KEY")
+	}
+	if c.Unique {
+		r.write(" UNIQUE")
+	}
+	if c.Check != nil {
+		r.write(" CHECK (")
+		r.ddlExpr(c.Check)
+		r.write(")")
+	}
+	if c.References != nil && !r.d.ddl.hoistForeignKeys {
+		r.write(" ")
+		r.references(c.References)
+	}
+}
+
+func (r *renderer) constraint(c *nodes.TableConstraint) {
+	if c.References != nil && c.Kind != nodes.ConstraintForeignKey {
+		renderErrorf("ON DELETE and ON UPDATE apply only to FOREIGN KEY constraints")
+	}
+	if c.Name != "" {
+		r.write("CONSTRAINT ")
+		r.ident(c.Name)
+		r.write(" ")
+	}
+	switch c.Kind {
+	case nodes.ConstraintPrimaryKey:
+		r.write("PRIMARY KEY (")
+		r.identList(c.Columns)
+		r.write(")")
+	case nodes.ConstraintUnique:
+		r.write("UNIQUE (")
+		r.identList(c.Columns)
+		r.write(")")
+	case nodes.ConstraintCheck:
+		r.write("CHECK (")
+		r.ddlExpr(c.Check)
+		r.write(")")
+	case nodes.ConstraintForeignKey:
+		r.write("FOREIGN KEY (")
+		r.identList(c.Columns)
+		r.write(") ")
+		r.references(c.References)
+	}
+}
+
+func (r *renderer) references(ref *nodes.ForeignKeyRef) {
+	r.write("REFERENCES ")
+	r.ident(ref.Table.Name)
+	if len(ref.Columns) > 0 {
+		r.write(" (")
+		r.identList(ref.Columns)
+		r.write(")")
+	}
+	if ref.OnDelete != nodes.ActionNone {
+		r.write(" ON DELETE ")
+		r.write(ref.OnDelete.String())
+	}
+	if ref.OnUpdate != nodes.ActionNone {
+		r.write(" ON UPDATE ")
+		r.write(ref.OnUpdate.String())
+	}
+}
+
+func (r *renderer) alterTable(n *nodes.AlterTableStatement) {
+	rules := r.d.ddl
+	// Group actions into statements: one per action, or all combinable
+	// actions together with renames split out where required.
+	var groups [][]*nodes.AlterAction
+	var current []*nodes.AlterAction
+	for _, a := range n.Actions {
+		if a.Kind == nodes.AlterAddConstraint && rules.noAddConstraint {
+			r.unsupported("ALTER TABLE ADD CONSTRAINT")
+		}
+		if a.Kind == nodes.AlterRenameColumn && rules.noRenameColumn {
+			r.unsupported("ALTER TABLE RENAME COLUMN")
+		}
+		separate := rules.alterSingleAction ||
+			(rules.alterSeparateRename && a.Kind == nodes.AlterRenameColumn)
+		if separate {
+			if len(current) > 0 {
+				groups = append(groups, current)
+				current = nil
+			}
+			groups = append(groups, []*nodes.AlterAction{a})
+			continue
+		}
+		current = append(current, a)
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			r.write("; ")
+		}
+		r.write("ALTER TABLE ")
+		r.ident(n.Table.Name)
+		r.write(" ")
+		for j, a := range group {
+			if j > 0 {
+				r.write(", ")
+			}
+			r.alterAction(a)
+		}
+	}
+}
+
+func (r *renderer) alterAction(a *nodes.AlterAction) {
+	switch a.Kind {
+	case nodes.AlterAddColumn:
+		if r.d.ddl.addWithoutColumn {
+			r.write("ADD ")
+		} else {
+			r.write("ADD COLUMN ")
+		}
+		r.columnDef(a.Column)
+		if a.Column.References != nil && r.d.ddl.hoistForeignKeys {
+			r.write(", ADD ")
+			r.constraint(&nodes.TableConstraint{
+				Kind:       nodes.ConstraintForeignKey,
+				Columns:    []string{a.Column.Name},
+				References: a.Column.References,
+			})
+		}
+	case nodes.AlterDropColumn:
+		r.write("DROP COLUMN ")
+		r.ident(a.Name)
+	case nodes.AlterRenameColumn:
+		r.write("RENAME COLUMN ")
+		r.ident(a.Name)
+		r.write(" TO ")
+		r.ident(a.NewName)
+	case nodes.AlterAddConstraint:
+		r.write("ADD ")
+		r.constraint(a.Constraint)
+	}
+}
+
+func (r *renderer) createIndex(n *nodes.CreateIndexStatement) {
+	rules := r.d.ddl
+	if n.IfNotExists && rules.noIndexIfNotExists {
+		r.unsupported("CREATE INDEX IF NOT EXISTS")
+	}
+	if len(n.Wheres) > 0 && rules.noPartialIndex {
+		r.unsupported("partial indexes")
+	}
+	if n.Concurrently && !rules.concurrentIndex && rules.onlineIndex == "" {
+		r.unsupported("concurrent index builds")
+	}
+
+	r.write("CREATE ")
+	if n.Unique {
+		r.write("UNIQUE ")
+	}
+	r.write("INDEX ")
+	if n.Concurrently && rules.concurrentIndex {
+		r.write("CONCURRENTLY ")
+	}
+	if n.IfNotExists {
+		r.write("IF NOT EXISTS ")
+	}
+	r.ident(n.Name)
+	r.write(" ON ")
+	r.ident(n.Table.Name)
+	r.write(" (")
+	for i, c := range n.Columns {
+		if i > 0 {
+			r.write(", ")
+		}
+		r.indexElement(c)
+	}
+	r.write(")")
+	if len(n.Wheres) > 0 {
+		savedDDL, savedInline := r.inDDL, r.inline
+		r.inDDL, r.inline = true, true
+		r.clause(" WHERE ", n.Wheres, " AND ")
+		r.inDDL, r.inline = savedDDL, savedInline
+	}
+	if n.Concurrently && !rules.concurrentIndex {
+		r.write(rules.onlineIndex)
+	}
+}
+
+// indexElement writes one index key: a bare column, or a parenthesised
+// expression, optionally followed by an ordering.
+func (r *renderer) indexElement(n nodes.Node) {
+	if o, ok := n.(*nodes.OrderingNode); ok {
+		r.indexElement(o.Expr)
+		r.orderingSuffix(o)
+		return
+	}
+	if _, ok := n.(*nodes.Attribute); ok {
+		r.ddlExpr(n)
+		return
+	}
+	r.write("(")
+	r.ddlExpr(n)
+	r.write(")")
+}
+
+func (r *renderer) drop(n *nodes.DropStatement) {
+	rules := r.d.ddl
+	if n.Cascade && rules.noDropCascade {
+		r.unsupported("DROP ... CASCADE")
+	}
+	if n.IfExists && rules.noDropIfExists {
+		r.unsupported("DROP ... IF EXISTS")
+	}
+	if n.Concurrently && !rules.concurrentIndex {
+		r.unsupported("DROP INDEX CONCURRENTLY")
+	}
+
+	if n.Kind == nodes.DropIndex {
+		r.write("DROP INDEX ")
+		if n.Concurrently {
+			r.write("CONCURRENTLY ")
+		}
+	} else {
+		r.write("DROP TABLE ")
+	}
+	if n.IfExists {
+		r.write("IF EXISTS ")
+	}
+	r.ident(n.Name)
+	if n.Kind == nodes.DropIndex && rules.dropIndexOnTable {
+		if n.On == nil {
+			r.unsupported("DROP INDEX without the owning table")
+		}
+		r.write(" ON ")
+		r.ident(n.On.Name)
+	}
+	if n.Cascade {
+		r.write(" CASCADE")
+	}
+}
+
+// ddlQuery renders the query of a view or CREATE TABLE AS. Values are
+// inlined since the statement cannot take bind parameters.
+func (r *renderer) ddlQuery(n nodes.Node) {
+	saved := r.inline
+	r.inline = true
+	r.node(n)
+	r.inline = saved
+}
+
+// nameList writes an optional parenthesised list of column names.
+func (r *renderer) nameList(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	r.write(" (")
+	r.identList(names)
+	r.write(")")
+}
+
+func (r *renderer) createView(n *nodes.CreateViewStatement) {
+	rules := r.d.ddl
+	switch {
+	case n.Materialized && !rules.materializedViews:
+		r.unsupported("materialized views")
+	case n.Materialized && n.OrReplace:
+		r.unsupported("CREATE OR REPLACE MATERIALIZED VIEW")
+	case n.OrReplace && rules.noOrReplaceView:
+		r.unsupported("CREATE OR REPLACE VIEW")
+	case n.IfNotExists && !n.Materialized && !rules.viewIfNotExists:
+		r.unsupported("CREATE VIEW IF NOT EXISTS")
+	case n.WithNoData && !n.Materialized:
+		r.unsupported("WITH NO DATA on a plain view")
+	}
+
+	r.write("CREATE ")
+	if n.OrReplace {
+		r.write("OR REPLACE ")
+	}
+	if n.Materialized {
+		r.write("MATERIALIZED ")
+	}
+	r.write("VIEW ")
+	if n.IfNotExists {
+		r.write("IF NOT EXISTS ")
+	}
+	r.ident(n.Name)
+	r.nameList(n.Columns)
+	r.write(" AS ")
+	r.ddlQuery(n.Query)
+	if n.WithNoData {
+		r.write(" WITH NO DATA")
+	}
+}
+
+func (r *renderer) createTableAs(n *nodes.CreateTableAsStatement) {
+	if r.d.ddl.noCreateTableAs {
+		r.unsupported("CREATE TABLE AS")
+	}
+	if len(n.Columns) > 0 && !r.d.ddl.ctasColumnNames {
+		r.unsupported("column names in CREATE TABLE AS")
+	}
+	r.write("CREATE TABLE ")
+	if n.IfNotExists {
+		r.write("IF NOT EXISTS ")
+	}
+	r.ident(n.Table.Name)
+	r.nameList(n.Columns)
+	r.write(" AS ")
+	r.ddlQuery(n.Query)
+}
+
+func (r *renderer) refreshMaterializedView(n *nodes.RefreshMaterializedViewStatement) {
+	if !r.d.ddl.materializedViews {
+		r.unsupported("materialized views")
+	}
+	if n.Concurrently && n.WithNoData {
+		r.unsupported("REFRESH MATERIALIZED VIEW CONCURRENTLY ... WITH NO DATA")
+	}
+	r.write("REFRESH MATERIALIZED VIEW ")
+	if n.Concurrently {
+		r.write("CONCURRENTLY ")
+	}
+	r.ident(n.Name)
+	if n.WithNoData {
+		r.write(" WITH NO DATA")
+	}
+}
@@ -0,0 +1,40 @@
+package visitors
+
+import (
+	"strings"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func (r *renderer) explain(n *nodes.ExplainStatement) {
+	if r.d.explain != nil {
+		r.d.explain(r, n.Options)
+	} else {
+		explainPostgres(r, n.Options)
+	}
+	r.node(n.Statement)
+}
+
+// explainPostgres writes EXPLAIN with a parenthesised option list:
+// EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON).
+func explainPostgres(r *renderer, o nodes.ExplainOptions) {
+	var opts []string
+	if o.Analyze {
+		opts = append(opts, "ANALYZE")
+	}
+	if o.Buffers {
+		opts = append(opts, "BUFFERS")
+	}
+	if o.Verbose {
+		opts = append(opts, "VERBOSE")
+	}
+	if o.Format == nodes.ExplainJSON {
+		opts = append(opts, "FORMAT JSON")
+	}
+	r.write("EXPLAIN ")
+	if len(opts) > 0 {
+		r.write("(")
+		r.write(strings.Join(opts, ", "))
+		r.write(") ")
+	}
+}
@@ -1,14 +1,11 @@
 package visitors
 
-import (
-	"github.com/bawdo/gosbee/internal/quoting"
-	"github.com/bawdo/gosbee/nodes"
-)
+import "github.com/bawdo/gosbee/nodes"
 
 // SQLiteVisitor generates SQLite-dialect SQL.
 // Identifiers are quoted with double quotes: "table"."column" (ANSI SQL).
 type SQLiteVisitor struct {
-	*baseVisitor
+	*BaseVisitor
 }
 
 // NewSQLiteVisitor creates a SQLiteVisitor ready for use.
@@ -16,27 +13,66 @@ type SQLiteVisitor struct {
 // Pass WithoutParams() to disable (not recommended for production).
 func NewSQLiteVisitor(opts ...Option) *SQLiteVisitor {
 	v := &SQLiteVisitor{}
-	v.baseVisitor = &baseVisitor{
-		outer:        v,
-		quoteIdent:   quoting.DoubleQuote,
-		placeholder:  func(_ int) string { return "?" },
-		parameterize: true, // Enable by default
-	}
+	v.BaseVisitor = &BaseVisitor{d: &Dialect{
+		name:             "SQLite",
+		quote:            writeDoubleQuoted,
+		plainIdent:       isMixedIdent,
+		reserved:         sqliteReserved,
+		writePlaceholder: writeQuestionPlaceholder,
+		parameterize:     true, // Enable by default
+		ddl: ddlRules{
+			alterSingleAction: true,
+			noAddConstraint:   true,
+			noDropCascade:     true,
+			noOrReplaceView:   true,
+			viewIfNotExists:   true,
+		},
+		comparison: sqliteComparison,
+		explain:    explainSQLite,
+	}}
 	v.applyOptions(opts)
 	return v
 }
 
-func (v *SQLiteVisitor) VisitComparison(n *nodes.ComparisonNode) string {
+// sqliteComparison renders the comparison operators SQLite spells differently.
+func sqliteComparison(r *renderer, n *nodes.ComparisonNode) bool {
 	switch n.Op {
 	case nodes.OpRegexp:
-		return n.Left.Accept(v) + " REGEXP " + n.Right.Accept(v)
+		r.node(n.Left)
+		r.write(" REGEXP ")
+		r.node(n.Right)
 	case nodes.OpNotRegexp:
-		return n.Left.Accept(v) + " NOT REGEXP " + n.Right.Accept(v)
+		r.node(n.Left)
+		r.write(" NOT REGEXP ")
+		r.node(n.Right)
 	case nodes.OpCaseSensitiveEq:
-		return n.Left.Accept(v) + " = " + n.Right.Accept(v) + " COLLATE BINARY"
+		r.node(n.Left)
+		r.write(" = ")
+		r.node(n.Right)
+		r.write(" COLLATE BINARY")
 	case nodes.OpCaseInsensitiveEq:
-		return n.Left.Accept(v) + " = " + n.Right.Accept(v) + " COLLATE NOCASE"
+		r.node(n.Left)
+		r.write(" = ")
+		r.node(n.Right)
+		r.write(" COLLATE NOCASE")
 	default:
-		return v.baseVisitor.VisitComparison(n)
+		return false
+	}
+	return true
+}
+
+// explainSQLite writes EXPLAIN QUERY PLAN, the only human-readable plan
+// SQLite produces; it takes no options.
+func explainSQLite(r *renderer, o nodes.ExplainOptions) {
+	switch {
+	case o.Analyze:
+		r.unsupported("EXPLAIN ANALYZE")
+	case o.Buffers:
+		r.unsupported("EXPLAIN BUFFERS")
+	case o.Verbose:
+		r.unsupported("EXPLAIN VERBOSE")
+	case o.Format == nodes.ExplainJSON:
+		r.unsupported("EXPLAIN FORMAT JSON")
 	}
+	r.write("EXPLAIN QUERY PLAN ")
 }
@@ -0,0 +1,101 @@
+package visitors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TreeVisitor walks the AST and produces an indented text tree drawn with
+// box-drawing characters, for terminals:
+//
+//	SelectCore
+//	├── FROM: Table users
+//	└── WHERE[0]: Comparison =
+//	    ├── LEFT: Attribute users.active
+//	    └── RIGHT: Literal true
+//
+// It builds the same graph as DotVisitor. The first node of each plugin
+// cluster is marked with the plugin's name, and SetColor paints labels in
+// the DOT colours. It implements nodes.Visitor.
+type TreeVisitor struct {
+	*DotVisitor
+	color bool
+}
+
+// NewTreeVisitor creates a new TreeVisitor ready to walk an AST.
+func NewTreeVisitor() *TreeVisitor {
+	return &TreeVisitor{DotVisitor: NewDotVisitor()}
+}
+
+// SetColor enables ANSI 24-bit colours in the output of ToTree.
+func (tv *TreeVisitor) SetColor(enabled bool) {
+	tv.color = enabled
+}
+
+// ToTree generates the tree text. It is empty if nothing was visited.
+func (tv *TreeVisitor) ToTree() string {
+	if len(tv.nodes) == 0 {
+		return ""
+	}
+	byID := make(map[string]dotNode, len(tv.nodes))
+	for _, n := range tv.nodes {
+		byID[n.id] = n
+	}
+	children := make(map[string][]dotEdge)
+	for _, e := range tv.edges {
+		children[e.from] = append(children[e.from], e)
+	}
+	cluster := make(map[string]int)
+	for i, c := range tv.clusters {
+		for _, id := range c.nodeIDs {
+			cluster[id] = i + 1
+		}
+	}
+
+	var sb strings.Builder
+	var walk func(id, edge, parent, indent string, last, root bool)
+	walk = func(id, edge, parent, indent string, last, root bool) {
+		line := strings.ReplaceAll(byID[id].label, "\\n", " ")
+		line = tv.paint(line, byID[id].color)
+		if edge != "" {
+			line = edge + ": " + line
+		}
+		if c := cluster[id]; c != 0 && cluster[parent] != c {
+			pc := tv.clusters[c-1]
+			line += " " + tv.paint("["+pc.name+"]", pc.color)
+		}
+
+		childIndent := indent
+		switch {
+		case root:
+		case last:
+			sb.WriteString(indent + "└── ")
+			childIndent += "    "
+		default:
+			sb.WriteString(indent + "├── ")
+			childIndent += "│   "
+		}
+		sb.WriteString(line + "\n")
+
+		kids := children[id]
+		for i, e := range kids {
+			walk(e.to, e.label, id, childIndent, i == len(kids)-1, false)
+		}
+	}
+	walk(tv.nodes[0].id, "", "", "", true, true)
+	return sb.String()
+}
+
+// paint wraps s in the ANSI foreground colour for a "#RRGGBB" colour when
+// colours are enabled.
+func (tv *TreeVisitor) paint(s, color string) string {
+	if !tv.color || len(color) != 7 || color[0] != '#' {
+		return s
+	}
+	rgb, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return s
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", rgb>>16, rgb>>8&0xff, rgb&0xff, s)
+}
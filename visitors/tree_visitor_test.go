@@ -0,0 +1,75 @@
+package visitors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bawdo/gosbee/nodes"
+)
+
+func TestTreeEmpty(t *testing.T) {
+	if got := NewTreeVisitor().ToTree(); got != "" {
+		t.Errorf("expected empty tree, got:\n%s", got)
+	}
+}
+
+func TestTreeSelectCore(t *testing.T) {
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From: users,
+		Wheres: []nodes.Node{
+			users.Col("active").Eq(nodes.Literal(true)),
+			users.Col("deleted_at").IsNull(),
+		},
+	}
+	tv := NewTreeVisitor()
+	core.Accept(tv)
+
+	want := `SelectCore
+├── FROM: Table users
+├── WHERE[0]: Comparison =
+│   ├── LEFT: Attribute users.active
+│   └── RIGHT: Literal true
+└── WHERE[1]: Unary IS NULL
+    └── EXPR: Attribute users.deleted_at
+`
+	if got := tv.ToTree(); got != want {
+		t.Errorf("unexpected tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTreeProvenanceMarksPlugin(t *testing.T) {
+	users := nodes.NewTable("users")
+	core := &nodes.SelectCore{
+		From: users,
+		Wheres: []nodes.Node{
+			users.Col("active").Eq(nodes.Literal(true)),
+			users.Col("deleted_at").IsNull(),
+		},
+	}
+	prov := NewPluginProvenance()
+	prov.AddWhere("softdelete", "#CC6666", 1)
+
+	tv := NewTreeVisitor()
+	tv.SetProvenance(prov)
+	core.Accept(tv)
+	out := tv.ToTree()
+
+	if !strings.Contains(out, "└── WHERE[1]: Unary IS NULL [softdelete]\n") {
+		t.Errorf("expected plugin marker on cluster root, got:\n%s", out)
+	}
+	if strings.Count(out, "[softdelete]") != 1 {
+		t.Errorf("expected one plugin marker, got:\n%s", out)
+	}
+}
+
+func TestTreeColor(t *testing.T) {
+	tv := NewTreeVisitor()
+	tv.SetColor(true)
+	nodes.NewTable("users").Accept(tv)
+	out := tv.ToTree()
+
+	if out != "\x1b[38;2;108;166;205mTable users\x1b[0m\n" {
+		t.Errorf("expected coloured label, got: %q", out)
+	}
+}
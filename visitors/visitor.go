@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/bawdo/gosbee/internal/quoting"
 	"github.com/bawdo/gosbee/nodes"
 )
 
@@ -61,6 +60,8 @@ var joinTypeSQL = [...]string{
 	nodes.FullOuterJoin:  "FULL OUTER JOIN",
 	nodes.CrossJoin:      "CROSS JOIN",
 	nodes.StringJoin:     "",
+	nodes.ArrayJoin:      "ARRAY JOIN",
+	nodes.LeftArrayJoin:  "LEFT ARRAY JOIN",
 }
 
 // SQL keywords for LockMode values.
@@ -83,7 +84,7 @@ var setOpTypeSQL = [...]string{
 }
 
 // Option configures a visitor at construction time.
-type Option func(*baseVisitor)
+type Option func(*BaseVisitor)
 
 // WithParams enables parameterized query mode. When enabled, literal values
 // are replaced with bind placeholders and collected for separate retrieval.
@@ -91,8 +92,8 @@ type Option func(*baseVisitor)
 // Note: Parameterized mode is now enabled by default. This option is kept
 // for backwards compatibility and has no effect.
 func WithParams() Option {
-	return func(b *baseVisitor) {
-		b.parameterize = true
+	return func(b *BaseVisitor) {
+		b.d.parameterize = true
 	}
 }
 
@@ -105,439 +106,153 @@ func WithParams() Option {
 // with basic escaping only. This is convenient for debugging but creates serious
 // security vulnerabilities with untrusted input.
 func WithoutParams() Option {
-	return func(b *baseVisitor) {
-		b.parameterize = false
+	return func(b *BaseVisitor) {
+		b.d.parameterize = false
 	}
 }
 
-// baseVisitor implements the shared SQL generation logic used by all dialects.
-// Dialect-specific visitors embed *baseVisitor and set the outer field to
-// themselves, enabling correct virtual dispatch through the Visitor interface.
-type baseVisitor struct {
-	// outer is the concrete dialect visitor. All recursive Accept calls
-	// go through outer so that dialect overrides are respected.
-	outer nodes.Visitor
-
-	// quoteIdent quotes a SQL identifier (table name, column name).
-	quoteIdent func(string) string
+// BaseVisitor is the nodes.Visitor compatibility shim shared by all dialects.
+// SQL generation itself lives in renderer, which walks the whole tree in a
+// single pass; every Visit method simply renders its node through it.
+//
+// A BaseVisitor carries two kinds of state. The dialect is immutable once
+// the constructor returns and is exposed through Dialect for concurrent use.
+// The parameter fields back Params and Reset, so a visitor itself is not
+// goroutine-safe.
+//
+// BaseVisitor is exported so that a dialect defined outside this package
+// can embed it, as the built-in visitors do; see NewBaseVisitor.
+type BaseVisitor struct {
+	// d is the immutable dialect configuration.
+	d *Dialect
 
-	// parameterize enables bind-parameter mode.
-	parameterize bool
+	// active is set when this visitor is the shim of an in-progress render,
+	// in which case Visit methods write through that renderer.
+	active *renderer
 
-	// params accumulates bind parameter values during SQL generation.
+	// params accumulates bind parameter values across Accept calls.
 	params []any
 
 	// paramIndex tracks the next parameter number (1-based).
 	paramIndex int
 
-	// placeholder returns the bind placeholder for a given parameter index.
-	// PostgreSQL uses $1, $2; MySQL/SQLite use ?.
-	placeholder func(int) string
+	// namedIndex maps a named parameter to the index it was first bound to.
+	namedIndex map[string]int
 }
 
-// applyOptions applies functional options to the baseVisitor.
-func (b *baseVisitor) applyOptions(opts []Option) {
+// NewBaseVisitor returns a visitor rendering with a copy of d, typically a
+// built-in dialect adapted with Extend:
+//
+//	type CockroachVisitor struct{ *visitors.BaseVisitor }
+//
+//	func NewCockroachVisitor(opts ...visitors.Option) *CockroachVisitor {
+//		d := visitors.NewPostgresVisitor().Dialect().Extend("CockroachDB")
+//		return &CockroachVisitor{visitors.NewBaseVisitor(d, opts...)}
+//	}
+func NewBaseVisitor(d *Dialect, opts ...Option) *BaseVisitor {
+	c := *d
+	b := &BaseVisitor{d: &c}
+	b.applyOptions(opts)
+	return b
+}
+
+// applyOptions applies functional options to the BaseVisitor.
+func (b *BaseVisitor) applyOptions(opts []Option) {
 	for _, o := range opts {
 		o(b)
 	}
 }
 
 // Params returns the collected bind parameters from the last SQL generation.
-func (b *baseVisitor) Params() []any {
+func (b *BaseVisitor) Params() []any {
 	return b.params
 }
 
 // Reset clears collected parameters for reuse.
-func (b *baseVisitor) Reset() {
+func (b *BaseVisitor) Reset() {
 	b.params = nil
 	b.paramIndex = 0
-}
-
-func (b *baseVisitor) VisitTable(n *nodes.Table) string {
-	return b.quoteIdent(n.Name)
-}
-
-func (b *baseVisitor) VisitTableAlias(n *nodes.TableAlias) string {
-	if tbl, ok := n.Relation.(*nodes.Table); ok {
-		return b.quoteIdent(tbl.Name) + " AS " + b.quoteIdent(n.AliasName)
-	}
-	return "(" + n.Relation.Accept(b.outer) + ") AS " + b.quoteIdent(n.AliasName)
-}
-
-func (b *baseVisitor) VisitAttribute(n *nodes.Attribute) string {
-	return b.qualifierName(n.Relation) + "." + b.quoteIdent(n.Name)
-}
-
-// qualifierName returns the quoted name used to qualify a column reference.
-func (b *baseVisitor) qualifierName(rel nodes.Node) string {
-	return b.quoteIdent(nodes.RelationName(rel))
-}
-
-func (b *baseVisitor) VisitLiteral(n *nodes.LiteralNode) string {
-	return b.literalToSQL(n.Value)
-}
-
-func (b *baseVisitor) literalToSQL(val any) string {
-	// nil always renders as NULL keyword, never parameterized.
-	if val == nil {
-		return "NULL"
-	}
-
-	// In parameterize mode, emit a placeholder and collect the value.
-	if b.parameterize {
-		b.paramIndex++
-		b.params = append(b.params, val)
-		return b.placeholder(b.paramIndex)
-	}
-
-	switch v := val.(type) {
-	case string:
-		return "'" + quoting.EscapeString(v) + "'"
-	case bool:
-		if v {
-			return "TRUE"
-		}
-		return "FALSE"
-	case int:
-		return fmt.Sprintf("%d", v)
-	case int8:
-		return fmt.Sprintf("%d", v)
-	case int16:
-		return fmt.Sprintf("%d", v)
-	case int32:
-		return fmt.Sprintf("%d", v)
-	case int64:
-		return fmt.Sprintf("%d", v)
-	case uint:
-		return fmt.Sprintf("%d", v)
-	case uint8:
-		return fmt.Sprintf("%d", v)
-	case uint16:
-		return fmt.Sprintf("%d", v)
-	case uint32:
-		return fmt.Sprintf("%d", v)
-	case uint64:
-		return fmt.Sprintf("%d", v)
-	case float32:
-		return fmt.Sprintf("%g", v)
-	case float64:
-		return fmt.Sprintf("%g", v)
-	default:
-		panic(fmt.Sprintf("gosbee: unsupported literal type %T", v))
-	}
-}
-
-func (b *baseVisitor) VisitStar(n *nodes.StarNode) string {
-	if n.Table != nil {
-		return b.quoteIdent(n.Table.Name) + ".*"
-	}
-	return "*"
-}
-
-func (b *baseVisitor) VisitSqlLiteral(n *nodes.SqlLiteral) string {
-	if b.parameterize && len(n.Binds) > 0 {
-		b.params = append(b.params, n.Binds...)
-		for range n.Binds {
-			b.paramIndex++
-		}
-	}
-	return string(n.Raw)
-}
-
-func (b *baseVisitor) VisitComparison(n *nodes.ComparisonNode) string {
-	left := n.Left.Accept(b.outer)
-	right := n.Right.Accept(b.outer)
-	if n.Op == nodes.OpCaseInsensitiveEq {
-		return "LOWER(" + left + ") = LOWER(" + right + ")"
-	}
-	return left + " " + comparisonOpSQL[n.Op] + " " + right
-}
-
-func (b *baseVisitor) VisitUnary(n *nodes.UnaryNode) string {
-	expr := n.Expr.Accept(b.outer)
-	switch n.Op {
-	case nodes.OpIsNull:
-		return expr + " IS NULL"
-	case nodes.OpIsNotNull:
-		return expr + " IS NOT NULL"
-	default:
-		return expr
-	}
-}
-
-func (b *baseVisitor) VisitAnd(n *nodes.AndNode) string {
-	left := n.Left.Accept(b.outer)
-	right := n.Right.Accept(b.outer)
-	return left + " AND " + right
-}
-
-func (b *baseVisitor) VisitOr(n *nodes.OrNode) string {
-	left := n.Left.Accept(b.outer)
-	right := n.Right.Accept(b.outer)
-	return left + " OR " + right
-}
-
-func (b *baseVisitor) VisitNot(n *nodes.NotNode) string {
-	return "NOT (" + n.Expr.Accept(b.outer) + ")"
-}
-
-func (b *baseVisitor) VisitIn(n *nodes.InNode) string {
-	expr := n.Expr.Accept(b.outer)
-	vals := make([]string, len(n.Vals))
-	for i, v := range n.Vals {
-		vals[i] = v.Accept(b.outer)
-	}
-	keyword := "IN"
-	if n.Negate {
-		keyword = "NOT IN"
-	}
-	return expr + " " + keyword + " (" + strings.Join(vals, ", ") + ")"
-}
-
-func (b *baseVisitor) VisitBetween(n *nodes.BetweenNode) string {
-	expr := n.Expr.Accept(b.outer)
-	low := n.Low.Accept(b.outer)
-	high := n.High.Accept(b.outer)
-	keyword := "BETWEEN"
-	if n.Negate {
-		keyword = "NOT BETWEEN"
-	}
-	return expr + " " + keyword + " " + low + " AND " + high
-}
-
-func (b *baseVisitor) VisitGrouping(n *nodes.GroupingNode) string {
-	return "(" + n.Expr.Accept(b.outer) + ")"
-}
-
-func (b *baseVisitor) VisitOrdering(n *nodes.OrderingNode) string {
-	expr := n.Expr.Accept(b.outer)
-	if n.Direction == nodes.Desc {
-		expr += " DESC"
-	} else {
-		expr += " ASC"
-	}
-	switch n.Nulls {
-	case nodes.NullsFirst:
-		expr += " NULLS FIRST"
-	case nodes.NullsLast:
-		expr += " NULLS LAST"
-	}
-	return expr
-}
-
-func (b *baseVisitor) VisitJoin(n *nodes.JoinNode) string {
-	// StringJoin: raw SQL fragment, output directly.
-	if n.Type == nodes.StringJoin {
-		return n.Right.Accept(b.outer)
-	}
-
-	rightSQL := n.Right.Accept(b.outer)
-
-	// Wrap subqueries in parentheses.
-	if _, ok := n.Right.(*nodes.SelectCore); ok {
-		rightSQL = "(" + rightSQL + ")"
-	}
-
-	var sb strings.Builder
-	sb.WriteString(joinTypeSQL[n.Type])
-	if n.Lateral {
-		sb.WriteString(" LATERAL")
-	}
-	sb.WriteString(" ")
-	sb.WriteString(rightSQL)
-
-	if n.On != nil {
-		sb.WriteString(" ON ")
-		sb.WriteString(n.On.Accept(b.outer))
-	}
-
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitInsertStatement(n *nodes.InsertStatement) string {
-	var sb strings.Builder
-
-	sb.WriteString("INSERT INTO ")
-	sb.WriteString(n.Into.Accept(b.outer))
-
-	// Columns
-	if len(n.Columns) > 0 {
-		sb.WriteString(" (")
-		cols := make([]string, len(n.Columns))
-		for i, c := range n.Columns {
-			cols[i] = b.quoteIdent(c.(*nodes.Attribute).Name)
-		}
-		sb.WriteString(strings.Join(cols, ", "))
-		sb.WriteString(")")
-	}
-
-	// INSERT FROM SELECT
-	if n.Select != nil {
-		sb.WriteString(" ")
-		sb.WriteString(n.Select.Accept(b.outer))
-	} else if len(n.Values) > 0 {
-		sb.WriteString(" VALUES ")
-		rows := make([]string, len(n.Values))
-		for i, row := range n.Values {
-			vals := make([]string, len(row))
-			for j, v := range row {
-				vals[j] = v.Accept(b.outer)
-			}
-			rows[i] = "(" + strings.Join(vals, ", ") + ")"
-		}
-		sb.WriteString(strings.Join(rows, ", "))
-	}
-
-	// ON CONFLICT
-	if n.OnConflict != nil {
-		sb.WriteString(" ")
-		sb.WriteString(n.OnConflict.Accept(b.outer))
-	}
-
-	// RETURNING
-	if len(n.Returning) > 0 {
-		sb.WriteString(" RETURNING ")
-		rets := make([]string, len(n.Returning))
-		for i, r := range n.Returning {
-			rets[i] = r.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(rets, ", "))
-	}
-
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitUpdateStatement(n *nodes.UpdateStatement) string {
-	var sb strings.Builder
-
-	sb.WriteString("UPDATE ")
-	sb.WriteString(n.Table.Accept(b.outer))
-
-	// SET
-	if len(n.Assignments) > 0 {
-		sb.WriteString(" SET ")
-		assigns := make([]string, len(n.Assignments))
-		for i, a := range n.Assignments {
-			assigns[i] = a.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(assigns, ", "))
-	}
-
-	// WHERE
-	if len(n.Wheres) > 0 {
-		sb.WriteString(" WHERE ")
-		wheres := make([]string, len(n.Wheres))
-		for i, w := range n.Wheres {
-			wheres[i] = w.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(wheres, " AND "))
-	}
-
-	// RETURNING
-	if len(n.Returning) > 0 {
-		sb.WriteString(" RETURNING ")
-		rets := make([]string, len(n.Returning))
-		for i, r := range n.Returning {
-			rets[i] = r.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(rets, ", "))
-	}
-
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitDeleteStatement(n *nodes.DeleteStatement) string {
-	var sb strings.Builder
-
-	sb.WriteString("DELETE FROM ")
-	sb.WriteString(n.From.Accept(b.outer))
-
-	// WHERE
-	if len(n.Wheres) > 0 {
-		sb.WriteString(" WHERE ")
-		wheres := make([]string, len(n.Wheres))
-		for i, w := range n.Wheres {
-			wheres[i] = w.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(wheres, " AND "))
-	}
-
-	// RETURNING
-	if len(n.Returning) > 0 {
-		sb.WriteString(" RETURNING ")
-		rets := make([]string, len(n.Returning))
-		for i, r := range n.Returning {
-			rets[i] = r.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(rets, ", "))
-	}
-
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitAssignment(n *nodes.AssignmentNode) string {
-	left := n.Left.Accept(b.outer)
-	right := n.Right.Accept(b.outer)
-	return left + " = " + right
-}
-
-func (b *baseVisitor) VisitOnConflict(n *nodes.OnConflictNode) string {
-	var sb strings.Builder
-
-	sb.WriteString("ON CONFLICT")
-
-	if len(n.Columns) > 0 {
-		sb.WriteString(" (")
-		cols := make([]string, len(n.Columns))
-		for i, c := range n.Columns {
-			cols[i] = b.quoteIdent(c.(*nodes.Attribute).Name)
-		}
-		sb.WriteString(strings.Join(cols, ", "))
-		sb.WriteString(")")
-	}
-
-	if n.Action == nodes.DoNothing {
-		sb.WriteString(" DO NOTHING")
-	} else {
-		sb.WriteString(" DO UPDATE SET ")
-		assigns := make([]string, len(n.Assignments))
-		for i, a := range n.Assignments {
-			assigns[i] = a.Accept(b.outer)
-		}
-		sb.WriteString(strings.Join(assigns, ", "))
-
-		if len(n.Wheres) > 0 {
-			sb.WriteString(" WHERE ")
-			wheres := make([]string, len(n.Wheres))
-			for i, w := range n.Wheres {
-				wheres[i] = w.Accept(b.outer)
-			}
-			sb.WriteString(strings.Join(wheres, " AND "))
-		}
-	}
-
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitInfix(n *nodes.InfixNode) string {
-	left := n.Left.Accept(b.outer)
-	if needsParens(n.Left) {
-		left = "(" + left + ")"
-	}
-	right := n.Right.Accept(b.outer)
-	if needsParens(n.Right) {
-		right = "(" + right + ")"
-	}
-	return left + " " + infixOpSQL[n.Op] + " " + right
-}
-
-func (b *baseVisitor) VisitUnaryMath(n *nodes.UnaryMathNode) string {
-	expr := n.Expr.Accept(b.outer)
-	if needsParens(n.Expr) {
-		expr = "(" + expr + ")"
-	}
-	return "~" + expr
-}
+	b.namedIndex = nil
+}
+
+// Dialect returns the visitor's immutable dialect. Unlike the visitor, the
+// dialect keeps no state between calls and may be shared by goroutines.
+func (b *BaseVisitor) Dialect() *Dialect {
+	return b.d
+}
+
+// quoteIdent returns name quoted for the visitor's dialect.
+func (b *BaseVisitor) quoteIdent(name string) string {
+	return b.d.quoteIdent(name)
+}
+
+// render is the body of every Visit method. Inside an active render it
+// writes through that renderer; otherwise it renders n on its own,
+// continuing the parameter numbering of earlier Accept calls.
+func (b *BaseVisitor) render(n nodes.Node) string {
+	if b.active != nil {
+		return b.active.capture(n)
+	}
+	r := newRenderer(b.d)
+	r.params, r.paramIndex, r.namedIndex = b.params, b.paramIndex, b.namedIndex
+	r.node(n)
+	b.params, b.paramIndex, b.namedIndex = r.params, r.paramIndex, r.namedIndex
+	return r.String()
+}
+
+// --- nodes.Visitor implementation ---
+
+func (b *BaseVisitor) VisitTable(n *nodes.Table) string                      { return b.render(n) }
+func (b *BaseVisitor) VisitTableAlias(n *nodes.TableAlias) string            { return b.render(n) }
+func (b *BaseVisitor) VisitAttribute(n *nodes.Attribute) string              { return b.render(n) }
+func (b *BaseVisitor) VisitLiteral(n *nodes.LiteralNode) string              { return b.render(n) }
+func (b *BaseVisitor) VisitStar(n *nodes.StarNode) string                    { return b.render(n) }
+func (b *BaseVisitor) VisitSqlLiteral(n *nodes.SqlLiteral) string            { return b.render(n) }
+func (b *BaseVisitor) VisitComparison(n *nodes.ComparisonNode) string        { return b.render(n) }
+func (b *BaseVisitor) VisitUnary(n *nodes.UnaryNode) string                  { return b.render(n) }
+func (b *BaseVisitor) VisitAnd(n *nodes.AndNode) string                      { return b.render(n) }
+func (b *BaseVisitor) VisitOr(n *nodes.OrNode) string                        { return b.render(n) }
+func (b *BaseVisitor) VisitNot(n *nodes.NotNode) string                      { return b.render(n) }
+func (b *BaseVisitor) VisitIn(n *nodes.InNode) string                        { return b.render(n) }
+func (b *BaseVisitor) VisitBetween(n *nodes.BetweenNode) string              { return b.render(n) }
+func (b *BaseVisitor) VisitGrouping(n *nodes.GroupingNode) string            { return b.render(n) }
+func (b *BaseVisitor) VisitJoin(n *nodes.JoinNode) string                    { return b.render(n) }
+func (b *BaseVisitor) VisitOrdering(n *nodes.OrderingNode) string            { return b.render(n) }
+func (b *BaseVisitor) VisitSelectCore(n *nodes.SelectCore) string            { return b.render(n) }
+func (b *BaseVisitor) VisitInsertStatement(n *nodes.InsertStatement) string  { return b.render(n) }
+func (b *BaseVisitor) VisitUpdateStatement(n *nodes.UpdateStatement) string  { return b.render(n) }
+func (b *BaseVisitor) VisitDeleteStatement(n *nodes.DeleteStatement) string  { return b.render(n) }
+func (b *BaseVisitor) VisitAssignment(n *nodes.AssignmentNode) string        { return b.render(n) }
+func (b *BaseVisitor) VisitOnConflict(n *nodes.OnConflictNode) string        { return b.render(n) }
+func (b *BaseVisitor) VisitInfix(n *nodes.InfixNode) string                  { return b.render(n) }
+func (b *BaseVisitor) VisitUnaryMath(n *nodes.UnaryMathNode) string          { return b.render(n) }
+func (b *BaseVisitor) VisitAggregate(n *nodes.AggregateNode) string          { return b.render(n) }
+func (b *BaseVisitor) VisitExtract(n *nodes.ExtractNode) string              { return b.render(n) }
+func (b *BaseVisitor) VisitWindowFunction(n *nodes.WindowFuncNode) string    { return b.render(n) }
+func (b *BaseVisitor) VisitOver(n *nodes.OverNode) string                    { return b.render(n) }
+func (b *BaseVisitor) VisitExists(n *nodes.ExistsNode) string                { return b.render(n) }
+func (b *BaseVisitor) VisitSetOperation(n *nodes.SetOperationNode) string    { return b.render(n) }
+func (b *BaseVisitor) VisitCTE(n *nodes.CTENode) string                      { return b.render(n) }
+func (b *BaseVisitor) VisitNamedFunction(n *nodes.NamedFunctionNode) string  { return b.render(n) }
+func (b *BaseVisitor) VisitCase(n *nodes.CaseNode) string                    { return b.render(n) }
+func (b *BaseVisitor) VisitGroupingSet(n *nodes.GroupingSetNode) string      { return b.render(n) }
+func (b *BaseVisitor) VisitAlias(n *nodes.AliasNode) string                  { return b.render(n) }
+func (b *BaseVisitor) VisitBindParam(n *nodes.BindParamNode) string          { return b.render(n) }
+func (b *BaseVisitor) VisitCasted(n *nodes.CastedNode) string                { return b.render(n) }
+func (b *BaseVisitor) VisitNamedParam(n *nodes.NamedParamNode) string        { return b.render(n) }
+func (b *BaseVisitor) VisitCreateTable(n *nodes.CreateTableStatement) string { return b.render(n) }
+func (b *BaseVisitor) VisitAlterTable(n *nodes.AlterTableStatement) string   { return b.render(n) }
+func (b *BaseVisitor) VisitCreateIndex(n *nodes.CreateIndexStatement) string { return b.render(n) }
+func (b *BaseVisitor) VisitDrop(n *nodes.DropStatement) string               { return b.render(n) }
+func (b *BaseVisitor) VisitCreateView(n *nodes.CreateViewStatement) string   { return b.render(n) }
+func (b *BaseVisitor) VisitCreateTableAs(n *nodes.CreateTableAsStatement) string {
+	return b.render(n)
+}
+func (b *BaseVisitor) VisitRefreshMaterializedView(n *nodes.RefreshMaterializedViewStatement) string {
+	return b.render(n)
+}
+func (b *BaseVisitor) VisitExplain(n *nodes.ExplainStatement) string { return b.render(n) }
+func (b *BaseVisitor) VisitExtension(n nodes.Extension) string       { return b.render(n) }
 
 // Aggregate function SQL names.
 var aggregateFuncSQL = [...]string{
@@ -548,27 +263,6 @@ var aggregateFuncSQL = [...]string{
 	nodes.AggMax:   "MAX",
 }
 
-func (b *baseVisitor) VisitAggregate(n *nodes.AggregateNode) string {
-	var sb strings.Builder
-	sb.WriteString(aggregateFuncSQL[n.Func])
-	sb.WriteString("(")
-	if n.Distinct {
-		sb.WriteString("DISTINCT ")
-	}
-	if n.Expr == nil {
-		sb.WriteString("*")
-	} else {
-		sb.WriteString(n.Expr.Accept(b.outer))
-	}
-	sb.WriteString(")")
-	if n.Filter != nil {
-		sb.WriteString(" FILTER (WHERE ")
-		sb.WriteString(n.Filter.Accept(b.outer))
-		sb.WriteString(")")
-	}
-	return sb.String()
-}
-
 // Extract field SQL names.
 var extractFieldSQL = [...]string{
 	nodes.ExtractYear:    "YEAR",
@@ -584,10 +278,6 @@ var extractFieldSQL = [...]string{
 	nodes.ExtractWeek:    "WEEK",
 }
 
-func (b *baseVisitor) VisitExtract(n *nodes.ExtractNode) string {
-	return "EXTRACT(" + extractFieldSQL[n.Field] + " FROM " + n.Expr.Accept(b.outer) + ")"
-}
-
 // Window function SQL names.
 var windowFuncSQL = [...]string{
 	nodes.WinRowNumber:   "ROW_NUMBER",
@@ -603,124 +293,6 @@ var windowFuncSQL = [...]string{
 	nodes.WinPercentRank: "PERCENT_RANK",
 }
 
-func (b *baseVisitor) VisitWindowFunction(n *nodes.WindowFuncNode) string {
-	var sb strings.Builder
-	sb.WriteString(windowFuncSQL[n.Func])
-	sb.WriteString("(")
-	for i, arg := range n.Args {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(arg.Accept(b.outer))
-	}
-	sb.WriteString(")")
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitOver(n *nodes.OverNode) string {
-	var sb strings.Builder
-	sb.WriteString(n.Expr.Accept(b.outer))
-	sb.WriteString(" OVER ")
-	if n.WindowName != "" {
-		sb.WriteString(b.quoteIdent(n.WindowName))
-	} else {
-		sb.WriteString(b.renderWindowDef(n.Window))
-	}
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitExists(n *nodes.ExistsNode) string {
-	var sb strings.Builder
-	if n.Negated {
-		sb.WriteString("NOT ")
-	}
-	sb.WriteString("EXISTS (")
-	sb.WriteString(n.Subquery.Accept(b.outer))
-	sb.WriteString(")")
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitSetOperation(n *nodes.SetOperationNode) string {
-	var sb strings.Builder
-	sb.WriteString("(")
-	sb.WriteString(n.Left.Accept(b.outer))
-	sb.WriteString(") ")
-	sb.WriteString(setOpTypeSQL[n.Type])
-	sb.WriteString(" (")
-	sb.WriteString(n.Right.Accept(b.outer))
-	sb.WriteString(")")
-	b.writeClause(&sb, " ORDER BY ", n.Orders, ", ")
-	b.writeNodeClause(&sb, " LIMIT ", n.Limit)
-	b.writeNodeClause(&sb, " OFFSET ", n.Offset)
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitCTE(n *nodes.CTENode) string {
-	var sb strings.Builder
-	sb.WriteString(b.quoteIdent(n.Name))
-	if len(n.Columns) > 0 {
-		sb.WriteString(" (")
-		quoted := make([]string, len(n.Columns))
-		for i, c := range n.Columns {
-			quoted[i] = b.quoteIdent(c)
-		}
-		sb.WriteString(strings.Join(quoted, ", "))
-		sb.WriteString(")")
-	}
-	sb.WriteString(" AS (")
-	sb.WriteString(n.Query.Accept(b.outer))
-	sb.WriteString(")")
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitNamedFunction(n *nodes.NamedFunctionNode) string {
-	var sb strings.Builder
-	validateSQLFunctionName(n.Name)
-	// Special case: CAST(expr AS type)
-	if n.Name == "CAST" && len(n.Args) == 2 {
-		sb.WriteString("CAST(")
-		sb.WriteString(n.Args[0].Accept(b.outer))
-		sb.WriteString(" AS ")
-		sb.WriteString(n.Args[1].Accept(b.outer))
-		sb.WriteString(")")
-		return sb.String()
-	}
-	sb.WriteString(n.Name)
-	sb.WriteString("(")
-	if n.Distinct {
-		sb.WriteString("DISTINCT ")
-	}
-	for i, arg := range n.Args {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(arg.Accept(b.outer))
-	}
-	sb.WriteString(")")
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitCase(n *nodes.CaseNode) string {
-	var sb strings.Builder
-	sb.WriteString("CASE")
-	if n.Operand != nil {
-		sb.WriteString(" ")
-		sb.WriteString(n.Operand.Accept(b.outer))
-	}
-	for _, w := range n.Whens {
-		sb.WriteString(" WHEN ")
-		sb.WriteString(w.Condition.Accept(b.outer))
-		sb.WriteString(" THEN ")
-		sb.WriteString(w.Result.Accept(b.outer))
-	}
-	if n.ElseVal != nil {
-		sb.WriteString(" ELSE ")
-		sb.WriteString(n.ElseVal.Accept(b.outer))
-	}
-	sb.WriteString(" END")
-	return sb.String()
-}
-
 // Grouping set type SQL keywords.
 var groupingSetTypeSQL = [...]string{
 	nodes.Cube:         "CUBE",
@@ -728,59 +300,30 @@ var groupingSetTypeSQL = [...]string{
 	nodes.GroupingSets: "GROUPING SETS",
 }
 
-func (b *baseVisitor) VisitGroupingSet(n *nodes.GroupingSetNode) string {
-	var sb strings.Builder
-	sb.WriteString(groupingSetTypeSQL[n.Type])
-	sb.WriteString("(")
-	if n.Type == nodes.GroupingSets {
-		// GROUPING SETS ((col1, col2), (col3), ())
-		for i, set := range n.Sets {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString("(")
-			for j, col := range set {
-				if j > 0 {
-					sb.WriteString(", ")
-				}
-				sb.WriteString(col.Accept(b.outer))
-			}
-			sb.WriteString(")")
-		}
-	} else {
-		// CUBE(col1, col2) or ROLLUP(col1, col2)
-		for i, col := range n.Columns {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString(col.Accept(b.outer))
-		}
-	}
-	sb.WriteString(")")
-	return sb.String()
-}
-
-func (b *baseVisitor) VisitAlias(n *nodes.AliasNode) string {
-	return n.Expr.Accept(b.outer) + " AS " + b.quoteIdent(n.Name)
+// Frame type SQL keywords.
+var frameTypeSQL = [...]string{
+	nodes.FrameRows:  "ROWS",
+	nodes.FrameRange: "RANGE",
 }
 
-func (b *baseVisitor) VisitBindParam(n *nodes.BindParamNode) string {
-	// Always parameterize if in param mode, otherwise render as literal.
-	if b.parameterize {
-		b.paramIndex++
-		b.params = append(b.params, n.Value)
-		return b.placeholder(b.paramIndex)
-	}
-	return b.literalToSQL(n.Value)
+// renderErrorf aborts rendering with a *nodes.RenderError, which managers
+// return from ToSQL.
+func renderErrorf(format string, args ...any) {
+	panic(&nodes.RenderError{Message: fmt.Sprintf(format, args...)})
 }
 
-func (b *baseVisitor) VisitCasted(n *nodes.CastedNode) string {
-	valSQL := b.literalToSQL(n.Value)
-	if n.TypeName != "" {
-		validateSQLTypeName(n.TypeName)
-		return "CAST(" + valSQL + " AS " + n.TypeName + ")"
+// catchRenderError recovers a *nodes.UnsupportedError or *nodes.RenderError
+// raised while rendering into *err. Other panics are re-raised.
+func catchRenderError(err *error) {
+	switch e := recover().(type) {
+	case nil:
+	case *nodes.UnsupportedError:
+		*err = e
+	case *nodes.RenderError:
+		*err = e
+	default:
+		panic(e)
 	}
-	return valSQL
 }
 
 // validateSQLTypeName panics if the type name contains characters outside
@@ -791,7 +334,7 @@ func validateSQLTypeName(name string) {
 		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') &&
 			(c < '0' || c > '9') && c != ' ' && c != '(' &&
 			c != ')' && c != ',' && c != '_' {
-			panic(fmt.Sprintf("gosbee: invalid SQL type name character %q in %q", string(c), name))
+			renderErrorf("invalid SQL type name character %q in %q", string(c), name)
 		}
 	}
 }
@@ -803,7 +346,7 @@ func validateSQLFunctionName(name string) {
 	for _, c := range name {
 		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') &&
 			(c < '0' || c > '9') && c != '_' {
-			panic(fmt.Sprintf("gosbee: invalid SQL function name character %q in %q", string(c), name))
+			renderErrorf("invalid SQL function name character %q in %q", string(c), name)
 		}
 	}
 }
@@ -883,177 +426,3 @@ func renderBoundSQL(v nodes.Visitor, fb nodes.FrameBound) string {
 		return ""
 	}
 }
-
-// Frame type SQL keywords.
-var frameTypeSQL = [...]string{
-	nodes.FrameRows:  "ROWS",
-	nodes.FrameRange: "RANGE",
-}
-
-// renderWindowDef renders a window definition as SQL: (PARTITION BY ... ORDER BY ... ROWS/RANGE ...)
-func (b *baseVisitor) renderWindowDef(w *nodes.WindowDefinition) string {
-	return RenderWindowDef(b.outer, w)
-}
-
-func (b *baseVisitor) VisitSelectCore(n *nodes.SelectCore) string {
-	var sb strings.Builder
-
-	b.writeCTEs(&sb, n.CTEs)
-	b.writeComment(&sb, n.Comment)
-	sb.WriteString("SELECT ")
-	b.writeHints(&sb, n.Hints)
-	b.writeDistinct(&sb, n.Distinct, n.DistinctOn)
-	b.writeProjections(&sb, n.Projections)
-	b.writeFrom(&sb, n.From)
-	b.writeJoins(&sb, n.Joins)
-	b.writeClause(&sb, " WHERE ", n.Wheres, " AND ")
-	b.writeClause(&sb, " GROUP BY ", n.Groups, ", ")
-	b.writeClause(&sb, " HAVING ", n.Havings, " AND ")
-	b.writeWindowClause(&sb, n.Windows)
-	b.writeClause(&sb, " ORDER BY ", n.Orders, ", ")
-	b.writeNodeClause(&sb, " LIMIT ", n.Limit)
-	b.writeNodeClause(&sb, " OFFSET ", n.Offset)
-	b.writeLock(&sb, n.Lock, n.SkipLocked)
-
-	return sb.String()
-}
-
-// writeClause writes "keyword item1 sep item2 sep ..." if items is non-empty.
-func (b *baseVisitor) writeClause(sb *strings.Builder, keyword string, items []nodes.Node, sep string) {
-	if len(items) == 0 {
-		return
-	}
-	sb.WriteString(keyword)
-	for i, item := range items {
-		if i > 0 {
-			sb.WriteString(sep)
-		}
-		sb.WriteString(item.Accept(b.outer))
-	}
-}
-
-// writeNodeClause writes "keyword node" if node is non-nil.
-func (b *baseVisitor) writeNodeClause(sb *strings.Builder, keyword string, n nodes.Node) {
-	if n != nil {
-		sb.WriteString(keyword)
-		sb.WriteString(n.Accept(b.outer))
-	}
-}
-
-func (b *baseVisitor) writeCTEs(sb *strings.Builder, ctes []*nodes.CTENode) {
-	if len(ctes) == 0 {
-		return
-	}
-	hasRecursive := false
-	for _, cte := range ctes {
-		if cte.Recursive {
-			hasRecursive = true
-			break
-		}
-	}
-	if hasRecursive {
-		sb.WriteString("WITH RECURSIVE ")
-	} else {
-		sb.WriteString("WITH ")
-	}
-	for i, cte := range ctes {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(cte.Accept(b.outer))
-	}
-	sb.WriteString(" ")
-}
-
-func (b *baseVisitor) writeComment(sb *strings.Builder, comment string) {
-	if comment != "" {
-		sb.WriteString("/* ")
-		sb.WriteString(strings.ReplaceAll(comment, "*/", "* /"))
-		sb.WriteString(" */ ")
-	}
-}
-
-func (b *baseVisitor) writeHints(sb *strings.Builder, hints []string) {
-	if len(hints) == 0 {
-		return
-	}
-	sb.WriteString("/*+ ")
-	for i, h := range hints {
-		if i > 0 {
-			sb.WriteString(" ")
-		}
-		sb.WriteString(strings.ReplaceAll(h, "*/", "* /"))
-	}
-	sb.WriteString(" */ ")
-}
-
-func (b *baseVisitor) writeDistinct(sb *strings.Builder, distinct bool, distinctOn []nodes.Node) {
-	if len(distinctOn) > 0 {
-		sb.WriteString("DISTINCT ON (")
-		for i, c := range distinctOn {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString(c.Accept(b.outer))
-		}
-		sb.WriteString(") ")
-	} else if distinct {
-		sb.WriteString("DISTINCT ")
-	}
-}
-
-func (b *baseVisitor) writeProjections(sb *strings.Builder, projections []nodes.Node) {
-	if len(projections) == 0 {
-		sb.WriteString("*")
-		return
-	}
-	for i, p := range projections {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(p.Accept(b.outer))
-	}
-}
-
-func (b *baseVisitor) writeFrom(sb *strings.Builder, from nodes.Node) {
-	if from != nil {
-		sb.WriteString(" FROM ")
-		sb.WriteString(from.Accept(b.outer))
-	}
-}
-
-func (b *baseVisitor) writeJoins(sb *strings.Builder, joins []*nodes.JoinNode) {
-	for _, j := range joins {
-		sb.WriteString(" ")
-		sb.WriteString(j.Accept(b.outer))
-	}
-}
-
-func (b *baseVisitor) writeWindowClause(sb *strings.Builder, windows []*nodes.WindowDefinition) {
-	if len(windows) == 0 {
-		return
-	}
-	sb.WriteString(" WINDOW ")
-	for i, w := range windows {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(b.quoteIdent(w.Name))
-		sb.WriteString(" AS ")
-		sb.WriteString(b.renderWindowDef(&nodes.WindowDefinition{
-			PartitionBy: w.PartitionBy,
-			OrderBy:     w.OrderBy,
-			Frame:       w.Frame,
-		}))
-	}
-}
-
-func (b *baseVisitor) writeLock(sb *strings.Builder, lock nodes.LockMode, skipLocked bool) {
-	if lock != nodes.NoLock {
-		sb.WriteString(" ")
-		sb.WriteString(lockModeSQL[lock])
-		if skipLocked {
-			sb.WriteString(" SKIP LOCKED")
-		}
-	}
-}
@@ -123,6 +123,78 @@ func TestVisitSqlLiteral(t *testing.T) {
 	testutil.AssertSQL(t, NewPostgresVisitor(WithoutParams()), raw, `COUNT(*)`)
 }
 
+func mustFragment(t *testing.T, raw nodes.RawSQL, args ...any) *nodes.SqlLiteral {
+	t.Helper()
+	frag, err := nodes.NewSqlFragment(raw, args...)
+	if err != nil {
+		t.Fatalf("NewSqlFragment failed: %v", err)
+	}
+	return frag
+}
+
+func TestVisitSqlFragmentRunningIndexPostgres(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	frag := mustFragment(t, "date_trunc(?, ?) > ?", "day", users.Col("created_at"), "2024-01-01")
+	core := &nodes.SelectCore{
+		From:   users,
+		Wheres: []nodes.Node{users.Col("active").Eq(true), frag},
+	}
+	v := NewPostgresVisitor()
+	sql := core.Accept(v)
+	want := `SELECT * FROM "users" WHERE "users"."active" = $1 AND date_trunc($2, "users"."created_at") > $3`
+	if sql != want {
+		t.Errorf("expected:\n  %s\ngot:\n  %s", want, sql)
+	}
+	params := v.Params()
+	if len(params) != 3 || params[0] != true || params[1] != "day" || params[2] != "2024-01-01" {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestVisitSqlFragmentMySQL(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	frag := mustFragment(t, "IFNULL(?, ?)", users.Col("nick"), "anon")
+	v := NewMySQLVisitor()
+	sql := frag.Accept(v)
+	if sql != "IFNULL(`users`.`nick`, ?)" {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+	if len(v.Params()) != 1 || v.Params()[0] != "anon" {
+		t.Errorf("unexpected params: %v", v.Params())
+	}
+}
+
+func TestVisitSqlFragmentWithoutParams(t *testing.T) {
+	t.Parallel()
+	frag := mustFragment(t, "data ?? ? AND level > ?", "tags", 3)
+	testutil.AssertSQL(t, NewPostgresVisitor(WithoutParams()), frag, `data ? 'tags' AND level > 3`)
+}
+
+func TestVisitSqlFragmentCountMismatchPanics(t *testing.T) {
+	t.Parallel()
+	frag := &nodes.SqlLiteral{Raw: "a = ? AND b = ?", Binds: []any{1}, Placeholders: true}
+	defer func() {
+		if _, ok := recover().(*nodes.RenderError); !ok {
+			t.Error("expected a *nodes.RenderError panic for mismatched placeholder count")
+		}
+	}()
+	frag.Accept(NewPostgresVisitor())
+}
+
+func TestVisitBoundSqlLiteralUnchanged(t *testing.T) {
+	t.Parallel()
+	lit := nodes.NewBoundSqlLiteral("id = $1", 42)
+	v := NewPostgresVisitor()
+	if sql := lit.Accept(v); sql != "id = $1" {
+		t.Errorf("unexpected SQL: %s", sql)
+	}
+	if len(v.Params()) != 1 {
+		t.Errorf("expected 1 param, got %v", v.Params())
+	}
+}
+
 // --- Comparison ---
 
 func TestVisitEq(t *testing.T) {
@@ -1214,6 +1286,26 @@ func TestVisitInsertOnConflictDoNothing(t *testing.T) {
 		`INSERT INTO "users" ("email", "name") VALUES ('a@b.com', 'Alice') ON CONFLICT ("email") DO NOTHING`)
 }
 
+func TestVisitInsertRawColumns(t *testing.T) {
+	t.Parallel()
+	users := nodes.NewTable("users")
+	stmt := &nodes.InsertStatement{
+		Into:    users,
+		Columns: []nodes.Node{nodes.NewSqlLiteral("email"), nodes.NewAttribute(users, "name")},
+		Values:  [][]nodes.Node{{nodes.Literal("a@b.com"), nodes.Literal("Alice")}},
+		OnConflict: &nodes.OnConflictNode{
+			Columns: []nodes.Node{nodes.NewSqlLiteral("lower(email)")},
+			Action:  nodes.DoNothing,
+		},
+	}
+	testutil.AssertSQL(t, NewPostgresVisitor(WithoutParams()), stmt,
+		`INSERT INTO "users" (email, "name") VALUES ('a@b.com', 'Alice') ON CONFLICT (lower(email)) DO NOTHING`)
+
+	stmt.OnConflict = nil
+	testutil.AssertSQL(t, NewFormattingVisitor(NewPostgresVisitor(WithoutParams())), stmt,
+		"INSERT INTO \"users\" (email, \"name\")\nVALUES ('a@b.com', 'Alice')")
+}
+
 func TestVisitInsertOnConflictDoUpdate(t *testing.T) {
 	t.Parallel()
 	users := nodes.NewTable("users")
@@ -3024,6 +3116,52 @@ func TestVisitBindParamMySQL(t *testing.T) {
 	}
 }
 
+// --- NamedParam SQL ---
+
+func TestVisitNamedParamPostgresReusesIndex(t *testing.T) {
+	t.Parallel()
+	v := NewPostgresVisitor()
+	users := nodes.NewTable("users")
+	cond := users.Col("tenant_id").Eq(nodes.Named("tenant")).
+		And(users.Col("age").Gt(18)).
+		And(users.Col("owner_id").Eq(nodes.Named("tenant")))
+	testutil.AssertSQL(t, v, cond,
+		`"users"."tenant_id" = $1 AND "users"."age" > $2 AND "users"."owner_id" = $1`)
+	params := v.Params()
+	if len(params) != 2 {
+		t.Fatalf("expected 2 params, got %v", params)
+	}
+	if np, ok := params[0].(*nodes.NamedParamNode); !ok || np.Name != "tenant" {
+		t.Errorf("expected named slot for tenant, got %v", params[0])
+	}
+}
+
+func TestVisitNamedParamMySQLRepeatsSlot(t *testing.T) {
+	t.Parallel()
+	v := NewMySQLVisitor()
+	users := nodes.NewTable("users")
+	cond := users.Col("a").Eq(nodes.Named("x")).And(users.Col("b").Eq(nodes.Named("x")))
+	testutil.AssertSQL(t, v, cond, "`users`.`a` = ? AND `users`.`b` = ?")
+	if len(v.Params()) != 2 {
+		t.Errorf("expected 2 params, got %v", v.Params())
+	}
+}
+
+func TestVisitNamedParamWithoutParams(t *testing.T) {
+	t.Parallel()
+	v := NewSQLiteVisitor(WithoutParams())
+	testutil.AssertSQL(t, v, nodes.Named("x"), "?")
+}
+
+func TestVisitNamedParamResetClearsIndex(t *testing.T) {
+	t.Parallel()
+	v := NewPostgresVisitor()
+	nodes.Named("x").Accept(v)
+	v.Reset()
+	testutil.AssertSQL(t, v, nodes.NewBindParam(1), "$1")
+	testutil.AssertSQL(t, v, nodes.Named("x"), "$2")
+}
+
 // --- Casted SQL ---
 
 func TestVisitCasted(t *testing.T) {
@@ -3124,6 +3262,15 @@ func TestDotVisitBindParam(t *testing.T) {
 	assertContains(t, dot, "BindParam")
 }
 
+func TestDotVisitNamedParam(t *testing.T) {
+	t.Parallel()
+	dv := NewDotVisitor()
+	nodes.Named("tenant_id").Accept(dv)
+	dot := dv.ToDot()
+	assertContains(t, dot, "NamedParam")
+	assertContains(t, dot, "tenant_id")
+}
+
 func TestDotVisitCasted(t *testing.T) {
 	t.Parallel()
 	c := nodes.NewCasted(42, "integer")